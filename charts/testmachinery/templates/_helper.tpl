@@ -25,6 +25,14 @@ controller:
     namespace: {{ .Release.Namespace }}
     deploymentName: {{ .Values.argo.argo.name }}
     interval: {{ .Values.controller.argoHealthCheckInterval }}
+  {{- if .Values.controller.admissionPolicies }}
+  admissionPolicies:
+  {{- toYaml .Values.controller.admissionPolicies | nindent 4 }}
+  {{- end }}
+  {{- if .Values.controller.defaulting }}
+  defaulting:
+  {{- toYaml .Values.controller.defaulting | nindent 4 }}
+  {{- end }}
 
 testmachinery:
   namespace: {{ .Release.Namespace }}
@@ -49,11 +57,52 @@ testmachinery:
   {{- toYaml .Values.testmachinery.landscapeMappings | nindent 4 }}
   {{- end }}
 
+  {{- if .Values.testmachinery.repositoryCache }}
+  repositoryCache:
+  {{- toYaml .Values.testmachinery.repositoryCache | nindent 4 }}
+  {{- end }}
+
+  {{- if .Values.testmachinery.configSources }}
+  configSources:
+    {{- if .Values.testmachinery.configSources.allowedSecretNamespaces }}
+    allowedSecretNamespaces:
+    {{- toYaml .Values.testmachinery.configSources.allowedSecretNamespaces | nindent 4 }}
+    {{- end }}
+    {{- if .Values.testmachinery.configSources.vault }}
+    vault:
+      address: {{ required "Missing an entry for .Values.testmachinery.configSources.vault.address!" .Values.testmachinery.configSources.vault.address }}
+      tokenPath: /etc/testmachinery-controller/secrets/vault/token
+      {{- if .Values.testmachinery.configSources.vault.namespace }}
+      namespace: {{ .Values.testmachinery.configSources.vault.namespace }}
+      {{- end }}
+      {{- if .Values.testmachinery.configSources.vault.kvVersion }}
+      kvVersion: {{ .Values.testmachinery.configSources.vault.kvVersion }}
+      {{- end }}
+    {{- end }}
+  {{- end }}
+
+  {{- if .Values.testmachinery.kubeconfigPreflight }}
+  kubeconfigPreflight:
+  {{- toYaml .Values.testmachinery.kubeconfigPreflight | nindent 4 }}
+  {{- end }}
+
 github:
   cache:
     cacheDir: {{ .Values.testmachinery.github.cache.cacheDir }}
     cacheDiskSizeGB: {{ .Values.testmachinery.github.cache.cacheDiskSizeGB }}
     maxAgeSeconds: {{ .Values.testmachinery.github.cache.maxAgeSeconds }}
+    {{- if .Values.testmachinery.github.cache.rateLimitReserve }}
+    rateLimitReserve: {{ .Values.testmachinery.github.cache.rateLimitReserve }}
+    {{- end }}
+    {{- if .Values.testmachinery.github.cache.shared }}
+    shared:
+    {{- toYaml .Values.testmachinery.github.cache.shared | nindent 6 }}
+    {{- end }}
+    {{- if .Values.testmachinery.github.cache.server }}
+    server:
+      bindAddress: ":{{ .Values.testmachinery.github.cache.server.port }}"
+      tokenPath: /etc/testmachinery-controller/secrets/github-cache/token
+    {{- end }}
   {{- if .Values.testmachinery.github.credentials }}
   secretsPath: /etc/testmachinery-controller/secrets/git/github-secrets.yaml # mount secrets and specify the path
   {{- end }}
@@ -11,7 +11,9 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/gardener/test-infra/cmd/elasticsearch/cmd/ingest"
+	"github.com/gardener/test-infra/cmd/elasticsearch/cmd/migrate"
 	"github.com/gardener/test-infra/cmd/elasticsearch/cmd/precompute"
+	"github.com/gardener/test-infra/cmd/elasticsearch/cmd/setup"
 	"github.com/gardener/test-infra/pkg/logger"
 )
 
@@ -56,4 +58,6 @@ func init() {
 
 	precompute.AddCommand(rootCmd)
 	ingest.AddCommand(rootCmd)
+	setup.AddCommand(rootCmd)
+	migrate.AddCommand(rootCmd)
 }
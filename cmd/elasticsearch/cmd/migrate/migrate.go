@@ -0,0 +1,90 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gardener/test-infra/pkg/apis/config"
+	"github.com/gardener/test-infra/pkg/logger"
+	"github.com/gardener/test-infra/pkg/util/elasticsearch"
+	"github.com/gardener/test-infra/pkg/util/elasticsearch/index"
+)
+
+var (
+	// only touch ES when true, dry-run otherwise
+	updateES     bool
+	flavor       string
+	patterns     []string
+	pollInterval time.Duration
+)
+
+// AddCommand adds the migrate subcommand to another command.
+func AddCommand(cmd *cobra.Command) {
+	cmd.AddCommand(migrateCmd)
+}
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Reindexes existing testmachinery indices with an outdated mapping into indices with the current index template.",
+	PreRun: func(cmd *cobra.Command, args []string) {
+		if updateES {
+			logger.Log.Info("Starting 'elasticsearch migrate' in update mode", "elasticsearch endpoint", cmd.Flag("endpoint").Value, "elasticsearch user", cmd.Flag("user").Value)
+		} else {
+			logger.Log.Info("Starting 'elasticsearch migrate' in dry-run mode")
+		}
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := run(cmd); err != nil {
+			logger.Log.Error(err, "error during execution")
+			return err
+		}
+		return nil
+	},
+	PostRun: func(cmd *cobra.Command, args []string) {
+		logger.Log.Info("Finished 'elasticsearch migrate'")
+	},
+}
+
+// package init defines the flags for the migrate command
+func init() {
+	migrateCmd.Flags().BoolVar(&updateES, "update", false, "when false, only prints the indices that would be migrated instead of touching elasticsearch")
+	migrateCmd.Flags().StringVar(&flavor, "flavor", string(index.FlavorElasticsearch), "search engine that manages the index lifecycle: elasticsearch (ILM) or opensearch (ISM)")
+	migrateCmd.Flags().StringSliceVar(&patterns, "index", []string{"testmachinery-*", "tm-*"}, "index patterns of the indices to migrate")
+	migrateCmd.Flags().DurationVar(&pollInterval, "poll-interval", 5*time.Second, "interval in which the progress of the reindexing is reported")
+}
+
+func run(cmd *cobra.Command) error {
+	if flavor != string(index.FlavorElasticsearch) && flavor != string(index.FlavorOpenSearch) {
+		return fmt.Errorf("unknown flavor %q", flavor)
+	}
+	esClient, err := elasticsearch.NewClient(config.ElasticSearch{
+		Endpoint: cmd.Flag("endpoint").Value.String(),
+		Username: cmd.Flag("user").Value.String(),
+		Password: cmd.Flag("password").Value.String(),
+	})
+	if err != nil {
+		return err
+	}
+
+	manager := index.NewManager(logger.Log.WithName("migrate"), esClient, index.Options{
+		Flavor:       index.Flavor(flavor),
+		DryRun:       !updateES,
+		PollInterval: pollInterval,
+	})
+	results, err := manager.Migrate(context.Background(), patterns)
+	for _, result := range results {
+		if result.Skipped != "" {
+			fmt.Printf("%s: skipped, %s\n", result.Index, result.Skipped)
+			continue
+		}
+		fmt.Printf("%s: %d documents -> %s\n", result.Index, result.Docs, result.Destination)
+	}
+	return err
+}
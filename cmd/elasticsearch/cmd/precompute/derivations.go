@@ -0,0 +1,55 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package precompute
+
+import (
+	"reflect"
+
+	"github.com/gardener/test-infra/pkg/testmachinery/collector"
+	"github.com/gardener/test-infra/pkg/testmachinery/metadata"
+	"github.com/gardener/test-infra/pkg/util/elasticsearch/derivation"
+)
+
+// package init registers the derivations of the testmachinery documents
+func init() {
+	derivation.Register(derivation.Derivation{
+		Name:        "teststep-precomputed",
+		Description: "re-computes the precomputed fields (.pre) of teststep documents like phaseNum and clusterDomain",
+		Filter:      map[string]interface{}{"term": map[string]interface{}{"type.keyword": metadata.SummaryTypeTeststep}},
+		Derive:      deriveTeststepPrecomputed,
+	})
+}
+
+// deriveTeststepPrecomputed recomputes the precomputed fields of a teststep summary
+func deriveTeststepPrecomputed(doc derivation.Document) (map[string]interface{}, error) {
+	var summary metadata.StepSummary
+	if err := doc.Decode(&summary); err != nil {
+		return nil, err
+	}
+	meta := summary.Metadata
+	if summary.Type != metadata.SummaryTypeTeststep || meta == nil {
+		// entries without metadata are probably too old
+		return nil, nil
+	}
+
+	if meta.KubernetesVersion == "" && meta.Annotations != nil {
+		meta.KubernetesVersion = meta.Annotations["metadata.testmachinery.gardener.cloud/k8sVersion"]
+	}
+	if meta.KubernetesVersion == "" && meta.Annotations != nil {
+		meta.KubernetesVersion = meta.Annotations["testrunner.testmachinery.sapcloud.io/k8sVersion"]
+	}
+
+	var clusterDomain string
+	oldPreComputed := summary.PreComputed
+	if oldPreComputed != nil {
+		clusterDomain = oldPreComputed.ClusterDomain
+	}
+
+	newPreComputed := collector.PreComputeTeststepFields(summary.Phase, meta.Metadata, clusterDomain)
+	if reflect.DeepEqual(oldPreComputed, newPreComputed) {
+		return nil, nil
+	}
+	return map[string]interface{}{"pre": newPreComputed}, nil
+}
@@ -6,24 +6,26 @@ package precompute
 
 import (
 	"context"
-	"encoding/json"
-	"errors"
 	"fmt"
-	"io"
-	"net/http"
-	"reflect"
+	"os"
+	"sort"
+	"text/tabwriter"
 
 	"github.com/spf13/cobra"
 
 	"github.com/gardener/test-infra/pkg/apis/config"
 	"github.com/gardener/test-infra/pkg/logger"
-	"github.com/gardener/test-infra/pkg/testmachinery/collector"
 	"github.com/gardener/test-infra/pkg/util/elasticsearch"
+	"github.com/gardener/test-infra/pkg/util/elasticsearch/derivation"
 )
 
 var (
 	// only touch ES when true, dry-run otherwise
 	updateES bool
+	listOnly bool
+
+	derivationNames []string
+	opts            derivation.Options
 )
 
 // AddCommand adds the precompute subcommand to another command.
@@ -33,7 +35,7 @@ func AddCommand(cmd *cobra.Command) {
 
 var precomputeCmd = &cobra.Command{
 	Use:   "precompute",
-	Short: "Reads existing teststep metadata, re-computes the current precomputed values and optionally updates the respective elasticsearch document.",
+	Short: "Reads existing documents, re-computes the fields of the selected derivations and optionally updates the respective elasticsearch documents.",
 	PreRun: func(cmd *cobra.Command, args []string) {
 		if updateES {
 			logger.Log.Info("Starting 'elasticsearch precompute' in update mode", "elasticsearch endpoint", cmd.Flag("endpoint").Value, "elasticsearch user", cmd.Flag("user").Value)
@@ -55,10 +57,32 @@ var precomputeCmd = &cobra.Command{
 
 // package init defines the flags for the precompute command
 func init() {
-	precomputeCmd.Flags().BoolVar(&updateES, "update", false, "when false, only prints potential updates to stdout instead of touching documents in elasticsearch")
+	precomputeCmd.Flags().BoolVar(&updateES, "update", false, "when false, only prints statistics of potential updates instead of touching documents in elasticsearch")
+	precomputeCmd.Flags().BoolVar(&listOnly, "list", false, "only lists the available derivations")
+	precomputeCmd.Flags().StringSliceVar(&derivationNames, "derivation", nil, "names of the derivations to apply; defaults to all derivations")
+	precomputeCmd.Flags().StringSliceVar(&opts.Indices, "index", []string{"testmachinery-*"}, "indices or index patterns whose documents are recomputed")
+	precomputeCmd.Flags().StringVar(&opts.TimeField, "time-field", "startTime", "date field that is used to filter and order the documents")
+	precomputeCmd.Flags().StringVar(&opts.From, "from", "", "only recompute documents newer than this date or date math expression, e.g. now-30d")
+	precomputeCmd.Flags().StringVar(&opts.To, "to", "", "only recompute documents older than this date or date math expression")
+	precomputeCmd.Flags().IntVar(&opts.PageSize, "page-size", 5000, "number of documents that are read and updated at once")
+	precomputeCmd.Flags().IntVar(&opts.Concurrency, "concurrency", 1, "number of pages that are recomputed and updated in parallel")
+	precomputeCmd.Flags().StringVar(&opts.CheckpointFile, "checkpoint-file", "", "file that stores the progress so that an interrupted run is resumed")
 }
 
 func run(cmd *cobra.Command) error {
+	if listOnly {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, d := range derivation.Registered() {
+			fmt.Fprintf(w, "%s\t%s\n", d.Name, d.Description)
+		}
+		return w.Flush()
+	}
+
+	derivations, err := derivation.Get(derivationNames...)
+	if err != nil {
+		return err
+	}
+
 	esClient, err := elasticsearch.NewClient(config.ElasticSearch{
 		Endpoint: cmd.Flag("endpoint").Value.String(),
 		Username: cmd.Flag("user").Value.String(),
@@ -68,118 +92,26 @@ func run(cmd *cobra.Command) error {
 		return err
 	}
 
-	pageSize := 5000
-	path, payload := BuildScrollQueryInitial("testmachinery-*", pageSize)
-
-	var processedItems int
-	for {
-		esResponse, err := queryAndRecomputeAndStore(esClient, path, payload)
-		if err != nil {
-			return err
-		}
-		processedItems += len(esResponse.Hits.Results)
-		logger.Log.Info(fmt.Sprintf("%d%% processed (%d/%d)", processedItems*100/esResponse.Hits.Total.Value, processedItems, esResponse.Hits.Total.Value))
-
-		// once we hit the first page with less than pageSize results, there will be no further page -> done
-		if len(esResponse.Hits.Results) < pageSize {
-			break
-		}
-
-		// on to the next page
-		scrollID := esResponse.ScrollID
-		path, payload = BuildScrollQueryNextPage(scrollID)
-	}
-
-	return nil
+	opts.DryRun = !updateES
+	stats, err := derivation.NewRunner(logger.Log.WithName("precompute"), esClient, derivations, opts).Run(context.Background())
+	printStats(stats)
+	return err
 }
 
-// queryAndRecomputeAndStore queries the testmachinery index for tm data, recomputes the pre-calculated fields and if changed, updates the respective data
-func queryAndRecomputeAndStore(esClient elasticsearch.Client, path string, payload io.Reader) (QueryResponse, error) {
-	ctx := context.Background()
-	defer ctx.Done()
-
-	bytes, err := esClient.RequestWithCtx(ctx, http.MethodGet, path, payload)
-	if err != nil {
-		return QueryResponse{}, err
-	}
-
-	var esResponse QueryResponse
-	if err := json.Unmarshal(bytes, &esResponse); err != nil {
-		return QueryResponse{}, err
-	}
-	logger.Log.V(6).Info("Got response from ES", "esResponse", esResponse)
-
-	var modifiedItems []Result
-	for _, result := range esResponse.Hits.Results {
-		meta := result.StepSummary.Metadata
-		if meta == nil {
-			logger.Log.V(5).Info("Skipping entry as no metadata present, probably too old", "index", result.Index, "docID", result.DocID)
-			continue
-		}
-
-		if meta.KubernetesVersion == "" && meta.Annotations != nil {
-			meta.KubernetesVersion = meta.Annotations["metadata.testmachinery.gardener.cloud/k8sVersion"]
-		}
-		if meta.KubernetesVersion == "" && meta.Annotations != nil {
-			meta.KubernetesVersion = meta.Annotations["testrunner.testmachinery.sapcloud.io/k8sVersion"]
-		}
-
-		var clusterDomain string
-		oldPreComputed := result.StepSummary.PreComputed
-		if oldPreComputed != nil {
-			clusterDomain = oldPreComputed.ClusterDomain
-		}
-
-		newPreComputed := collector.PreComputeTeststepFields(result.StepSummary.Phase, meta.Metadata, clusterDomain)
-
-		if reflect.DeepEqual(oldPreComputed, newPreComputed) {
-			logger.Log.V(6).Info("old and new precomputed are equal -> nothing to do")
-		} else {
-			logger.Log.V(4).Info("preComputed field changed", "oldPreComputed", oldPreComputed, "newPreComputed", newPreComputed)
-			result.StepSummary.PreComputed = newPreComputed
-			modifiedItems = append(modifiedItems, result)
-		}
-	}
-
-	if len(modifiedItems) == 0 {
-		logger.Log.V(6).Info("no modified data for any result (in this page) -> nothing to update")
-		return esResponse, nil
+func printStats(stats derivation.Stats) {
+	fmt.Printf("scanned %d documents using %s\n", stats.Scanned, stats.Search)
+	names := make([]string, 0, len(stats.Changed))
+	for name := range stats.Changed {
+		names = append(names, name)
 	}
-
-	path, payload, err = BuildBulkUpdateQuery(modifiedItems)
-	if err != nil {
-		return QueryResponse{}, err
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Printf("%s: %d documents changed\n", name, stats.Changed[name])
 	}
-
-	if !updateES {
-		payloadBytes, err := io.ReadAll(payload)
-		if err != nil {
-			logger.Log.Error(err, "could not parse bulk update payload into string")
-		}
-		logger.Log.Info("Not modifying elasticsearch data as update flag was not set.")
-		logger.Log.V(10).Info("", "path", path, "payload", string(payloadBytes))
-		return esResponse, nil
+	if updateES {
+		fmt.Printf("updated %d documents\n", stats.Updated)
 	}
-
-	// do the real update
-	logger.Log.V(6).Info("going to bulk update", "path", path)
-	bytes, err = esClient.RequestWithCtx(ctx, http.MethodPost, path, payload)
-	if err != nil {
-		logger.Log.Error(err, "Error during bulk update", "response", string(bytes))
-		return QueryResponse{}, err
-	}
-	bulkResponse := BulkResponse{}
-	if err := json.Unmarshal(bytes, &bulkResponse); err != nil {
-		return QueryResponse{}, err
+	if stats.Failed != 0 {
+		fmt.Printf("failed %d documents\n", stats.Failed)
 	}
-	// check for errors in the bulk
-	if bulkResponse.ErrorsOccurred {
-		logger.Log.Error(errors.New("one or more errors occurred during bulk update"), "Errors occurred during bulk update")
-		for _, item := range bulkResponse.Items {
-			logger.Log.Info("bulk item error", "errorType", item.Index.Error.Type, "errorReason", item.Index.Error.Reason, "httpStatus", item.Index.HTTPStatus, "index", item.Index.Index, "id", item.Index.ID)
-		}
-		// in bulk updates a few items could go wrong, hence we'd better not stop the whole process here but continue, maybe next bulk updates will succeed ¯\_(ツ)_/¯
-	}
-
-	return esResponse, nil
 }
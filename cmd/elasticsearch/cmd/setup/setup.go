@@ -0,0 +1,81 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package setup
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gardener/test-infra/pkg/apis/config"
+	"github.com/gardener/test-infra/pkg/logger"
+	"github.com/gardener/test-infra/pkg/util/elasticsearch"
+	"github.com/gardener/test-infra/pkg/util/elasticsearch/index"
+)
+
+var (
+	// only touch ES when true, dry-run otherwise
+	updateES  bool
+	flavor    string
+	lifecycle index.LifecycleConfig
+)
+
+// AddCommand adds the setup subcommand to another command.
+func AddCommand(cmd *cobra.Command) {
+	cmd.AddCommand(setupCmd)
+}
+
+var setupCmd = &cobra.Command{
+	Use:   "setup",
+	Short: "Installs the versioned index templates, lifecycle policies and write aliases of the testmachinery indices.",
+	PreRun: func(cmd *cobra.Command, args []string) {
+		if updateES {
+			logger.Log.Info("Starting 'elasticsearch setup' in update mode", "elasticsearch endpoint", cmd.Flag("endpoint").Value, "elasticsearch user", cmd.Flag("user").Value)
+		} else {
+			logger.Log.Info("Starting 'elasticsearch setup' in dry-run mode")
+		}
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := run(cmd); err != nil {
+			logger.Log.Error(err, "error during execution")
+			return err
+		}
+		return nil
+	},
+	PostRun: func(cmd *cobra.Command, args []string) {
+		logger.Log.Info("Finished 'elasticsearch setup'")
+	},
+}
+
+// package init defines the flags for the setup command
+func init() {
+	setupCmd.Flags().BoolVar(&updateES, "update", false, "when false, only prints the requests instead of modifying elasticsearch")
+	setupCmd.Flags().StringVar(&flavor, "flavor", string(index.FlavorElasticsearch), "search engine that manages the index lifecycle: elasticsearch (ILM) or opensearch (ISM)")
+	setupCmd.Flags().StringVar(&lifecycle.RolloverMaxAge, "rollover-max-age", "30d", "roll over the write index after the given age")
+	setupCmd.Flags().StringVar(&lifecycle.RolloverMaxSize, "rollover-max-size", "50gb", "roll over the write index once its primary shards reach the given size")
+	setupCmd.Flags().StringVar(&lifecycle.RetentionAge, "retention", "", "delete rolled over indices after the given age, e.g. 365d. Indices are kept forever if empty")
+}
+
+func run(cmd *cobra.Command) error {
+	if flavor != string(index.FlavorElasticsearch) && flavor != string(index.FlavorOpenSearch) {
+		return fmt.Errorf("unknown flavor %q", flavor)
+	}
+	esClient, err := elasticsearch.NewClient(config.ElasticSearch{
+		Endpoint: cmd.Flag("endpoint").Value.String(),
+		Username: cmd.Flag("user").Value.String(),
+		Password: cmd.Flag("password").Value.String(),
+	})
+	if err != nil {
+		return err
+	}
+
+	manager := index.NewManager(logger.Log.WithName("setup"), esClient, index.Options{
+		Flavor:    index.Flavor(flavor),
+		Lifecycle: lifecycle,
+		DryRun:    !updateES,
+	})
+	return manager.Setup(context.Background())
+}
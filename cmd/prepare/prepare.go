@@ -29,6 +29,7 @@ done
 */
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net"
@@ -40,9 +41,11 @@ import (
 
 	"github.com/go-logr/logr"
 
+	"github.com/gardener/test-infra/pkg/apis/config"
 	tmv1beta1 "github.com/gardener/test-infra/pkg/apis/testmachinery/v1beta1"
 	"github.com/gardener/test-infra/pkg/testmachinery"
 	prepare "github.com/gardener/test-infra/pkg/testmachinery/prepare"
+	"github.com/gardener/test-infra/pkg/testmachinery/prepare/repocache"
 	"github.com/gardener/test-infra/pkg/util"
 )
 
@@ -55,12 +58,34 @@ func runPrepare(log logr.Logger, cfg *prepare.Config, repoBasePath string) error
 		return err
 	}
 
+	var cache *repocache.Cache
+	if cfg.RepositoryCache != nil {
+		var err error
+		cache, err = repocache.New(log.WithName("repository-cache"), *cfg.RepositoryCache)
+		if err != nil {
+			log.Error(err, "unable to use repository cache, repositories are cloned directly")
+		}
+	}
+
 	for _, repo := range cfg.Repositories {
+		if cache != nil {
+			err := checkoutRepositoryFromCache(log.WithName("clone-repositories"), cache, repo, repoBasePath)
+			if err == nil {
+				continue
+			}
+			log.Error(err, "unable to check out repository from cache, cloning it directly", "repo", repo.URL)
+		}
 		if err := cloneRepository(log.WithName("clone-repositories"), repo, repoBasePath); err != nil {
 			return err
 		}
 	}
 
+	if cache != nil {
+		if err := cache.Evict(context.Background()); err != nil {
+			log.Error(err, "unable to evict mirrors of the repository cache")
+		}
+	}
+
 	if err := createTMKubeconfigFile(log.WithName("create-tm-kubeconfig")); err != nil {
 		return err
 	}
@@ -90,15 +115,49 @@ func createDirectories(log logr.Logger, directories []string) error {
 	return nil
 }
 
+func checkoutRepositoryFromCache(log logr.Logger, cache *repocache.Cache, repo *prepare.Repository, repoBasePath string) error {
+	repoPath := path.Join(repoBasePath, repo.Name)
+	revision := repo.Revision
+	if len(repo.Commit) != 0 {
+		revision = repo.Commit
+	}
+	log.Info("Check out repo from cache", "repo", repo.URL, "revision", repo.Revision, "commit", repo.Commit, "path", repoPath)
+	if err := cache.Checkout(context.Background(), repo.URL, revision, repoPath); err != nil {
+		return err
+	}
+	return os.RemoveAll(path.Join(repoPath, ".git"))
+}
+
+// checkoutRevision checks out the commit the revision was resolved to or the revision itself if it was not resolved.
+// The commit is fetched explicitly if it is not reachable from the fetched revision anymore, e.g. after a force push.
+func checkoutRevision(log logr.Logger, repo *prepare.Repository, repoPath string) error {
+	if len(repo.Commit) == 0 {
+		return runCommand(log, repoPath, "git", "checkout", repo.Revision, "--")
+	}
+	if err := runCommand(log, repoPath, "git", "checkout", repo.Commit, "--"); err == nil {
+		return nil
+	}
+	log.Info("commit not found => fetching commit", "commit", repo.Commit)
+	if err := runCommand(log, repoPath, "git", "fetch", "origin", repo.Commit); err != nil {
+		return err
+	}
+	return runCommand(log, repoPath, "git", "checkout", repo.Commit, "--")
+}
+
 func cloneRepository(log logr.Logger, repo *prepare.Repository, repoBasePath string) error {
 	cwd, err := os.Getwd()
 	if err != nil {
 		return err
 	}
 	repoPath := path.Join(repoBasePath, repo.Name)
-	log.Info("Clone repo", "repo", repo.URL, "revision", repo.Revision, "path", repoPath)
+	log.Info("Clone repo", "repo", repo.URL, "revision", repo.Revision, "commit", repo.Commit, "path", repoPath)
 
-	if err := runCommand(log, cwd, "git", "clone", bloblessClone, "-v", repo.URL, repoPath); err != nil {
+	cloneArgs := []string{"clone", bloblessClone, "-v", repo.URL, repoPath}
+	if repo.Provider == string(config.GitProviderGit) {
+		// plain git servers do not necessarily support partial clones
+		cloneArgs = []string{"clone", "-v", repo.URL, repoPath}
+	}
+	if err := runCommand(log, cwd, "git", cloneArgs...); err != nil {
 		// do some checks to diagnose why git clone fails
 		if addrs, e := net.LookupHost("github.com"); e == nil {
 			fmt.Printf("LookupHost github.com: %v\n", addrs)
@@ -114,7 +173,7 @@ func cloneRepository(log logr.Logger, repo *prepare.Repository, repoBasePath str
 		_ = runCommand(log, cwd, "nslookup", "kubernetes.default.svc.cluster.local")
 		// for whatever reason, git clone sometimes fails to resolve github.com => workaround by retrying
 		log.Info("git clone failed => retrying once")
-		if err := runCommand(log, cwd, "git", "clone", bloblessClone, "-v", repo.URL, repoPath); err != nil {
+		if err := runCommand(log, cwd, "git", cloneArgs...); err != nil {
 			return err
 		}
 	}
@@ -123,7 +182,7 @@ func cloneRepository(log logr.Logger, repo *prepare.Repository, repoBasePath str
 		return err
 	}
 
-	if err := runCommand(log, repoPath, "git", "checkout", repo.Revision, "--"); err != nil {
+	if err := checkoutRevision(log, repo, repoPath); err != nil {
 		return err
 	}
 
@@ -0,0 +1,60 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	flag "github.com/spf13/pflag"
+	"sigs.k8s.io/controller-runtime/pkg/manager/signals"
+
+	"github.com/gardener/test-infra/pkg/logger"
+	"github.com/gardener/test-infra/pkg/testmachinery/telemetry"
+)
+
+var (
+	kubeconfigPath string
+	outputPath     string
+	endpoints      []string
+	interval       = telemetry.DefaultInterval
+)
+
+func init() {
+	logger.InitFlags(nil)
+	flag.StringVar(&kubeconfigPath, "kubeconfig", "", "path to the shoot kubeconfig whose API server is probed")
+	flag.StringVar(&outputPath, "output", "", "path to the file the samples are written to")
+	flag.StringArrayVar(&endpoints, "endpoint", nil, "additional http(s) endpoint that is probed")
+	flag.DurationVar(&interval, "interval", telemetry.DefaultInterval, "interval between two probes")
+}
+
+func main() {
+	flag.Parse()
+	log, err := logger.NewCliLogger()
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+
+	if len(outputPath) == 0 {
+		log.Error(nil, "an output path has to be defined")
+		os.Exit(1)
+	}
+
+	writer, err := telemetry.NewSampleWriter(outputPath)
+	if err != nil {
+		log.Error(err, "unable to create sample writer")
+		os.Exit(1)
+	}
+	defer func() {
+		if err := writer.Close(); err != nil {
+			log.Error(err, "unable to close sample file")
+		}
+	}()
+
+	log.Info("start telemetry measurement", "kubeconfig", kubeconfigPath, "endpoints", endpoints, "interval", interval.String())
+	telemetry.NewProber(log, writer, interval, kubeconfigPath, endpoints).Run(signals.SetupSignalHandler())
+	log.Info("telemetry measurement stopped")
+}
@@ -76,9 +76,10 @@ func (o *options) run(ctx context.Context) {
 		o.log.Info("Setup webhooks")
 		// TODO use https://github.com/kubernetes-sigs/controller-runtime/pull/2998 when it becomes available in the controller-runtime
 		if err := builder.WebhookManagedBy(mgr, &v1beta1.Testrun{}).
-			WithValidator(&webhooks.TestRunCustomValidator{Log: logger.Log.WithName("validator")}).
+			WithValidator(&webhooks.TestRunCustomValidator{Log: logger.Log.WithName("validator"), Reader: mgr.GetAPIReader()}).
+			WithDefaulter(&webhooks.TestRunCustomDefaulter{Log: logger.Log.WithName("defaulter")}).
 			Complete(); err != nil {
-			o.log.Error(err, "unable to create webhook to validate TestRuns")
+			o.log.Error(err, "unable to create webhooks for TestRuns")
 			os.Exit(1)
 		}
 	}
@@ -6,14 +6,19 @@ package alert
 
 import (
 	"errors"
+	"fmt"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/gardener/test-infra/pkg/alert"
 	"github.com/gardener/test-infra/pkg/apis/config"
 	"github.com/gardener/test-infra/pkg/logger"
+	"github.com/gardener/test-infra/pkg/testmachinery"
 	"github.com/gardener/test-infra/pkg/util/elasticsearch"
+	kutil "github.com/gardener/test-infra/pkg/util/kubernetes"
 	"github.com/gardener/test-infra/pkg/util/slack"
 )
 
@@ -28,10 +33,37 @@ var (
 	minSuccessRate             int
 	testsSkip                  []string
 	testsFocus                 []string
+	routingConfigPath          string
+	dryRun                     bool
+	escalationDays             int
+	escalationChannel          string
+	outputs                    []string
+	alertmanagerURL            string
+	alertmanagerAlertTTL       time.Duration
+	webhookURL                 string
+	webhookHeaders             map[string]string
+	emailConfig                alert.EmailConfig
+	flakinessConfig            alert.FlakinessConfig
+	source                     string
+	sourceDir                  string
+	tmKubeconfigPath           string
+	namespace                  string
+)
+
+const (
+	outputSlack        = "slack"
+	outputAlertmanager = "alertmanager"
+	outputWebhook      = "webhook"
+	outputEmail        = "email"
+
+	sourceElasticsearch = "elasticsearch"
+	sourceDirectory     = "dir"
+	sourceTestruns      = "testruns"
 )
 
 // AddCommand adds alert to a command.
 func AddCommand(cmd *cobra.Command) {
+	alertCmd.AddCommand(summaryCmd)
 	cmd.AddCommand(alertCmd)
 }
 
@@ -47,19 +79,31 @@ var alertCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
-		slackClient, err := slack.New(logger.Log, slackToken)
-		if err != nil {
-			logger.Log.Error(err, "Cannot create slack client")
-			os.Exit(1)
+		var routing *alert.RoutingConfig
+		if routingConfigPath != "" {
+			var err error
+			routing, err = alert.ReadRoutingConfig(routingConfigPath)
+			if err != nil {
+				logger.Log.Error(err, "Cannot read routing config")
+				os.Exit(1)
+			}
+			if routing.DefaultChannel == "" {
+				routing.DefaultChannel = slackChannel
+			}
 		}
 
-		esClient, err := elasticsearch.NewClient(config.ElasticSearch{
-			Endpoint: elasticsearchEndpoint,
-			Username: elasticsearchUser,
-			Password: elasticsearchPass,
-		})
+		var esClient elasticsearch.Client
+		if elasticsearchEndpoint != "" {
+			var err error
+			esClient, err = newElasticsearchClient()
+			if err != nil {
+				logger.Log.Error(err, "Cannot create elasticsearch client")
+				os.Exit(1)
+			}
+		}
+		dataSource, err := newDataSource(esClient)
 		if err != nil {
-			logger.Log.Error(err, "Cannot create elasticsearch client")
+			logger.Log.Error(err, "Cannot create alert data source")
 			os.Exit(1)
 		}
 
@@ -70,19 +114,57 @@ var alertCmd = &cobra.Command{
 			SuccessRateThresholdPercent: minSuccessRate,
 			TestsSkip:                   testsSkip,
 			TestsFocus:                  testsFocus,
+			DryRun:                      dryRun,
+			EscalationDays:              escalationDays,
+			Flakiness:                   flakinessConfig,
+			DataSource:                  dataSource,
 		}
 		alertClient := alert.New(logger.Log.WithName("alert"), alertConfig)
-		newFailedTests, recoveredTests, err := alertClient.FindFailedAndRecoveredTests()
+		notification, err := alertClient.FindAlerts()
 		if err != nil {
-			logger.Log.Error(err, "failed to find test items for alert and recover messages. Cannot sent slack message.")
+			logger.Log.Error(err, "failed to find test items for alert and recover messages. Cannot sent alerts.")
 			os.Exit(1)
 		}
-		if err := alertClient.PostAlertMessageToSlack(slackClient, slackChannel, newFailedTests); err != nil {
-			logger.Log.Error(err, "failed to post an alert message to slack")
+		notification.Escalated, err = alertClient.FindAlertsToEscalate(time.Now())
+		if err != nil {
+			logger.Log.Error(err, "failed to find alerts to escalate")
 			os.Exit(1)
 		}
-		if err := alertClient.PostRecoverMessageToSlack(slackClient, slackChannel, recoveredTests); err != nil {
-			logger.Log.Error(err, "failed to post a recover message to slack")
+
+		if dryRun {
+			if routing == nil {
+				routing = &alert.RoutingConfig{DefaultChannel: slackChannel}
+			}
+			fmt.Println("Alerts:")
+			fmt.Println(alert.RenderRoutingDecisions(routing.Route(notification.New)))
+			fmt.Println("Recovered:")
+			fmt.Println(alert.RenderRoutingDecisions(routing.Route(notification.Recovered)))
+			fmt.Println("Escalations:")
+			fmt.Println(alert.RenderRoutingDecisions((&alert.RoutingConfig{DefaultChannel: escalationChannel}).Route(notification.Escalated)))
+			logger.Log.Info("finished alerting dry run")
+			os.Exit(0)
+		}
+
+		alertOutputs := make([]alert.Output, 0, len(outputs))
+		for _, output := range outputs {
+			switch output {
+			case outputSlack:
+				slackClient, err := slack.New(logger.Log, slackToken)
+				if err != nil {
+					logger.Log.Error(err, "Cannot create slack client")
+					os.Exit(1)
+				}
+				alertOutputs = append(alertOutputs, alert.NewSlackOutput(alertClient, slackClient, slackChannel, escalationChannel, routing))
+			case outputAlertmanager:
+				alertOutputs = append(alertOutputs, alert.NewAlertmanagerOutput(alertClient, alertmanagerURL, alertmanagerAlertTTL))
+			case outputWebhook:
+				alertOutputs = append(alertOutputs, alert.NewWebhookOutput(alertClient, webhookURL, webhookHeaders))
+			case outputEmail:
+				alertOutputs = append(alertOutputs, alert.NewEmailOutput(alertClient, emailConfig))
+			}
+		}
+		if err := alertClient.Notify(alertOutputs, notification); err != nil {
+			logger.Log.Error(err, "failed to send alerts")
 			os.Exit(1)
 		}
 
@@ -91,7 +173,147 @@ var alertCmd = &cobra.Command{
 	},
 }
 
+var summaryCmd = &cobra.Command{
+	Use:   "summary",
+	Short: "Posts a summary of all open alerts and their lifecycle state to slack.",
+	Run: func(cmd *cobra.Command, args []string) {
+		logger.Log.Info("Start testmachinery alert summary")
+		if err := validateElasticsearch(); err != nil {
+			logger.Log.Error(err, "alert arguments validation failed")
+			os.Exit(1)
+		}
+		if err := validateSlack(); err != nil {
+			logger.Log.Error(err, "alert arguments validation failed")
+			os.Exit(1)
+		}
+		esClient, err := newElasticsearchClient()
+		if err != nil {
+			logger.Log.Error(err, "Cannot create elasticsearch client")
+			os.Exit(1)
+		}
+		slackClient, err := slack.New(logger.Log, slackToken)
+		if err != nil {
+			logger.Log.Error(err, "Cannot create slack client")
+			os.Exit(1)
+		}
+		alertClient := alert.New(logger.Log.WithName("alert"), alert.Config{ESClient: esClient})
+		if err := alertClient.PostSummaryToSlack(slackClient, slackChannel); err != nil {
+			logger.Log.Error(err, "failed to post the alert summary to slack")
+			os.Exit(1)
+		}
+		logger.Log.Info("finished alert summary")
+	},
+}
+
+func newElasticsearchClient() (elasticsearch.Client, error) {
+	return elasticsearch.NewClient(config.ElasticSearch{
+		Endpoint: elasticsearchEndpoint,
+		Username: elasticsearchUser,
+		Password: elasticsearchPass,
+	})
+}
+
+func newDataSource(esClient elasticsearch.Client) (alert.DataSource, error) {
+	switch source {
+	case sourceDirectory:
+		return alert.NewDirDataSource(sourceDir), nil
+	case sourceTestruns:
+		tmClient, err := kutil.NewClientFromFile(tmKubeconfigPath, client.Options{
+			Scheme: testmachinery.TestMachineryScheme,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("cannot build kubernetes client from %s: %w", tmKubeconfigPath, err)
+		}
+		return alert.NewTestrunDataSource(tmClient, namespace), nil
+	default:
+		return alert.NewElasticsearchDataSource(esClient), nil
+	}
+}
+
 func validate() error {
+	switch source {
+	case sourceElasticsearch:
+		if err := validateElasticsearch(); err != nil {
+			return err
+		}
+	case sourceDirectory:
+		if sourceDir == "" {
+			return errors.New("source-dir argument is required for the dir source")
+		}
+	case sourceTestruns:
+		if tmKubeconfigPath == "" {
+			return errors.New("tm-kubeconfig-path argument is required for the testruns source")
+		}
+	default:
+		return fmt.Errorf("unknown source %q", source)
+	}
+	if elasticsearchEndpoint != "" {
+		if err := validateElasticsearch(); err != nil {
+			return err
+		}
+	}
+	if len(outputs) == 0 {
+		return errors.New("at least one output is required")
+	}
+	for _, output := range outputs {
+		switch output {
+		case outputSlack:
+			if err := validateSlack(); err != nil {
+				return err
+			}
+		case outputAlertmanager:
+			if alertmanagerURL == "" {
+				return errors.New("alertmanager-url argument is required for the alertmanager output")
+			}
+			if alertmanagerAlertTTL <= 0 {
+				return errors.New("alertmanager-alert-ttl <= 0 is not allowed")
+			}
+		case outputWebhook:
+			if webhookURL == "" {
+				return errors.New("webhook-url argument is required for the webhook output")
+			}
+		case outputEmail:
+			if emailConfig.Host == "" || emailConfig.From == "" {
+				return errors.New("smtp-host and smtp-from arguments are required for the email output")
+			}
+			if len(emailConfig.To) == 0 && !emailConfig.NotifyOwners {
+				return errors.New("smtp-to or smtp-notify-owners arguments are required for the email output")
+			}
+		default:
+			return fmt.Errorf("unknown output %q", output)
+		}
+	}
+	if continuousFailureThreshold == 0 {
+		return errors.New("min-continuous-failures=0 is not allowed")
+	}
+	if evalTimeDays <= 0 {
+		return errors.New("eval-time-days <= 0 is not allowed")
+	}
+	if minSuccessRate < 0 || minSuccessRate > 100 {
+		return errors.New("min-success-rate must have a value between 0 and 100")
+	}
+	if flakinessConfig.FlipRateThreshold < 0 || flakinessConfig.FlipRateThreshold > 1 {
+		return errors.New("flip-rate-threshold must have a value between 0 and 1")
+	}
+	if flakinessConfig.BaselineDays < 0 {
+		return errors.New("baseline-days < 0 is not allowed")
+	}
+	if flakinessConfig.RegressionSignificance <= 0 || flakinessConfig.RegressionSignificance >= 1 {
+		return errors.New("regression-significance must have a value between 0 and 1")
+	}
+	if flakinessConfig.DurationRegressionPercent < 0 {
+		return errors.New("duration-regression-percent < 0 is not allowed")
+	}
+	if escalationDays < 0 {
+		return errors.New("escalation-days < 0 is not allowed")
+	}
+	if escalationChannel == "" {
+		escalationChannel = slackChannel
+	}
+	return nil
+}
+
+func validateElasticsearch() error {
 	if elasticsearchEndpoint == "" {
 		return errors.New("elasticsearch-endpoint argument is required but empty")
 	}
@@ -101,34 +323,54 @@ func validate() error {
 	if elasticsearchPass == "" {
 		return errors.New("elasticsearch-pass argument is required but empty")
 	}
-	if slackToken == "" {
+	return nil
+}
+
+func validateSlack() error {
+	if slackToken == "" && !dryRun {
 		return errors.New("slack-token argument is required but empty")
 	}
 	if slackChannel == "" {
 		return errors.New("slack-channel argument is required but empty")
 	}
-	if continuousFailureThreshold == 0 {
-		return errors.New("min-continuous-failures=0 is not allowed")
-	}
-	if evalTimeDays <= 0 {
-		return errors.New("eval-time-days <= 0 is not allowed")
-	}
-	if minSuccessRate < 0 || minSuccessRate > 100 {
-		return errors.New("min-success-rate must have a value between 0 and 100")
-	}
 	return nil
 }
 
 func init() {
 	// parameter flags
-	alertCmd.Flags().StringVar(&elasticsearchEndpoint, "elasticsearch-endpoint", "", "Elasticsearch endpoint URL")
-	alertCmd.Flags().StringVar(&elasticsearchUser, "elasticsearch-user", "", "Elasticsearch username")
-	alertCmd.Flags().StringVar(&elasticsearchPass, "elasticsearch-pass", "", "Elasticsearch password")
-	alertCmd.Flags().StringVar(&slackToken, "slack-token", "", "Client token to authenticate")
-	alertCmd.Flags().StringVar(&slackChannel, "slack-channel", "", "Client channel id to send the message to.")
+	alertCmd.PersistentFlags().StringVar(&elasticsearchEndpoint, "elasticsearch-endpoint", "", "Elasticsearch endpoint URL")
+	alertCmd.PersistentFlags().StringVar(&elasticsearchUser, "elasticsearch-user", "", "Elasticsearch username")
+	alertCmd.PersistentFlags().StringVar(&elasticsearchPass, "elasticsearch-pass", "", "Elasticsearch password")
+	alertCmd.PersistentFlags().StringVar(&slackToken, "slack-token", "", "Client token to authenticate")
+	alertCmd.PersistentFlags().StringVar(&slackChannel, "slack-channel", "", "Client channel id to send the message to.")
+	alertCmd.Flags().StringVar(&source, "source", sourceElasticsearch, "source of the test runs: elasticsearch, dir or testruns. Alerts are only filed if an elasticsearch is configured")
+	alertCmd.Flags().StringVar(&sourceDir, "source-dir", "", "directory with the bulk files written by the collector for the dir source")
+	alertCmd.Flags().StringVar(&tmKubeconfigPath, "tm-kubeconfig-path", os.Getenv("KUBECONFIG"), "Path to the testmachinery cluster kubeconfig for the testruns source")
+	alertCmd.Flags().StringVarP(&namespace, "namespace", "n", "default", "Namespace of the testruns for the testruns source")
 	alertCmd.Flags().IntVar(&continuousFailureThreshold, "min-continuous-failures", 3, "if test fails >=n times send alert")
 	alertCmd.Flags().IntVar(&evalTimeDays, "eval-time-days", 3, "time period to evaluate")
 	alertCmd.Flags().IntVar(&minSuccessRate, "min-success-rate", 50, "if test success rate % falls below threshold, then post an alert")
 	alertCmd.Flags().StringArrayVar(&testsSkip, "skip", make([]string, 0), "regexp to filter context test names e.g. 'e2e-untracked.*aws'")
+	alertCmd.Flags().StringVar(&routingConfigPath, "routing-config", "", "Path to a routing config that routes alerts to the test owners, recipients and teams instead of only the slack channel")
+	alertCmd.Flags().Float64Var(&flakinessConfig.FlipRateThreshold, "flip-rate-threshold", 0, "alert tests whose result flips between success and failure in at least the given ratio (0-1) of consecutive runs, 0 disables the detector")
+	alertCmd.Flags().IntVar(&flakinessConfig.BaselineDays, "baseline-days", 0, "time period before the evaluation period that is used as baseline to detect success rate and duration regressions, 0 disables the detectors")
+	alertCmd.Flags().Float64Var(&flakinessConfig.RegressionSignificance, "regression-significance", 0.05, "p-value below which a lower success rate than in the baseline is alerted as regression")
+	alertCmd.Flags().IntVar(&flakinessConfig.DurationRegressionPercent, "duration-regression-percent", 0, "alert tests whose p50 or p95 duration increased by more than n percent compared to the baseline, 0 disables the detector")
+	alertCmd.Flags().IntVar(&flakinessConfig.MinRuns, "min-runs", 5, "minimal number of runs in a time period to evaluate the flakiness detectors")
+	alertCmd.Flags().IntVar(&escalationDays, "escalation-days", 0, "escalate firing alerts that are not acknowledged after n days, 0 disables the escalation")
+	alertCmd.Flags().StringVar(&escalationChannel, "escalation-channel", "", "Client channel id to send escalations to. Defaults to the slack channel")
+	alertCmd.Flags().StringSliceVar(&outputs, "output", []string{outputSlack}, "outputs to send the alerts to: slack, alertmanager, webhook or email")
+	alertCmd.Flags().StringVar(&alertmanagerURL, "alertmanager-url", "", "URL of a Prometheus Alertmanager compatible api")
+	alertCmd.Flags().DurationVar(&alertmanagerAlertTTL, "alertmanager-alert-ttl", 24*time.Hour, "time after which firing alerts end in the alertmanager if they are not sent again. Has to be longer than the interval of the alert runs")
+	alertCmd.Flags().StringVar(&webhookURL, "webhook-url", "", "URL of a webhook the alerts are posted to as json")
+	alertCmd.Flags().StringToStringVar(&webhookHeaders, "webhook-header", nil, "additional headers of the webhook requests, e.g. Authorization=\"Bearer token\"")
+	alertCmd.Flags().StringVar(&emailConfig.Host, "smtp-host", "", "smtp server host")
+	alertCmd.Flags().IntVar(&emailConfig.Port, "smtp-port", 587, "smtp server port")
+	alertCmd.Flags().StringVar(&emailConfig.Username, "smtp-user", "", "smtp username")
+	alertCmd.Flags().StringVar(&emailConfig.Password, "smtp-pass", "", "smtp password")
+	alertCmd.Flags().StringVar(&emailConfig.From, "smtp-from", "", "sender address of the alert emails")
+	alertCmd.Flags().StringArrayVar(&emailConfig.To, "smtp-to", make([]string, 0), "recipient address of the alert emails")
+	alertCmd.Flags().BoolVar(&emailConfig.NotifyOwners, "smtp-notify-owners", false, "additionally send the owners and recipients of the failing tests an email with their tests")
+	alertCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the routing decisions instead of posting alerts and updating the alert index")
 	alertCmd.Flags().StringArrayVar(&testsFocus, "focus", make([]string, 0), "regexp to keep context test names e.g. 'e2e-untracked.*aws. Is executed after skip filter.'")
 }
@@ -14,6 +14,8 @@ import (
 	"github.com/gardener/test-infra/cmd/testrunner/cmd/alert"
 	collectcmd "github.com/gardener/test-infra/cmd/testrunner/cmd/collect"
 	"github.com/gardener/test-infra/cmd/testrunner/cmd/docs"
+	flavorscmd "github.com/gardener/test-infra/cmd/testrunner/cmd/flavors"
+	mergeresultscmd "github.com/gardener/test-infra/cmd/testrunner/cmd/merge_results"
 	notifycmd "github.com/gardener/test-infra/cmd/testrunner/cmd/notify"
 	"github.com/gardener/test-infra/cmd/testrunner/cmd/run_template"
 	"github.com/gardener/test-infra/cmd/testrunner/cmd/run_testrun"
@@ -51,6 +53,8 @@ func init() {
 	addCommand(run_template.NewRunTemplateCommand)
 	addCommand(run_testrun.NewRunTestrunCommand)
 	collectcmd.AddCommand(rootCmd)
+	mergeresultscmd.AddCommand(rootCmd)
+	flavorscmd.AddCommand(rootCmd)
 	notifycmd.AddCommand(rootCmd)
 	docs.AddCommand(rootCmd)
 	versioncmd.AddCommand(rootCmd)
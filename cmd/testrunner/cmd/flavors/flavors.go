@@ -0,0 +1,189 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package flavorscmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/olekukonko/tablewriter/renderer"
+	"github.com/olekukonko/tablewriter/tw"
+	"github.com/spf13/cobra"
+
+	"github.com/gardener/test-infra/cmd/testrunner/cmd/run_template"
+	"github.com/gardener/test-infra/pkg/common"
+	"github.com/gardener/test-infra/pkg/logger"
+	"github.com/gardener/test-infra/pkg/shootflavors"
+	"github.com/gardener/test-infra/pkg/testrunner"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+var (
+	flavorConfigPath        string
+	cloudProfileSearchPath  string
+	filterPatchVersions     bool
+	output                  string
+	expirationWarningPeriod time.Duration
+	failOnWarning           bool
+)
+
+// AddCommand adds flavors to a command.
+func AddCommand(cmd *cobra.Command) {
+	cmd.AddCommand(flavorsCmd)
+}
+
+var flavorsCmd = &cobra.Command{
+	Use:   "flavors",
+	Short: "Validates a shoot flavor config and prints the resulting shoots.",
+	Long: `Validates a shoot flavor config using CloudProfiles from disk and prints all shoots that are described by the flavors
+with their resolved kubernetes and machine image versions.
+Warnings are printed for expiring or deprecated versions and duplicated shoots.
+If the config defines a selection, the shoots that would be tested today by "run-template" are printed separately.`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if output != outputTable && output != outputJSON {
+			return fmt.Errorf("unknown output format %q, must be one of %s or %s", output, outputTable, outputJSON)
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		cloudProfiles, err := testrunner.GetCloudProfilesFromDisk(filepath.Clean(cloudProfileSearchPath))
+		if err != nil {
+			logger.Log.Error(err, "unable to find CloudProfiles", "path", cloudProfileSearchPath)
+			os.Exit(1)
+		}
+		if len(cloudProfiles) == 0 {
+			logger.Log.Error(nil, "no CloudProfiles found", "path", cloudProfileSearchPath)
+			os.Exit(1)
+		}
+
+		flavors, selection, err := run_template.ReadShootFlavors(flavorConfigPath, nil, cloudProfiles, "", filterPatchVersions)
+		if err != nil {
+			logger.Log.Error(err, "invalid shoot flavor config", "file", flavorConfigPath)
+			os.Exit(1)
+		}
+
+		now := time.Now()
+		previews := flavors.Preview(now, expirationWarningPeriod)
+		selectionPreview, err := flavors.PreviewSelection(selection, now)
+		if err != nil {
+			logger.Log.Error(err, "invalid shoot flavor selection", "file", flavorConfigPath)
+			os.Exit(1)
+		}
+		switch output {
+		case outputJSON:
+			data, err := json.MarshalIndent(flavorsPreview{Shoots: previews, Selection: selectionPreview}, "", "  ")
+			if err != nil {
+				logger.Log.Error(err, "unable to marshal shoots")
+				os.Exit(1)
+			}
+			fmt.Println(string(data))
+		default:
+			renderPreviewTable(os.Stdout, previews)
+			if selectionPreview != nil {
+				renderSelection(os.Stdout, selectionPreview, len(previews))
+			}
+		}
+
+		warnings := 0
+		for _, preview := range previews {
+			warnings += len(preview.Warnings)
+		}
+		logger.Log.Info(fmt.Sprintf("%d shoots with %d warnings", len(previews), warnings))
+		if failOnWarning && warnings != 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	flavorsCmd.Flags().StringVar(&flavorConfigPath, "flavor-config", "", "Path to shoot test configuration.")
+	if err := flavorsCmd.MarkFlagRequired("flavor-config"); err != nil {
+		logger.Log.Error(err, "mark flag required", "flag", "flavor-config")
+	}
+	flavorsCmd.Flags().StringVar(&cloudProfileSearchPath, "cloud-profile-search-path", "", "Start searching for CloudProfiles here.")
+	if err := flavorsCmd.MarkFlagRequired("cloud-profile-search-path"); err != nil {
+		logger.Log.Error(err, "mark flag required", "flag", "cloud-profile-search-path")
+	}
+	flavorsCmd.Flags().BoolVar(&filterPatchVersions, "filter-patch-versions", false, "Filters patch versions so that only the latest patch versions per minor versions is used.")
+	flavorsCmd.Flags().StringVarP(&output, "output", "o", outputTable, "Output format of the shoots. One of table or json.")
+	flavorsCmd.Flags().DurationVar(&expirationWarningPeriod, "expiration-warning-period", 30*24*time.Hour, "Versions that expire within this period are reported as warning.")
+	flavorsCmd.Flags().BoolVar(&failOnWarning, "fail-on-warning", false, "Exits with 1 if at least one warning is reported.")
+}
+
+// flavorsPreview is the json output of all shoots and the shoots selected from them.
+type flavorsPreview struct {
+	Shoots    []shootflavors.ShootPreview    `json:"shoots"`
+	Selection *shootflavors.SelectionPreview `json:"selection,omitempty"`
+}
+
+func renderSelection(writer io.Writer, selection *shootflavors.SelectionPreview, total int) {
+	details := fmt.Sprintf("seed %d", selection.Seed)
+	if selection.Strategy == common.FlavorSelectionStrategyRotating {
+		details = fmt.Sprintf("%s, day %d", details, selection.Day)
+	}
+	shoots := make([]string, 0, len(selection.Shoots))
+	for _, i := range selection.Shoots {
+		shoots = append(shoots, strconv.Itoa(i))
+	}
+	fmt.Fprintf(writer, "Selected %d of %d shoots with the %s strategy (%s): %s\n",
+		len(selection.Shoots), total, selection.Strategy, details, strings.Join(shoots, ", "))
+}
+
+func renderPreviewTable(writer io.Writer, previews []shootflavors.ShootPreview) {
+	table := tablewriter.NewTable(writer,
+		tablewriter.WithHeader([]string{"#", "Description", "Provider", "Cloudprofile", "Region", "Kubernetes", "Upgrade", "Workers", "Warnings"}),
+		tablewriter.WithHeaderAutoWrap(tw.WrapNone),
+		tablewriter.WithRowAutoWrap(tw.WrapNone),
+		tablewriter.WithRenderer(renderer.NewBlueprint()),
+		tablewriter.WithRendition(tw.Rendition{
+			Symbols: tw.NewSymbols(tw.StyleASCII),
+			Borders: tw.Border{Top: tw.On, Bottom: tw.On, Left: tw.On, Right: tw.On},
+			Settings: tw.Settings{
+				Separators: tw.Separators{
+					BetweenRows: tw.On,
+				},
+			},
+		}),
+	)
+
+	for i, preview := range previews {
+		workers := make([]string, 0, len(preview.Workers))
+		for _, w := range preview.Workers {
+			worker := fmt.Sprintf("%s: %s %s/%s (%s)", w.Name, w.MachineType, w.MachineImage, w.MachineImageVersion, w.Architecture)
+			if w.UpdateStrategy != "" {
+				worker = fmt.Sprintf("%s [%s]", worker, w.UpdateStrategy)
+			}
+			workers = append(workers, worker)
+		}
+		row := []string{
+			strconv.Itoa(i),
+			preview.Description,
+			preview.Provider,
+			preview.Cloudprofile,
+			preview.Region,
+			preview.KubernetesVersion,
+			preview.Upgrade,
+			strings.Join(workers, "\n"),
+			strings.Join(preview.Warnings, "\n"),
+		}
+		if err := table.Append(row); err != nil {
+			fmt.Fprintf(os.Stderr, "Could not append row to flavors table: %v", err)
+		}
+	}
+	if err := table.Render(); err != nil {
+		fmt.Fprintf(os.Stderr, "Could not render flavors table: %v", err)
+	}
+}
@@ -0,0 +1,79 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package mergeresultscmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gardener/test-infra/pkg/logger"
+	"github.com/gardener/test-infra/pkg/testrunner/result"
+	"github.com/gardener/test-infra/pkg/util"
+)
+
+var (
+	resultsFiles    []string
+	outputFile      string
+	summaryFilePath string
+)
+
+var resultConfig = result.Config{}
+
+// AddCommand adds merge-results to a command.
+func AddCommand(cmd *cobra.Command) {
+	cmd.AddCommand(mergeResultsCmd)
+}
+
+var mergeResultsCmd = &cobra.Command{
+	Use:   "merge-results",
+	Short: "Merges the result files of multiple sharded testrunner invocations into one summary.",
+	Long: `Merges the overview result files that are written by sharded "run-template" invocations
+(see --shard-index/--shard-count and --output-dir-path) into one overview file, prints the summary
+and optionally posts one summary message to slack.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		logger.InitializeSummarySetup(summaryFilePath)
+
+		overview, err := result.MergeAssetOverviewFiles(logger.Log.WithName("merge"), resultsFiles, outputFile)
+		if err != nil {
+			logger.Log.Error(err, "unable to merge results files")
+			os.Exit(1)
+		}
+
+		table, err := util.RenderTableForSlack(logger.Log, result.ParseOverviewToTableItems(overview))
+		if err != nil {
+			logger.Log.Error(err, "unable to render results table")
+			os.Exit(1)
+		}
+		fmt.Println(table)
+		if err := logger.PostToSummaryFile(fmt.Sprintf("```\n%s\n```", table), true); err != nil {
+			logger.Log.Error(err, "unable to post summary to github step summary")
+		}
+
+		if !resultConfig.PostSummaryInSlack {
+			return
+		}
+		if err := result.PostOverviewSummaryInSlack(resultConfig, logger.Log, overview); err != nil {
+			logger.Log.Error(err, "error while posting notification on slack")
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	mergeResultsCmd.Flags().StringArrayVar(&resultsFiles, "results-file", []string{}, "Path or glob pattern of the overview result files that should be merged. Can be specified multiple times.")
+	if err := mergeResultsCmd.MarkFlagRequired("results-file"); err != nil {
+		logger.Log.Error(err, "mark flag required", "flag", "results-file")
+	}
+	mergeResultsCmd.Flags().StringVar(&outputFile, "output-file", "", "Path where the merged overview result file should be written to.")
+	mergeResultsCmd.Flags().StringVar(&summaryFilePath, "summary-file-path", "", "Path to a summary file. If set, the merged summary will be appended to this file.")
+
+	// slack notification
+	mergeResultsCmd.Flags().StringVar(&resultConfig.SlackToken, "slack-token", "", "Client token to authenticate")
+	mergeResultsCmd.Flags().StringVar(&resultConfig.SlackChannel, "slack-channel", "", "Client channel id to send the message to.")
+	mergeResultsCmd.Flags().StringVar(&resultConfig.CICDJobURL, "cicd-job-url", "", "CI/CD Job URL")
+	mergeResultsCmd.Flags().BoolVar(&resultConfig.PostSummaryInSlack, "post-summary-in-slack", false, "Post the merged summary in slack.")
+}
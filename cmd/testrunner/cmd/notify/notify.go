@@ -68,7 +68,7 @@ var notifyCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
-		tableItems := parseOverviewToTableItems(overview)
+		tableItems := result.ParseOverviewToTableItems(overview)
 		table, err := util.RenderTableForSlack(logger.Log, tableItems)
 		if err != nil {
 			return err
@@ -132,24 +132,3 @@ func legend() string {
 %s: Tests succeeded | %s: Tests failed | %s: Tests not applicable
 `, SucessSymbols[true], SucessSymbols[false], NA)
 }
-
-func parseOverviewToTableItems(overview result.AssetOverview) (tableItems util.TableItems) {
-	for _, overviewItem := range overview.AssetOverviewItems {
-		meta := overviewItem.Dimension
-		if meta.Cloudprovider == "" {
-			// skip gardener tests
-			continue
-		} else {
-			status := util.StatusSymbolFailure
-			if overviewItem.Successful {
-				status = util.StatusSymbolSuccess
-			}
-			item := &util.TableItem{
-				Meta:         util.ItemMeta{CloudProvider: meta.Cloudprovider, TestrunID: overviewItem.Name, OperatingSystem: meta.OperatingSystem, KubernetesVersion: meta.KubernetesVersion, FlavorDescription: meta.Description},
-				StatusSymbol: status,
-			}
-			tableItems = append(tableItems, item)
-		}
-	}
-	return tableItems
-}
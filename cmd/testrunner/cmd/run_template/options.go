@@ -44,6 +44,8 @@ type options struct {
 	testrunnerKubeconfigPath string
 	cloudProfileSearchPath   string
 	filterPatchVersions      bool
+	shardIndex               int
+	shardCount               int
 	failOnError              bool
 	timeout                  int64
 	summaryFilePath          string
@@ -103,23 +105,44 @@ func (o *options) Complete() error {
 		logger.Log.Error(err, "unable to parse shoot flavors from test configuration")
 		os.Exit(1)
 	}
-	o.shootFlavors = flavors.GetShoots()
+	o.shootFlavors, err = flavors.GetShootsForShard(o.shardIndex, o.shardCount)
+	if err != nil {
+		return err
+	}
+	logger.Log.Info(fmt.Sprintf("selected %d of %d shoot flavors for shard %d/%d", len(o.shootFlavors), len(flavors.GetShoots()), o.shardIndex, o.shardCount))
 	return nil
 }
 
 func GetShootFlavors(cfgPath string, k8sClient client.Client, cloudProfiles map[string]gardencorev1beta1.CloudProfile, shootPrefix string, filterPatchVersions bool) (*shootflavors.ExtendedFlavors, error) {
+	extendedFlavors, selection, err := ReadShootFlavors(cfgPath, k8sClient, cloudProfiles, shootPrefix, filterPatchVersions)
+	if err != nil {
+		return nil, err
+	}
+	if err := extendedFlavors.Select(selection, time.Now()); err != nil {
+		return nil, pkgerrors.Wrap(err, "unable to select shoot flavors")
+	}
+	return extendedFlavors, nil
+}
+
+// ReadShootFlavors parses the flavors config and returns all shoots of the flavors together with the configured selection
+// without applying the selection.
+func ReadShootFlavors(cfgPath string, k8sClient client.Client, cloudProfiles map[string]gardencorev1beta1.CloudProfile, shootPrefix string, filterPatchVersions bool) (*shootflavors.ExtendedFlavors, *common.FlavorSelection, error) {
 	// read and parse test shoot configuration
 	dat, err := os.ReadFile(filepath.Clean(cfgPath))
 	if err != nil {
-		return nil, pkgerrors.Wrapf(err, "unable to read test shoot configuration file from %s", cfgPath)
+		return nil, nil, pkgerrors.Wrapf(err, "unable to read test shoot configuration file from %s", cfgPath)
 	}
 
 	flavors := common.ExtendedShootFlavors{}
 	if err := yaml.Unmarshal(dat, &flavors); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return shootflavors.NewExtended(k8sClient, cloudProfiles, flavors.Flavors, shootPrefix, filterPatchVersions)
+	extendedFlavors, err := shootflavors.NewExtended(k8sClient, cloudProfiles, flavors.Flavors, shootPrefix, filterPatchVersions)
+	if err != nil {
+		return nil, nil, err
+	}
+	return extendedFlavors, flavors.Selection, nil
 }
 
 // Validate validates the options
@@ -147,6 +170,9 @@ func (o *options) Validate() error {
 			return errors.New("shoot-name is required")
 		}
 	}
+	if err := shootflavors.ValidateShard(o.shardIndex, o.shardCount); err != nil {
+		return fmt.Errorf("invalid shard-index/shard-count: %w", err)
+	}
 	return nil
 }
 
@@ -164,6 +190,7 @@ func (o *options) AddFlags(fs *pflag.FlagSet) error {
 	fs.BoolVar(&o.testrunnerConfig.Serial, "serial", false, "executes all testruns of a bucket only after the previous bucket has finished")
 	fs.IntVar(&o.testrunnerConfig.BackoffBucket, "backoff-bucket", 0, "Number of parallel created testruns per backoff period")
 	fs.DurationVar(&o.testrunnerConfig.BackoffPeriod, "backoff-period", 0, "Time to wait between the creation of testrun buckets")
+	fs.StringVar((*string)(&o.watchOptions.InformerType), "watch-informer", string(watch.CachedInformerType), "type of the underlaying watch. One of cached, polling or watch. The watch informer only watches the testruns of the current execution group and resumes after disconnects.")
 	fs.DurationVar(o.watchOptions.PollInterval, "poll-interval", time.Minute, "poll interval of the underlaying watch")
 
 	fs.BoolVar(&o.testrunnerConfig.NoExecutionGroup, "no-execution-group", false, "do not inject a execution group id into testruns")
@@ -197,6 +224,8 @@ func (o *options) AddFlags(fs *pflag.FlagSet) error {
 
 	fs.StringVar(&o.shootPrefix, "shoot-name", "", "Shoot name which is used to run tests.")
 	fs.BoolVar(&o.filterPatchVersions, "filter-patch-versions", false, "Filters patch versions so that only the latest patch versions per minor versions is used.")
+	fs.IntVar(&o.shardIndex, "shard-index", 0, "Index of the shard of shoot flavors that should be tested by this invocation. Has to be lower than shard-count.")
+	fs.IntVar(&o.shardCount, "shard-count", 1, "Number of shards the shoot flavors are partitioned into. Every flavor is deterministically assigned to exactly one shard.")
 
 	fs.StringVar(&o.shootParameters.Landscape, "landscape", "", "Current gardener landscape.")
 	fs.StringVar(&o.shootParameters.ComponentDescriptorPath, "component-descriptor-path", "", "Path to the component descriptor (BOM) of the current landscape.")
@@ -77,6 +77,7 @@ func (o *options) run(ctx context.Context) error {
 
 	logger.Log.V(3).Info("starting watcher")
 
+	testrunner.ScopeWatchToSession(&o.testrunnerConfig, &o.watchOptions)
 	watcher, err := watch.NewFromFile(logger.Log.WithName("watch"), o.tmKubeconfigPath, &o.watchOptions)
 	if err != nil {
 		return errors.Wrap(err, "unable to start testrun watch controller")
@@ -102,6 +103,7 @@ func (o *options) run(ctx context.Context) error {
 	if err := testrunner.ExecuteTestruns(logger.Log.WithName("Execute"), &o.testrunnerConfig, runs, o.testrunNamePrefix, collector.RunExecCh); err != nil {
 		return errors.Wrap(err, "unable to run testruns")
 	}
+	testrunner.LogWatchStatistics(logger.Log.WithName("watch"), watcher)
 
 	failed, err := collector.Collect(ctx, logger.Log.WithName("Collect"), o.testrunnerConfig.Watch.Client(), o.testrunnerConfig.Namespace, runs)
 	if err != nil {
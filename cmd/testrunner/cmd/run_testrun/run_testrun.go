@@ -56,6 +56,7 @@ func (o *options) run(ctx context.Context) error {
 
 	logger.InitializeSummarySetup(o.summaryFilePath)
 
+	testrunner.ScopeWatchToSession(&o.testrunnerConfig, &o.watchOptions)
 	watcher, err := watch.NewFromFile(logger.Log, o.tmKubeconfigPath, &o.watchOptions)
 	if err != nil {
 		logger.Log.Error(err, "unable to start testrun watch controller")
@@ -85,7 +86,9 @@ func (o *options) run(ctx context.Context) error {
 		Metadata: &metadata.Metadata{},
 	}
 
+	run.SetRunID(o.testrunnerConfig.ExecutionGroupID)
 	run.Exec(logger.Log.WithName("execute"), &o.testrunnerConfig, o.testrunNamePrefix)
+	testrunner.LogWatchStatistics(logger.Log, watcher)
 	if run.Error != nil {
 		logger.Log.Error(run.Error, "testrunner execution disrupted")
 		os.Exit(1)
@@ -5,6 +5,7 @@
 package alert
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -45,51 +46,102 @@ type Config struct {
 	ESClient                    elasticsearch.Client
 	TestsSkip                   []string
 	TestsFocus                  []string
+	DryRun                      bool // DryRun does not modify the alert index
+	EscalationDays              int  // EscalationDays after which firing alerts that are not acknowledged are escalated, 0 disables escalation
+	Flakiness                   FlakinessConfig
+	DataSource                  DataSource // DataSource of the test runs, defaults to the ESClient. Alerts are not filed without ESClient
 }
 
 // New creates a new instance of alert
 func New(log logr.Logger, cfg Config) *Alert {
+	if cfg.DataSource == nil && cfg.ESClient != nil {
+		cfg.DataSource = NewElasticsearchDataSource(cfg.ESClient)
+	}
 	return &Alert{
 		log: log,
 		cfg: cfg,
 	}
 }
 
-// FindFailedAndRecoveredTests finds distinct, failed tests, that has not yet been posted on slack in recent n days
-func (alerter *Alert) FindFailedAndRecoveredTests() (map[string]TestDetails, map[string]TestDetails, error) {
+// FindAlerts finds the new failing tests that have no open alert, all failing tests whose alert is not snoozed
+// and the recovered tests that have an open alert
+func (alerter *Alert) FindAlerts() (Notification, error) {
 	testAggregationsRaw, err := alerter.retrieveTestAggregations()
 	if err != nil {
-		return nil, nil, err
+		return Notification{}, err
 	}
 	contextToTestDetailMap := alerter.extractTestDetailItems(testAggregationsRaw)
 	if err := alerter.removeExcludedTests(&contextToTestDetailMap); err != nil {
-		return nil, nil, err
+		return Notification{}, err
+	}
+	if err := alerter.addBaselines(contextToTestDetailMap); err != nil {
+		return Notification{}, err
 	}
+	alerter.evaluateTests(contextToTestDetailMap)
 	if err := alerter.deleteOutdatedAlertsFromDB(); err != nil {
-		return nil, nil, err
+		return Notification{}, err
 	}
 	alreadyFiledAlerts, err := alerter.getFiledAlerts()
 	if err != nil {
-		return nil, nil, err
+		return Notification{}, err
+	}
+	now := time.Now()
+	openFiledAlerts := openAlerts(alreadyFiledAlerts)
+	recoveredTests := alerter.extractRecoveredTests(contextToTestDetailMap, openFiledAlerts)
+	failedTests := alerter.removeSuccessfulTests(contextToTestDetailMap)
+
+	firingTests := make(map[string]TestDetails, len(failedTests))
+	newFailedTests := make(map[string]TestDetails, len(failedTests))
+	for key, test := range failedTests {
+		newFailedTests[key] = test
+		if filedAlert, ok := openFiledAlerts[key]; ok && filedAlert.CurrentState(now) == AlertStateSnoozed {
+			continue
+		}
+		firingTests[key] = test
 	}
-	recoveredTests := alerter.extractRecoveredTests(contextToTestDetailMap, alreadyFiledAlerts)
-	newFailedTests := alerter.removeSuccessfulTests(contextToTestDetailMap)
-	alerter.removeAlreadyFiledAlerts(newFailedTests, alreadyFiledAlerts)
-	return newFailedTests, recoveredTests, nil
+	alerter.removeAlreadyFiledAlerts(newFailedTests, openFiledAlerts, now)
+	return Notification{New: newFailedTests, Firing: firingTests, Recovered: recoveredTests}, nil
 }
 
-// retrieveTestAggregations retrieves test aggregations from elasticsearch
+// retrieveTestAggregations retrieves test aggregations from the data source
 func (alerter *Alert) retrieveTestAggregations() (TestContextAggregation, error) {
-	payloadFormated := alerter.printESAggregationPayload()
-	var testContextAggregation TestContextAggregation
-	if err := alerter.elasticRequest("/testmachinery-*/_search", http.MethodGet, payloadFormated, &testContextAggregation); err != nil {
-		return TestContextAggregation{}, errors.Wrap(err, "failed to retrieve testmachinery test aggregations from elasticsearch")
+	testContextAggregation, err := alerter.cfg.DataSource.Aggregate(context.Background(), AggregationRequest{
+		FromDays:    alerter.cfg.EvalTimeDays,
+		MinDocCount: alerter.cfg.ContinuousFailureThreshold,
+		TrendSize:   alerter.cfg.ContinuousFailureThreshold,
+	})
+	if err != nil {
+		return TestContextAggregation{}, errors.Wrap(err, "failed to retrieve testmachinery test aggregations")
 	}
 	alerter.log.V(3).Info(fmt.Sprintf("retrieved %d distinct test aggregations", len(testContextAggregation.Aggs.TestContext.TestDetailsRaw)))
 
 	return testContextAggregation, nil
 }
 
+// addBaselines adds the test runs of the baseline time range before the evaluation time range to the tests
+func (alerter *Alert) addBaselines(tests map[string]TestDetails) error {
+	if alerter.cfg.Flakiness.BaselineDays <= 0 || len(tests) == 0 {
+		return nil
+	}
+	baselineAggregation, err := alerter.cfg.DataSource.Aggregate(context.Background(), AggregationRequest{
+		FromDays:    alerter.cfg.EvalTimeDays + alerter.cfg.Flakiness.BaselineDays,
+		ToDays:      alerter.cfg.EvalTimeDays,
+		MinDocCount: 1,
+		TrendSize:   alerter.cfg.ContinuousFailureThreshold,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve testmachinery baseline aggregations")
+	}
+	for _, testDoc := range baselineAggregation.Aggs.TestContext.TestDetailsRaw {
+		if test, ok := tests[testDoc.Testcontext]; ok {
+			test.Baseline = runSamples(testDoc.History.Hits.Hits)
+			tests[testDoc.Testcontext] = test
+		}
+	}
+	alerter.log.V(3).Info(fmt.Sprintf("retrieved %d baseline test aggregations", len(baselineAggregation.Aggs.TestContext.TestDetailsRaw)))
+	return nil
+}
+
 // extractTestDetailItems parses raw elasticsearch test aggregations into test details
 func (alerter *Alert) extractTestDetailItems(testContextAggregation TestContextAggregation) map[string]TestDetails {
 	contextToTestDetailMap := make(map[string]TestDetails)
@@ -97,7 +149,7 @@ func (alerter *Alert) extractTestDetailItems(testContextAggregation TestContextA
 		testDocDetails := testDoc.Details.Hits.Docs[0].Source
 		testFailedContinuously := true
 		for _, trendItem := range testDoc.SuccessTrend.Hits.Hits {
-			if trendItem.Source.Pre.PhaseNum != 0 {
+			if trendItem.Source.Pre.PhaseNum != nil && *trendItem.Source.Pre.PhaseNum != 0 {
 				testFailedContinuously = false
 				break
 			}
@@ -111,10 +163,14 @@ func (alerter *Alert) extractTestDetailItems(testContextAggregation TestContextA
 			Cloudprovider:       testDocDetails.TM.Cloudprovider,
 			Landscape:           testDocDetails.TM.Landscape,
 			TestrunID:           testDocDetails.TM.Testrun.ID,
+			Labels:              testDocDetails.Labels,
+			Owner:               testDocDetails.Owner,
+			Recipients:          testDocDetails.Recipients,
 			SuccessRate:         testDoc.SuccessRate.Value,
 			Context:             testDoc.Testcontext,
 			FailedContinuously:  testFailedContinuously,
 			Successful:          successful,
+			History:             runSamples(testDoc.History.Hits.Hits),
 		}
 		contextToTestDetailMap[testDoc.Testcontext] = parsedTestDetail
 	}
@@ -125,7 +181,7 @@ func (alerter *Alert) extractTestDetailItems(testContextAggregation TestContextA
 func (alerter *Alert) removeSuccessfulTests(contextToTestDetailMap map[string]TestDetails) map[string]TestDetails {
 	testsSizeBefore := len(contextToTestDetailMap)
 	for key, value := range contextToTestDetailMap {
-		if !value.FailedContinuously && value.SuccessRate >= float64(alerter.cfg.SuccessRateThresholdPercent) {
+		if value.Successful {
 			delete(contextToTestDetailMap, key)
 		}
 	}
@@ -174,17 +230,24 @@ func (alerter *Alert) removeExcludedTests(tests *map[string]TestDetails) error {
 	return nil
 }
 
-// removeAlreadyFiledAlerts filters out tests that have already been alerted in recent n days
-func (alerter *Alert) removeAlreadyFiledAlerts(tests map[string]TestDetails, alreadyFiledAlerts AlertDocs) {
+// removeAlreadyFiledAlerts filters out tests that have an open alert.
+// Tests whose alert snooze has expired are alerted again.
+func (alerter *Alert) removeAlreadyFiledAlerts(tests map[string]TestDetails, openFiledAlerts map[string]TestDetails, now time.Time) {
 	testsSizeBefore := len(tests)
-	for _, alertItem := range alreadyFiledAlerts.Hits.AlertItems {
-		delete(tests, alertItem.Source.Context)
+	for testContext, filedAlert := range openFiledAlerts {
+		if filedAlert.suppresses(now) {
+			delete(tests, testContext)
+		}
 	}
 	alerter.log.V(3).Info(fmt.Sprintf("%d/%d tests alerts have been discarded, since they have already been posted in slack", testsSizeBefore-len(tests), testsSizeBefore))
 }
 
 // getFiledAlerts gets list of existing alert docs in elasticsearch
 func (alerter *Alert) getFiledAlerts() (AlertDocs, error) {
+	if alerter.cfg.ESClient == nil {
+		alerter.log.V(3).Info("no elasticsearch configured, no alerts have been filed")
+		return AlertDocs{}, nil
+	}
 	var alreadyFiledAlerts AlertDocs
 	if err := alerter.elasticRequest("/tm-alerter*/_search", http.MethodGet, `{"size": 10000}`, &alreadyFiledAlerts); err != nil {
 		return AlertDocs{}, errors.Wrap(err, "failed to get elasticsearch alerter items")
@@ -193,23 +256,42 @@ func (alerter *Alert) getFiledAlerts() (AlertDocs, error) {
 	return alreadyFiledAlerts, nil
 }
 
-// deleteOutdatedAlertDocsPayload deletes all elasticsearch documents that are older than n days
+// deleteOutdatedAlertsFromDB deletes all elasticsearch documents that are resolved for more than n days
+// and documents without lifecycle state that are older than n days
 func (alerter *Alert) deleteOutdatedAlertsFromDB() error {
+	if alerter.cfg.DryRun || alerter.cfg.ESClient == nil {
+		alerter.log.V(3).Info("skip deletion of outdated elasticsearch alerter docs", "dryRun", alerter.cfg.DryRun)
+		return nil
+	}
 	alerter.log.V(3).Info("delete outdated elasticsearch alerter docs")
-	deleteOutdatedAlertDocsPayload := fmt.Sprintf(`{ "query": { "range": { "datetime": { "lt": "now-%dd" } } } }`, alerter.cfg.EvalTimeDays)
+	deleteOutdatedAlertDocsPayload := fmt.Sprintf(`{ "query": { "bool": {
+		"should": [
+			{ "range": { "resolvedAt": { "lt": "now-%[1]dd" } } },
+			{ "bool": { "must_not": { "exists": { "field": "state" } }, "must": { "range": { "datetime": { "lt": "now-%[1]dd" } } } } }
+		],
+		"minimum_should_match": 1
+	} } }`, alerter.cfg.EvalTimeDays)
 	if err := alerter.elasticRequest("/tm-alerter*/_delete_by_query", http.MethodPost, deleteOutdatedAlertDocsPayload, nil); err != nil {
 		return errors.Wrap(err, "failed to delete outdated elasticsearch alerter items")
 	}
 	return nil
 }
 
-// PostAlertMessageToSlack posts alerts to slack
-func (alerter *Alert) PostAlertMessageToSlack(client slack.Client, channel string, failedTests map[string]TestDetails) error {
+// sendAlertMessageToSlack posts alerts to a slack channel
+func (alerter *Alert) sendAlertMessageToSlack(client slack.Client, channel string, failedTests map[string]TestDetails) error {
 	if len(failedTests) == 0 {
 		alerter.log.Info("no new failed tests found, nothing to post in slack")
 		return nil
 	}
-	message := createAlertMessage(failedTests, alerter)
+	if err := alerter.postAlertMessage(client, channel, createAlertMessage(failedTests, alerter)); err != nil {
+		return err
+	}
+	alerter.log.Info("Sent slack alerter message", "failing tests", len(failedTests))
+	return nil
+}
+
+// postAlertMessage posts an alert message in several parts to a slack channel
+func (alerter *Alert) postAlertMessage(client slack.Client, channel, message string) error {
 	splitedMessage := splitSlackMessage(message, 3900)
 	messagePrefix := "*🔥 New Testmachinery Alerts:* \n"
 	messageSuffix := ""
@@ -225,10 +307,6 @@ func (alerter *Alert) PostAlertMessageToSlack(client slack.Client, channel strin
 		}
 		time.Sleep(1200 * time.Millisecond) // need to wait 1 sec due to slack limits
 	}
-	alerter.log.Info("Sent slack alerter message", "failing tests", len(failedTests))
-	if err := alerter.filePostedAlerts(failedTests); err != nil {
-		return err
-	}
 	return nil
 }
 
@@ -244,28 +322,40 @@ func createAlertMessage(failedTests map[string]TestDetails, alert *Alert) string
 	row := 0
 	for _, mapKey := range sortedKeys {
 		test := failedTests[mapKey]
-		failureReason := ""
-		if test.SuccessRate < float64(alert.cfg.SuccessRateThresholdPercent) {
-			failureReason = fmt.Sprintf("success rate < %d%%", alert.cfg.SuccessRateThresholdPercent)
-		} else if test.FailedContinuously {
-			failureReason = fmt.Sprintf(">%d failures in row", alert.cfg.ContinuousFailureThreshold-1)
-		}
 		newRow := []string{test.Name,
 			test.Landscape,
 			util.StringDefault(test.Cloudprovider, "-"),
 			util.StringDefault(test.K8sVersion, "-"),
 			util.StringDefault(test.OperatingSystem, "-"),
 			fmt.Sprintf("%d%%", int(test.SuccessRate)),
-			failureReason,
+			alert.failureReason(test),
 			test.LastFailedTimestamp,
 		}
 		content = append(content, newRow)
 		row++
 	}
 
+	return renderTable([]string{"Test", "Landscape", "Provider", "K8s Ver", "OS", "Success", "Alert Reason", "Last failure"}, content)
+}
+
+// failureReason returns the reason why an alert is raised for a test
+func (alerter *Alert) failureReason(test TestDetails) string {
+	if len(test.Reasons) != 0 {
+		return strings.Join(test.Reasons, ", ")
+	}
+	if test.SuccessRate < float64(alerter.cfg.SuccessRateThresholdPercent) {
+		return fmt.Sprintf("success rate < %d%%", alerter.cfg.SuccessRateThresholdPercent)
+	} else if test.FailedContinuously {
+		return fmt.Sprintf(">%d failures in row", alerter.cfg.ContinuousFailureThreshold-1)
+	}
+	return ""
+}
+
+// renderTable renders an ascii table that is used in slack messages
+func renderTable(header []string, content [][]string) string {
 	writer := &strings.Builder{}
 	table := tablewriter.NewTable(writer,
-		tablewriter.WithHeader([]string{"Test", "Landscape", "Provider", "K8s Ver", "OS", "Success", "Alert Reason", "Last failure"}),
+		tablewriter.WithHeader(header),
 		tablewriter.WithHeaderAlignment(tw.AlignCenter),
 		tablewriter.WithRowAlignment(tw.AlignCenter),
 		tablewriter.WithRenderer(renderer.NewBlueprint()),
@@ -283,13 +373,21 @@ func createAlertMessage(failedTests map[string]TestDetails, alert *Alert) string
 	return writer.String()
 }
 
-// PostRecoverMessageToSlack posts alerts to slack
-func (alerter *Alert) PostRecoverMessageToSlack(client slack.Client, channel string, recoveredTests map[string]TestDetails) error {
+// sendRecoverMessageToSlack posts recovered tests to a slack channel
+func (alerter *Alert) sendRecoverMessageToSlack(client slack.Client, channel string, recoveredTests map[string]TestDetails) error {
 	if len(recoveredTests) == 0 {
 		alerter.log.Info("no new recovered tests found, nothing to post in slack")
 		return nil
 	}
-	message := createRecoverMessage(recoveredTests)
+	if err := alerter.postRecoverMessage(client, channel, createRecoverMessage(recoveredTests)); err != nil {
+		return err
+	}
+	alerter.log.Info("Sent slack recover message", "recovered tests", len(recoveredTests))
+	return nil
+}
+
+// postRecoverMessage posts a recover message in several parts to a slack channel
+func (alerter *Alert) postRecoverMessage(client slack.Client, channel, message string) error {
 	splittedMessage := splitSlackMessage(message, 3900)
 	messagePrefix := "*🍏 Testmachinery Tests Got Healthy:* \n"
 	for i, messageSplitItem := range splittedMessage {
@@ -301,15 +399,6 @@ func (alerter *Alert) PostRecoverMessageToSlack(client slack.Client, channel str
 		}
 		time.Sleep(1200 * time.Millisecond) // need to wait 1 sec due to slack limits
 	}
-	alerter.log.Info("Sent slack recover message", "recovered tests", len(recoveredTests))
-
-	testNames := make([]string, 0, len(recoveredTests))
-	for key := range recoveredTests {
-		testNames = append(testNames, key)
-	}
-	if err := alerter.deleteRecoveredTestsFromAlertIndex(testNames); err != nil {
-		return err
-	}
 	return nil
 }
 
@@ -335,34 +424,7 @@ func createRecoverMessage(recoveredTests map[string]TestDetails) string {
 		row++
 	}
 
-	writer := &strings.Builder{}
-	table := tablewriter.NewTable(writer,
-		tablewriter.WithHeader([]string{"Test", "Landscape", "Provider", "K8s Ver", "OS"}),
-		tablewriter.WithHeaderAlignment(tw.AlignCenter),
-		tablewriter.WithRowAlignment(tw.AlignCenter),
-		tablewriter.WithRenderer(renderer.NewBlueprint()),
-		tablewriter.WithRendition(tw.Rendition{
-			Symbols: tw.NewSymbols(tw.StyleASCII),
-			Borders: tw.Border{Top: tw.On, Bottom: tw.On, Left: tw.On, Right: tw.On},
-		}),
-	)
-
-	if err := table.Bulk(content); err != nil {
-		return fmt.Errorf("unable to add table content: %w", err).Error()
-	}
-	if err := table.Render(); err != nil {
-		return fmt.Errorf("unable to render table: %w", err).Error()
-	}
-	return writer.String()
-}
-
-func (alerter *Alert) deleteRecoveredTestsFromAlertIndex(testNames []string) error {
-	alerter.log.V(3).Info("delete recovered tests from elasticsearch tm-alerter index")
-	deleteOutdatedAlertDocsPayload := fmt.Sprintf(`{ "query": { "terms" : { "testContext.keyword" : ["%s"] } } }`, strings.Join(testNames, `", "`))
-	if err := alerter.elasticRequest("/tm-alerter*/_delete_by_query", http.MethodPost, deleteOutdatedAlertDocsPayload, nil); err != nil {
-		return errors.Wrapf(err, "failed to delete recovered elasticsearch test docs")
-	}
-	return nil
+	return renderTable([]string{"Test", "Landscape", "Provider", "K8s Ver", "OS"}, content)
 }
 
 // splitSlackMessage split message line wise based on given characters limit
@@ -400,6 +462,10 @@ func (alerter *Alert) elasticRequest(urlAttributes, httpMethod, payloadFormated
 
 // filePostedAlerts posts test contexts to elasticsearch
 func (alerter *Alert) filePostedAlerts(tests map[string]TestDetails) error {
+	if alerter.cfg.ESClient == nil {
+		alerter.log.V(3).Info("no elasticsearch configured, alerts are not filed")
+		return nil
+	}
 	payload := alerter.generatePostedAlertsPayload(tests)
 	if err := alerter.elasticRequest("/tm-alerter/_bulk", http.MethodPost, payload, nil); err != nil {
 		return errors.Wrap(err, "failed to store alerted tests in elasticsearch")
@@ -408,12 +474,13 @@ func (alerter *Alert) filePostedAlerts(tests map[string]TestDetails) error {
 	return nil
 }
 
-func (alerter *Alert) extractRecoveredTests(testContextToTestMap map[string]TestDetails, filedAlerts AlertDocs) map[string]TestDetails {
+// extractRecoveredTests returns the successful tests that have an open alert
+func (alerter *Alert) extractRecoveredTests(testContextToTestMap map[string]TestDetails, openFiledAlerts map[string]TestDetails) map[string]TestDetails {
 	recoveredTests := make(map[string]TestDetails)
-	for _, filedAlert := range filedAlerts.Hits.AlertItems {
-		test, ok := testContextToTestMap[filedAlert.Source.Context]
+	for testContext := range openFiledAlerts {
+		test, ok := testContextToTestMap[testContext]
 		if ok && test.Successful {
-			recoveredTests[filedAlert.Source.Context] = test
+			recoveredTests[testContext] = test
 		}
 	}
 	return recoveredTests
@@ -425,6 +492,7 @@ func (alerter *Alert) generatePostedAlertsPayload(tests map[string]TestDetails)
 	payload := ""
 	for _, test := range tests {
 		test.FiledAlertDataTime = datetime
+		test.State = AlertStateFiring
 		bulkString, err := test.ElasticsearchBulkString()
 		if err != nil {
 			alerter.log.Error(err, "Failed to marshal test details item", "item", test)
@@ -433,68 +501,3 @@ func (alerter *Alert) generatePostedAlertsPayload(tests map[string]TestDetails)
 	}
 	return payload
 }
-
-// printESAggregationPayload format elasticsearch aggregation payload
-func (alerter *Alert) printESAggregationPayload() string {
-	return fmt.Sprintf(`{
-		"size": 0,
-		"query": {
-			"bool": {
-				"must": [
-					{ "match": { "type": "teststep" } },
-					{ "range": { "startTime": { "gte": "now-%dd" } } }
-				],
-				"should": [
-					{ "term": { "phase.keyword": "Failed" } },
-					{ "term": { "phase.keyword": "Succeeded" } },
-					{ "term": { "phase.keyword": "Skipped" } }
-				],
-				"must_not": { "match": { "tm.annotations.testmachinery.sapcloud.io/purpose.keyword":   "beta" }},
-				"minimum_should_match": 1
-			}
-		},
-		"aggs": {
-			"test_context": {
-				"terms": {
-					"script": {
-						"source": "def landscape = ''; def k8s_version = ''; def name = ''; def provider = 'none'; def os = ''; if (doc['tm.cloudprovider.keyword'].size() != 0) { provider = doc['tm.cloudprovider.keyword'].value;  } if (doc['tm.landscape.keyword'].size() != 0) { landscape = doc['tm.landscape.keyword'].value; } if (doc['tm.operating_system.keyword'].size() != 0) { os = doc['tm.operating_system.keyword'].value; } if (doc['tm.k8s_version.keyword'].size() != 0) { k8s_version = '_v' + doc['tm.k8s_version.keyword'].value; } if (doc['name.keyword'].size() != 0) { name = doc['name.keyword'].value; } name + '_' + landscape + '_' + provider + k8s_version + '_' + os;",
-						"lang": "painless"
-					},
-					"min_doc_count": %d,
-					"size": 10000
-				},
-				"aggs": {
-					"success_rate": { "avg": { "field": "pre.phaseNum" } },
-					"success_trend": {
-						"top_hits": {
-							"sort": [ { "startTime": { "order": "desc" } } ],
-							"_source": { "includes": [ "pre.phaseNum" ] },
-							"size": %d
-						}
-					},
-					"details": {
-						"top_hits": {
-							"sort": [
-								{ "pre.phaseNum": { "order": "asc" } },
-					        	{ "startTime": { "order": "desc" } }
-							],
-							"_source": {
-								"includes": [
-									"name",
-									"startTime",
-									"pre.clusterDomain",
-									"tm.landscape",
-									"tm.tr.id",
-									"tm.cloudprovider",
-									"tm.k8s_version",
-									"tm.operating_system"
-								]
-							},
-							"size": 1
-						}
-					}
-				}
-			}
-		}
-	}`, alerter.cfg.EvalTimeDays, alerter.cfg.ContinuousFailureThreshold, alerter.cfg.ContinuousFailureThreshold)
-}
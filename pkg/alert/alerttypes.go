@@ -9,36 +9,42 @@ import (
 type TestContextAggregation struct {
 	Aggs struct {
 		TestContext struct {
-			TestDetailsRaw []struct {
-				Testcontext string `json:"key"`
-				Details     struct {
-					Hits struct {
-						Docs []ESTestmachineryDoc `json:"hits"`
-					} `json:"hits"`
-				} `json:"details"`
-				SuccessTrend struct {
-					Hits struct {
-						Hits []struct {
-							Source struct {
-								Pre struct {
-									PhaseNum int `json:"phaseNum"`
-								} `json:"pre"`
-							} `json:"_source"`
-						} `json:"hits"`
-					} `json:"hits"`
-				} `json:"success_trend"`
-				SuccessRate struct {
-					Value float64 `json:"value"`
-				} `json:"success_rate"`
-			} `json:"buckets"`
+			TestDetailsRaw []TestContextBucket `json:"buckets"`
 		} `json:"test_context"`
 	} `json:"aggregations"`
 }
 
+// TestContextBucket is the aggregation of all test runs of one test context
+type TestContextBucket struct {
+	Testcontext string `json:"key"`
+	DocCount    int    `json:"doc_count"`
+	Details     struct {
+		Hits struct {
+			Docs []ESTestmachineryDoc `json:"hits"`
+		} `json:"hits"`
+	} `json:"details"`
+	SuccessTrend struct {
+		Hits struct {
+			Hits []ESRunDoc `json:"hits"`
+		} `json:"hits"`
+	} `json:"success_trend"`
+	SuccessRate struct {
+		Value float64 `json:"value"`
+	} `json:"success_rate"`
+	History struct {
+		Hits struct {
+			Hits []ESRunDoc `json:"hits"`
+		} `json:"hits"`
+	} `json:"history"`
+}
+
 type ESTestmachineryDoc struct {
 	Source struct {
-		Name          string `json:"name"`
-		LastTimestamp string `json:"startTime"`
+		Name          string   `json:"name"`
+		LastTimestamp string   `json:"startTime"`
+		Labels        []string `json:"labels"`
+		Owner         string   `json:"owner"`
+		Recipients    []string `json:"recipientsOnFailure"`
 		TM            struct {
 			Cloudprovider   string `json:"cloudprovider"`
 			K8sVersion      string `json:"k8s_version"`
@@ -51,6 +57,17 @@ type ESTestmachineryDoc struct {
 	} `json:"_source"`
 }
 
+// ESRunDoc is a test run of the execution history of a test context
+type ESRunDoc struct {
+	Source struct {
+		StartTime string `json:"startTime"`
+		Duration  int64  `json:"duration"`
+		Pre       struct {
+			PhaseNum *int `json:"phaseNum"`
+		} `json:"pre"`
+	} `json:"_source"`
+}
+
 // AlertDocs elasticsearch alert docs structure
 type AlertDocs struct {
 	Hits struct {
@@ -62,25 +79,46 @@ type AlertDocs struct {
 
 // TestDetails describes a test which is used for alert message
 type TestDetails struct {
-	Name                string  `json:"name"`               // Name test name
-	Context             string  `json:"testContext"`        // Context is the concatenation of name and several test dimensions
-	FailedContinuously  bool    `json:"failedContinuously"` // FailedContinuously true if n recent test runs were failing in a row
-	LastFailedTimestamp string  `json:"lastTimeFailed"`     // LastFailedTimestamp timestamp of last failed test execution
-	SuccessRate         float64 `json:"successRate"`        // SuccessRate of recent n days
-	FiledAlertDataTime  string  `json:"datetime"`           // FiledAlertDataTime is the timestamp when the alert has been filed in slack
-	Successful          bool    `json:"-"`                  // Successful is true if success rate doesn't go below threshold and isn't failed continuously
-	Cloudprovider       string  `json:"cloudprovider,omitempty"`
-	OperatingSystem     string  `json:"operatingSystem,omitempty"`
-	Landscape           string  `json:"landscape"`
-	K8sVersion          string  `json:"k8sVersion,omitempty"`
-	TestrunID           string  `json:"testrunID"`
+	Name                string   `json:"name"`               // Name test name
+	Context             string   `json:"testContext"`        // Context is the concatenation of name and several test dimensions
+	FailedContinuously  bool     `json:"failedContinuously"` // FailedContinuously true if n recent test runs were failing in a row
+	LastFailedTimestamp string   `json:"lastTimeFailed"`     // LastFailedTimestamp timestamp of last failed test execution
+	SuccessRate         float64  `json:"successRate"`        // SuccessRate of recent n days
+	FiledAlertDataTime  string   `json:"datetime"`           // FiledAlertDataTime is the timestamp when the alert has been filed in slack
+	Successful          bool     `json:"-"`                  // Successful is true if success rate doesn't go below threshold and isn't failed continuously
+	Cloudprovider       string   `json:"cloudprovider,omitempty"`
+	OperatingSystem     string   `json:"operatingSystem,omitempty"`
+	Landscape           string   `json:"landscape"`
+	K8sVersion          string   `json:"k8sVersion,omitempty"`
+	TestrunID           string   `json:"testrunID"`
+	Labels              []string `json:"labels,omitempty"`              // Labels of the test definition
+	Owner               string   `json:"owner,omitempty"`               // Owner email address of the test definition
+	Recipients          []string `json:"recipientsOnFailure,omitempty"` // Recipients additional email addresses of the test definition that are notified on failure
+	Reasons             []string `json:"reasons,omitempty"`             // Reasons why the test is alerted
+
+	History  []RunSample `json:"-"` // History of the test runs in the evaluation period ordered by start time
+	Baseline []RunSample `json:"-"` // Baseline are the test runs of the baseline period before the evaluation period ordered by start time
+
+	State          AlertState `json:"state,omitempty"`          // State is the lifecycle state of a filed alert
+	AcknowledgedBy string     `json:"acknowledgedBy,omitempty"` // AcknowledgedBy is the user that acknowledged the alert
+	AcknowledgedAt string     `json:"acknowledgedAt,omitempty"` // AcknowledgedAt is the timestamp when the alert has been acknowledged
+	SnoozedBy      string     `json:"snoozedBy,omitempty"`      // SnoozedBy is the user that snoozed the alert
+	SnoozedUntil   string     `json:"snoozedUntil,omitempty"`   // SnoozedUntil is the timestamp when the snooze of the alert expires
+	EscalatedAt    string     `json:"escalatedAt,omitempty"`    // EscalatedAt is the timestamp when the unacknowledged alert has been escalated
+	ResolvedBy     string     `json:"resolvedBy,omitempty"`     // ResolvedBy is the user that resolved the alert or testmachinery if the test recovered
+	ResolvedAt     string     `json:"resolvedAt,omitempty"`     // ResolvedAt is the timestamp when the alert has been resolved
 }
 
-// ElasticsearchBulkString creates an elastic search bulk string for ingestion
+// ElasticsearchBulkString creates an elastic search bulk string for ingestion.
+// The test context is used as document id so that a test context is only filed once.
 func (test TestDetails) ElasticsearchBulkString() (string, error) {
 	testDetailsMarshaled, err := json.Marshal(test)
 	if err != nil {
 		return "", err
 	}
-	return fmt.Sprintf(`{ "index":{} }`+"\n%s\n", string(testDetailsMarshaled)), nil
+	id, err := json.Marshal(test.Context)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf(`{ "index":{ "_id": %s } }`+"\n%s\n", string(id), string(testDetailsMarshaled)), nil
 }
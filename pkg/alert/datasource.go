@@ -0,0 +1,327 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	argov1 "github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	tmv1beta1 "github.com/gardener/test-infra/pkg/apis/testmachinery/v1beta1"
+	"github.com/gardener/test-infra/pkg/common"
+	"github.com/gardener/test-infra/pkg/testmachinery/collector"
+	"github.com/gardener/test-infra/pkg/testmachinery/metadata"
+	"github.com/gardener/test-infra/pkg/util"
+	"github.com/gardener/test-infra/pkg/util/elasticsearch"
+)
+
+// AggregationRequest selects the test runs that are aggregated per test context
+type AggregationRequest struct {
+	FromDays    int // FromDays only test runs that started after now - n days are aggregated
+	ToDays      int // ToDays only test runs that started before now - n days are aggregated
+	MinDocCount int // MinDocCount test contexts with less test runs are omitted
+	TrendSize   int // TrendSize number of the latest test runs that are used to detect continuous failures
+}
+
+// DataSource aggregates the test runs of the testmachinery per test context
+type DataSource interface {
+	Aggregate(ctx context.Context, req AggregationRequest) (TestContextAggregation, error)
+}
+
+type elasticsearchDataSource struct {
+	client elasticsearch.Client
+}
+
+// NewElasticsearchDataSource creates a data source that aggregates the teststep documents of the testmachinery indices
+func NewElasticsearchDataSource(client elasticsearch.Client) DataSource {
+	return &elasticsearchDataSource{client: client}
+}
+
+func (s *elasticsearchDataSource) Aggregate(ctx context.Context, req AggregationRequest) (TestContextAggregation, error) {
+	body, err := s.client.RequestWithCtx(ctx, http.MethodGet, "/testmachinery-*/_search", strings.NewReader(esAggregationPayload(req)))
+	if err != nil {
+		return TestContextAggregation{}, errors.Wrap(err, "failed to call elasticsearch")
+	}
+	var aggregation TestContextAggregation
+	if err := json.Unmarshal(body, &aggregation); err != nil {
+		return TestContextAggregation{}, errors.Wrapf(err, "failed to unmarshal %s", string(body))
+	}
+	return aggregation, nil
+}
+
+type dirDataSource struct {
+	dir string
+	now func() time.Time
+}
+
+// NewDirDataSource creates a data source that aggregates the teststep documents of the bulk files
+// that have been written by the collector to a local directory
+func NewDirDataSource(dir string) DataSource {
+	return &dirDataSource{dir: dir, now: time.Now}
+}
+
+func (s *dirDataSource) Aggregate(_ context.Context, req AggregationRequest) (TestContextAggregation, error) {
+	docs := make([]json.RawMessage, 0)
+	err := filepath.WalkDir(s.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		data, err := os.ReadFile(filepath.Clean(path))
+		if err != nil {
+			return err
+		}
+		for line := range util.ReadLines(data) {
+			if json.Valid(line) {
+				docs = append(docs, line)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return TestContextAggregation{}, errors.Wrapf(err, "unable to read bulk files from %s", s.dir)
+	}
+	return aggregateStepDocs(docs, req, s.now())
+}
+
+type testrunDataSource struct {
+	client    client.Client
+	namespace string
+	now       func() time.Time
+}
+
+// NewTestrunDataSource creates a data source that aggregates the steps of the testruns in a namespace of the testmachinery cluster
+func NewTestrunDataSource(k8sClient client.Client, namespace string) DataSource {
+	return &testrunDataSource{client: k8sClient, namespace: namespace, now: time.Now}
+}
+
+func (s *testrunDataSource) Aggregate(ctx context.Context, req AggregationRequest) (TestContextAggregation, error) {
+	testruns := &tmv1beta1.TestrunList{}
+	if err := s.client.List(ctx, testruns, client.InNamespace(s.namespace)); err != nil {
+		return TestContextAggregation{}, errors.Wrapf(err, "unable to list testruns in namespace %s", s.namespace)
+	}
+	docs := make([]json.RawMessage, 0)
+	for i := range testruns.Items {
+		tr := &testruns.Items[i]
+		meta := metadata.FromTestrun(tr)
+		meta.Annotations = tr.Annotations
+		_, summaries := collector.GenerateSummary(tr, meta, "")
+		for _, summary := range summaries {
+			doc, err := json.Marshal(summary)
+			if err != nil {
+				return TestContextAggregation{}, errors.Wrapf(err, "unable to marshal summary of step %s of testrun %s", summary.StepName, tr.Name)
+			}
+			docs = append(docs, doc)
+		}
+	}
+	return aggregateStepDocs(docs, req, s.now())
+}
+
+// stepDoc contains the fields of a teststep document that are needed to aggregate it
+type stepDoc struct {
+	Type      metadata.SummaryType `json:"type"`
+	Name      string               `json:"name"`
+	Phase     argov1.NodePhase     `json:"phase"`
+	StartTime string               `json:"startTime"`
+	TM        struct {
+		Landscape       string            `json:"landscape"`
+		Cloudprovider   string            `json:"cloudprovider"`
+		K8sVersion      string            `json:"k8s_version"`
+		OperatingSystem string            `json:"operating_system"`
+		Annotations     map[string]string `json:"annotations"`
+	} `json:"tm"`
+	Pre struct {
+		PhaseNum *int `json:"phaseNum"`
+	} `json:"pre"`
+
+	startTime time.Time
+	raw       json.RawMessage
+}
+
+// context returns the test context of the document the same way as the elasticsearch aggregation
+func (d stepDoc) context() string {
+	provider := util.StringDefault(d.TM.Cloudprovider, "none")
+	k8sVersion := ""
+	if d.TM.K8sVersion != "" {
+		k8sVersion = "_v" + d.TM.K8sVersion
+	}
+	return d.Name + "_" + d.TM.Landscape + "_" + provider + k8sVersion + "_" + d.TM.OperatingSystem
+}
+
+// aggregateStepDocs aggregates teststep documents per test context like the elasticsearch aggregation query
+func aggregateStepDocs(rawDocs []json.RawMessage, req AggregationRequest, now time.Time) (TestContextAggregation, error) {
+	var (
+		from     = now.AddDate(0, 0, -req.FromDays)
+		to       = now.AddDate(0, 0, -req.ToDays)
+		phases   = sets.New(tmv1beta1.StepPhaseFailed, tmv1beta1.StepPhaseSuccess, tmv1beta1.StepPhaseSkipped)
+		contexts = make(map[string][]stepDoc)
+	)
+	for _, raw := range rawDocs {
+		var doc stepDoc
+		if err := json.Unmarshal(raw, &doc); err != nil {
+			return TestContextAggregation{}, errors.Wrap(err, "unable to decode step document")
+		}
+		if doc.Type != metadata.SummaryTypeTeststep {
+			continue
+		}
+		if !phases.Has(doc.Phase) || doc.TM.Annotations[common.AnnotationTestrunPurpose] == "beta" {
+			continue
+		}
+		startTime, err := time.Parse(time.RFC3339, doc.StartTime)
+		if err != nil || startTime.Before(from) || !startTime.Before(to) {
+			continue
+		}
+		doc.startTime, doc.raw = startTime, raw
+		contexts[doc.context()] = append(contexts[doc.context()], doc)
+	}
+
+	var aggregation TestContextAggregation
+	for _, key := range sets.List(sets.KeySet(contexts)) {
+		docs := contexts[key]
+		if len(docs) < req.MinDocCount {
+			continue
+		}
+		bucket := TestContextBucket{Testcontext: key, DocCount: len(docs)}
+
+		phaseNums, phaseSum := 0, 0
+		for _, doc := range docs {
+			if doc.Pre.PhaseNum != nil {
+				phaseNums++
+				phaseSum += *doc.Pre.PhaseNum
+			}
+		}
+		if phaseNums != 0 {
+			bucket.SuccessRate.Value = float64(phaseSum) / float64(phaseNums)
+		}
+
+		sort.SliceStable(docs, func(i, j int) bool { return docs[i].startTime.After(docs[j].startTime) })
+		var err error
+		if bucket.SuccessTrend.Hits.Hits, err = runDocs(docs, req.TrendSize); err != nil {
+			return TestContextAggregation{}, errors.Wrapf(err, "unable to decode the success trend of %s", key)
+		}
+		if bucket.History.Hits.Hits, err = runDocs(docs, historySize); err != nil {
+			return TestContextAggregation{}, errors.Wrapf(err, "unable to decode the history of %s", key)
+		}
+
+		// the details show the latest failed run or the latest run if the test never failed
+		details := docs[0]
+		for _, doc := range docs {
+			if doc.Pre.PhaseNum != nil && (details.Pre.PhaseNum == nil || *doc.Pre.PhaseNum < *details.Pre.PhaseNum) {
+				details = doc
+			}
+		}
+		var detailsDoc ESTestmachineryDoc
+		if err := json.Unmarshal(details.raw, &detailsDoc.Source); err != nil {
+			return TestContextAggregation{}, errors.Wrapf(err, "unable to decode the details of %s", key)
+		}
+		bucket.Details.Hits.Docs = []ESTestmachineryDoc{detailsDoc}
+
+		aggregation.Aggs.TestContext.TestDetailsRaw = append(aggregation.Aggs.TestContext.TestDetailsRaw, bucket)
+	}
+	return aggregation, nil
+}
+
+// runDocs returns the run documents of the first n step documents
+func runDocs(docs []stepDoc, n int) ([]ESRunDoc, error) {
+	if n > len(docs) {
+		n = len(docs)
+	}
+	runs := make([]ESRunDoc, n)
+	for i := range runs {
+		if err := json.Unmarshal(docs[i].raw, &runs[i].Source); err != nil {
+			return nil, err
+		}
+	}
+	return runs, nil
+}
+
+// esAggregationPayload formats the elasticsearch aggregation payload of the test runs that started between now-fromDays and now-toDays
+func esAggregationPayload(req AggregationRequest) string {
+	return fmt.Sprintf(`{
+		"size": 0,
+		"query": {
+			"bool": {
+				"must": [
+					{ "match": { "type": "teststep" } },
+					{ "range": { "startTime": { "gte": "now-%dd", "lt": "now-%dd" } } }
+				],
+				"should": [
+					{ "term": { "phase.keyword": "Failed" } },
+					{ "term": { "phase.keyword": "Succeeded" } },
+					{ "term": { "phase.keyword": "Skipped" } }
+				],
+				"must_not": { "match": { "tm.annotations.testmachinery.sapcloud.io/purpose.keyword":   "beta" }},
+				"minimum_should_match": 1
+			}
+		},
+		"aggs": {
+			"test_context": {
+				"terms": {
+					"script": {
+						"source": "def landscape = ''; def k8s_version = ''; def name = ''; def provider = 'none'; def os = ''; if (doc['tm.cloudprovider.keyword'].size() != 0) { provider = doc['tm.cloudprovider.keyword'].value;  } if (doc['tm.landscape.keyword'].size() != 0) { landscape = doc['tm.landscape.keyword'].value; } if (doc['tm.operating_system.keyword'].size() != 0) { os = doc['tm.operating_system.keyword'].value; } if (doc['tm.k8s_version.keyword'].size() != 0) { k8s_version = '_v' + doc['tm.k8s_version.keyword'].value; } if (doc['name.keyword'].size() != 0) { name = doc['name.keyword'].value; } name + '_' + landscape + '_' + provider + k8s_version + '_' + os;",
+						"lang": "painless"
+					},
+					"min_doc_count": %d,
+					"size": 10000
+				},
+				"aggs": {
+					"success_rate": { "avg": { "field": "pre.phaseNum" } },
+					"success_trend": {
+						"top_hits": {
+							"sort": [ { "startTime": { "order": "desc" } } ],
+							"_source": { "includes": [ "pre.phaseNum" ] },
+							"size": %d
+						}
+					},
+					"history": {
+						"top_hits": {
+							"sort": [ { "startTime": { "order": "desc" } } ],
+							"_source": { "includes": [ "pre.phaseNum", "startTime", "duration" ] },
+							"size": %d
+						}
+					},
+					"details": {
+						"top_hits": {
+							"sort": [
+								{ "pre.phaseNum": { "order": "asc" } },
+					        	{ "startTime": { "order": "desc" } }
+							],
+							"_source": {
+								"includes": [
+									"name",
+									"startTime",
+									"labels",
+									"owner",
+									"recipientsOnFailure",
+									"pre.clusterDomain",
+									"tm.landscape",
+									"tm.tr.id",
+									"tm.cloudprovider",
+									"tm.k8s_version",
+									"tm.operating_system"
+								]
+							},
+							"size": 1
+						}
+					}
+				}
+			}
+		}
+	}`, req.FromDays, req.ToDays, req.MinDocCount, req.TrendSize, historySize)
+}
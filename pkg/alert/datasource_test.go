@@ -0,0 +1,180 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	argov1 "github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	tmv1beta1 "github.com/gardener/test-infra/pkg/apis/testmachinery/v1beta1"
+	"github.com/gardener/test-infra/pkg/common"
+)
+
+var _ = Describe("alert data sources", func() {
+
+	var (
+		now = time.Date(2024, 5, 17, 12, 0, 0, 0, time.UTC)
+		req = AggregationRequest{FromDays: 3, MinDocCount: 1, TrendSize: 2}
+	)
+
+	buckets := func(aggregation TestContextAggregation) map[string]TestContextBucket {
+		result := make(map[string]TestContextBucket)
+		for _, bucket := range aggregation.Aggs.TestContext.TestDetailsRaw {
+			result[bucket.Testcontext] = bucket
+		}
+		return result
+	}
+
+	Context("directory", func() {
+		var source DataSource
+
+		BeforeEach(func() {
+			source = &dirDataSource{dir: "testdata/bulk", now: func() time.Time { return now }}
+		})
+
+		It("should aggregate the teststeps of the bulk files per test context", func() {
+			aggregation, err := source.Aggregate(context.Background(), req)
+			Expect(err).ToNot(HaveOccurred())
+
+			result := buckets(aggregation)
+			Expect(result).To(HaveLen(2))
+
+			bucket := result["gardener-test_dev_aws_v1.29_gardenlinux"]
+			Expect(bucket.DocCount).To(Equal(3))
+			Expect(bucket.SuccessRate.Value).To(BeNumerically("~", 33.3, 0.1))
+			Expect(bucket.SuccessTrend.Hits.Hits).To(HaveLen(2))
+			Expect(*bucket.SuccessTrend.Hits.Hits[0].Source.Pre.PhaseNum).To(Equal(0))
+			Expect(bucket.History.Hits.Hits).To(HaveLen(3))
+			Expect(bucket.History.Hits.Hits[2].Source.Duration).To(Equal(int64(500)))
+			Expect(bucket.Details.Hits.Docs).To(HaveLen(1))
+			details := bucket.Details.Hits.Docs[0].Source
+			Expect(details.TM.Testrun.ID).To(Equal("tr-3"))
+			Expect(details.Owner).To(Equal("owner@example.com"))
+			Expect(details.Labels).To(ConsistOf("default"))
+
+			bucket = result["other-test_dev_gcp_"]
+			Expect(bucket.DocCount).To(Equal(2))
+			Expect(bucket.SuccessRate.Value).To(Equal(100.0))
+			Expect(bucket.Details.Hits.Docs[0].Source.TM.Testrun.ID).To(Equal("tr-4"))
+		})
+
+		It("should omit test contexts with less runs than the min doc count", func() {
+			aggregation, err := source.Aggregate(context.Background(), AggregationRequest{FromDays: 3, MinDocCount: 3, TrendSize: 3})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(buckets(aggregation)).To(ConsistOf(HaveField("Testcontext", "gardener-test_dev_aws_v1.29_gardenlinux")))
+		})
+
+		It("should only aggregate the test runs of the requested time range", func() {
+			aggregation, err := source.Aggregate(context.Background(), AggregationRequest{FromDays: 20, ToDays: 3, MinDocCount: 1})
+			Expect(err).ToNot(HaveOccurred())
+			result := buckets(aggregation)
+			Expect(result).To(HaveLen(1))
+			Expect(result["gardener-test_dev_aws_v1.29_gardenlinux"].DocCount).To(Equal(1))
+		})
+
+		It("should fail for malformed step documents", func() {
+			_, err := aggregateStepDocs([]json.RawMessage{
+				[]byte(`{"type":"teststep","name":"a","phase":"Failed","startTime":"2024-05-16T08:00:00Z","pre":{"phaseNum":"0"}}`),
+			}, req, now)
+			Expect(err).To(MatchError(ContainSubstring("unable to decode step document")))
+
+			_, err = aggregateStepDocs([]json.RawMessage{
+				[]byte(`{"type":"teststep","name":"a","phase":"Failed","startTime":"2024-05-16T08:00:00Z","duration":"long","pre":{"phaseNum":0}}`),
+			}, req, now)
+			Expect(err).To(MatchError(ContainSubstring("unable to decode the success trend of a_")))
+		})
+
+		It("should find failing tests without elasticsearch", func() {
+			a := New(logr.Discard(), Config{
+				DataSource:                  source,
+				EvalTimeDays:                3,
+				SuccessRateThresholdPercent: 50,
+				ContinuousFailureThreshold:  2,
+			})
+			notification, err := a.FindAlerts()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(notification.New).To(HaveLen(1))
+			test := notification.New["gardener-test_dev_aws_v1.29_gardenlinux"]
+			Expect(test.FailedContinuously).To(BeTrue())
+			Expect(test.Reasons).To(ConsistOf("success rate < 50%"))
+			Expect(notification.Recovered).To(BeEmpty())
+
+			server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {}))
+			defer server.Close()
+			Expect(a.Notify([]Output{NewWebhookOutput(a, server.URL, nil)}, notification)).To(Succeed())
+		})
+	})
+
+	Context("elasticsearch", func() {
+		It("should send the aggregation query to the testmachinery indices", func() {
+			es := &fakeElasticsearch{response: `{"aggregations": {"test_context": {"buckets": [{"key": "a", "doc_count": 2}]}}}`}
+			aggregation, err := NewElasticsearchDataSource(es).Aggregate(context.Background(), AggregationRequest{FromDays: 10, ToDays: 3, MinDocCount: 1, TrendSize: 3})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(es.path).To(Equal("/testmachinery-*/_search"))
+			Expect(es.payload).To(HaveKey("aggs"))
+			Expect(buckets(aggregation)).To(HaveKeyWithValue("a", HaveField("DocCount", 2)))
+		})
+	})
+
+	Context("testruns", func() {
+		It("should aggregate the steps of the testruns in the namespace", func() {
+			scheme := runtime.NewScheme()
+			Expect(tmv1beta1.AddToScheme(scheme)).To(Succeed())
+
+			testrun := func(name, namespace string, phase argov1.NodePhase, start time.Time) *tmv1beta1.Testrun {
+				startTime := metav1.NewTime(start)
+				return &tmv1beta1.Testrun{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      name,
+						Namespace: namespace,
+						Annotations: map[string]string{
+							common.AnnotationLandscape:       "dev",
+							common.AnnotationCloudProvider:   "aws",
+							common.AnnotationOperatingSystem: "gardenlinux",
+						},
+					},
+					Status: tmv1beta1.TestrunStatus{
+						Steps: []*tmv1beta1.StepStatus{{
+							Name:           "step",
+							Phase:          phase,
+							StartTime:      &startTime,
+							Duration:       60,
+							TestDefinition: tmv1beta1.StepStatusTestDefinition{Name: "gardener-test", Owner: "owner@example.com"},
+						}},
+					},
+				}
+			}
+			k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				testrun("tr-1", "default", tmv1beta1.StepPhaseSuccess, now.Add(-48*time.Hour)),
+				testrun("tr-2", "default", tmv1beta1.StepPhaseFailed, now.Add(-24*time.Hour)),
+				testrun("tr-3", "default", tmv1beta1.StepPhaseRunning, now.Add(-1*time.Hour)),
+				testrun("tr-4", "other", tmv1beta1.StepPhaseFailed, now.Add(-1*time.Hour)),
+			).Build()
+
+			source := &testrunDataSource{client: k8sClient, namespace: "default", now: func() time.Time { return now }}
+			aggregation, err := source.Aggregate(context.Background(), req)
+			Expect(err).ToNot(HaveOccurred())
+
+			result := buckets(aggregation)
+			Expect(result).To(HaveLen(1))
+			bucket := result["gardener-test_dev_aws_gardenlinux"]
+			Expect(bucket.DocCount).To(Equal(2))
+			Expect(bucket.SuccessRate.Value).To(Equal(50.0))
+			Expect(bucket.Details.Hits.Docs[0].Source.TM.Testrun.ID).To(Equal("tr-2"))
+			Expect(bucket.Details.Hits.Docs[0].Source.Owner).To(Equal("owner@example.com"))
+		})
+	})
+})
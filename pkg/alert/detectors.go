@@ -0,0 +1,204 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package alert
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	// historySize is the maximum number of test runs per test context that are used by the flakiness detectors
+	historySize = 100
+	// defaultMinRuns is the minimal number of test runs that are needed for a statistical detector
+	defaultMinRuns = 5
+	// defaultRegressionSignificance is the default p-value below which a success rate regression is reported
+	defaultRegressionSignificance = 0.05
+)
+
+// FlakinessConfig configures the detectors that find flaky and regressed tests in addition to the
+// success rate and continuous failure thresholds
+type FlakinessConfig struct {
+	// FlipRateThreshold reports tests whose result alternates between success and failure in at least the given ratio of consecutive runs.
+	// 0 disables the detector.
+	FlipRateThreshold float64
+	// BaselineDays is the time range in days before the evaluation time range that is used as baseline
+	// to detect success rate and duration regressions. 0 disables both detectors.
+	BaselineDays int
+	// RegressionSignificance is the p-value below which a lower success rate than in the baseline is reported as regression
+	RegressionSignificance float64
+	// DurationRegressionPercent reports tests whose p50 or p95 duration of successful runs increased by more than the given percent compared to the baseline.
+	// 0 disables the detector.
+	DurationRegressionPercent int
+	// MinRuns is the minimal number of runs in a time range that are needed to evaluate a detector
+	MinRuns int
+}
+
+// RunSample is the result of one test run
+type RunSample struct {
+	StartTime time.Time
+	Succeeded bool
+	Duration  time.Duration
+}
+
+// runSamples converts the run history of elasticsearch into samples ordered by start time.
+// Skipped runs have no phase and are ignored.
+func runSamples(docs []ESRunDoc) []RunSample {
+	samples := make([]RunSample, 0, len(docs))
+	for _, doc := range docs {
+		if doc.Source.Pre.PhaseNum == nil {
+			continue
+		}
+		startTime, _ := time.Parse(time.RFC3339, doc.Source.StartTime)
+		samples = append(samples, RunSample{
+			StartTime: startTime,
+			Succeeded: *doc.Source.Pre.PhaseNum != 0,
+			Duration:  time.Duration(doc.Source.Duration) * time.Second,
+		})
+	}
+	sort.SliceStable(samples, func(i, j int) bool {
+		return samples[i].StartTime.Before(samples[j].StartTime)
+	})
+	return samples
+}
+
+// evaluateTests sets the alert reasons of all tests and marks the tests without any reason as successful
+func (alerter *Alert) evaluateTests(tests map[string]TestDetails) {
+	for key, test := range tests {
+		test.Reasons = nil
+		if reason := alerter.failureReason(test); reason != "" {
+			test.Reasons = append(test.Reasons, reason)
+		}
+		test.Reasons = append(test.Reasons, alerter.detectFlakiness(test)...)
+		test.Successful = len(test.Reasons) == 0
+		tests[key] = test
+	}
+}
+
+// detectFlakiness runs all enabled flakiness detectors on the history of a test and returns the found reasons
+func (alerter *Alert) detectFlakiness(test TestDetails) []string {
+	var (
+		cfg     = alerter.cfg.Flakiness
+		reasons []string
+		minRuns = cfg.MinRuns
+	)
+	if minRuns <= 0 {
+		minRuns = defaultMinRuns
+	}
+
+	if cfg.FlipRateThreshold > 0 && len(test.History) >= minRuns {
+		if rate := flipRate(test.History); rate >= cfg.FlipRateThreshold {
+			reasons = append(reasons, fmt.Sprintf("flaky: flip rate %d%%", int(math.Round(rate*100))))
+		}
+	}
+
+	if cfg.BaselineDays <= 0 || len(test.Baseline) < minRuns || len(test.History) < minRuns {
+		return reasons
+	}
+
+	significance := cfg.RegressionSignificance
+	if significance <= 0 {
+		significance = defaultRegressionSignificance
+	}
+	baselineSucceeded, recentSucceeded := countSucceeded(test.Baseline), countSucceeded(test.History)
+	pValue := regressionPValue(baselineSucceeded, len(test.Baseline), recentSucceeded, len(test.History))
+	if pValue < significance {
+		reasons = append(reasons, fmt.Sprintf("regression: success %d%% -> %d%% (p=%.3f)",
+			percent(baselineSucceeded, len(test.Baseline)), percent(recentSucceeded, len(test.History)), pValue))
+	}
+
+	if cfg.DurationRegressionPercent > 0 {
+		baselineDurations, recentDurations := succeededDurations(test.Baseline), succeededDurations(test.History)
+		if len(baselineDurations) < minRuns || len(recentDurations) < minRuns {
+			return reasons
+		}
+		for _, p := range []int{50, 95} {
+			before, after := percentile(baselineDurations, p), percentile(recentDurations, p)
+			if before <= 0 {
+				continue
+			}
+			if increase := float64(after-before) / float64(before) * 100; increase > float64(cfg.DurationRegressionPercent) {
+				reasons = append(reasons, fmt.Sprintf("p%d duration +%d%% (%s -> %s)", p, int(increase), before, after))
+			}
+		}
+	}
+	return reasons
+}
+
+// flipRate returns the ratio of consecutive runs whose result differs
+func flipRate(samples []RunSample) float64 {
+	if len(samples) < 2 {
+		return 0
+	}
+	flips := 0
+	for i := 1; i < len(samples); i++ {
+		if samples[i].Succeeded != samples[i-1].Succeeded {
+			flips++
+		}
+	}
+	return float64(flips) / float64(len(samples)-1)
+}
+
+// regressionPValue returns the p-value of a one-sided two-proportion z-test
+// with the null hypothesis that the recent success rate is not lower than the baseline success rate.
+func regressionPValue(baselineSucceeded, baselineRuns, recentSucceeded, recentRuns int) float64 {
+	if baselineRuns == 0 || recentRuns == 0 {
+		return 1
+	}
+	var (
+		baselineRate = float64(baselineSucceeded) / float64(baselineRuns)
+		recentRate   = float64(recentSucceeded) / float64(recentRuns)
+		pooledRate   = float64(baselineSucceeded+recentSucceeded) / float64(baselineRuns+recentRuns)
+		stdErr       = math.Sqrt(pooledRate * (1 - pooledRate) * (1/float64(baselineRuns) + 1/float64(recentRuns)))
+	)
+	if stdErr == 0 {
+		return 1
+	}
+	z := (baselineRate - recentRate) / stdErr
+	return 0.5 * math.Erfc(z/math.Sqrt2)
+}
+
+// percentile returns the nearest-rank percentile of sorted values
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(float64(p) / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// succeededDurations returns the sorted durations of all successful runs
+func succeededDurations(samples []RunSample) []time.Duration {
+	durations := make([]time.Duration, 0, len(samples))
+	for _, sample := range samples {
+		if sample.Succeeded {
+			durations = append(durations, sample.Duration)
+		}
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	return durations
+}
+
+func countSucceeded(samples []RunSample) int {
+	count := 0
+	for _, sample := range samples {
+		if sample.Succeeded {
+			count++
+		}
+	}
+	return count
+}
+
+func percent(part, total int) int {
+	if total == 0 {
+		return 0
+	}
+	return part * 100 / total
+}
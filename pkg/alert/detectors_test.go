@@ -0,0 +1,127 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package alert
+
+import (
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("flakiness detectors", func() {
+
+	samples := func(results string, duration time.Duration) []RunSample {
+		start := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+		runs := make([]RunSample, 0, len(results))
+		for i, result := range results {
+			runs = append(runs, RunSample{
+				StartTime: start.Add(time.Duration(i) * time.Hour),
+				Succeeded: result == 's',
+				Duration:  duration,
+			})
+		}
+		return runs
+	}
+
+	It("should order the run history and ignore skipped runs", func() {
+		failed, succeeded := 0, 100
+		docs := make([]ESRunDoc, 3)
+		docs[0].Source.StartTime, docs[0].Source.Pre.PhaseNum, docs[0].Source.Duration = "2024-05-02T00:00:00Z", &failed, 60
+		docs[1].Source.StartTime = "2024-05-03T00:00:00Z"
+		docs[2].Source.StartTime, docs[2].Source.Pre.PhaseNum = "2024-05-01T00:00:00Z", &succeeded
+
+		runs := runSamples(docs)
+		Expect(runs).To(HaveLen(2))
+		Expect(runs[0].Succeeded).To(BeTrue())
+		Expect(runs[1].Succeeded).To(BeFalse())
+		Expect(runs[1].Duration).To(Equal(time.Minute))
+	})
+
+	It("should calculate the flip rate of consecutive runs", func() {
+		Expect(flipRate(samples("sfsfs", 0))).To(Equal(1.0))
+		Expect(flipRate(samples("sssff", 0))).To(Equal(0.25))
+		Expect(flipRate(samples("s", 0))).To(Equal(0.0))
+	})
+
+	It("should only report significant success rate regressions", func() {
+		Expect(regressionPValue(19, 20, 10, 20)).To(BeNumerically("<", 0.01))
+		Expect(regressionPValue(19, 20, 18, 20)).To(BeNumerically(">", 0.05))
+		Expect(regressionPValue(10, 20, 19, 20)).To(BeNumerically(">", 0.9))
+		Expect(regressionPValue(20, 20, 20, 20)).To(Equal(1.0))
+	})
+
+	It("should calculate nearest-rank percentiles", func() {
+		values := []time.Duration{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+		Expect(percentile(values, 50)).To(Equal(time.Duration(5)))
+		Expect(percentile(values, 95)).To(Equal(time.Duration(10)))
+		Expect(percentile(nil, 95)).To(Equal(time.Duration(0)))
+	})
+
+	Context("evaluation", func() {
+		var a *Alert
+
+		BeforeEach(func() {
+			a = New(logr.Discard(), Config{
+				SuccessRateThresholdPercent: 50,
+				ContinuousFailureThreshold:  3,
+				Flakiness: FlakinessConfig{
+					FlipRateThreshold:         0.5,
+					BaselineDays:              7,
+					RegressionSignificance:    0.05,
+					DurationRegressionPercent: 20,
+					MinRuns:                   5,
+				},
+			})
+		})
+
+		It("should alert flaky tests even if their success rate is above the threshold", func() {
+			tests := map[string]TestDetails{
+				"a": {Name: "a", SuccessRate: 60, History: samples("sfsfsfsfss", time.Minute)},
+			}
+			a.evaluateTests(tests)
+			Expect(tests["a"].Successful).To(BeFalse())
+			Expect(tests["a"].Reasons).To(ConsistOf("flaky: flip rate 89%"))
+			Expect(a.failureReason(tests["a"])).To(Equal("flaky: flip rate 89%"))
+		})
+
+		It("should alert success rate and duration regressions compared to the baseline", func() {
+			tests := map[string]TestDetails{
+				"a": {
+					Name:        "a",
+					SuccessRate: 60,
+					History:     samples("sssssfffff", 20*time.Minute),
+					Baseline:    samples("ssssssssssssssssssss", 10*time.Minute),
+				},
+			}
+			a.evaluateTests(tests)
+			Expect(tests["a"].Successful).To(BeFalse())
+			Expect(tests["a"].Reasons).To(ConsistOf(
+				HavePrefix("regression: success 100% -> 50%"),
+				"p50 duration +100% (10m0s -> 20m0s)",
+				"p95 duration +100% (10m0s -> 20m0s)",
+			))
+		})
+
+		It("should not evaluate detectors without enough runs", func() {
+			tests := map[string]TestDetails{
+				"a": {Name: "a", SuccessRate: 100, History: samples("sfsf", time.Minute), Baseline: samples("ssss", time.Second)},
+			}
+			a.evaluateTests(tests)
+			Expect(tests["a"].Successful).To(BeTrue())
+			Expect(tests["a"].Reasons).To(BeEmpty())
+		})
+
+		It("should keep the threshold reasons", func() {
+			tests := map[string]TestDetails{
+				"a": {Name: "a", SuccessRate: 0, FailedContinuously: true, History: samples("fffff", time.Minute)},
+			}
+			a.evaluateTests(tests)
+			Expect(tests["a"].Reasons).To(ConsistOf("success rate < 50%"))
+			Expect(a.removeSuccessfulTests(tests)).To(HaveKey("a"))
+		})
+	})
+})
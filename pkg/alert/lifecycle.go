@@ -0,0 +1,389 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package alert
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/gardener/test-infra/pkg/util"
+	"github.com/gardener/test-infra/pkg/util/slack"
+)
+
+// AlertState describes the lifecycle state of a filed alert
+type AlertState string
+
+const (
+	// AlertStateFiring is the state of a posted alert that has not been handled yet
+	AlertStateFiring AlertState = "firing"
+	// AlertStateAcknowledged is the state of an alert that somebody is taking care of
+	AlertStateAcknowledged AlertState = "acknowledged"
+	// AlertStateSnoozed is the state of an alert that is muted until a given time
+	AlertStateSnoozed AlertState = "snoozed"
+	// AlertStateResolved is the state of an alert whose test recovered or that has been resolved manually
+	AlertStateResolved AlertState = "resolved"
+)
+
+// AlertUpdate describes a lifecycle transition of filed alerts
+type AlertUpdate struct {
+	State AlertState
+	User  string
+	Until *time.Time // Until is the end of a snooze
+}
+
+// CurrentState returns the lifecycle state of a filed alert at the given time.
+// Alerts that were filed without a state are firing and alerts whose snooze has expired are firing again.
+func (test TestDetails) CurrentState(now time.Time) AlertState {
+	switch test.State {
+	case "":
+		return AlertStateFiring
+	case AlertStateSnoozed:
+		if until, err := time.Parse(time.RFC3339, test.SnoozedUntil); err == nil && !until.After(now) {
+			return AlertStateFiring
+		}
+	}
+	return test.State
+}
+
+// IsOpen returns true if the alert is not resolved
+func (test TestDetails) IsOpen() bool {
+	return test.State != AlertStateResolved
+}
+
+// suppresses returns true if the filed alert suppresses a new alert for the same test context
+func (test TestDetails) suppresses(now time.Time) bool {
+	if !test.IsOpen() {
+		return false
+	}
+	return test.State != AlertStateSnoozed || test.CurrentState(now) == AlertStateSnoozed
+}
+
+// firingSince returns the time since when the filed alert is firing
+func (test TestDetails) firingSince() (time.Time, error) {
+	since, err := time.Parse(time.RFC3339, test.FiledAlertDataTime)
+	if err != nil {
+		return time.Time{}, errors.Wrapf(err, "invalid alert datetime %q of %s", test.FiledAlertDataTime, test.Context)
+	}
+	if test.State == AlertStateSnoozed {
+		if until, err := time.Parse(time.RFC3339, test.SnoozedUntil); err == nil && until.After(since) {
+			return until, nil
+		}
+	}
+	return since, nil
+}
+
+// fields returns the alert doc fields that are set by the update
+func (u AlertUpdate) fields(now time.Time) map[string]interface{} {
+	timestamp := now.UTC().Format(time.RFC3339)
+	fields := map[string]interface{}{
+		"state": u.State,
+	}
+	switch u.State {
+	case AlertStateAcknowledged:
+		fields["acknowledgedBy"] = u.User
+		fields["acknowledgedAt"] = timestamp
+	case AlertStateSnoozed:
+		fields["snoozedBy"] = u.User
+		if u.Until != nil {
+			fields["snoozedUntil"] = u.Until.UTC().Format(time.RFC3339)
+		}
+	case AlertStateResolved:
+		fields["resolvedBy"] = u.User
+		fields["resolvedAt"] = timestamp
+	}
+	return fields
+}
+
+// UpdateAlerts sets the lifecycle state of all open alerts whose test context or test name matches one of the given tests.
+// The number of updated alerts is returned.
+func (alerter *Alert) UpdateAlerts(tests []string, update AlertUpdate) (int, error) {
+	return alerter.updateAlertDocs(tests, update.fields(time.Now()))
+}
+
+func (alerter *Alert) updateAlertDocs(tests []string, fields map[string]interface{}) (int, error) {
+	if len(tests) == 0 {
+		return 0, nil
+	}
+	if alerter.cfg.ESClient == nil {
+		return 0, errors.New("the alert lifecycle requires elasticsearch")
+	}
+	payload, err := json.Marshal(map[string]interface{}{
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"should": []interface{}{
+					map[string]interface{}{"terms": map[string]interface{}{"testContext.keyword": tests}},
+					map[string]interface{}{"terms": map[string]interface{}{"name.keyword": tests}},
+				},
+				"must_not":             map[string]interface{}{"term": map[string]interface{}{"state.keyword": AlertStateResolved}},
+				"minimum_should_match": 1,
+			},
+		},
+		"script": map[string]interface{}{
+			"source": "for (entry in params.fields.entrySet()) { ctx._source[entry.getKey()] = entry.getValue(); }",
+			"lang":   "painless",
+			"params": map[string]interface{}{"fields": fields},
+		},
+	})
+	if err != nil {
+		return 0, errors.Wrap(err, "unable to marshal alert update")
+	}
+
+	var result struct {
+		Updated int `json:"updated"`
+	}
+	if err := alerter.elasticRequest("/tm-alerter*/_update_by_query?refresh=true", http.MethodPost, string(payload), &result); err != nil {
+		return 0, errors.Wrap(err, "failed to update elasticsearch alerter items")
+	}
+	alerter.log.V(3).Info(fmt.Sprintf("updated %d alerts", result.Updated), "fields", fields)
+	return result.Updated, nil
+}
+
+// resolveRecoveredTests sets the alerts of the recovered tests to resolved
+func (alerter *Alert) resolveRecoveredTests(recoveredTests map[string]TestDetails) error {
+	if _, err := alerter.UpdateAlerts(sortedTestKeys(recoveredTests), AlertUpdate{State: AlertStateResolved, User: "testmachinery"}); err != nil {
+		return errors.Wrap(err, "failed to resolve recovered tests")
+	}
+	return nil
+}
+
+// GetOpenAlerts returns all filed alerts that are not resolved
+func (alerter *Alert) GetOpenAlerts() (map[string]TestDetails, error) {
+	filedAlerts, err := alerter.getFiledAlerts()
+	if err != nil {
+		return nil, err
+	}
+	return openAlerts(filedAlerts), nil
+}
+
+// openAlerts returns the latest filed alert of every test context that is not resolved
+func openAlerts(filedAlerts AlertDocs) map[string]TestDetails {
+	alerts := make(map[string]TestDetails)
+	for _, item := range filedAlerts.Hits.AlertItems {
+		test := item.Source
+		if !test.IsOpen() {
+			continue
+		}
+		if existing, ok := alerts[test.Context]; ok && existing.FiledAlertDataTime > test.FiledAlertDataTime {
+			continue
+		}
+		alerts[test.Context] = test
+	}
+	return alerts
+}
+
+// FindAlertsToEscalate returns all firing alerts that have not been acknowledged
+// within EscalationDays after they were filed and that have not been escalated yet.
+func (alerter *Alert) FindAlertsToEscalate(now time.Time) (map[string]TestDetails, error) {
+	if alerter.cfg.EscalationDays <= 0 {
+		return map[string]TestDetails{}, nil
+	}
+	alerts, err := alerter.GetOpenAlerts()
+	if err != nil {
+		return nil, err
+	}
+	return alerter.selectAlertsToEscalate(alerts, now), nil
+}
+
+func (alerter *Alert) selectAlertsToEscalate(alerts map[string]TestDetails, now time.Time) map[string]TestDetails {
+	escalate := make(map[string]TestDetails)
+	deadline := now.Add(-time.Duration(alerter.cfg.EscalationDays) * 24 * time.Hour)
+	for key, test := range alerts {
+		if test.CurrentState(now) != AlertStateFiring || test.EscalatedAt != "" {
+			continue
+		}
+		since, err := test.firingSince()
+		if err != nil {
+			alerter.log.Error(err, "unable to determine escalation of alert")
+			continue
+		}
+		if since.Before(deadline) {
+			escalate[key] = test
+		}
+	}
+	return escalate
+}
+
+// sendEscalationMessageToSlack posts the alerts that are firing for too long without acknowledgement to the escalation channel.
+func (alerter *Alert) sendEscalationMessageToSlack(client slack.Client, channel string, tests map[string]TestDetails) error {
+	if len(tests) == 0 {
+		alerter.log.Info("no alerts to escalate")
+		return nil
+	}
+	message := createLifecycleMessage(tests, time.Now())
+	for i, messageSplitItem := range splitSlackMessage(message, 3900) {
+		messagePrefix := ""
+		if i == 0 {
+			messagePrefix = fmt.Sprintf("*🚨 Testmachinery alerts not acknowledged within %d days:* \n", alerter.cfg.EscalationDays)
+		}
+		if err := client.PostMessage(channel, fmt.Sprintf("%s```%s```", messagePrefix, messageSplitItem)); err != nil {
+			return errors.Wrap(err, "failed to post a slack message")
+		}
+		time.Sleep(1200 * time.Millisecond) // need to wait 1 sec due to slack limits
+	}
+	alerter.log.Info("Sent slack escalation message", "escalated alerts", len(tests))
+	return nil
+}
+
+// markEscalated marks the alerts as escalated so that they are not escalated again.
+func (alerter *Alert) markEscalated(tests map[string]TestDetails) error {
+	if _, err := alerter.updateAlertDocs(sortedTestKeys(tests), map[string]interface{}{
+		"escalatedAt": time.Now().UTC().Format(time.RFC3339),
+	}); err != nil {
+		return errors.Wrap(err, "failed to mark alerts as escalated")
+	}
+	return nil
+}
+
+// PostSummaryToSlack posts a report of all open alerts and their lifecycle states
+func (alerter *Alert) PostSummaryToSlack(client slack.Client, channel string) error {
+	alerts, err := alerter.GetOpenAlerts()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	if len(alerts) == 0 {
+		return client.PostMessage(channel, "*📋 Testmachinery alert summary:* no open alerts")
+	}
+	counts := make(map[AlertState]int)
+	for _, test := range alerts {
+		counts[test.CurrentState(now)]++
+	}
+	messagePrefix := fmt.Sprintf("*📋 Testmachinery alert summary:* %d firing, %d acknowledged, %d snoozed \n",
+		counts[AlertStateFiring], counts[AlertStateAcknowledged], counts[AlertStateSnoozed])
+	for i, messageSplitItem := range splitSlackMessage(createLifecycleMessage(alerts, now), 3900) {
+		if i != 0 {
+			messagePrefix = ""
+		}
+		if err := client.PostMessage(channel, fmt.Sprintf("%s```%s```", messagePrefix, messageSplitItem)); err != nil {
+			return errors.Wrap(err, "failed to post a slack message")
+		}
+		time.Sleep(1200 * time.Millisecond) // need to wait 1 sec due to slack limits
+	}
+	alerter.log.Info("Sent slack alert summary", "open alerts", len(alerts))
+	return nil
+}
+
+// createLifecycleMessage creates a table of alerts with their lifecycle state, sorted by state and age
+func createLifecycleMessage(alerts map[string]TestDetails, now time.Time) string {
+	stateOrder := map[AlertState]int{AlertStateFiring: 0, AlertStateAcknowledged: 1, AlertStateSnoozed: 2, AlertStateResolved: 3}
+	keys := sortedTestKeys(alerts)
+	sort.SliceStable(keys, func(i, j int) bool {
+		a, b := alerts[keys[i]], alerts[keys[j]]
+		if stateOrder[a.CurrentState(now)] != stateOrder[b.CurrentState(now)] {
+			return stateOrder[a.CurrentState(now)] < stateOrder[b.CurrentState(now)]
+		}
+		return a.FiledAlertDataTime < b.FiledAlertDataTime
+	})
+
+	content := make([][]string, 0, len(keys))
+	for _, key := range keys {
+		test := alerts[key]
+		state := test.CurrentState(now)
+		details := "-"
+		switch state {
+		case AlertStateAcknowledged:
+			details = fmt.Sprintf("by %s", util.StringDefault(test.AcknowledgedBy, "-"))
+		case AlertStateSnoozed:
+			details = fmt.Sprintf("until %s", test.SnoozedUntil)
+		case AlertStateFiring:
+			if test.EscalatedAt != "" {
+				details = "escalated"
+			}
+		}
+		age := "-"
+		if filed, err := time.Parse(time.RFC3339, test.FiledAlertDataTime); err == nil {
+			age = fmt.Sprintf("%dd", int(now.Sub(filed).Hours()/24))
+		}
+		content = append(content, []string{
+			test.Name,
+			test.Landscape,
+			util.StringDefault(test.Cloudprovider, "-"),
+			util.StringDefault(test.K8sVersion, "-"),
+			util.StringDefault(test.OperatingSystem, "-"),
+			string(state),
+			details,
+			age,
+			util.StringDefault(test.Owner, "-"),
+		})
+	}
+	return renderTable([]string{"Test", "Landscape", "Provider", "K8s Ver", "OS", "State", "Details", "Age", "Owner"}, content)
+}
+
+// ExecuteCommand executes an alert lifecycle command of a user and returns the response message.
+// Supported commands are "ack <test>...", "snooze <duration> <test>...", "resolve <test>..." and "list".
+// Tests are referenced by their test name or test context.
+func (alerter *Alert) ExecuteCommand(text, user string, now time.Time) (string, error) {
+	args := strings.Fields(text)
+	if len(args) == 0 {
+		return commandUsage, nil
+	}
+	switch args[0] {
+	case "list":
+		alerts, err := alerter.GetOpenAlerts()
+		if err != nil {
+			return "", err
+		}
+		if len(alerts) == 0 {
+			return "There are no open alerts", nil
+		}
+		return fmt.Sprintf("```%s```", createLifecycleMessage(alerts, now)), nil
+	case "ack", "resolve":
+		if len(args) < 2 {
+			return "", fmt.Errorf("no test specified\n%s", commandUsage)
+		}
+		update := AlertUpdate{State: AlertStateAcknowledged, User: user}
+		if args[0] == "resolve" {
+			update.State = AlertStateResolved
+		}
+		n, err := alerter.updateAlertDocs(args[1:], update.fields(now))
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s %d alerts of %s", update.State, n, strings.Join(args[1:], ", ")), nil
+	case "snooze":
+		if len(args) < 3 {
+			return "", fmt.Errorf("no duration or test specified\n%s", commandUsage)
+		}
+		duration, err := ParseSnoozeDuration(args[1])
+		if err != nil {
+			return "", err
+		}
+		until := now.Add(duration)
+		n, err := alerter.updateAlertDocs(args[2:], AlertUpdate{State: AlertStateSnoozed, User: user, Until: &until}.fields(now))
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("snoozed %d alerts of %s until %s", n, strings.Join(args[2:], ", "), until.UTC().Format(time.RFC3339)), nil
+	default:
+		return "", fmt.Errorf("unknown command %q\n%s", args[0], commandUsage)
+	}
+}
+
+const commandUsage = "Usage: `ack <test>...`, `snooze <duration, e.g. 2d or 12h> <test>...`, `resolve <test>...` or `list`"
+
+// ParseSnoozeDuration parses a go duration that additionally supports days, e.g. "3d"
+func ParseSnoozeDuration(value string) (time.Duration, error) {
+	var days int
+	if _, err := fmt.Sscanf(value, "%dd", &days); err == nil && strings.HasSuffix(value, "d") {
+		if days <= 0 {
+			return 0, fmt.Errorf("snooze duration %q has to be positive", value)
+		}
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid snooze duration %q", value)
+	}
+	if duration <= 0 {
+		return 0, fmt.Errorf("snooze duration %q has to be positive", value)
+	}
+	return duration, nil
+}
@@ -0,0 +1,192 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("alert lifecycle", func() {
+
+	var (
+		now = time.Date(2024, 5, 17, 12, 0, 0, 0, time.UTC)
+		es  *fakeElasticsearch
+		a   *Alert
+	)
+
+	BeforeEach(func() {
+		es = &fakeElasticsearch{response: `{"updated": 2}`}
+		a = New(logr.Discard(), Config{ESClient: es, EscalationDays: 2})
+	})
+
+	Context("state", func() {
+		It("should treat alerts without state as firing", func() {
+			Expect(TestDetails{}.CurrentState(now)).To(Equal(AlertStateFiring))
+		})
+
+		It("should be firing again after the snooze expired", func() {
+			test := TestDetails{State: AlertStateSnoozed, SnoozedUntil: now.Add(time.Hour).Format(time.RFC3339)}
+			Expect(test.CurrentState(now)).To(Equal(AlertStateSnoozed))
+			Expect(test.suppresses(now)).To(BeTrue())
+			Expect(test.CurrentState(now.Add(2 * time.Hour))).To(Equal(AlertStateFiring))
+			Expect(test.suppresses(now.Add(2 * time.Hour))).To(BeFalse())
+		})
+
+		It("should only keep the latest open alert of a test context", func() {
+			docs := AlertDocs{}
+			for _, test := range []TestDetails{
+				{Context: "a", FiledAlertDataTime: "2024-05-01T00:00:00Z", State: AlertStateAcknowledged},
+				{Context: "a", FiledAlertDataTime: "2024-05-02T00:00:00Z"},
+				{Context: "b", State: AlertStateResolved},
+			} {
+				docs.Hits.AlertItems = append(docs.Hits.AlertItems, struct {
+					Source TestDetails `json:"_source"`
+				}{Source: test})
+			}
+			alerts := openAlerts(docs)
+			Expect(alerts).To(HaveLen(1))
+			Expect(alerts["a"].FiledAlertDataTime).To(Equal("2024-05-02T00:00:00Z"))
+		})
+	})
+
+	Context("escalation", func() {
+		It("should escalate alerts that are not acknowledged in time", func() {
+			alerts := map[string]TestDetails{
+				"old":          {Context: "old", FiledAlertDataTime: now.Add(-72 * time.Hour).Format(time.RFC3339), State: AlertStateFiring},
+				"new":          {Context: "new", FiledAlertDataTime: now.Add(-24 * time.Hour).Format(time.RFC3339), State: AlertStateFiring},
+				"acknowledged": {Context: "acknowledged", FiledAlertDataTime: now.Add(-72 * time.Hour).Format(time.RFC3339), State: AlertStateAcknowledged},
+				"escalated":    {Context: "escalated", FiledAlertDataTime: now.Add(-72 * time.Hour).Format(time.RFC3339), EscalatedAt: "2024-05-16T00:00:00Z"},
+				"snoozed": {Context: "snoozed", FiledAlertDataTime: now.Add(-96 * time.Hour).Format(time.RFC3339), State: AlertStateSnoozed,
+					SnoozedUntil: now.Add(-24 * time.Hour).Format(time.RFC3339)},
+			}
+			Expect(a.selectAlertsToEscalate(alerts, now)).To(HaveKey("old"))
+			Expect(a.selectAlertsToEscalate(alerts, now)).To(HaveLen(1))
+		})
+	})
+
+	Context("commands", func() {
+		It("should snooze alerts", func() {
+			msg, err := a.ExecuteCommand("snooze 2d test-a", "john", now)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(msg).To(Equal("snoozed 2 alerts of test-a until 2024-05-19T12:00:00Z"))
+
+			Expect(es.path).To(Equal("/tm-alerter*/_update_by_query?refresh=true"))
+			Expect(es.payload).To(HaveKeyWithValue("script", HaveKeyWithValue("params", HaveKeyWithValue("fields", Equal(map[string]interface{}{
+				"state":        "snoozed",
+				"snoozedBy":    "john",
+				"snoozedUntil": "2024-05-19T12:00:00Z",
+			})))))
+		})
+
+		It("should acknowledge alerts", func() {
+			_, err := a.ExecuteCommand("ack test-a test-b", "john", now)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(es.payload).To(HaveKeyWithValue("script", HaveKeyWithValue("params", HaveKeyWithValue("fields", HaveKeyWithValue("acknowledgedBy", "john")))))
+		})
+
+		It("should fail for unknown commands and invalid durations", func() {
+			_, err := a.ExecuteCommand("foo", "john", now)
+			Expect(err).To(HaveOccurred())
+			_, err = a.ExecuteCommand("snooze -1d test-a", "john", now)
+			Expect(err).To(HaveOccurred())
+			_, err = a.ExecuteCommand("ack", "john", now)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should parse snooze durations", func() {
+			Expect(ParseSnoozeDuration("3d")).To(Equal(72 * time.Hour))
+			Expect(ParseSnoozeDuration("90m")).To(Equal(90 * time.Minute))
+			_, err := ParseSnoozeDuration("3x")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("slack command", func() {
+		var handler *SlackCommandHandler
+
+		BeforeEach(func() {
+			handler = NewSlackCommandHandler(logr.Discard(), a, "secret")
+			handler.now = func() time.Time { return now }
+		})
+
+		request := func(timestamp time.Time, secret string) *httptest.ResponseRecorder {
+			body := url.Values{"text": {"ack test-a"}, "user_name": {"john"}}.Encode()
+			ts := strconv.FormatInt(timestamp.Unix(), 10)
+			req := httptest.NewRequest(http.MethodPost, "/alerts/slack", strings.NewReader(body))
+			req.Header.Set("X-Slack-Request-Timestamp", ts)
+			req.Header.Set("X-Slack-Signature", SlackSignature([]byte(secret), ts, []byte(body)))
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			return rec
+		}
+
+		It("should execute signed commands", func() {
+			rec := request(now, "secret")
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			response := slackCommandResponse{}
+			Expect(json.Unmarshal(rec.Body.Bytes(), &response)).To(Succeed())
+			Expect(response).To(Equal(slackCommandResponse{ResponseType: "in_channel", Text: "acknowledged 2 alerts of test-a"}))
+		})
+
+		It("should reject requests with an invalid signature", func() {
+			Expect(request(now, "other").Code).To(Equal(http.StatusUnauthorized))
+			Expect(es.path).To(BeEmpty())
+		})
+
+		It("should reject old requests", func() {
+			Expect(request(now.Add(-10*time.Minute), "secret").Code).To(Equal(http.StatusUnauthorized))
+		})
+	})
+})
+
+// fakeElasticsearch records the requested paths and the last json document of the payload and returns a fixed response
+type fakeElasticsearch struct {
+	response string
+	paths    []string
+	path     string
+	payload  map[string]interface{}
+}
+
+func (f *fakeElasticsearch) Request(_, path string, payload io.Reader) ([]byte, error) {
+	f.path = path
+	f.paths = append(f.paths, path)
+	data, err := io.ReadAll(payload)
+	if err != nil {
+		return nil, err
+	}
+	f.payload = map[string]interface{}{}
+	if len(data) == 0 {
+		return []byte(f.response), nil
+	}
+	if strings.HasSuffix(path, "/_bulk") {
+		// bulk payloads contain one json document per line
+		for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+			Expect(json.Unmarshal([]byte(line), &f.payload)).To(Succeed())
+		}
+		return []byte(f.response), nil
+	}
+	Expect(json.Unmarshal(data, &f.payload)).To(Succeed())
+	return []byte(f.response), nil
+}
+
+func (f *fakeElasticsearch) RequestWithCtx(_ context.Context, httpMethod, path string, payload io.Reader) ([]byte, error) {
+	return f.Request(httpMethod, path, payload)
+}
+
+func (f *fakeElasticsearch) Bulk(_ []byte) error { return nil }
+
+func (f *fakeElasticsearch) BulkFromFile(_ string) error { return nil }
@@ -0,0 +1,401 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package alert
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/gardener/test-infra/pkg/util"
+	"github.com/gardener/test-infra/pkg/util/slack"
+)
+
+// Notification contains the result of an alert evaluation that is sent to the outputs
+type Notification struct {
+	New       map[string]TestDetails // New failing tests that have not been alerted yet
+	Firing    map[string]TestDetails // Firing are all failing tests including the new ones whose alert is not snoozed
+	Recovered map[string]TestDetails // Recovered tests that have an open alert
+	Escalated map[string]TestDetails // Escalated are firing alerts that have not been acknowledged within the escalation days
+}
+
+// Output sends notifications about failing and recovered tests to a notification target
+type Output interface {
+	// Name returns the name of the output
+	Name() string
+	// Send sends the notification
+	Send(notification Notification) error
+}
+
+// Notify sends the notification to all outputs and updates the alert index afterwards:
+// new alerts are filed, the alerts of recovered tests are resolved and escalated alerts are marked as escalated.
+// The alert index is not updated if any output failed so that the notification is sent again with the next run.
+func (alerter *Alert) Notify(outputs []Output, notification Notification) error {
+	var allErrs *multierror.Error
+	for _, output := range outputs {
+		if err := output.Send(notification); err != nil {
+			allErrs = multierror.Append(allErrs, errors.Wrapf(err, "unable to send alerts to %s", output.Name()))
+		}
+	}
+	if allErrs != nil {
+		return util.ReturnMultiError(allErrs)
+	}
+
+	if len(notification.New) != 0 {
+		if err := alerter.filePostedAlerts(notification.New); err != nil {
+			allErrs = multierror.Append(allErrs, err)
+		}
+	}
+	if len(notification.Recovered) != 0 {
+		if err := alerter.resolveRecoveredTests(notification.Recovered); err != nil {
+			allErrs = multierror.Append(allErrs, err)
+		}
+	}
+	if len(notification.Escalated) != 0 {
+		if err := alerter.markEscalated(notification.Escalated); err != nil {
+			allErrs = multierror.Append(allErrs, err)
+		}
+	}
+	return util.ReturnMultiError(allErrs)
+}
+
+type slackOutput struct {
+	alerter           *Alert
+	client            slack.Client
+	channel           string
+	escalationChannel string
+	routing           *RoutingConfig
+}
+
+// NewSlackOutput creates an output that posts new and recovered tests to a slack channel and escalated alerts to the escalation channel.
+// If a routing config is given the new and recovered tests are routed to the owners of the tests instead.
+func NewSlackOutput(alerter *Alert, client slack.Client, channel, escalationChannel string, routing *RoutingConfig) Output {
+	return &slackOutput{
+		alerter:           alerter,
+		client:            client,
+		channel:           channel,
+		escalationChannel: escalationChannel,
+		routing:           routing,
+	}
+}
+
+func (o *slackOutput) Name() string {
+	return "slack"
+}
+
+func (o *slackOutput) Send(notification Notification) error {
+	if o.routing != nil {
+		if err := o.alerter.sendRoutedAlertMessagesToSlack(o.client, o.routing, notification.New); err != nil {
+			return err
+		}
+		if err := o.alerter.sendRoutedRecoverMessagesToSlack(o.client, o.routing, notification.Recovered); err != nil {
+			return err
+		}
+	} else {
+		if err := o.alerter.sendAlertMessageToSlack(o.client, o.channel, notification.New); err != nil {
+			return err
+		}
+		if err := o.alerter.sendRecoverMessageToSlack(o.client, o.channel, notification.Recovered); err != nil {
+			return err
+		}
+	}
+	return o.alerter.sendEscalationMessageToSlack(o.client, o.escalationChannel, notification.Escalated)
+}
+
+type alertmanagerOutput struct {
+	alerter *Alert
+	client  *http.Client
+	url     string
+	ttl     time.Duration
+	now     func() time.Time
+}
+
+// alertmanagerAlert is an alert of the alertmanager v2 api
+type alertmanagerAlert struct {
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	StartsAt    string            `json:"startsAt,omitempty"`
+	EndsAt      string            `json:"endsAt,omitempty"`
+}
+
+// NewAlertmanagerOutput creates an output that sends all firing tests to a Prometheus Alertmanager compatible api.
+// Firing tests are sent with every run and end after the ttl if they are not sent again,
+// so the ttl has to be longer than the interval of the alert runs; recovered tests are resolved.
+// Escalations are left to the routing of the alertmanager.
+func NewAlertmanagerOutput(alerter *Alert, url string, ttl time.Duration) Output {
+	return &alertmanagerOutput{
+		alerter: alerter,
+		client:  &http.Client{Timeout: 30 * time.Second},
+		url:     strings.TrimSuffix(url, "/") + "/api/v2/alerts",
+		ttl:     ttl,
+		now:     time.Now,
+	}
+}
+
+func (o *alertmanagerOutput) Name() string {
+	return "alertmanager"
+}
+
+func (o *alertmanagerOutput) Send(notification Notification) error {
+	now := o.now().UTC()
+	alerts := make([]alertmanagerAlert, 0, len(notification.Firing)+len(notification.Recovered))
+	for _, key := range sortedTestKeys(notification.Firing) {
+		alert := o.alert(notification.Firing[key])
+		alert.EndsAt = now.Add(o.ttl).Format(time.RFC3339)
+		alerts = append(alerts, alert)
+	}
+	for _, key := range sortedTestKeys(notification.Recovered) {
+		alert := o.alert(notification.Recovered[key])
+		alert.EndsAt = now.Format(time.RFC3339)
+		alerts = append(alerts, alert)
+	}
+	if len(alerts) == 0 {
+		return nil
+	}
+	if err := postJSON(o.client, o.url, nil, alerts); err != nil {
+		return err
+	}
+	o.alerter.log.Info("Sent alerts to alertmanager", "firing tests", len(notification.Firing), "recovered tests", len(notification.Recovered))
+	return nil
+}
+
+func (o *alertmanagerOutput) alert(test TestDetails) alertmanagerAlert {
+	alert := alertmanagerAlert{
+		Labels: map[string]string{
+			"alertname":   "TestmachineryTestFailing",
+			"test":        test.Name,
+			"landscape":   test.Landscape,
+			"provider":    test.Cloudprovider,
+			"k8s_version": test.K8sVersion,
+			"os":          test.OperatingSystem,
+		},
+		Annotations: map[string]string{
+			"summary":      fmt.Sprintf("Test %s is failing", test.Name),
+			"reason":       o.alerter.failureReason(test),
+			"success_rate": fmt.Sprintf("%d%%", int(test.SuccessRate)),
+			"last_failure": test.LastFailedTimestamp,
+			"testrun_id":   test.TestrunID,
+		},
+	}
+	if test.Owner != "" {
+		alert.Annotations["owner"] = test.Owner
+	}
+	return alert
+}
+
+type webhookOutput struct {
+	alerter *Alert
+	client  *http.Client
+	url     string
+	headers map[string]string
+}
+
+// WebhookAlert is a test alert that is sent to a webhook
+type WebhookAlert struct {
+	TestDetails
+	Reason string `json:"reason,omitempty"`
+}
+
+// WebhookPayload is the json payload that is sent to a webhook
+type WebhookPayload struct {
+	New       []WebhookAlert `json:"new"`
+	Firing    []WebhookAlert `json:"firing"`
+	Recovered []WebhookAlert `json:"recovered"`
+	Escalated []WebhookAlert `json:"escalated"`
+}
+
+// NewWebhookOutput creates an output that posts the notification as json to a generic webhook.
+// The given headers are added to every request, e.g. for authorization.
+func NewWebhookOutput(alerter *Alert, url string, headers map[string]string) Output {
+	return &webhookOutput{
+		alerter: alerter,
+		client:  &http.Client{Timeout: 30 * time.Second},
+		url:     url,
+		headers: headers,
+	}
+}
+
+func (o *webhookOutput) Name() string {
+	return "webhook"
+}
+
+func (o *webhookOutput) Send(notification Notification) error {
+	if len(notification.Firing) == 0 && len(notification.Recovered) == 0 && len(notification.Escalated) == 0 {
+		return nil
+	}
+	payload := WebhookPayload{
+		New:       o.alerts(notification.New),
+		Firing:    o.alerts(notification.Firing),
+		Recovered: o.alerts(notification.Recovered),
+		Escalated: o.alerts(notification.Escalated),
+	}
+	if err := postJSON(o.client, o.url, o.headers, payload); err != nil {
+		return err
+	}
+	o.alerter.log.Info("Sent alerts to webhook", "new tests", len(payload.New), "firing tests", len(payload.Firing),
+		"recovered tests", len(payload.Recovered), "escalated alerts", len(payload.Escalated))
+	return nil
+}
+
+func (o *webhookOutput) alerts(tests map[string]TestDetails) []WebhookAlert {
+	alerts := make([]WebhookAlert, 0, len(tests))
+	for _, key := range sortedTestKeys(tests) {
+		alert := WebhookAlert{TestDetails: tests[key]}
+		if !alert.Successful {
+			alert.Reason = o.alerter.failureReason(alert.TestDetails)
+		}
+		alerts = append(alerts, alert)
+	}
+	return alerts
+}
+
+// EmailConfig describes the smtp server and the recipients of alert emails
+type EmailConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	To       []string
+	// NotifyOwners additionally sends the owners and recipients of the tests an email with their tests
+	NotifyOwners bool
+}
+
+type emailOutput struct {
+	alerter  *Alert
+	cfg      EmailConfig
+	sendMail func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewEmailOutput creates an output that sends new and recovered tests and escalated alerts via smtp
+func NewEmailOutput(alerter *Alert, cfg EmailConfig) Output {
+	return &emailOutput{
+		alerter:  alerter,
+		cfg:      cfg,
+		sendMail: smtp.SendMail,
+	}
+}
+
+func (o *emailOutput) Name() string {
+	return "email"
+}
+
+func (o *emailOutput) Send(notification Notification) error {
+	if len(notification.New) == 0 && len(notification.Recovered) == 0 && len(notification.Escalated) == 0 {
+		return nil
+	}
+
+	var allErrs *multierror.Error
+	if len(o.cfg.To) != 0 {
+		if err := o.send(o.cfg.To, notification.New, notification.Recovered, notification.Escalated); err != nil {
+			allErrs = multierror.Append(allErrs, err)
+		}
+	}
+	if o.cfg.NotifyOwners {
+		newByOwner, recoveredByOwner, escalatedByOwner := testsByOwner(notification.New), testsByOwner(notification.Recovered), testsByOwner(notification.Escalated)
+		owners := sets.KeySet(newByOwner).Union(sets.KeySet(recoveredByOwner)).Union(sets.KeySet(escalatedByOwner)).Delete(o.cfg.To...)
+		for _, owner := range sets.List(owners) {
+			if err := o.send([]string{owner}, newByOwner[owner], recoveredByOwner[owner], escalatedByOwner[owner]); err != nil {
+				allErrs = multierror.Append(allErrs, err)
+			}
+		}
+	}
+	if err := util.ReturnMultiError(allErrs); err != nil {
+		return err
+	}
+	o.alerter.log.Info("Sent alert emails", "new tests", len(notification.New), "recovered tests", len(notification.Recovered), "escalated alerts", len(notification.Escalated))
+	return nil
+}
+
+func (o *emailOutput) send(to []string, newTests, recoveredTests, escalatedTests map[string]TestDetails) error {
+	var auth smtp.Auth
+	if o.cfg.Username != "" {
+		auth = smtp.PlainAuth("", o.cfg.Username, o.cfg.Password, o.cfg.Host)
+	}
+	addr := net.JoinHostPort(o.cfg.Host, strconv.Itoa(o.cfg.Port))
+	if err := o.sendMail(addr, auth, o.cfg.From, to, o.message(to, newTests, recoveredTests, escalatedTests)); err != nil {
+		return errors.Wrapf(err, "unable to send email to %s", strings.Join(to, ","))
+	}
+	return nil
+}
+
+func (o *emailOutput) message(to []string, newTests, recoveredTests, escalatedTests map[string]TestDetails) []byte {
+	subject := fmt.Sprintf("Testmachinery: %d new alerts, %d recovered tests", len(newTests), len(recoveredTests))
+	if len(escalatedTests) != 0 {
+		subject += fmt.Sprintf(", %d escalated alerts", len(escalatedTests))
+	}
+
+	msg := &bytes.Buffer{}
+	fmt.Fprintf(msg, "From: %s\r\n", o.cfg.From)
+	fmt.Fprintf(msg, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(msg, "Subject: %s\r\n", subject)
+	fmt.Fprintf(msg, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(msg, "Content-Type: text/plain; charset=\"utf-8\"\r\n\r\n")
+	if len(newTests) != 0 {
+		fmt.Fprintf(msg, "New Testmachinery Alerts:\r\n%s\r\n", createAlertMessage(newTests, o.alerter))
+	}
+	if len(recoveredTests) != 0 {
+		fmt.Fprintf(msg, "Testmachinery Tests Got Healthy:\r\n%s\r\n", createRecoverMessage(recoveredTests))
+	}
+	if len(escalatedTests) != 0 {
+		fmt.Fprintf(msg, "Testmachinery Alerts Not Acknowledged Within %d Days:\r\n%s\r\n",
+			o.alerter.cfg.EscalationDays, createLifecycleMessage(escalatedTests, time.Now()))
+	}
+	return msg.Bytes()
+}
+
+// testsByOwner groups tests by their owner and recipients
+func testsByOwner(tests map[string]TestDetails) map[string]map[string]TestDetails {
+	result := make(map[string]map[string]TestDetails)
+	for key, test := range tests {
+		for _, owner := range append([]string{test.Owner}, test.Recipients...) {
+			if owner == "" {
+				continue
+			}
+			if _, ok := result[owner]; !ok {
+				result[owner] = make(map[string]TestDetails)
+			}
+			result[owner][key] = test
+		}
+	}
+	return result
+}
+
+// postJSON posts the json encoded payload to the url and fails for non 2xx responses
+func postJSON(client *http.Client, url string, headers map[string]string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return errors.Wrap(err, "unable to marshal payload")
+	}
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return errors.Wrapf(err, "unable to create request for %s", url)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "unable to post to %s", url)
+	}
+	defer func() { _ = res.Body.Close() }()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return fmt.Errorf("unexpected response from %s: %d %s", url, res.StatusCode, string(body))
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package alert
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/gardener/test-infra/pkg/util/slack"
+)
+
+var _ = Describe("alert outputs", func() {
+
+	var (
+		now          = time.Date(2024, 5, 17, 12, 0, 0, 0, time.UTC)
+		a            *Alert
+		es           *fakeElasticsearch
+		notification Notification
+		server       *httptest.Server
+		requests     []*http.Request
+		bodies       [][]byte
+	)
+
+	BeforeEach(func() {
+		es = &fakeElasticsearch{response: `{}`}
+		a = New(logr.Discard(), Config{ESClient: es, SuccessRateThresholdPercent: 50, ContinuousFailureThreshold: 3})
+		failing := TestDetails{Name: "a", Context: "a_aws", Landscape: "dev", Cloudprovider: "aws", K8sVersion: "1.30", OperatingSystem: "gardenlinux", SuccessRate: 20, Owner: "owner@example.com"}
+		recovered := TestDetails{Name: "b", Context: "b_gcp", Landscape: "dev", Cloudprovider: "gcp", Successful: true, SuccessRate: 100, Recipients: []string{"team@example.com"}}
+		notification = Notification{
+			New:       map[string]TestDetails{"a_aws": failing},
+			Firing:    map[string]TestDetails{"a_aws": failing},
+			Recovered: map[string]TestDetails{"b_gcp": recovered},
+		}
+		requests, bodies = nil, nil
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body := make([]byte, r.ContentLength)
+			_, _ = r.Body.Read(body)
+			requests = append(requests, r)
+			bodies = append(bodies, body)
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should send firing and resolved alerts to the alertmanager", func() {
+		output := NewAlertmanagerOutput(a, server.URL+"/", 3*time.Hour)
+		output.(*alertmanagerOutput).now = func() time.Time { return now }
+		Expect(output.Send(notification)).To(Succeed())
+
+		Expect(requests).To(HaveLen(1))
+		Expect(requests[0].URL.Path).To(Equal("/api/v2/alerts"))
+		alerts := make([]alertmanagerAlert, 0)
+		Expect(json.Unmarshal(bodies[0], &alerts)).To(Succeed())
+		Expect(alerts).To(HaveLen(2))
+		Expect(alerts[0].Labels).To(Equal(map[string]string{
+			"alertname":   "TestmachineryTestFailing",
+			"test":        "a",
+			"landscape":   "dev",
+			"provider":    "aws",
+			"k8s_version": "1.30",
+			"os":          "gardenlinux",
+		}))
+		Expect(alerts[0].Annotations).To(HaveKeyWithValue("reason", "success rate < 50%"))
+		Expect(alerts[0].EndsAt).To(Equal("2024-05-17T15:00:00Z"))
+		Expect(alerts[1].Labels).To(HaveKeyWithValue("test", "b"))
+		Expect(alerts[1].EndsAt).To(Equal("2024-05-17T12:00:00Z"))
+	})
+
+	It("should post the notification to a webhook", func() {
+		Expect(NewWebhookOutput(a, server.URL, map[string]string{"Authorization": "Bearer abc"}).Send(notification)).To(Succeed())
+
+		Expect(requests).To(HaveLen(1))
+		Expect(requests[0].Header.Get("Authorization")).To(Equal("Bearer abc"))
+		payload := WebhookPayload{}
+		Expect(json.Unmarshal(bodies[0], &payload)).To(Succeed())
+		Expect(payload.New).To(HaveLen(1))
+		Expect(payload.New[0].Context).To(Equal("a_aws"))
+		Expect(payload.New[0].Reason).To(Equal("success rate < 50%"))
+		Expect(payload.Firing).To(HaveLen(1))
+		Expect(payload.Recovered).To(HaveLen(1))
+		Expect(payload.Recovered[0].Reason).To(BeEmpty())
+		Expect(payload.Escalated).To(BeEmpty())
+	})
+
+	It("should fail for unsuccessful webhook responses", func() {
+		server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		})
+		Expect(NewWebhookOutput(a, server.URL, nil).Send(notification)).ToNot(Succeed())
+	})
+
+	It("should send emails to the recipients and the owners", func() {
+		type mail struct {
+			addr string
+			to   []string
+			msg  string
+		}
+		mails := make([]mail, 0)
+		output := NewEmailOutput(a, EmailConfig{Host: "smtp.example.com", Port: 25, From: "tm@example.com", To: []string{"all@example.com"}, NotifyOwners: true})
+		output.(*emailOutput).sendMail = func(addr string, _ smtp.Auth, _ string, to []string, msg []byte) error {
+			mails = append(mails, mail{addr: addr, to: to, msg: string(msg)})
+			return nil
+		}
+		Expect(output.Send(notification)).To(Succeed())
+
+		Expect(mails).To(HaveLen(3))
+		Expect(mails[0].addr).To(Equal("smtp.example.com:25"))
+		Expect(mails[0].to).To(Equal([]string{"all@example.com"}))
+		Expect(mails[0].msg).To(ContainSubstring("Subject: Testmachinery: 1 new alerts, 1 recovered tests"))
+		Expect(mails[1].to).To(Equal([]string{"owner@example.com"}))
+		Expect(mails[1].msg).To(ContainSubstring("Subject: Testmachinery: 1 new alerts, 0 recovered tests"))
+		Expect(mails[2].to).To(Equal([]string{"team@example.com"}))
+		Expect(mails[2].msg).To(ContainSubstring("Subject: Testmachinery: 0 new alerts, 1 recovered tests"))
+	})
+
+	It("should send escalated alerts via email", func() {
+		var msgs []string
+		a.cfg.EscalationDays = 3
+		output := NewEmailOutput(a, EmailConfig{Host: "smtp.example.com", Port: 25, From: "tm@example.com", To: []string{"all@example.com"}})
+		output.(*emailOutput).sendMail = func(_ string, _ smtp.Auth, _ string, _ []string, msg []byte) error {
+			msgs = append(msgs, string(msg))
+			return nil
+		}
+		Expect(output.Send(Notification{Escalated: notification.Firing})).To(Succeed())
+
+		Expect(msgs).To(HaveLen(1))
+		Expect(msgs[0]).To(ContainSubstring("Subject: Testmachinery: 0 new alerts, 0 recovered tests, 1 escalated alerts"))
+		Expect(msgs[0]).To(ContainSubstring("Testmachinery Alerts Not Acknowledged Within 3 Days"))
+		Expect(msgs[0]).To(ContainSubstring("owner@example.com"))
+	})
+
+	It("should post escalated alerts to the slack escalation channel", func() {
+		client := &fakeSlack{}
+		output := NewSlackOutput(a, client, "alerts", "escalations", nil)
+		Expect(output.Send(Notification{Escalated: notification.Firing})).To(Succeed())
+
+		Expect(client.channels).To(Equal([]string{"escalations"}))
+		Expect(es.paths).To(BeEmpty())
+	})
+
+	It("should file the alerts that are posted to slack when notifying", func() {
+		client := &fakeSlack{}
+		output := NewSlackOutput(a, client, "alerts", "escalations", nil)
+		Expect(a.Notify([]Output{output}, Notification{New: notification.New})).To(Succeed())
+
+		Expect(client.channels).To(Equal([]string{"alerts"}))
+		Expect(es.paths).To(Equal([]string{"/tm-alerter/_bulk"}))
+	})
+
+	Context("notify", func() {
+		It("should file new alerts and resolve recovered tests after sending", func() {
+			Expect(a.Notify([]Output{NewWebhookOutput(a, server.URL, nil)}, notification)).To(Succeed())
+			Expect(requests).To(HaveLen(1))
+			Expect(es.paths).To(Equal([]string{"/tm-alerter/_bulk", "/tm-alerter*/_update_by_query?refresh=true"}))
+		})
+
+		It("should not update the alert index if an output failed", func() {
+			Expect(a.Notify([]Output{NewWebhookOutput(a, server.URL, nil), failingOutput{}}, notification)).ToNot(Succeed())
+			Expect(requests).To(HaveLen(1))
+			Expect(es.paths).To(BeEmpty())
+		})
+
+		It("should mark escalated alerts after sending", func() {
+			Expect(a.Notify([]Output{NewWebhookOutput(a, server.URL, nil)}, Notification{Escalated: notification.Firing})).To(Succeed())
+			Expect(requests).To(HaveLen(1))
+			Expect(es.paths).To(Equal([]string{"/tm-alerter*/_update_by_query?refresh=true"}))
+			Expect(es.payload).To(HaveKeyWithValue("script", HaveKeyWithValue("params", HaveKeyWithValue("fields", HaveKey("escalatedAt")))))
+		})
+	})
+})
+
+type failingOutput struct{}
+
+func (failingOutput) Name() string { return "failing" }
+
+func (failingOutput) Send(_ Notification) error { return errors.New("failed") }
+
+// fakeSlack records the channels messages are posted to
+type fakeSlack struct {
+	channels []string
+}
+
+func (f *fakeSlack) PostMessage(channel string, _ string) error {
+	f.channels = append(f.channels, channel)
+	return nil
+}
+
+func (f *fakeSlack) PostRawMessage(_ slack.MessageRequest) error { return nil }
@@ -0,0 +1,326 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package alert
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/yaml"
+
+	"github.com/gardener/test-infra/pkg/util"
+	"github.com/gardener/test-infra/pkg/util/slack"
+)
+
+// RoutingRule describes why an alert is routed to a target
+type RoutingRule string
+
+const (
+	// RoutingRuleOwner routes the alert to the owner of the test definition
+	RoutingRuleOwner RoutingRule = "owner"
+	// RoutingRuleRecipient routes the alert to a recipient on failure of the test definition
+	RoutingRuleRecipient RoutingRule = "recipient"
+	// RoutingRuleTeam routes the alert to a team whose label is set on the test definition
+	RoutingRuleTeam RoutingRule = "team"
+	// RoutingRuleDefault routes the alert to the default channel
+	RoutingRuleDefault RoutingRule = "default"
+)
+
+// RoutingConfig describes how alerts are routed to the owners and recipients of the failing tests
+type RoutingConfig struct {
+	// Recipients maps email addresses of test owners and recipients to slack user ids or channels
+	Recipients map[string]string `json:"recipients,omitempty"`
+	// Teams routes the alerts of tests with one of the team labels to the channel of the team
+	Teams []TeamRoute `json:"teams,omitempty"`
+	// DefaultChannel receives all alerts that cannot be routed to an owner, a recipient or a team
+	DefaultChannel string `json:"defaultChannel,omitempty"`
+	// AlwaysNotifyDefaultChannel additionally sends all alerts to the default channel
+	AlwaysNotifyDefaultChannel bool `json:"alwaysNotifyDefaultChannel,omitempty"`
+}
+
+// TeamRoute maps test definition labels to the slack channel of a team
+type TeamRoute struct {
+	Name    string   `json:"name"`
+	Labels  []string `json:"labels"`
+	Channel string   `json:"channel"`
+}
+
+// RoutingTarget is a slack user id or channel an alert is sent to
+type RoutingTarget struct {
+	Target string      `json:"target"`
+	Rule   RoutingRule `json:"rule"`
+	Via    string      `json:"via,omitempty"` // Via is the email address or team name that matched the rule
+}
+
+// RoutingDecision describes to which targets the alert of a test context is routed
+type RoutingDecision struct {
+	Test       TestDetails     `json:"test"`
+	Targets    []RoutingTarget `json:"targets"`
+	Unresolved []string        `json:"unresolved,omitempty"` // Unresolved email addresses that have no configured target
+}
+
+// Digest contains all alerts that are sent to one target
+type Digest struct {
+	Target string
+	Tests  map[string]TestDetails
+}
+
+// ReadRoutingConfig reads and validates a routing configuration file
+func ReadRoutingConfig(path string) (*RoutingConfig, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read routing config from %s", path)
+	}
+	config := &RoutingConfig{}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, errors.Wrapf(err, "unable to parse routing config %s", path)
+	}
+	if err := config.Validate(); err != nil {
+		return nil, errors.Wrapf(err, "invalid routing config %s", path)
+	}
+	return config, nil
+}
+
+// Validate validates the routing configuration
+func (c *RoutingConfig) Validate() error {
+	var allErrs *multierror.Error
+	for email, target := range c.Recipients {
+		if target == "" {
+			allErrs = multierror.Append(allErrs, fmt.Errorf("recipients.%s: target must not be empty", email))
+		}
+	}
+	for i, team := range c.Teams {
+		if team.Name == "" {
+			allErrs = multierror.Append(allErrs, fmt.Errorf("teams[%d].name: name must not be empty", i))
+		}
+		if team.Channel == "" {
+			allErrs = multierror.Append(allErrs, fmt.Errorf("teams[%d].channel: channel must not be empty", i))
+		}
+		if len(team.Labels) == 0 {
+			allErrs = multierror.Append(allErrs, fmt.Errorf("teams[%d].labels: at least one label has to be defined", i))
+		}
+	}
+	return util.ReturnMultiError(allErrs)
+}
+
+// Route decides to which targets the alerts of the given tests are sent.
+// Every target is only notified once per test context even if several rules match.
+// Tests that cannot be routed to any owner, recipient or team are sent to the default channel.
+func (c *RoutingConfig) Route(tests map[string]TestDetails) ([]RoutingDecision, []Digest) {
+	decisions := make([]RoutingDecision, 0, len(tests))
+	digests := make(map[string]*Digest)
+	for _, key := range sortedTestKeys(tests) {
+		decision := c.route(tests[key])
+		for _, target := range decision.Targets {
+			digest, ok := digests[target.Target]
+			if !ok {
+				digest = &Digest{Target: target.Target, Tests: make(map[string]TestDetails)}
+				digests[target.Target] = digest
+			}
+			digest.Tests[key] = decision.Test
+		}
+		decisions = append(decisions, decision)
+	}
+
+	result := make([]Digest, 0, len(digests))
+	for _, target := range sets.List(sets.KeySet(digests)) {
+		result = append(result, *digests[target])
+	}
+	return decisions, result
+}
+
+func (c *RoutingConfig) route(test TestDetails) RoutingDecision {
+	var (
+		decision = RoutingDecision{Test: test, Targets: make([]RoutingTarget, 0)}
+		seen     = sets.New[string]()
+	)
+	add := func(target string, rule RoutingRule, via string) {
+		if target == "" || seen.Has(target) {
+			return
+		}
+		seen.Insert(target)
+		decision.Targets = append(decision.Targets, RoutingTarget{Target: target, Rule: rule, Via: via})
+	}
+	resolve := func(email string, rule RoutingRule) {
+		if email == "" {
+			return
+		}
+		target, ok := c.Recipients[email]
+		if !ok {
+			decision.Unresolved = append(decision.Unresolved, email)
+			return
+		}
+		add(target, rule, email)
+	}
+
+	resolve(test.Owner, RoutingRuleOwner)
+	for _, recipient := range test.Recipients {
+		resolve(recipient, RoutingRuleRecipient)
+	}
+	labels := sets.New(test.Labels...)
+	for _, team := range c.Teams {
+		if labels.HasAny(team.Labels...) {
+			add(team.Channel, RoutingRuleTeam, team.Name)
+		}
+	}
+	if len(decision.Targets) == 0 || c.AlwaysNotifyDefaultChannel {
+		add(c.DefaultChannel, RoutingRuleDefault, "")
+	}
+	return decision
+}
+
+// sendRoutedAlertMessagesToSlack posts one alert digest to every target the failed tests are routed to
+func (alerter *Alert) sendRoutedAlertMessagesToSlack(client slack.Client, routing *RoutingConfig, failedTests map[string]TestDetails) error {
+	if len(failedTests) == 0 {
+		alerter.log.Info("no new failed tests found, nothing to post in slack")
+		return nil
+	}
+	decisions, digests := routing.Route(failedTests)
+	alerter.logUnroutedTests(decisions)
+	for _, digest := range digests {
+		if err := alerter.postAlertMessage(client, digest.Target, createDigestMessage(digest.Tests, alerter)); err != nil {
+			return errors.Wrapf(err, "failed to post alert digest to %s", digest.Target)
+		}
+		alerter.log.Info("Sent slack alerter digest", "target", digest.Target, "failing tests", len(digest.Tests))
+	}
+	return nil
+}
+
+// sendRoutedRecoverMessagesToSlack posts one recover message to every target the recovered tests are routed to
+func (alerter *Alert) sendRoutedRecoverMessagesToSlack(client slack.Client, routing *RoutingConfig, recoveredTests map[string]TestDetails) error {
+	if len(recoveredTests) == 0 {
+		alerter.log.Info("no new recovered tests found, nothing to post in slack")
+		return nil
+	}
+	decisions, digests := routing.Route(recoveredTests)
+	alerter.logUnroutedTests(decisions)
+	for _, digest := range digests {
+		if err := alerter.postRecoverMessage(client, digest.Target, createRecoverMessage(digest.Tests)); err != nil {
+			return errors.Wrapf(err, "failed to post recover message to %s", digest.Target)
+		}
+		alerter.log.Info("Sent slack recover message", "target", digest.Target, "recovered tests", len(digest.Tests))
+	}
+	return nil
+}
+
+// logUnroutedTests logs the tests that are not routed to any target and returns their number.
+// This happens if no rule matches and no default channel is configured.
+func (alerter *Alert) logUnroutedTests(decisions []RoutingDecision) int {
+	unrouted := 0
+	for _, decision := range decisions {
+		if len(decision.Targets) != 0 {
+			continue
+		}
+		unrouted++
+		alerter.log.Info("test cannot be routed to any target, no message is sent", "test", decision.Test.Name, "context", decision.Test.Context, "unresolved", decision.Unresolved)
+	}
+	if unrouted != 0 {
+		alerter.log.Info("tests without routing target, configure a default channel to receive them", "unrouted tests", unrouted)
+	}
+	return unrouted
+}
+
+// createDigestMessage creates an alert message that contains every failing test only once.
+// The dimensions of all failing contexts of a test are merged into one row.
+func createDigestMessage(failedTests map[string]TestDetails, alert *Alert) string {
+	byName := make(map[string][]TestDetails)
+	for _, key := range sortedTestKeys(failedTests) {
+		test := failedTests[key]
+		byName[test.Name] = append(byName[test.Name], test)
+	}
+
+	content := make([][]string, 0, len(byName))
+	for _, name := range sets.List(sets.KeySet(byName)) {
+		var (
+			tests                                = byName[name]
+			landscapes, providers, versions, oss = sets.New[string](), sets.New[string](), sets.New[string](), sets.New[string]()
+			reasons                              = sets.New[string]()
+			minSuccessRate                       = tests[0].SuccessRate
+			lastFailure                          = ""
+		)
+		for _, test := range tests {
+			landscapes.Insert(test.Landscape)
+			providers.Insert(util.StringDefault(test.Cloudprovider, "-"))
+			versions.Insert(util.StringDefault(test.K8sVersion, "-"))
+			oss.Insert(util.StringDefault(test.OperatingSystem, "-"))
+			if reason := alert.failureReason(test); reason != "" {
+				reasons.Insert(reason)
+			}
+			if test.SuccessRate < minSuccessRate {
+				minSuccessRate = test.SuccessRate
+			}
+			if test.LastFailedTimestamp > lastFailure {
+				lastFailure = test.LastFailedTimestamp
+			}
+		}
+		content = append(content, []string{
+			name,
+			fmt.Sprintf("%d", len(tests)),
+			strings.Join(sets.List(landscapes), ","),
+			strings.Join(sets.List(providers), ","),
+			strings.Join(sets.List(versions), ","),
+			strings.Join(sets.List(oss), ","),
+			fmt.Sprintf("%d%%", int(minSuccessRate)),
+			strings.Join(sets.List(reasons), ","),
+			lastFailure,
+		})
+	}
+
+	return renderTable([]string{"Test", "Contexts", "Landscape", "Provider", "K8s Ver", "OS", "Min Success", "Alert Reason", "Last failure"}, content)
+}
+
+// RenderRoutingDecisions renders a table of the routing decisions and the resulting digests
+func RenderRoutingDecisions(decisions []RoutingDecision, digests []Digest) string {
+	content := make([][]string, 0, len(decisions))
+	for _, decision := range decisions {
+		targets := make([]string, 0, len(decision.Targets))
+		for _, target := range decision.Targets {
+			if target.Via != "" {
+				targets = append(targets, fmt.Sprintf("%s (%s %s)", target.Target, target.Rule, target.Via))
+				continue
+			}
+			targets = append(targets, fmt.Sprintf("%s (%s)", target.Target, target.Rule))
+		}
+		content = append(content, []string{
+			decision.Test.Context,
+			util.StringDefault(decision.Test.Owner, "-"),
+			util.StringDefault(strings.Join(decision.Test.Recipients, ","), "-"),
+			util.StringDefault(strings.Join(decision.Test.Labels, ","), "-"),
+			util.StringDefault(strings.Join(targets, ","), "-"),
+			util.StringDefault(strings.Join(decision.Unresolved, ","), "-"),
+		})
+	}
+	decisionTable := renderTable([]string{"Test Context", "Owner", "Recipients", "Labels", "Targets", "Unresolved"}, content)
+
+	content = make([][]string, 0, len(digests))
+	for _, digest := range digests {
+		names := sets.New[string]()
+		for _, test := range digest.Tests {
+			names.Insert(test.Name)
+		}
+		content = append(content, []string{
+			digest.Target,
+			fmt.Sprintf("%d", names.Len()),
+			fmt.Sprintf("%d", len(digest.Tests)),
+		})
+	}
+	digestTable := renderTable([]string{"Target", "Tests", "Contexts"}, content)
+	return decisionTable + "\n" + digestTable
+}
+
+func sortedTestKeys(tests map[string]TestDetails) []string {
+	keys := make([]string, 0, len(tests))
+	for k := range tests {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,105 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package alert
+
+import (
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("alert routing", func() {
+
+	var (
+		routing *RoutingConfig
+		tests   map[string]TestDetails
+	)
+
+	BeforeEach(func() {
+		routing = &RoutingConfig{
+			Recipients: map[string]string{
+				"owner@example.com":     "U1",
+				"recipient@example.com": "U2",
+				"team@example.com":      "C-team",
+			},
+			Teams: []TeamRoute{
+				{Name: "networking", Labels: []string{"networking"}, Channel: "C-team"},
+			},
+			DefaultChannel: "C-default",
+		}
+		tests = map[string]TestDetails{
+			"a_aws": {Name: "a", Context: "a_aws", Cloudprovider: "aws", Owner: "owner@example.com", Recipients: []string{"recipient@example.com"}},
+			"a_gcp": {Name: "a", Context: "a_gcp", Cloudprovider: "gcp", Owner: "owner@example.com", Recipients: []string{"recipient@example.com"}},
+			"b_aws": {Name: "b", Context: "b_aws", Cloudprovider: "aws", Owner: "team@example.com", Labels: []string{"networking"}},
+			"c_aws": {Name: "c", Context: "c_aws", Cloudprovider: "aws", Owner: "unknown@example.com"},
+		}
+	})
+
+	It("should route alerts to owners, recipients and teams", func() {
+		decisions, digests := routing.Route(tests)
+		Expect(decisions).To(HaveLen(4))
+		Expect(decisions[0].Targets).To(Equal([]RoutingTarget{
+			{Target: "U1", Rule: RoutingRuleOwner, Via: "owner@example.com"},
+			{Target: "U2", Rule: RoutingRuleRecipient, Via: "recipient@example.com"},
+		}))
+
+		targets := make(map[string][]string)
+		for _, digest := range digests {
+			for key := range digest.Tests {
+				targets[digest.Target] = append(targets[digest.Target], key)
+			}
+		}
+		Expect(targets).To(HaveLen(4))
+		Expect(targets["U1"]).To(ConsistOf("a_aws", "a_gcp"))
+		Expect(targets["U2"]).To(ConsistOf("a_aws", "a_gcp"))
+		Expect(targets["C-default"]).To(ConsistOf("c_aws"))
+	})
+
+	It("should notify a target only once if several rules match", func() {
+		decisions, _ := routing.Route(tests)
+		Expect(decisions[2].Targets).To(Equal([]RoutingTarget{
+			{Target: "C-team", Rule: RoutingRuleOwner, Via: "team@example.com"},
+		}))
+	})
+
+	It("should route unresolved tests to the default channel", func() {
+		decisions, _ := routing.Route(tests)
+		Expect(decisions[3].Unresolved).To(ConsistOf("unknown@example.com"))
+		Expect(decisions[3].Targets).To(Equal([]RoutingTarget{{Target: "C-default", Rule: RoutingRuleDefault}}))
+	})
+
+	It("should additionally notify the default channel", func() {
+		routing.AlwaysNotifyDefaultChannel = true
+		_, digests := routing.Route(tests)
+		Expect(digests[0].Target).To(Equal("C-default"))
+		Expect(digests[0].Tests).To(HaveLen(4))
+	})
+
+	It("should count the tests that cannot be routed without a default channel", func() {
+		routing.DefaultChannel = ""
+		decisions, digests := routing.Route(tests)
+		Expect(decisions[3].Targets).To(BeEmpty())
+		Expect(digests).To(HaveLen(3))
+
+		alerter := New(logr.Discard(), Config{})
+		Expect(alerter.logUnroutedTests(decisions)).To(Equal(1))
+	})
+
+	It("should deduplicate the contexts of a test in a digest", func() {
+		alerter := New(logr.Discard(), Config{SuccessRateThresholdPercent: 50})
+		message := createDigestMessage(map[string]TestDetails{
+			"a_aws": tests["a_aws"],
+			"a_gcp": tests["a_gcp"],
+		}, alerter)
+		Expect(message).To(ContainSubstring("aws,gcp"))
+		Expect(message).To(MatchRegexp(`\|\s+a\s+\|\s+2\s+\|`))
+	})
+
+	It("should fail for an invalid config", func() {
+		routing.Teams = append(routing.Teams, TeamRoute{Name: "foo"})
+		routing.Recipients["foo@example.com"] = ""
+		Expect(routing.Validate()).To(HaveOccurred())
+	})
+})
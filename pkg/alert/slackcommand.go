@@ -0,0 +1,108 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package alert
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+)
+
+// maxSlackRequestAge is the maximum age of a slack request to protect against replay attacks
+const maxSlackRequestAge = 5 * time.Minute
+
+// SlackCommandHandler serves a slack slash command that acknowledges, snoozes and resolves alerts
+type SlackCommandHandler struct {
+	log           logr.Logger
+	alerter       *Alert
+	signingSecret []byte
+	now           func() time.Time
+}
+
+// NewSlackCommandHandler creates a new handler for the alert slack slash command.
+// The requests are verified with the signing secret of the slack app.
+func NewSlackCommandHandler(log logr.Logger, alerter *Alert, signingSecret string) *SlackCommandHandler {
+	return &SlackCommandHandler{
+		log:           log,
+		alerter:       alerter,
+		signingSecret: []byte(signingSecret),
+		now:           time.Now,
+	}
+}
+
+type slackCommandResponse struct {
+	ResponseType string `json:"response_type"`
+	Text         string `json:"text"`
+}
+
+func (h *SlackCommandHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		http.Error(w, "unable to read request", http.StatusBadRequest)
+		return
+	}
+	if err := h.verify(r.Header, body); err != nil {
+		h.log.V(3).Info("slack request verification failed", "error", err.Error())
+		http.Error(w, "verification failed", http.StatusUnauthorized)
+		return
+	}
+	values, err := url.ParseQuery(string(body))
+	if err != nil {
+		http.Error(w, "unable to parse request", http.StatusBadRequest)
+		return
+	}
+
+	user := values.Get("user_name")
+	response := slackCommandResponse{ResponseType: "in_channel"}
+	response.Text, err = h.alerter.ExecuteCommand(values.Get("text"), user, h.now())
+	if err != nil {
+		h.log.V(3).Info("alert command failed", "user", user, "text", values.Get("text"), "error", err.Error())
+		response = slackCommandResponse{ResponseType: "ephemeral", Text: err.Error()}
+	} else {
+		h.log.Info("executed alert command", "user", user, "text", values.Get("text"))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.log.Error(err, "unable to write slack response")
+	}
+}
+
+// verify verifies the signature of a slack request.
+// See https://api.slack.com/authentication/verifying-requests-from-slack
+func (h *SlackCommandHandler) verify(header http.Header, body []byte) error {
+	timestamp := header.Get("X-Slack-Request-Timestamp")
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return errors.Wrap(err, "invalid request timestamp")
+	}
+	if math.Abs(h.now().Sub(time.Unix(seconds, 0)).Seconds()) > maxSlackRequestAge.Seconds() {
+		return errors.New("request timestamp is too old")
+	}
+	expected := SlackSignature(h.signingSecret, timestamp, body)
+	if !hmac.Equal([]byte(expected), []byte(header.Get("X-Slack-Signature"))) {
+		return errors.New("invalid signature")
+	}
+	return nil
+}
+
+// SlackSignature computes the slack request signature of a body with the given timestamp
+func SlackSignature(signingSecret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, signingSecret)
+	mac.Write(bytes.Join([][]byte{[]byte("v0"), []byte(timestamp), body}, []byte(":")))
+	return fmt.Sprintf("v0=%s", hex.EncodeToString(mac.Sum(nil)))
+}
@@ -17,6 +17,7 @@ type BotConfiguration struct {
 	Webserver       Webserver `json:"webserver"`
 	Dashboard       Dashboard `json:"dashboard"`
 	GitHubBot       GitHubBot `json:"githubBot"`
+	Alerting        Alerting  `json:"alerting"`
 }
 
 // Webserver configures the webserver that servres the bot and the dashboard
@@ -126,3 +127,15 @@ type GitHubBot struct {
 	// GitHubCache configures the cache for the github api
 	GitHubCache GitHubCache `json:"cache"`
 }
+
+// Alerting contains the configuration for the slack command to acknowledge, snooze and resolve test alerts
+type Alerting struct {
+	// Enabled defines if the slack command should be served at /alerts/slack
+	Enabled bool `json:"enabled"`
+
+	// SlackSigningSecret is the signing secret of the slack app that is used to verify the slack requests
+	SlackSigningSecret string `json:"slackSigningSecret"`
+
+	// ElasticSearch holds the connection to the elasticsearch instance that stores the alerts
+	ElasticSearch ElasticSearch `json:"elasticsearch"`
+}
@@ -0,0 +1,48 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	tmv1beta1 "github.com/gardener/test-infra/pkg/apis/testmachinery/v1beta1"
+)
+
+// Defaulting configures the defaults that the mutating webhook applies to new testruns.
+type Defaulting struct {
+	// Profiles are the default profiles that are applied to testruns of their namespaces.
+	// All matching profiles are applied in order so that a field is defaulted by the first profile that defines it.
+	Profiles []DefaultProfile `json:"profiles"`
+}
+
+// DefaultProfile defines defaults for testruns of specific namespaces.
+// Only fields that are not set by the testrun are defaulted.
+type DefaultProfile struct {
+	// Name of the profile.
+	Name string `json:"name"`
+
+	// Namespaces is a list of namespaces whose testruns the profile is applied to.
+	// The profile is applied to testruns of all namespaces if no namespace is defined.
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// TTLSecondsAfterFinished is the default ttl of testruns.
+	// +optional
+	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty"`
+
+	// LocationSets are the default location sets of testruns that define neither location sets nor test locations.
+	// +optional
+	LocationSets []tmv1beta1.LocationSet `json:"locationSets,omitempty"`
+
+	// Config are global config elements that are added to testruns that do not define a config element with the same name.
+	// +optional
+	Config []tmv1beta1.ConfigElement `json:"config,omitempty"`
+
+	// Annotations are added to testruns that do not define the annotation.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// DefaultCreator sets the creator of testruns without creator to the user that creates the testrun.
+	// +optional
+	DefaultCreator bool `json:"defaultCreator,omitempty"`
+}
@@ -44,6 +44,15 @@ type Controller struct {
 
 	// DependencyHealthCheck specifies a deployment whose health is relevant for the controller.
 	DependencyHealthCheck HealthCheckTarget `json:"dependencyHealthCheck,omitempty"`
+
+	// AdmissionPolicies are evaluated by the testrun admission webhook.
+	// Policies for TestDefinitions are evaluated when the TestDefinitions of a testrun are resolved by the controller.
+	// +optional
+	AdmissionPolicies *AdmissionPolicies `json:"admissionPolicies,omitempty"`
+
+	// Defaulting configures the defaults that are applied to new testruns by the mutating webhook.
+	// +optional
+	Defaulting *Defaulting `json:"defaulting,omitempty"`
 }
 
 // TTLController contains the ttl controller configuration.
@@ -68,6 +77,26 @@ type ElasticSearch struct {
 	Endpoint string `json:"endpoint,omitempty"`
 	Username string `json:"username,omitempty"`
 	Password string `json:"password,omitempty"`
+
+	// Spool configures a durable buffer for results that could not be ingested into elasticsearch.
+	Spool ElasticSearchSpool `json:"spool,omitempty"`
+}
+
+// ElasticSearchSpool configures where bulk payloads are stored while elasticsearch is unavailable.
+// The spool is disabled if neither a directory nor a s3 prefix is configured.
+type ElasticSearchSpool struct {
+	// Dir is a directory on a persistent volume that stores the bulk payloads.
+	Dir string `json:"dir,omitempty"`
+
+	// S3Prefix is the object prefix in the configured s3 bucket that stores the bulk payloads.
+	S3Prefix string `json:"s3Prefix,omitempty"`
+
+	// RetryInterval is the initial interval in which the ingestion of spooled payloads is retried.
+	// The interval is doubled after every failed retry.
+	RetryInterval metav1.Duration `json:"retryInterval,omitempty"`
+
+	// MaxRetryInterval is the maximum interval in which the ingestion of spooled payloads is retried.
+	MaxRetryInterval metav1.Duration `json:"maxRetryInterval,omitempty"`
 }
 
 // HealthCheckTarget specifies a deployment whose health should be checked.
@@ -0,0 +1,96 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+// PolicyEnforcement defines how violations of an admission policy are handled.
+type PolicyEnforcement string
+
+const (
+	// PolicyEnforcementDeny rejects testruns that violate the policy.
+	PolicyEnforcementDeny PolicyEnforcement = "deny"
+	// PolicyEnforcementWarn admits testruns that violate the policy and returns the violations as warnings.
+	PolicyEnforcementWarn PolicyEnforcement = "warn"
+)
+
+// AdmissionPolicies are policies that testruns have to comply with.
+// Policies that are not defined are not evaluated.
+type AdmissionPolicies struct {
+	// Images restricts the images of TestDefinitions.
+	// +optional
+	Images *ImagePolicy `json:"images,omitempty"`
+
+	// ActiveDeadline restricts the activeDeadlineSeconds of TestDefinitions.
+	// +optional
+	ActiveDeadline *ActiveDeadlinePolicy `json:"activeDeadline,omitempty"`
+
+	// Annotations defines annotations that testruns are required to have.
+	// +optional
+	Annotations *AnnotationPolicy `json:"annotations,omitempty"`
+
+	// UntrustedSteps forbids untrusted steps in testruns that define kubeconfigs other than the shoot kubeconfig.
+	// +optional
+	UntrustedSteps *UntrustedStepPolicy `json:"untrustedSteps,omitempty"`
+
+	// Locations restricts the domains of git locations of testruns.
+	// +optional
+	Locations *LocationPolicy `json:"locations,omitempty"`
+}
+
+// ImagePolicy restricts the images of TestDefinitions.
+// TestDefinitions without an image use the base image that is always allowed.
+type ImagePolicy struct {
+	// Enforcement defines how violations are handled.
+	// Defaults to "deny".
+	// +optional
+	Enforcement PolicyEnforcement `json:"enforcement,omitempty"`
+
+	// AllowedRegistries is a list of registries or repository prefixes that images have to be pulled from,
+	// e.g. "europe-docker.pkg.dev/gardener-project".
+	// Images without a registry are pulled from "docker.io".
+	AllowedRegistries []string `json:"allowedRegistries"`
+}
+
+// ActiveDeadlinePolicy restricts the activeDeadlineSeconds of TestDefinitions.
+type ActiveDeadlinePolicy struct {
+	// Enforcement defines how violations are handled.
+	// Defaults to "deny".
+	// +optional
+	Enforcement PolicyEnforcement `json:"enforcement,omitempty"`
+
+	// MaxSeconds is the maximum activeDeadlineSeconds of a TestDefinition.
+	MaxSeconds int64 `json:"maxSeconds"`
+}
+
+// AnnotationPolicy defines annotations that testruns are required to have.
+type AnnotationPolicy struct {
+	// Enforcement defines how violations are handled.
+	// Defaults to "deny".
+	// +optional
+	Enforcement PolicyEnforcement `json:"enforcement,omitempty"`
+
+	// Required is a list of annotation keys that have to be set with a non empty value.
+	Required []string `json:"required"`
+}
+
+// UntrustedStepPolicy forbids untrusted steps in testruns with trusted kubeconfigs.
+// Trusted kubeconfigs are the host, gardener and seed kubeconfig and all additional kubeconfigs.
+type UntrustedStepPolicy struct {
+	// Enforcement defines how violations are handled.
+	// Defaults to "deny".
+	// +optional
+	Enforcement PolicyEnforcement `json:"enforcement,omitempty"`
+}
+
+// LocationPolicy restricts the domains of git locations.
+type LocationPolicy struct {
+	// Enforcement defines how violations are handled.
+	// Defaults to "deny".
+	// +optional
+	Enforcement PolicyEnforcement `json:"enforcement,omitempty"`
+
+	// AllowedDomains is a list of domains that git locations have to point to.
+	// Subdomains of the domains are allowed as well, e.g. "github.com" allows "api.github.com" but not "evilgithub.com".
+	AllowedDomains []string `json:"allowedDomains"`
+}
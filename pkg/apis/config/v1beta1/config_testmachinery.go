@@ -6,6 +6,8 @@ package v1beta1
 
 import (
 	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // TestMachinery holds information about the testmachinery
@@ -46,6 +48,20 @@ type TestMachinery struct {
 
 	// LandscapeMappings defines how to connect to landscapes using the respective OpenIDConnect IDP
 	LandscapeMappings []LandscapeMapping `json:"landscapeMappings,omitempty"`
+
+	// RepositoryCache configures a shared cache of git mirrors that is used by the prepare step to clone repositories.
+	// Repositories are cloned directly from their remote if no cache is configured.
+	// +optional
+	RepositoryCache *RepositoryCache `json:"repositoryCache,omitempty"`
+
+	// ConfigSources configures the additional sources config elements of testruns can read their values from.
+	// +optional
+	ConfigSources *ConfigSources `json:"configSources,omitempty"`
+
+	// KubeconfigPreflight enables checks of the kubeconfigs of testruns before their workflow is created.
+	// The kubeconfigs are not checked if no preflight is configured.
+	// +optional
+	KubeconfigPreflight *KubeconfigPreflight `json:"kubeconfigPreflight,omitempty"`
 }
 
 // Locations defines test location configurations.
@@ -53,6 +69,38 @@ type Locations struct {
 	// ExcludeDomains is a list of domains that should be excluded and no test definition fetched from.
 	// Note that the domain and all its subdomains are ignored.
 	ExcludeDomains []string `json:"excludeDomains,omitempty"`
+
+	// GitProviders configures how TestDefinitions are read from git locations of specific domains.
+	// Locations of domains without a provider are read via the GitHub API.
+	GitProviders []GitProvider `json:"gitProviders,omitempty"`
+}
+
+// GitProviderType is the type of git server of a domain.
+type GitProviderType string
+
+const (
+	// GitProviderGitHub reads TestDefinitions via the GitHub API.
+	GitProviderGitHub GitProviderType = "github"
+	// GitProviderGitLab reads TestDefinitions via the GitLab API.
+	GitProviderGitLab GitProviderType = "gitlab"
+	// GitProviderGit reads TestDefinitions by a shallow and sparse fetch via the git protocol.
+	// It can be used for any git server.
+	GitProviderGit GitProviderType = "git"
+)
+
+// GitProvider defines the git provider of a domain.
+type GitProvider struct {
+	// Domain of the git server.
+	// Note that the domain and all its subdomains are matched.
+	Domain string `json:"domain"`
+
+	// Type of the git provider.
+	Type GitProviderType `json:"type"`
+
+	// APIURL is the url of the provider's API.
+	// Defaults to the apiUrl of the matching github secret or the default API path of the provider.
+	// +optional
+	APIURL string `json:"apiUrl,omitempty"`
 }
 
 // GitHub holds all github related information needed in the testmachinery.
@@ -68,6 +116,83 @@ type GitHubCache struct {
 	CacheDir        string `json:"cacheDir,omitempty"`
 	CacheDiskSizeGB int    `json:"cacheDiskSizeGB,omitempty"`
 	MaxAgeSeconds   int    `json:"maxAgeSeconds,omitempty"`
+
+	// Shared configures a cache backend that is shared with other processes, e.g. the controller and the bot.
+	// The local memory or disk cache is not used if a shared backend is defined.
+	// +optional
+	Shared *SharedGitHubCache `json:"shared,omitempty"`
+
+	// Server serves the cache as http cache service that can be used by other processes as shared backend.
+	// +optional
+	Server *GitHubCacheServer `json:"server,omitempty"`
+
+	// RateLimitReserve is the number of requests of the rate limit of an installation
+	// that are reserved for requests that cannot be answered by the cache.
+	// Stale cached responses are returned without revalidation
+	// once the remaining rate limit of an installation drops below the reserve.
+	// +optional
+	RateLimitReserve int `json:"rateLimitReserve,omitempty"`
+}
+
+// SharedGitHubCache configures a github cache backend that is shared between processes.
+// Exactly one backend has to be defined.
+type SharedGitHubCache struct {
+	// Redis configures a redis compatible server as backend.
+	// +optional
+	Redis *RedisGitHubCache `json:"redis,omitempty"`
+
+	// HTTP configures a http cache service as backend, e.g. the cache served by the controller.
+	// +optional
+	HTTP *HTTPGitHubCache `json:"http,omitempty"`
+}
+
+// RedisGitHubCache configures a redis compatible server as github cache backend.
+type RedisGitHubCache struct {
+	// Address is the host:port of the redis server.
+	Address string `json:"address"`
+
+	// Password is the password that is used to authenticate to the redis server.
+	// +optional
+	Password string `json:"password,omitempty"`
+
+	// DB is the redis database that is used.
+	// +optional
+	DB int `json:"db,omitempty"`
+
+	// KeyPrefix is prepended to all keys written by the cache.
+	// Defaults to "ghcache:".
+	// +optional
+	KeyPrefix string `json:"keyPrefix,omitempty"`
+
+	// TTL is the time after which cached responses are removed from the redis server.
+	// Defaults to 24h.
+	// +optional
+	TTL metav1.Duration `json:"ttl,omitempty"`
+}
+
+// HTTPGitHubCache configures a http cache service as github cache backend.
+type HTTPGitHubCache struct {
+	// URL is the base url of the cache service.
+	URL string `json:"url"`
+
+	// Token is the bearer token that is used to authenticate to the cache service.
+	// +optional
+	Token string `json:"token,omitempty"`
+}
+
+// GitHubCacheServer configures the http cache service that serves the github cache to other processes.
+type GitHubCacheServer struct {
+	// BindAddress is the address the cache service listens on, e.g. ":8090".
+	BindAddress string `json:"bindAddress"`
+
+	// Token is the bearer token that clients have to present.
+	// Either the token or the token path has to be defined.
+	// +optional
+	Token string `json:"token,omitempty"`
+
+	// TokenPath is the path to a file that contains the bearer token that clients have to present.
+	// +optional
+	TokenPath string `json:"tokenPath,omitempty"`
 }
 
 // LandscapeMapping defines how to connect to a landscape using an OpenIDConnect IDP
@@ -87,3 +212,105 @@ type LandscapeMapping struct {
 	// AllowUntrustedUsage defines, if a token is allowed to be used in untrusted steps
 	AllowUntrustedUsage bool `json:"allowUntrustedUsage"`
 }
+
+// RepositoryCache configures the shared cache of bare git mirrors.
+// Exactly one of PVC or S3 has to be defined.
+type RepositoryCache struct {
+	// PVC stores the mirrors on a persistent volume claim.
+	// +optional
+	PVC *RepositoryCachePVC `json:"pvc,omitempty"`
+
+	// S3 stores the mirrors as git bundles in a s3 bucket.
+	// +optional
+	S3 *RepositoryCacheS3 `json:"s3,omitempty"`
+
+	// MaxAge is the duration after which mirrors that have not been used are evicted.
+	// Defaults to 168h.
+	// +optional
+	MaxAge metav1.Duration `json:"maxAge,omitempty"`
+
+	// MaxSizeGB is the maximum size of all mirrors.
+	// The least recently used mirrors are evicted if the size is exceeded.
+	// +optional
+	MaxSizeGB int `json:"maxSizeGB,omitempty"`
+
+	// LockTimeout is the duration a prepare step waits for a mirror that is locked by another testrun.
+	// The repository is cloned directly from the remote if the timeout is exceeded.
+	// Defaults to 5m.
+	// +optional
+	LockTimeout metav1.Duration `json:"lockTimeout,omitempty"`
+}
+
+// RepositoryCachePVC defines a persistent volume claim that stores the repository cache.
+type RepositoryCachePVC struct {
+	// ClaimName is the name of the persistent volume claim.
+	// The claim has to exist in the namespace of the testruns and has to support the ReadWriteMany access mode.
+	ClaimName string `json:"claimName"`
+}
+
+// RepositoryCacheS3 defines a s3 bucket that stores the repository cache.
+type RepositoryCacheS3 struct {
+	Server     S3Server `json:"server"`
+	BucketName string   `json:"bucketName"`
+
+	// Prefix of all objects of the cache.
+	// Defaults to "repository-cache".
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+
+	// SecretName is the name of a secret in the namespace of the testruns
+	// that contains the "accessKey" and "secretKey" of the bucket.
+	SecretName string `json:"secretName"`
+}
+
+// ConfigSources configures the sources that config elements can read their values from
+// in addition to secrets and configmaps in the namespace of the testrun.
+// The values are resolved when the workflow of a testrun is generated.
+type ConfigSources struct {
+	// AllowedSecretNamespaces is a list of namespaces whose secrets can be referenced by config elements.
+	// +optional
+	AllowedSecretNamespaces []string `json:"allowedSecretNamespaces,omitempty"`
+
+	// Vault configures a Vault compatible KV secrets engine.
+	// +optional
+	Vault *VaultConfigSource `json:"vault,omitempty"`
+}
+
+// VaultConfigSource configures the access to a Vault compatible KV secrets engine.
+type VaultConfigSource struct {
+	// Address is the base url of the Vault server, e.g. "https://vault.example.com".
+	Address string `json:"address"`
+
+	// TokenPath is the path to a file that contains the token that is used to authenticate to the Vault server.
+	// The file is read for every testrun so that the token can be rotated.
+	TokenPath string `json:"tokenPath"`
+
+	// Namespace is the Vault namespace that is sent with every request.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// KVVersion is the version of the KV secrets engine, either 1 or 2.
+	// Defaults to 2.
+	// +optional
+	KVVersion int `json:"kvVersion,omitempty"`
+}
+
+// KubeconfigPreflight configures the checks of the kubeconfigs of testruns.
+// All kubeconfigs are parsed and checked for expired client certificates and valid token files.
+type KubeconfigPreflight struct {
+	// CertificateExpirationWarning is the duration before the expiration of a client certificate
+	// in which a warning is issued.
+	// Defaults to 24h.
+	// +optional
+	CertificateExpirationWarning metav1.Duration `json:"certificateExpirationWarning,omitempty"`
+
+	// ProbeServer enables a request to the /version endpoint of the cluster of every kubeconfig.
+	// Kubeconfigs that use token files or exec plugins are not probed as their credentials are not available to the controller.
+	// +optional
+	ProbeServer bool `json:"probeServer,omitempty"`
+
+	// ProbeTimeout is the timeout of the request to the /version endpoint.
+	// Defaults to 10s.
+	// +optional
+	ProbeTimeout metav1.Duration `json:"probeTimeout,omitempty"`
+}
@@ -52,6 +52,24 @@ func SetDefaults_ControllerConfig(obj *Controller) {
 	if obj.DependencyHealthCheck.Interval.Duration == 0 {
 		obj.DependencyHealthCheck.Interval.Duration = time.Minute
 	}
+
+	if policies := obj.AdmissionPolicies; policies != nil {
+		if policies.Images != nil && len(policies.Images.Enforcement) == 0 {
+			policies.Images.Enforcement = PolicyEnforcementDeny
+		}
+		if policies.ActiveDeadline != nil && len(policies.ActiveDeadline.Enforcement) == 0 {
+			policies.ActiveDeadline.Enforcement = PolicyEnforcementDeny
+		}
+		if policies.Annotations != nil && len(policies.Annotations.Enforcement) == 0 {
+			policies.Annotations.Enforcement = PolicyEnforcementDeny
+		}
+		if policies.UntrustedSteps != nil && len(policies.UntrustedSteps.Enforcement) == 0 {
+			policies.UntrustedSteps.Enforcement = PolicyEnforcementDeny
+		}
+		if policies.Locations != nil && len(policies.Locations.Enforcement) == 0 {
+			policies.Locations.Enforcement = PolicyEnforcementDeny
+		}
+	}
 }
 
 // SetDefaults_TestMachineryConfiguration sets default values for the TestMachinery objects
@@ -69,6 +87,43 @@ func SetDefaults_TestMachineryConfiguration(obj *TestMachinery) {
 	if len(obj.Namespace) == 0 {
 		obj.Namespace = "default"
 	}
+
+	if cache := obj.RepositoryCache; cache != nil {
+		if cache.MaxAge.Duration == 0 {
+			cache.MaxAge.Duration = 7 * 24 * time.Hour
+		}
+		if cache.LockTimeout.Duration == 0 {
+			cache.LockTimeout.Duration = 5 * time.Minute
+		}
+		if cache.S3 != nil && len(cache.S3.Prefix) == 0 {
+			cache.S3.Prefix = "repository-cache"
+		}
+	}
+
+	if sources := obj.ConfigSources; sources != nil && sources.Vault != nil {
+		if sources.Vault.KVVersion == 0 {
+			sources.Vault.KVVersion = 2
+		}
+	}
+
+	if preflight := obj.KubeconfigPreflight; preflight != nil {
+		if preflight.CertificateExpirationWarning.Duration == 0 {
+			preflight.CertificateExpirationWarning.Duration = 24 * time.Hour
+		}
+		if preflight.ProbeTimeout.Duration == 0 {
+			preflight.ProbeTimeout.Duration = 10 * time.Second
+		}
+	}
+}
+
+// SetDefaults_RedisGitHubCache sets default values for the RedisGitHubCache objects
+func SetDefaults_RedisGitHubCache(obj *RedisGitHubCache) {
+	if len(obj.KeyPrefix) == 0 {
+		obj.KeyPrefix = "ghcache:"
+	}
+	if obj.TTL.Duration == 0 {
+		obj.TTL.Duration = 24 * time.Hour
+	}
 }
 
 // SetDefaults_Webserver sets default values for the Webserver objects
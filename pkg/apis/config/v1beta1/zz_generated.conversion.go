@@ -14,6 +14,7 @@ import (
 	unsafe "unsafe"
 
 	config "github.com/gardener/test-infra/pkg/apis/config"
+	testmachineryv1beta1 "github.com/gardener/test-infra/pkg/apis/testmachinery/v1beta1"
 	conversion "k8s.io/apimachinery/pkg/conversion"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
@@ -25,6 +26,46 @@ func init() {
 // RegisterConversions adds conversion functions to the given scheme.
 // Public to allow building arbitrary schemes.
 func RegisterConversions(s *runtime.Scheme) error {
+	if err := s.AddGeneratedConversionFunc((*ActiveDeadlinePolicy)(nil), (*config.ActiveDeadlinePolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_ActiveDeadlinePolicy_To_config_ActiveDeadlinePolicy(a.(*ActiveDeadlinePolicy), b.(*config.ActiveDeadlinePolicy), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.ActiveDeadlinePolicy)(nil), (*ActiveDeadlinePolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_ActiveDeadlinePolicy_To_v1beta1_ActiveDeadlinePolicy(a.(*config.ActiveDeadlinePolicy), b.(*ActiveDeadlinePolicy), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*AdmissionPolicies)(nil), (*config.AdmissionPolicies)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_AdmissionPolicies_To_config_AdmissionPolicies(a.(*AdmissionPolicies), b.(*config.AdmissionPolicies), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.AdmissionPolicies)(nil), (*AdmissionPolicies)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_AdmissionPolicies_To_v1beta1_AdmissionPolicies(a.(*config.AdmissionPolicies), b.(*AdmissionPolicies), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*Alerting)(nil), (*config.Alerting)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_Alerting_To_config_Alerting(a.(*Alerting), b.(*config.Alerting), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.Alerting)(nil), (*Alerting)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_Alerting_To_v1beta1_Alerting(a.(*config.Alerting), b.(*Alerting), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*AnnotationPolicy)(nil), (*config.AnnotationPolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_AnnotationPolicy_To_config_AnnotationPolicy(a.(*AnnotationPolicy), b.(*config.AnnotationPolicy), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.AnnotationPolicy)(nil), (*AnnotationPolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_AnnotationPolicy_To_v1beta1_AnnotationPolicy(a.(*config.AnnotationPolicy), b.(*AnnotationPolicy), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*BotConfiguration)(nil), (*config.BotConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_BotConfiguration_To_config_BotConfiguration(a.(*BotConfiguration), b.(*config.BotConfiguration), scope)
 	}); err != nil {
@@ -45,6 +86,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*ConfigSources)(nil), (*config.ConfigSources)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_ConfigSources_To_config_ConfigSources(a.(*ConfigSources), b.(*config.ConfigSources), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.ConfigSources)(nil), (*ConfigSources)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_ConfigSources_To_v1beta1_ConfigSources(a.(*config.ConfigSources), b.(*ConfigSources), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*Configuration)(nil), (*config.Configuration)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_Configuration_To_config_Configuration(a.(*Configuration), b.(*config.Configuration), scope)
 	}); err != nil {
@@ -85,6 +136,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*DefaultProfile)(nil), (*config.DefaultProfile)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_DefaultProfile_To_config_DefaultProfile(a.(*DefaultProfile), b.(*config.DefaultProfile), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.DefaultProfile)(nil), (*DefaultProfile)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_DefaultProfile_To_v1beta1_DefaultProfile(a.(*config.DefaultProfile), b.(*DefaultProfile), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*Defaulting)(nil), (*config.Defaulting)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_Defaulting_To_config_Defaulting(a.(*Defaulting), b.(*config.Defaulting), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.Defaulting)(nil), (*Defaulting)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_Defaulting_To_v1beta1_Defaulting(a.(*config.Defaulting), b.(*Defaulting), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*ElasticSearch)(nil), (*config.ElasticSearch)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_ElasticSearch_To_config_ElasticSearch(a.(*ElasticSearch), b.(*config.ElasticSearch), scope)
 	}); err != nil {
@@ -95,6 +166,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*ElasticSearchSpool)(nil), (*config.ElasticSearchSpool)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_ElasticSearchSpool_To_config_ElasticSearchSpool(a.(*ElasticSearchSpool), b.(*config.ElasticSearchSpool), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.ElasticSearchSpool)(nil), (*ElasticSearchSpool)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_ElasticSearchSpool_To_v1beta1_ElasticSearchSpool(a.(*config.ElasticSearchSpool), b.(*ElasticSearchSpool), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*GitHub)(nil), (*config.GitHub)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_GitHub_To_config_GitHub(a.(*GitHub), b.(*config.GitHub), scope)
 	}); err != nil {
@@ -135,6 +216,36 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*GitHubCacheServer)(nil), (*config.GitHubCacheServer)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_GitHubCacheServer_To_config_GitHubCacheServer(a.(*GitHubCacheServer), b.(*config.GitHubCacheServer), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.GitHubCacheServer)(nil), (*GitHubCacheServer)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_GitHubCacheServer_To_v1beta1_GitHubCacheServer(a.(*config.GitHubCacheServer), b.(*GitHubCacheServer), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*GitProvider)(nil), (*config.GitProvider)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_GitProvider_To_config_GitProvider(a.(*GitProvider), b.(*config.GitProvider), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.GitProvider)(nil), (*GitProvider)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_GitProvider_To_v1beta1_GitProvider(a.(*config.GitProvider), b.(*GitProvider), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*HTTPGitHubCache)(nil), (*config.HTTPGitHubCache)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_HTTPGitHubCache_To_config_HTTPGitHubCache(a.(*HTTPGitHubCache), b.(*config.HTTPGitHubCache), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.HTTPGitHubCache)(nil), (*HTTPGitHubCache)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_HTTPGitHubCache_To_v1beta1_HTTPGitHubCache(a.(*config.HTTPGitHubCache), b.(*HTTPGitHubCache), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*HealthCheckTarget)(nil), (*config.HealthCheckTarget)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_HealthCheckTarget_To_config_HealthCheckTarget(a.(*HealthCheckTarget), b.(*config.HealthCheckTarget), scope)
 	}); err != nil {
@@ -145,6 +256,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*ImagePolicy)(nil), (*config.ImagePolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_ImagePolicy_To_config_ImagePolicy(a.(*ImagePolicy), b.(*config.ImagePolicy), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.ImagePolicy)(nil), (*ImagePolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_ImagePolicy_To_v1beta1_ImagePolicy(a.(*config.ImagePolicy), b.(*ImagePolicy), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*KubeconfigPreflight)(nil), (*config.KubeconfigPreflight)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_KubeconfigPreflight_To_config_KubeconfigPreflight(a.(*KubeconfigPreflight), b.(*config.KubeconfigPreflight), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.KubeconfigPreflight)(nil), (*KubeconfigPreflight)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_KubeconfigPreflight_To_v1beta1_KubeconfigPreflight(a.(*config.KubeconfigPreflight), b.(*KubeconfigPreflight), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*LandscapeMapping)(nil), (*config.LandscapeMapping)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_LandscapeMapping_To_config_LandscapeMapping(a.(*LandscapeMapping), b.(*config.LandscapeMapping), scope)
 	}); err != nil {
@@ -155,6 +286,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*LocationPolicy)(nil), (*config.LocationPolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_LocationPolicy_To_config_LocationPolicy(a.(*LocationPolicy), b.(*config.LocationPolicy), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.LocationPolicy)(nil), (*LocationPolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_LocationPolicy_To_v1beta1_LocationPolicy(a.(*config.LocationPolicy), b.(*LocationPolicy), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*Locations)(nil), (*config.Locations)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_Locations_To_config_Locations(a.(*Locations), b.(*config.Locations), scope)
 	}); err != nil {
@@ -175,6 +316,46 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*RedisGitHubCache)(nil), (*config.RedisGitHubCache)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_RedisGitHubCache_To_config_RedisGitHubCache(a.(*RedisGitHubCache), b.(*config.RedisGitHubCache), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.RedisGitHubCache)(nil), (*RedisGitHubCache)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_RedisGitHubCache_To_v1beta1_RedisGitHubCache(a.(*config.RedisGitHubCache), b.(*RedisGitHubCache), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*RepositoryCache)(nil), (*config.RepositoryCache)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_RepositoryCache_To_config_RepositoryCache(a.(*RepositoryCache), b.(*config.RepositoryCache), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.RepositoryCache)(nil), (*RepositoryCache)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_RepositoryCache_To_v1beta1_RepositoryCache(a.(*config.RepositoryCache), b.(*RepositoryCache), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*RepositoryCachePVC)(nil), (*config.RepositoryCachePVC)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_RepositoryCachePVC_To_config_RepositoryCachePVC(a.(*RepositoryCachePVC), b.(*config.RepositoryCachePVC), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.RepositoryCachePVC)(nil), (*RepositoryCachePVC)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_RepositoryCachePVC_To_v1beta1_RepositoryCachePVC(a.(*config.RepositoryCachePVC), b.(*RepositoryCachePVC), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*RepositoryCacheS3)(nil), (*config.RepositoryCacheS3)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_RepositoryCacheS3_To_config_RepositoryCacheS3(a.(*RepositoryCacheS3), b.(*config.RepositoryCacheS3), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.RepositoryCacheS3)(nil), (*RepositoryCacheS3)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_RepositoryCacheS3_To_v1beta1_RepositoryCacheS3(a.(*config.RepositoryCacheS3), b.(*RepositoryCacheS3), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*S3)(nil), (*config.S3)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_S3_To_config_S3(a.(*S3), b.(*config.S3), scope)
 	}); err != nil {
@@ -195,6 +376,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*SharedGitHubCache)(nil), (*config.SharedGitHubCache)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_SharedGitHubCache_To_config_SharedGitHubCache(a.(*SharedGitHubCache), b.(*config.SharedGitHubCache), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.SharedGitHubCache)(nil), (*SharedGitHubCache)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_SharedGitHubCache_To_v1beta1_SharedGitHubCache(a.(*config.SharedGitHubCache), b.(*SharedGitHubCache), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*TTLController)(nil), (*config.TTLController)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_TTLController_To_config_TTLController(a.(*TTLController), b.(*config.TTLController), scope)
 	}); err != nil {
@@ -215,6 +406,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*UntrustedStepPolicy)(nil), (*config.UntrustedStepPolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_UntrustedStepPolicy_To_config_UntrustedStepPolicy(a.(*UntrustedStepPolicy), b.(*config.UntrustedStepPolicy), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.UntrustedStepPolicy)(nil), (*UntrustedStepPolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_UntrustedStepPolicy_To_v1beta1_UntrustedStepPolicy(a.(*config.UntrustedStepPolicy), b.(*UntrustedStepPolicy), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*VaultConfigSource)(nil), (*config.VaultConfigSource)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_VaultConfigSource_To_config_VaultConfigSource(a.(*VaultConfigSource), b.(*config.VaultConfigSource), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.VaultConfigSource)(nil), (*VaultConfigSource)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_VaultConfigSource_To_v1beta1_VaultConfigSource(a.(*config.VaultConfigSource), b.(*VaultConfigSource), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*WebhookConfig)(nil), (*config.WebhookConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_WebhookConfig_To_config_WebhookConfig(a.(*WebhookConfig), b.(*config.WebhookConfig), scope)
 	}); err != nil {
@@ -238,6 +449,106 @@ func RegisterConversions(s *runtime.Scheme) error {
 	return nil
 }
 
+func autoConvert_v1beta1_ActiveDeadlinePolicy_To_config_ActiveDeadlinePolicy(in *ActiveDeadlinePolicy, out *config.ActiveDeadlinePolicy, s conversion.Scope) error {
+	out.Enforcement = config.PolicyEnforcement(in.Enforcement)
+	out.MaxSeconds = in.MaxSeconds
+	return nil
+}
+
+// Convert_v1beta1_ActiveDeadlinePolicy_To_config_ActiveDeadlinePolicy is an autogenerated conversion function.
+func Convert_v1beta1_ActiveDeadlinePolicy_To_config_ActiveDeadlinePolicy(in *ActiveDeadlinePolicy, out *config.ActiveDeadlinePolicy, s conversion.Scope) error {
+	return autoConvert_v1beta1_ActiveDeadlinePolicy_To_config_ActiveDeadlinePolicy(in, out, s)
+}
+
+func autoConvert_config_ActiveDeadlinePolicy_To_v1beta1_ActiveDeadlinePolicy(in *config.ActiveDeadlinePolicy, out *ActiveDeadlinePolicy, s conversion.Scope) error {
+	out.Enforcement = PolicyEnforcement(in.Enforcement)
+	out.MaxSeconds = in.MaxSeconds
+	return nil
+}
+
+// Convert_config_ActiveDeadlinePolicy_To_v1beta1_ActiveDeadlinePolicy is an autogenerated conversion function.
+func Convert_config_ActiveDeadlinePolicy_To_v1beta1_ActiveDeadlinePolicy(in *config.ActiveDeadlinePolicy, out *ActiveDeadlinePolicy, s conversion.Scope) error {
+	return autoConvert_config_ActiveDeadlinePolicy_To_v1beta1_ActiveDeadlinePolicy(in, out, s)
+}
+
+func autoConvert_v1beta1_AdmissionPolicies_To_config_AdmissionPolicies(in *AdmissionPolicies, out *config.AdmissionPolicies, s conversion.Scope) error {
+	out.Images = (*config.ImagePolicy)(unsafe.Pointer(in.Images))
+	out.ActiveDeadline = (*config.ActiveDeadlinePolicy)(unsafe.Pointer(in.ActiveDeadline))
+	out.Annotations = (*config.AnnotationPolicy)(unsafe.Pointer(in.Annotations))
+	out.UntrustedSteps = (*config.UntrustedStepPolicy)(unsafe.Pointer(in.UntrustedSteps))
+	out.Locations = (*config.LocationPolicy)(unsafe.Pointer(in.Locations))
+	return nil
+}
+
+// Convert_v1beta1_AdmissionPolicies_To_config_AdmissionPolicies is an autogenerated conversion function.
+func Convert_v1beta1_AdmissionPolicies_To_config_AdmissionPolicies(in *AdmissionPolicies, out *config.AdmissionPolicies, s conversion.Scope) error {
+	return autoConvert_v1beta1_AdmissionPolicies_To_config_AdmissionPolicies(in, out, s)
+}
+
+func autoConvert_config_AdmissionPolicies_To_v1beta1_AdmissionPolicies(in *config.AdmissionPolicies, out *AdmissionPolicies, s conversion.Scope) error {
+	out.Images = (*ImagePolicy)(unsafe.Pointer(in.Images))
+	out.ActiveDeadline = (*ActiveDeadlinePolicy)(unsafe.Pointer(in.ActiveDeadline))
+	out.Annotations = (*AnnotationPolicy)(unsafe.Pointer(in.Annotations))
+	out.UntrustedSteps = (*UntrustedStepPolicy)(unsafe.Pointer(in.UntrustedSteps))
+	out.Locations = (*LocationPolicy)(unsafe.Pointer(in.Locations))
+	return nil
+}
+
+// Convert_config_AdmissionPolicies_To_v1beta1_AdmissionPolicies is an autogenerated conversion function.
+func Convert_config_AdmissionPolicies_To_v1beta1_AdmissionPolicies(in *config.AdmissionPolicies, out *AdmissionPolicies, s conversion.Scope) error {
+	return autoConvert_config_AdmissionPolicies_To_v1beta1_AdmissionPolicies(in, out, s)
+}
+
+func autoConvert_v1beta1_Alerting_To_config_Alerting(in *Alerting, out *config.Alerting, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	out.SlackSigningSecret = in.SlackSigningSecret
+	if err := Convert_v1beta1_ElasticSearch_To_config_ElasticSearch(&in.ElasticSearch, &out.ElasticSearch, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1beta1_Alerting_To_config_Alerting is an autogenerated conversion function.
+func Convert_v1beta1_Alerting_To_config_Alerting(in *Alerting, out *config.Alerting, s conversion.Scope) error {
+	return autoConvert_v1beta1_Alerting_To_config_Alerting(in, out, s)
+}
+
+func autoConvert_config_Alerting_To_v1beta1_Alerting(in *config.Alerting, out *Alerting, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	out.SlackSigningSecret = in.SlackSigningSecret
+	if err := Convert_config_ElasticSearch_To_v1beta1_ElasticSearch(&in.ElasticSearch, &out.ElasticSearch, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_config_Alerting_To_v1beta1_Alerting is an autogenerated conversion function.
+func Convert_config_Alerting_To_v1beta1_Alerting(in *config.Alerting, out *Alerting, s conversion.Scope) error {
+	return autoConvert_config_Alerting_To_v1beta1_Alerting(in, out, s)
+}
+
+func autoConvert_v1beta1_AnnotationPolicy_To_config_AnnotationPolicy(in *AnnotationPolicy, out *config.AnnotationPolicy, s conversion.Scope) error {
+	out.Enforcement = config.PolicyEnforcement(in.Enforcement)
+	out.Required = *(*[]string)(unsafe.Pointer(&in.Required))
+	return nil
+}
+
+// Convert_v1beta1_AnnotationPolicy_To_config_AnnotationPolicy is an autogenerated conversion function.
+func Convert_v1beta1_AnnotationPolicy_To_config_AnnotationPolicy(in *AnnotationPolicy, out *config.AnnotationPolicy, s conversion.Scope) error {
+	return autoConvert_v1beta1_AnnotationPolicy_To_config_AnnotationPolicy(in, out, s)
+}
+
+func autoConvert_config_AnnotationPolicy_To_v1beta1_AnnotationPolicy(in *config.AnnotationPolicy, out *AnnotationPolicy, s conversion.Scope) error {
+	out.Enforcement = PolicyEnforcement(in.Enforcement)
+	out.Required = *(*[]string)(unsafe.Pointer(&in.Required))
+	return nil
+}
+
+// Convert_config_AnnotationPolicy_To_v1beta1_AnnotationPolicy is an autogenerated conversion function.
+func Convert_config_AnnotationPolicy_To_v1beta1_AnnotationPolicy(in *config.AnnotationPolicy, out *AnnotationPolicy, s conversion.Scope) error {
+	return autoConvert_config_AnnotationPolicy_To_v1beta1_AnnotationPolicy(in, out, s)
+}
+
 func autoConvert_v1beta1_BotConfiguration_To_config_BotConfiguration(in *BotConfiguration, out *config.BotConfiguration, s conversion.Scope) error {
 	if err := Convert_v1beta1_Webserver_To_config_Webserver(&in.Webserver, &out.Webserver, s); err != nil {
 		return err
@@ -248,6 +559,9 @@ func autoConvert_v1beta1_BotConfiguration_To_config_BotConfiguration(in *BotConf
 	if err := Convert_v1beta1_GitHubBot_To_config_GitHubBot(&in.GitHubBot, &out.GitHubBot, s); err != nil {
 		return err
 	}
+	if err := Convert_v1beta1_Alerting_To_config_Alerting(&in.Alerting, &out.Alerting, s); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -266,6 +580,9 @@ func autoConvert_config_BotConfiguration_To_v1beta1_BotConfiguration(in *config.
 	if err := Convert_config_GitHubBot_To_v1beta1_GitHubBot(&in.GitHubBot, &out.GitHubBot, s); err != nil {
 		return err
 	}
+	if err := Convert_config_Alerting_To_v1beta1_Alerting(&in.Alerting, &out.Alerting, s); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -296,6 +613,28 @@ func Convert_config_Certificate_To_v1beta1_Certificate(in *config.Certificate, o
 	return autoConvert_config_Certificate_To_v1beta1_Certificate(in, out, s)
 }
 
+func autoConvert_v1beta1_ConfigSources_To_config_ConfigSources(in *ConfigSources, out *config.ConfigSources, s conversion.Scope) error {
+	out.AllowedSecretNamespaces = *(*[]string)(unsafe.Pointer(&in.AllowedSecretNamespaces))
+	out.Vault = (*config.VaultConfigSource)(unsafe.Pointer(in.Vault))
+	return nil
+}
+
+// Convert_v1beta1_ConfigSources_To_config_ConfigSources is an autogenerated conversion function.
+func Convert_v1beta1_ConfigSources_To_config_ConfigSources(in *ConfigSources, out *config.ConfigSources, s conversion.Scope) error {
+	return autoConvert_v1beta1_ConfigSources_To_config_ConfigSources(in, out, s)
+}
+
+func autoConvert_config_ConfigSources_To_v1beta1_ConfigSources(in *config.ConfigSources, out *ConfigSources, s conversion.Scope) error {
+	out.AllowedSecretNamespaces = *(*[]string)(unsafe.Pointer(&in.AllowedSecretNamespaces))
+	out.Vault = (*VaultConfigSource)(unsafe.Pointer(in.Vault))
+	return nil
+}
+
+// Convert_config_ConfigSources_To_v1beta1_ConfigSources is an autogenerated conversion function.
+func Convert_config_ConfigSources_To_v1beta1_ConfigSources(in *config.ConfigSources, out *ConfigSources, s conversion.Scope) error {
+	return autoConvert_config_ConfigSources_To_v1beta1_ConfigSources(in, out, s)
+}
+
 func autoConvert_v1beta1_Configuration_To_config_Configuration(in *Configuration, out *config.Configuration, s conversion.Scope) error {
 	if err := Convert_v1beta1_Controller_To_config_Controller(&in.Controller, &out.Controller, s); err != nil {
 		return err
@@ -352,6 +691,8 @@ func autoConvert_v1beta1_Controller_To_config_Controller(in *Controller, out *co
 	if err := Convert_v1beta1_HealthCheckTarget_To_config_HealthCheckTarget(&in.DependencyHealthCheck, &out.DependencyHealthCheck, s); err != nil {
 		return err
 	}
+	out.AdmissionPolicies = (*config.AdmissionPolicies)(unsafe.Pointer(in.AdmissionPolicies))
+	out.Defaulting = (*config.Defaulting)(unsafe.Pointer(in.Defaulting))
 	return nil
 }
 
@@ -374,6 +715,8 @@ func autoConvert_config_Controller_To_v1beta1_Controller(in *config.Controller,
 	if err := Convert_config_HealthCheckTarget_To_v1beta1_HealthCheckTarget(&in.DependencyHealthCheck, &out.DependencyHealthCheck, s); err != nil {
 		return err
 	}
+	out.AdmissionPolicies = (*AdmissionPolicies)(unsafe.Pointer(in.AdmissionPolicies))
+	out.Defaulting = (*Defaulting)(unsafe.Pointer(in.Defaulting))
 	return nil
 }
 
@@ -434,10 +777,65 @@ func Convert_config_DashboardAuthentication_To_v1beta1_DashboardAuthentication(i
 	return autoConvert_config_DashboardAuthentication_To_v1beta1_DashboardAuthentication(in, out, s)
 }
 
+func autoConvert_v1beta1_DefaultProfile_To_config_DefaultProfile(in *DefaultProfile, out *config.DefaultProfile, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Namespaces = *(*[]string)(unsafe.Pointer(&in.Namespaces))
+	out.TTLSecondsAfterFinished = (*int32)(unsafe.Pointer(in.TTLSecondsAfterFinished))
+	out.LocationSets = *(*[]testmachineryv1beta1.LocationSet)(unsafe.Pointer(&in.LocationSets))
+	out.Config = *(*[]testmachineryv1beta1.ConfigElement)(unsafe.Pointer(&in.Config))
+	out.Annotations = *(*map[string]string)(unsafe.Pointer(&in.Annotations))
+	out.DefaultCreator = in.DefaultCreator
+	return nil
+}
+
+// Convert_v1beta1_DefaultProfile_To_config_DefaultProfile is an autogenerated conversion function.
+func Convert_v1beta1_DefaultProfile_To_config_DefaultProfile(in *DefaultProfile, out *config.DefaultProfile, s conversion.Scope) error {
+	return autoConvert_v1beta1_DefaultProfile_To_config_DefaultProfile(in, out, s)
+}
+
+func autoConvert_config_DefaultProfile_To_v1beta1_DefaultProfile(in *config.DefaultProfile, out *DefaultProfile, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Namespaces = *(*[]string)(unsafe.Pointer(&in.Namespaces))
+	out.TTLSecondsAfterFinished = (*int32)(unsafe.Pointer(in.TTLSecondsAfterFinished))
+	out.LocationSets = *(*[]testmachineryv1beta1.LocationSet)(unsafe.Pointer(&in.LocationSets))
+	out.Config = *(*[]testmachineryv1beta1.ConfigElement)(unsafe.Pointer(&in.Config))
+	out.Annotations = *(*map[string]string)(unsafe.Pointer(&in.Annotations))
+	out.DefaultCreator = in.DefaultCreator
+	return nil
+}
+
+// Convert_config_DefaultProfile_To_v1beta1_DefaultProfile is an autogenerated conversion function.
+func Convert_config_DefaultProfile_To_v1beta1_DefaultProfile(in *config.DefaultProfile, out *DefaultProfile, s conversion.Scope) error {
+	return autoConvert_config_DefaultProfile_To_v1beta1_DefaultProfile(in, out, s)
+}
+
+func autoConvert_v1beta1_Defaulting_To_config_Defaulting(in *Defaulting, out *config.Defaulting, s conversion.Scope) error {
+	out.Profiles = *(*[]config.DefaultProfile)(unsafe.Pointer(&in.Profiles))
+	return nil
+}
+
+// Convert_v1beta1_Defaulting_To_config_Defaulting is an autogenerated conversion function.
+func Convert_v1beta1_Defaulting_To_config_Defaulting(in *Defaulting, out *config.Defaulting, s conversion.Scope) error {
+	return autoConvert_v1beta1_Defaulting_To_config_Defaulting(in, out, s)
+}
+
+func autoConvert_config_Defaulting_To_v1beta1_Defaulting(in *config.Defaulting, out *Defaulting, s conversion.Scope) error {
+	out.Profiles = *(*[]DefaultProfile)(unsafe.Pointer(&in.Profiles))
+	return nil
+}
+
+// Convert_config_Defaulting_To_v1beta1_Defaulting is an autogenerated conversion function.
+func Convert_config_Defaulting_To_v1beta1_Defaulting(in *config.Defaulting, out *Defaulting, s conversion.Scope) error {
+	return autoConvert_config_Defaulting_To_v1beta1_Defaulting(in, out, s)
+}
+
 func autoConvert_v1beta1_ElasticSearch_To_config_ElasticSearch(in *ElasticSearch, out *config.ElasticSearch, s conversion.Scope) error {
 	out.Endpoint = in.Endpoint
 	out.Username = in.Username
 	out.Password = in.Password
+	if err := Convert_v1beta1_ElasticSearchSpool_To_config_ElasticSearchSpool(&in.Spool, &out.Spool, s); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -450,6 +848,9 @@ func autoConvert_config_ElasticSearch_To_v1beta1_ElasticSearch(in *config.Elasti
 	out.Endpoint = in.Endpoint
 	out.Username = in.Username
 	out.Password = in.Password
+	if err := Convert_config_ElasticSearchSpool_To_v1beta1_ElasticSearchSpool(&in.Spool, &out.Spool, s); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -458,6 +859,32 @@ func Convert_config_ElasticSearch_To_v1beta1_ElasticSearch(in *config.ElasticSea
 	return autoConvert_config_ElasticSearch_To_v1beta1_ElasticSearch(in, out, s)
 }
 
+func autoConvert_v1beta1_ElasticSearchSpool_To_config_ElasticSearchSpool(in *ElasticSearchSpool, out *config.ElasticSearchSpool, s conversion.Scope) error {
+	out.Dir = in.Dir
+	out.S3Prefix = in.S3Prefix
+	out.RetryInterval = in.RetryInterval
+	out.MaxRetryInterval = in.MaxRetryInterval
+	return nil
+}
+
+// Convert_v1beta1_ElasticSearchSpool_To_config_ElasticSearchSpool is an autogenerated conversion function.
+func Convert_v1beta1_ElasticSearchSpool_To_config_ElasticSearchSpool(in *ElasticSearchSpool, out *config.ElasticSearchSpool, s conversion.Scope) error {
+	return autoConvert_v1beta1_ElasticSearchSpool_To_config_ElasticSearchSpool(in, out, s)
+}
+
+func autoConvert_config_ElasticSearchSpool_To_v1beta1_ElasticSearchSpool(in *config.ElasticSearchSpool, out *ElasticSearchSpool, s conversion.Scope) error {
+	out.Dir = in.Dir
+	out.S3Prefix = in.S3Prefix
+	out.RetryInterval = in.RetryInterval
+	out.MaxRetryInterval = in.MaxRetryInterval
+	return nil
+}
+
+// Convert_config_ElasticSearchSpool_To_v1beta1_ElasticSearchSpool is an autogenerated conversion function.
+func Convert_config_ElasticSearchSpool_To_v1beta1_ElasticSearchSpool(in *config.ElasticSearchSpool, out *ElasticSearchSpool, s conversion.Scope) error {
+	return autoConvert_config_ElasticSearchSpool_To_v1beta1_ElasticSearchSpool(in, out, s)
+}
+
 func autoConvert_v1beta1_GitHub_To_config_GitHub(in *GitHub, out *config.GitHub, s conversion.Scope) error {
 	if in.Cache != nil {
 		in, out := &in.Cache, &out.Cache
@@ -562,6 +989,9 @@ func autoConvert_v1beta1_GitHubCache_To_config_GitHubCache(in *GitHubCache, out
 	out.CacheDir = in.CacheDir
 	out.CacheDiskSizeGB = uint64(in.CacheDiskSizeGB)
 	out.MaxAgeSeconds = in.MaxAgeSeconds
+	out.Shared = (*config.SharedGitHubCache)(unsafe.Pointer(in.Shared))
+	out.Server = (*config.GitHubCacheServer)(unsafe.Pointer(in.Server))
+	out.RateLimitReserve = in.RateLimitReserve
 	return nil
 }
 
@@ -574,6 +1004,9 @@ func autoConvert_config_GitHubCache_To_v1beta1_GitHubCache(in *config.GitHubCach
 	out.CacheDir = in.CacheDir
 	out.CacheDiskSizeGB = int(in.CacheDiskSizeGB)
 	out.MaxAgeSeconds = in.MaxAgeSeconds
+	out.Shared = (*SharedGitHubCache)(unsafe.Pointer(in.Shared))
+	out.Server = (*GitHubCacheServer)(unsafe.Pointer(in.Server))
+	out.RateLimitReserve = in.RateLimitReserve
 	return nil
 }
 
@@ -582,6 +1015,76 @@ func Convert_config_GitHubCache_To_v1beta1_GitHubCache(in *config.GitHubCache, o
 	return autoConvert_config_GitHubCache_To_v1beta1_GitHubCache(in, out, s)
 }
 
+func autoConvert_v1beta1_GitHubCacheServer_To_config_GitHubCacheServer(in *GitHubCacheServer, out *config.GitHubCacheServer, s conversion.Scope) error {
+	out.BindAddress = in.BindAddress
+	out.Token = in.Token
+	out.TokenPath = in.TokenPath
+	return nil
+}
+
+// Convert_v1beta1_GitHubCacheServer_To_config_GitHubCacheServer is an autogenerated conversion function.
+func Convert_v1beta1_GitHubCacheServer_To_config_GitHubCacheServer(in *GitHubCacheServer, out *config.GitHubCacheServer, s conversion.Scope) error {
+	return autoConvert_v1beta1_GitHubCacheServer_To_config_GitHubCacheServer(in, out, s)
+}
+
+func autoConvert_config_GitHubCacheServer_To_v1beta1_GitHubCacheServer(in *config.GitHubCacheServer, out *GitHubCacheServer, s conversion.Scope) error {
+	out.BindAddress = in.BindAddress
+	out.Token = in.Token
+	out.TokenPath = in.TokenPath
+	return nil
+}
+
+// Convert_config_GitHubCacheServer_To_v1beta1_GitHubCacheServer is an autogenerated conversion function.
+func Convert_config_GitHubCacheServer_To_v1beta1_GitHubCacheServer(in *config.GitHubCacheServer, out *GitHubCacheServer, s conversion.Scope) error {
+	return autoConvert_config_GitHubCacheServer_To_v1beta1_GitHubCacheServer(in, out, s)
+}
+
+func autoConvert_v1beta1_GitProvider_To_config_GitProvider(in *GitProvider, out *config.GitProvider, s conversion.Scope) error {
+	out.Domain = in.Domain
+	out.Type = config.GitProviderType(in.Type)
+	out.APIURL = in.APIURL
+	return nil
+}
+
+// Convert_v1beta1_GitProvider_To_config_GitProvider is an autogenerated conversion function.
+func Convert_v1beta1_GitProvider_To_config_GitProvider(in *GitProvider, out *config.GitProvider, s conversion.Scope) error {
+	return autoConvert_v1beta1_GitProvider_To_config_GitProvider(in, out, s)
+}
+
+func autoConvert_config_GitProvider_To_v1beta1_GitProvider(in *config.GitProvider, out *GitProvider, s conversion.Scope) error {
+	out.Domain = in.Domain
+	out.Type = GitProviderType(in.Type)
+	out.APIURL = in.APIURL
+	return nil
+}
+
+// Convert_config_GitProvider_To_v1beta1_GitProvider is an autogenerated conversion function.
+func Convert_config_GitProvider_To_v1beta1_GitProvider(in *config.GitProvider, out *GitProvider, s conversion.Scope) error {
+	return autoConvert_config_GitProvider_To_v1beta1_GitProvider(in, out, s)
+}
+
+func autoConvert_v1beta1_HTTPGitHubCache_To_config_HTTPGitHubCache(in *HTTPGitHubCache, out *config.HTTPGitHubCache, s conversion.Scope) error {
+	out.URL = in.URL
+	out.Token = in.Token
+	return nil
+}
+
+// Convert_v1beta1_HTTPGitHubCache_To_config_HTTPGitHubCache is an autogenerated conversion function.
+func Convert_v1beta1_HTTPGitHubCache_To_config_HTTPGitHubCache(in *HTTPGitHubCache, out *config.HTTPGitHubCache, s conversion.Scope) error {
+	return autoConvert_v1beta1_HTTPGitHubCache_To_config_HTTPGitHubCache(in, out, s)
+}
+
+func autoConvert_config_HTTPGitHubCache_To_v1beta1_HTTPGitHubCache(in *config.HTTPGitHubCache, out *HTTPGitHubCache, s conversion.Scope) error {
+	out.URL = in.URL
+	out.Token = in.Token
+	return nil
+}
+
+// Convert_config_HTTPGitHubCache_To_v1beta1_HTTPGitHubCache is an autogenerated conversion function.
+func Convert_config_HTTPGitHubCache_To_v1beta1_HTTPGitHubCache(in *config.HTTPGitHubCache, out *HTTPGitHubCache, s conversion.Scope) error {
+	return autoConvert_config_HTTPGitHubCache_To_v1beta1_HTTPGitHubCache(in, out, s)
+}
+
 func autoConvert_v1beta1_HealthCheckTarget_To_config_HealthCheckTarget(in *HealthCheckTarget, out *config.HealthCheckTarget, s conversion.Scope) error {
 	out.Namespace = in.Namespace
 	out.DeploymentName = in.DeploymentName
@@ -606,6 +1109,52 @@ func Convert_config_HealthCheckTarget_To_v1beta1_HealthCheckTarget(in *config.He
 	return autoConvert_config_HealthCheckTarget_To_v1beta1_HealthCheckTarget(in, out, s)
 }
 
+func autoConvert_v1beta1_ImagePolicy_To_config_ImagePolicy(in *ImagePolicy, out *config.ImagePolicy, s conversion.Scope) error {
+	out.Enforcement = config.PolicyEnforcement(in.Enforcement)
+	out.AllowedRegistries = *(*[]string)(unsafe.Pointer(&in.AllowedRegistries))
+	return nil
+}
+
+// Convert_v1beta1_ImagePolicy_To_config_ImagePolicy is an autogenerated conversion function.
+func Convert_v1beta1_ImagePolicy_To_config_ImagePolicy(in *ImagePolicy, out *config.ImagePolicy, s conversion.Scope) error {
+	return autoConvert_v1beta1_ImagePolicy_To_config_ImagePolicy(in, out, s)
+}
+
+func autoConvert_config_ImagePolicy_To_v1beta1_ImagePolicy(in *config.ImagePolicy, out *ImagePolicy, s conversion.Scope) error {
+	out.Enforcement = PolicyEnforcement(in.Enforcement)
+	out.AllowedRegistries = *(*[]string)(unsafe.Pointer(&in.AllowedRegistries))
+	return nil
+}
+
+// Convert_config_ImagePolicy_To_v1beta1_ImagePolicy is an autogenerated conversion function.
+func Convert_config_ImagePolicy_To_v1beta1_ImagePolicy(in *config.ImagePolicy, out *ImagePolicy, s conversion.Scope) error {
+	return autoConvert_config_ImagePolicy_To_v1beta1_ImagePolicy(in, out, s)
+}
+
+func autoConvert_v1beta1_KubeconfigPreflight_To_config_KubeconfigPreflight(in *KubeconfigPreflight, out *config.KubeconfigPreflight, s conversion.Scope) error {
+	out.CertificateExpirationWarning = in.CertificateExpirationWarning
+	out.ProbeServer = in.ProbeServer
+	out.ProbeTimeout = in.ProbeTimeout
+	return nil
+}
+
+// Convert_v1beta1_KubeconfigPreflight_To_config_KubeconfigPreflight is an autogenerated conversion function.
+func Convert_v1beta1_KubeconfigPreflight_To_config_KubeconfigPreflight(in *KubeconfigPreflight, out *config.KubeconfigPreflight, s conversion.Scope) error {
+	return autoConvert_v1beta1_KubeconfigPreflight_To_config_KubeconfigPreflight(in, out, s)
+}
+
+func autoConvert_config_KubeconfigPreflight_To_v1beta1_KubeconfigPreflight(in *config.KubeconfigPreflight, out *KubeconfigPreflight, s conversion.Scope) error {
+	out.CertificateExpirationWarning = in.CertificateExpirationWarning
+	out.ProbeServer = in.ProbeServer
+	out.ProbeTimeout = in.ProbeTimeout
+	return nil
+}
+
+// Convert_config_KubeconfigPreflight_To_v1beta1_KubeconfigPreflight is an autogenerated conversion function.
+func Convert_config_KubeconfigPreflight_To_v1beta1_KubeconfigPreflight(in *config.KubeconfigPreflight, out *KubeconfigPreflight, s conversion.Scope) error {
+	return autoConvert_config_KubeconfigPreflight_To_v1beta1_KubeconfigPreflight(in, out, s)
+}
+
 func autoConvert_v1beta1_LandscapeMapping_To_config_LandscapeMapping(in *LandscapeMapping, out *config.LandscapeMapping, s conversion.Scope) error {
 	out.Namespace = in.Namespace
 	out.ApiServerUrl = in.ApiServerUrl
@@ -634,8 +1183,31 @@ func Convert_config_LandscapeMapping_To_v1beta1_LandscapeMapping(in *config.Land
 	return autoConvert_config_LandscapeMapping_To_v1beta1_LandscapeMapping(in, out, s)
 }
 
+func autoConvert_v1beta1_LocationPolicy_To_config_LocationPolicy(in *LocationPolicy, out *config.LocationPolicy, s conversion.Scope) error {
+	out.Enforcement = config.PolicyEnforcement(in.Enforcement)
+	out.AllowedDomains = *(*[]string)(unsafe.Pointer(&in.AllowedDomains))
+	return nil
+}
+
+// Convert_v1beta1_LocationPolicy_To_config_LocationPolicy is an autogenerated conversion function.
+func Convert_v1beta1_LocationPolicy_To_config_LocationPolicy(in *LocationPolicy, out *config.LocationPolicy, s conversion.Scope) error {
+	return autoConvert_v1beta1_LocationPolicy_To_config_LocationPolicy(in, out, s)
+}
+
+func autoConvert_config_LocationPolicy_To_v1beta1_LocationPolicy(in *config.LocationPolicy, out *LocationPolicy, s conversion.Scope) error {
+	out.Enforcement = PolicyEnforcement(in.Enforcement)
+	out.AllowedDomains = *(*[]string)(unsafe.Pointer(&in.AllowedDomains))
+	return nil
+}
+
+// Convert_config_LocationPolicy_To_v1beta1_LocationPolicy is an autogenerated conversion function.
+func Convert_config_LocationPolicy_To_v1beta1_LocationPolicy(in *config.LocationPolicy, out *LocationPolicy, s conversion.Scope) error {
+	return autoConvert_config_LocationPolicy_To_v1beta1_LocationPolicy(in, out, s)
+}
+
 func autoConvert_v1beta1_Locations_To_config_Locations(in *Locations, out *config.Locations, s conversion.Scope) error {
 	out.ExcludeDomains = *(*[]string)(unsafe.Pointer(&in.ExcludeDomains))
+	out.GitProviders = *(*[]config.GitProvider)(unsafe.Pointer(&in.GitProviders))
 	return nil
 }
 
@@ -646,6 +1218,7 @@ func Convert_v1beta1_Locations_To_config_Locations(in *Locations, out *config.Lo
 
 func autoConvert_config_Locations_To_v1beta1_Locations(in *config.Locations, out *Locations, s conversion.Scope) error {
 	out.ExcludeDomains = *(*[]string)(unsafe.Pointer(&in.ExcludeDomains))
+	out.GitProviders = *(*[]GitProvider)(unsafe.Pointer(&in.GitProviders))
 	return nil
 }
 
@@ -678,6 +1251,112 @@ func Convert_config_OAuth_To_v1beta1_OAuth(in *config.OAuth, out *OAuth, s conve
 	return autoConvert_config_OAuth_To_v1beta1_OAuth(in, out, s)
 }
 
+func autoConvert_v1beta1_RedisGitHubCache_To_config_RedisGitHubCache(in *RedisGitHubCache, out *config.RedisGitHubCache, s conversion.Scope) error {
+	out.Address = in.Address
+	out.Password = in.Password
+	out.DB = in.DB
+	out.KeyPrefix = in.KeyPrefix
+	out.TTL = in.TTL
+	return nil
+}
+
+// Convert_v1beta1_RedisGitHubCache_To_config_RedisGitHubCache is an autogenerated conversion function.
+func Convert_v1beta1_RedisGitHubCache_To_config_RedisGitHubCache(in *RedisGitHubCache, out *config.RedisGitHubCache, s conversion.Scope) error {
+	return autoConvert_v1beta1_RedisGitHubCache_To_config_RedisGitHubCache(in, out, s)
+}
+
+func autoConvert_config_RedisGitHubCache_To_v1beta1_RedisGitHubCache(in *config.RedisGitHubCache, out *RedisGitHubCache, s conversion.Scope) error {
+	out.Address = in.Address
+	out.Password = in.Password
+	out.DB = in.DB
+	out.KeyPrefix = in.KeyPrefix
+	out.TTL = in.TTL
+	return nil
+}
+
+// Convert_config_RedisGitHubCache_To_v1beta1_RedisGitHubCache is an autogenerated conversion function.
+func Convert_config_RedisGitHubCache_To_v1beta1_RedisGitHubCache(in *config.RedisGitHubCache, out *RedisGitHubCache, s conversion.Scope) error {
+	return autoConvert_config_RedisGitHubCache_To_v1beta1_RedisGitHubCache(in, out, s)
+}
+
+func autoConvert_v1beta1_RepositoryCache_To_config_RepositoryCache(in *RepositoryCache, out *config.RepositoryCache, s conversion.Scope) error {
+	out.PVC = (*config.RepositoryCachePVC)(unsafe.Pointer(in.PVC))
+	out.S3 = (*config.RepositoryCacheS3)(unsafe.Pointer(in.S3))
+	out.MaxAge = in.MaxAge
+	out.MaxSizeGB = in.MaxSizeGB
+	out.LockTimeout = in.LockTimeout
+	return nil
+}
+
+// Convert_v1beta1_RepositoryCache_To_config_RepositoryCache is an autogenerated conversion function.
+func Convert_v1beta1_RepositoryCache_To_config_RepositoryCache(in *RepositoryCache, out *config.RepositoryCache, s conversion.Scope) error {
+	return autoConvert_v1beta1_RepositoryCache_To_config_RepositoryCache(in, out, s)
+}
+
+func autoConvert_config_RepositoryCache_To_v1beta1_RepositoryCache(in *config.RepositoryCache, out *RepositoryCache, s conversion.Scope) error {
+	out.PVC = (*RepositoryCachePVC)(unsafe.Pointer(in.PVC))
+	out.S3 = (*RepositoryCacheS3)(unsafe.Pointer(in.S3))
+	out.MaxAge = in.MaxAge
+	out.MaxSizeGB = in.MaxSizeGB
+	out.LockTimeout = in.LockTimeout
+	return nil
+}
+
+// Convert_config_RepositoryCache_To_v1beta1_RepositoryCache is an autogenerated conversion function.
+func Convert_config_RepositoryCache_To_v1beta1_RepositoryCache(in *config.RepositoryCache, out *RepositoryCache, s conversion.Scope) error {
+	return autoConvert_config_RepositoryCache_To_v1beta1_RepositoryCache(in, out, s)
+}
+
+func autoConvert_v1beta1_RepositoryCachePVC_To_config_RepositoryCachePVC(in *RepositoryCachePVC, out *config.RepositoryCachePVC, s conversion.Scope) error {
+	out.ClaimName = in.ClaimName
+	return nil
+}
+
+// Convert_v1beta1_RepositoryCachePVC_To_config_RepositoryCachePVC is an autogenerated conversion function.
+func Convert_v1beta1_RepositoryCachePVC_To_config_RepositoryCachePVC(in *RepositoryCachePVC, out *config.RepositoryCachePVC, s conversion.Scope) error {
+	return autoConvert_v1beta1_RepositoryCachePVC_To_config_RepositoryCachePVC(in, out, s)
+}
+
+func autoConvert_config_RepositoryCachePVC_To_v1beta1_RepositoryCachePVC(in *config.RepositoryCachePVC, out *RepositoryCachePVC, s conversion.Scope) error {
+	out.ClaimName = in.ClaimName
+	return nil
+}
+
+// Convert_config_RepositoryCachePVC_To_v1beta1_RepositoryCachePVC is an autogenerated conversion function.
+func Convert_config_RepositoryCachePVC_To_v1beta1_RepositoryCachePVC(in *config.RepositoryCachePVC, out *RepositoryCachePVC, s conversion.Scope) error {
+	return autoConvert_config_RepositoryCachePVC_To_v1beta1_RepositoryCachePVC(in, out, s)
+}
+
+func autoConvert_v1beta1_RepositoryCacheS3_To_config_RepositoryCacheS3(in *RepositoryCacheS3, out *config.RepositoryCacheS3, s conversion.Scope) error {
+	if err := Convert_v1beta1_S3Server_To_config_S3Server(&in.Server, &out.Server, s); err != nil {
+		return err
+	}
+	out.BucketName = in.BucketName
+	out.Prefix = in.Prefix
+	out.SecretName = in.SecretName
+	return nil
+}
+
+// Convert_v1beta1_RepositoryCacheS3_To_config_RepositoryCacheS3 is an autogenerated conversion function.
+func Convert_v1beta1_RepositoryCacheS3_To_config_RepositoryCacheS3(in *RepositoryCacheS3, out *config.RepositoryCacheS3, s conversion.Scope) error {
+	return autoConvert_v1beta1_RepositoryCacheS3_To_config_RepositoryCacheS3(in, out, s)
+}
+
+func autoConvert_config_RepositoryCacheS3_To_v1beta1_RepositoryCacheS3(in *config.RepositoryCacheS3, out *RepositoryCacheS3, s conversion.Scope) error {
+	if err := Convert_config_S3Server_To_v1beta1_S3Server(&in.Server, &out.Server, s); err != nil {
+		return err
+	}
+	out.BucketName = in.BucketName
+	out.Prefix = in.Prefix
+	out.SecretName = in.SecretName
+	return nil
+}
+
+// Convert_config_RepositoryCacheS3_To_v1beta1_RepositoryCacheS3 is an autogenerated conversion function.
+func Convert_config_RepositoryCacheS3_To_v1beta1_RepositoryCacheS3(in *config.RepositoryCacheS3, out *RepositoryCacheS3, s conversion.Scope) error {
+	return autoConvert_config_RepositoryCacheS3_To_v1beta1_RepositoryCacheS3(in, out, s)
+}
+
 func autoConvert_v1beta1_S3_To_config_S3(in *S3, out *config.S3, s conversion.Scope) error {
 	if err := Convert_v1beta1_S3Server_To_config_S3Server(&in.Server, &out.Server, s); err != nil {
 		return err
@@ -730,6 +1409,28 @@ func Convert_config_S3Server_To_v1beta1_S3Server(in *config.S3Server, out *S3Ser
 	return autoConvert_config_S3Server_To_v1beta1_S3Server(in, out, s)
 }
 
+func autoConvert_v1beta1_SharedGitHubCache_To_config_SharedGitHubCache(in *SharedGitHubCache, out *config.SharedGitHubCache, s conversion.Scope) error {
+	out.Redis = (*config.RedisGitHubCache)(unsafe.Pointer(in.Redis))
+	out.HTTP = (*config.HTTPGitHubCache)(unsafe.Pointer(in.HTTP))
+	return nil
+}
+
+// Convert_v1beta1_SharedGitHubCache_To_config_SharedGitHubCache is an autogenerated conversion function.
+func Convert_v1beta1_SharedGitHubCache_To_config_SharedGitHubCache(in *SharedGitHubCache, out *config.SharedGitHubCache, s conversion.Scope) error {
+	return autoConvert_v1beta1_SharedGitHubCache_To_config_SharedGitHubCache(in, out, s)
+}
+
+func autoConvert_config_SharedGitHubCache_To_v1beta1_SharedGitHubCache(in *config.SharedGitHubCache, out *SharedGitHubCache, s conversion.Scope) error {
+	out.Redis = (*RedisGitHubCache)(unsafe.Pointer(in.Redis))
+	out.HTTP = (*HTTPGitHubCache)(unsafe.Pointer(in.HTTP))
+	return nil
+}
+
+// Convert_config_SharedGitHubCache_To_v1beta1_SharedGitHubCache is an autogenerated conversion function.
+func Convert_config_SharedGitHubCache_To_v1beta1_SharedGitHubCache(in *config.SharedGitHubCache, out *SharedGitHubCache, s conversion.Scope) error {
+	return autoConvert_config_SharedGitHubCache_To_v1beta1_SharedGitHubCache(in, out, s)
+}
+
 func autoConvert_v1beta1_TTLController_To_config_TTLController(in *TTLController, out *config.TTLController, s conversion.Scope) error {
 	out.Disable = in.Disable
 	out.MaxConcurrentSyncs = in.MaxConcurrentSyncs
@@ -767,6 +1468,9 @@ func autoConvert_v1beta1_TestMachinery_To_config_TestMachinery(in *TestMachinery
 	out.DisableCollector = in.DisableCollector
 	out.CleanWorkflowPods = in.CleanWorkflowPods
 	out.LandscapeMappings = *(*[]config.LandscapeMapping)(unsafe.Pointer(&in.LandscapeMappings))
+	out.RepositoryCache = (*config.RepositoryCache)(unsafe.Pointer(in.RepositoryCache))
+	out.ConfigSources = (*config.ConfigSources)(unsafe.Pointer(in.ConfigSources))
+	out.KubeconfigPreflight = (*config.KubeconfigPreflight)(unsafe.Pointer(in.KubeconfigPreflight))
 	return nil
 }
 
@@ -790,6 +1494,9 @@ func autoConvert_config_TestMachinery_To_v1beta1_TestMachinery(in *config.TestMa
 	out.DisableCollector = in.DisableCollector
 	out.CleanWorkflowPods = in.CleanWorkflowPods
 	out.LandscapeMappings = *(*[]LandscapeMapping)(unsafe.Pointer(&in.LandscapeMappings))
+	out.RepositoryCache = (*RepositoryCache)(unsafe.Pointer(in.RepositoryCache))
+	out.ConfigSources = (*ConfigSources)(unsafe.Pointer(in.ConfigSources))
+	out.KubeconfigPreflight = (*KubeconfigPreflight)(unsafe.Pointer(in.KubeconfigPreflight))
 	return nil
 }
 
@@ -798,6 +1505,52 @@ func Convert_config_TestMachinery_To_v1beta1_TestMachinery(in *config.TestMachin
 	return autoConvert_config_TestMachinery_To_v1beta1_TestMachinery(in, out, s)
 }
 
+func autoConvert_v1beta1_UntrustedStepPolicy_To_config_UntrustedStepPolicy(in *UntrustedStepPolicy, out *config.UntrustedStepPolicy, s conversion.Scope) error {
+	out.Enforcement = config.PolicyEnforcement(in.Enforcement)
+	return nil
+}
+
+// Convert_v1beta1_UntrustedStepPolicy_To_config_UntrustedStepPolicy is an autogenerated conversion function.
+func Convert_v1beta1_UntrustedStepPolicy_To_config_UntrustedStepPolicy(in *UntrustedStepPolicy, out *config.UntrustedStepPolicy, s conversion.Scope) error {
+	return autoConvert_v1beta1_UntrustedStepPolicy_To_config_UntrustedStepPolicy(in, out, s)
+}
+
+func autoConvert_config_UntrustedStepPolicy_To_v1beta1_UntrustedStepPolicy(in *config.UntrustedStepPolicy, out *UntrustedStepPolicy, s conversion.Scope) error {
+	out.Enforcement = PolicyEnforcement(in.Enforcement)
+	return nil
+}
+
+// Convert_config_UntrustedStepPolicy_To_v1beta1_UntrustedStepPolicy is an autogenerated conversion function.
+func Convert_config_UntrustedStepPolicy_To_v1beta1_UntrustedStepPolicy(in *config.UntrustedStepPolicy, out *UntrustedStepPolicy, s conversion.Scope) error {
+	return autoConvert_config_UntrustedStepPolicy_To_v1beta1_UntrustedStepPolicy(in, out, s)
+}
+
+func autoConvert_v1beta1_VaultConfigSource_To_config_VaultConfigSource(in *VaultConfigSource, out *config.VaultConfigSource, s conversion.Scope) error {
+	out.Address = in.Address
+	out.TokenPath = in.TokenPath
+	out.Namespace = in.Namespace
+	out.KVVersion = in.KVVersion
+	return nil
+}
+
+// Convert_v1beta1_VaultConfigSource_To_config_VaultConfigSource is an autogenerated conversion function.
+func Convert_v1beta1_VaultConfigSource_To_config_VaultConfigSource(in *VaultConfigSource, out *config.VaultConfigSource, s conversion.Scope) error {
+	return autoConvert_v1beta1_VaultConfigSource_To_config_VaultConfigSource(in, out, s)
+}
+
+func autoConvert_config_VaultConfigSource_To_v1beta1_VaultConfigSource(in *config.VaultConfigSource, out *VaultConfigSource, s conversion.Scope) error {
+	out.Address = in.Address
+	out.TokenPath = in.TokenPath
+	out.Namespace = in.Namespace
+	out.KVVersion = in.KVVersion
+	return nil
+}
+
+// Convert_config_VaultConfigSource_To_v1beta1_VaultConfigSource is an autogenerated conversion function.
+func Convert_config_VaultConfigSource_To_v1beta1_VaultConfigSource(in *config.VaultConfigSource, out *VaultConfigSource, s conversion.Scope) error {
+	return autoConvert_config_VaultConfigSource_To_v1beta1_VaultConfigSource(in, out, s)
+}
+
 func autoConvert_v1beta1_WebhookConfig_To_config_WebhookConfig(in *WebhookConfig, out *config.WebhookConfig, s conversion.Scope) error {
 	out.Port = in.Port
 	out.CertDir = in.CertDir
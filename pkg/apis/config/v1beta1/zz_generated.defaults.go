@@ -29,8 +29,20 @@ func SetObjectDefaults_BotConfiguration(in *BotConfiguration) {
 		SetDefaults_GitHubAuthentication(in.Dashboard.Authentication.GitHub)
 	}
 	SetDefaults_GitHubBot(&in.GitHubBot)
+	if in.GitHubBot.GitHubCache.Shared != nil {
+		if in.GitHubBot.GitHubCache.Shared.Redis != nil {
+			SetDefaults_RedisGitHubCache(in.GitHubBot.GitHubCache.Shared.Redis)
+		}
+	}
 }
 
 func SetObjectDefaults_Configuration(in *Configuration) {
 	SetDefaults_Configuration(in)
+	if in.GitHub.Cache != nil {
+		if in.GitHub.Cache.Shared != nil {
+			if in.GitHub.Cache.Shared.Redis != nil {
+				SetDefaults_RedisGitHubCache(in.GitHub.Cache.Shared.Redis)
+			}
+		}
+	}
 }
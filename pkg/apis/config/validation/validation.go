@@ -5,6 +5,10 @@
 package validation
 
 import (
+	"net/url"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	"github.com/gardener/test-infra/pkg/apis/config"
@@ -19,8 +23,281 @@ func ValidateConfiguration(config *config.Configuration) field.ErrorList {
 			allErrs = append(allErrs, field.Required(field.NewPath("elasticsearchConfiguration"), "elastic search config is required if collector is enabled"))
 		}
 	}
+	if config.ElasticSearch != nil {
+		allErrs = append(allErrs, validateElasticSearchSpool(config.ElasticSearch.Spool, config.S3, field.NewPath("elasticsearchConfiguration", "spool"))...)
+	}
 
 	allErrs = append(allErrs, validateS3Config(config.S3, field.NewPath("s3Configuration"))...)
+	allErrs = append(allErrs, validateGitProviders(config.TestMachinery.Locations.GitProviders, field.NewPath("testmachinery", "locations", "gitProviders"))...)
+	if config.TestMachinery.RepositoryCache != nil {
+		allErrs = append(allErrs, validateRepositoryCache(config.TestMachinery.RepositoryCache, field.NewPath("testmachinery", "repositoryCache"))...)
+	}
+	if config.TestMachinery.ConfigSources != nil {
+		allErrs = append(allErrs, validateConfigSources(config.TestMachinery.ConfigSources, field.NewPath("testmachinery", "configSources"))...)
+	}
+	if config.TestMachinery.KubeconfigPreflight != nil {
+		allErrs = append(allErrs, validateKubeconfigPreflight(config.TestMachinery.KubeconfigPreflight, field.NewPath("testmachinery", "kubeconfigPreflight"))...)
+	}
+	if config.Controller.AdmissionPolicies != nil {
+		allErrs = append(allErrs, validateAdmissionPolicies(config.Controller.AdmissionPolicies, field.NewPath("controller", "admissionPolicies"))...)
+	}
+	if config.Controller.Defaulting != nil {
+		allErrs = append(allErrs, validateDefaulting(config.Controller.Defaulting, field.NewPath("controller", "defaulting"))...)
+	}
+	if config.GitHub.Cache != nil {
+		allErrs = append(allErrs, ValidateGitHubCache(config.GitHub.Cache, field.NewPath("github", "cache"))...)
+	}
+
+	return allErrs
+}
+
+// ValidateGitHubCache validates the github cache and its shared backend
+func ValidateGitHubCache(cache *config.GitHubCache, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if cache.RateLimitReserve < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("rateLimitReserve"), cache.RateLimitReserve, "must not be negative"))
+	}
+	if shared := cache.Shared; shared != nil {
+		sharedPath := fldPath.Child("shared")
+		if (shared.Redis == nil) == (shared.HTTP == nil) {
+			allErrs = append(allErrs, field.Invalid(sharedPath, "", "exactly one of redis and http has to be defined"))
+		}
+		if shared.Redis != nil {
+			if len(shared.Redis.Address) == 0 {
+				allErrs = append(allErrs, field.Required(sharedPath.Child("redis", "address"), "address must be defined"))
+			}
+			if shared.Redis.DB < 0 {
+				allErrs = append(allErrs, field.Invalid(sharedPath.Child("redis", "db"), shared.Redis.DB, "must not be negative"))
+			}
+			if shared.Redis.TTL.Duration < 0 {
+				allErrs = append(allErrs, field.Invalid(sharedPath.Child("redis", "ttl"), shared.Redis.TTL.Duration.String(), "must not be negative"))
+			}
+		}
+		if shared.HTTP != nil {
+			if u, err := url.Parse(shared.HTTP.URL); err != nil || len(u.Scheme) == 0 || len(u.Host) == 0 {
+				allErrs = append(allErrs, field.Invalid(sharedPath.Child("http", "url"), shared.HTTP.URL, "must be an absolute url"))
+			}
+		}
+	}
+	if cache.Server != nil {
+		if len(cache.Server.BindAddress) == 0 {
+			allErrs = append(allErrs, field.Required(fldPath.Child("server", "bindAddress"), "bind address must be defined"))
+		}
+		if (len(cache.Server.Token) == 0) == (len(cache.Server.TokenPath) == 0) {
+			allErrs = append(allErrs, field.Required(fldPath.Child("server", "token"), "exactly one of token and tokenPath has to be defined"))
+		}
+		if cache.Shared != nil && cache.Shared.HTTP != nil {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("server"), "a cache that is served must not use a http cache service as backend"))
+		}
+	}
+
+	return allErrs
+}
+
+// validateGitProviders validates the git providers of location domains
+func validateGitProviders(providers []config.GitProvider, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	domains := sets.New[string]()
+	for i, provider := range providers {
+		providerPath := fldPath.Index(i)
+		if len(provider.Domain) == 0 {
+			allErrs = append(allErrs, field.Required(providerPath.Child("domain"), "domain must be defined"))
+		} else if domains.Has(provider.Domain) {
+			allErrs = append(allErrs, field.Duplicate(providerPath.Child("domain"), provider.Domain))
+		}
+		domains.Insert(provider.Domain)
+
+		switch provider.Type {
+		case config.GitProviderGitHub, config.GitProviderGitLab, config.GitProviderGit:
+		default:
+			allErrs = append(allErrs, field.NotSupported(providerPath.Child("type"), provider.Type,
+				[]config.GitProviderType{config.GitProviderGitHub, config.GitProviderGitLab, config.GitProviderGit}))
+		}
+		if len(provider.APIURL) != 0 && provider.Type == config.GitProviderGit {
+			allErrs = append(allErrs, field.Forbidden(providerPath.Child("apiUrl"), "the git provider does not use an API"))
+		}
+	}
+	return allErrs
+}
+
+// validateRepositoryCache validates the shared repository cache of the prepare step
+func validateRepositoryCache(cache *config.RepositoryCache, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if (cache.PVC == nil) == (cache.S3 == nil) {
+		allErrs = append(allErrs, field.Invalid(fldPath, "", "exactly one of pvc and s3 has to be defined"))
+	}
+	if cache.PVC != nil && len(cache.PVC.ClaimName) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("pvc", "claimName"), "claim name must be defined"))
+	}
+	if cache.S3 != nil {
+		s3Path := fldPath.Child("s3")
+		if len(cache.S3.Server.Endpoint) == 0 {
+			allErrs = append(allErrs, field.Required(s3Path.Child("server", "endpoint"), "endpoint must be defined"))
+		}
+		if len(cache.S3.BucketName) == 0 {
+			allErrs = append(allErrs, field.Required(s3Path.Child("bucketName"), "bucket name must be defined"))
+		}
+		if len(cache.S3.SecretName) == 0 {
+			allErrs = append(allErrs, field.Required(s3Path.Child("secretName"), "secret name must be defined"))
+		}
+	}
+	if cache.MaxAge.Duration < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("maxAge"), cache.MaxAge.Duration.String(), "must not be negative"))
+	}
+	if cache.MaxSizeGB < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("maxSizeGB"), cache.MaxSizeGB, "must not be negative"))
+	}
+	if cache.LockTimeout.Duration < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("lockTimeout"), cache.LockTimeout.Duration.String(), "must not be negative"))
+	}
+
+	return allErrs
+}
+
+// validateConfigSources validates the additional sources of config element values
+func validateConfigSources(sources *config.ConfigSources, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for i, namespace := range sources.AllowedSecretNamespaces {
+		if len(namespace) == 0 {
+			allErrs = append(allErrs, field.Required(fldPath.Child("allowedSecretNamespaces").Index(i), "namespace must not be empty"))
+		}
+	}
+	if sources.Vault != nil {
+		vaultPath := fldPath.Child("vault")
+		if u, err := url.Parse(sources.Vault.Address); err != nil || !u.IsAbs() {
+			allErrs = append(allErrs, field.Invalid(vaultPath.Child("address"), sources.Vault.Address, "address must be an absolute url"))
+		}
+		if len(sources.Vault.TokenPath) == 0 {
+			allErrs = append(allErrs, field.Required(vaultPath.Child("tokenPath"), "token path must be defined"))
+		}
+		if sources.Vault.KVVersion != 1 && sources.Vault.KVVersion != 2 {
+			allErrs = append(allErrs, field.NotSupported(vaultPath.Child("kvVersion"), sources.Vault.KVVersion, []string{"1", "2"}))
+		}
+	}
+
+	return allErrs
+}
+
+// validateKubeconfigPreflight validates the checks of testrun kubeconfigs
+func validateKubeconfigPreflight(preflight *config.KubeconfigPreflight, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if preflight.CertificateExpirationWarning.Duration < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("certificateExpirationWarning"), preflight.CertificateExpirationWarning.Duration.String(), "must not be negative"))
+	}
+	if preflight.ProbeTimeout.Duration < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("probeTimeout"), preflight.ProbeTimeout.Duration.String(), "must not be negative"))
+	}
+
+	return allErrs
+}
+
+// validateAdmissionPolicies validates the policies that testruns have to comply with
+func validateAdmissionPolicies(policies *config.AdmissionPolicies, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if policies.Images != nil {
+		allErrs = append(allErrs, validatePolicyEnforcement(policies.Images.Enforcement, fldPath.Child("images", "enforcement"))...)
+		allErrs = append(allErrs, validateNonEmptyList(policies.Images.AllowedRegistries, fldPath.Child("images", "allowedRegistries"))...)
+	}
+	if policies.ActiveDeadline != nil {
+		allErrs = append(allErrs, validatePolicyEnforcement(policies.ActiveDeadline.Enforcement, fldPath.Child("activeDeadline", "enforcement"))...)
+		if policies.ActiveDeadline.MaxSeconds <= 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("activeDeadline", "maxSeconds"), policies.ActiveDeadline.MaxSeconds, "must be greater than 0"))
+		}
+	}
+	if policies.Annotations != nil {
+		allErrs = append(allErrs, validatePolicyEnforcement(policies.Annotations.Enforcement, fldPath.Child("annotations", "enforcement"))...)
+		allErrs = append(allErrs, validateNonEmptyList(policies.Annotations.Required, fldPath.Child("annotations", "required"))...)
+	}
+	if policies.UntrustedSteps != nil {
+		allErrs = append(allErrs, validatePolicyEnforcement(policies.UntrustedSteps.Enforcement, fldPath.Child("untrustedSteps", "enforcement"))...)
+	}
+	if policies.Locations != nil {
+		allErrs = append(allErrs, validatePolicyEnforcement(policies.Locations.Enforcement, fldPath.Child("locations", "enforcement"))...)
+		allErrs = append(allErrs, validateNonEmptyList(policies.Locations.AllowedDomains, fldPath.Child("locations", "allowedDomains"))...)
+	}
+
+	return allErrs
+}
+
+// validateDefaulting validates the default profiles of testruns
+func validateDefaulting(defaulting *config.Defaulting, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	names := sets.New[string]()
+	for i, profile := range defaulting.Profiles {
+		profilePath := fldPath.Child("profiles").Index(i)
+		if len(profile.Name) == 0 {
+			allErrs = append(allErrs, field.Required(profilePath.Child("name"), "name must be defined"))
+		} else if names.Has(profile.Name) {
+			allErrs = append(allErrs, field.Duplicate(profilePath.Child("name"), profile.Name))
+		}
+		names.Insert(profile.Name)
+
+		for j, namespace := range profile.Namespaces {
+			for _, msg := range validation.IsDNS1123Label(namespace) {
+				allErrs = append(allErrs, field.Invalid(profilePath.Child("namespaces").Index(j), namespace, msg))
+			}
+		}
+		if profile.TTLSecondsAfterFinished != nil && *profile.TTLSecondsAfterFinished < 0 {
+			allErrs = append(allErrs, field.Invalid(profilePath.Child("ttlSecondsAfterFinished"), *profile.TTLSecondsAfterFinished, "must not be negative"))
+		}
+		for j, cfg := range profile.Config {
+			if len(cfg.Name) == 0 {
+				allErrs = append(allErrs, field.Required(profilePath.Child("config").Index(j).Child("name"), "name must be defined"))
+			}
+		}
+		for key := range profile.Annotations {
+			for _, msg := range validation.IsQualifiedName(key) {
+				allErrs = append(allErrs, field.Invalid(profilePath.Child("annotations").Key(key), key, msg))
+			}
+		}
+	}
+	return allErrs
+}
+
+func validatePolicyEnforcement(enforcement config.PolicyEnforcement, fldPath *field.Path) field.ErrorList {
+	switch enforcement {
+	case config.PolicyEnforcementDeny, config.PolicyEnforcementWarn:
+		return nil
+	default:
+		return field.ErrorList{field.NotSupported(fldPath, enforcement,
+			[]config.PolicyEnforcement{config.PolicyEnforcementDeny, config.PolicyEnforcementWarn})}
+	}
+}
+
+func validateNonEmptyList(values []string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if len(values) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath, "at least one entry has to be defined"))
+	}
+	for i, value := range values {
+		if len(value) == 0 {
+			allErrs = append(allErrs, field.Required(fldPath.Index(i), "must not be empty"))
+		}
+	}
+	return allErrs
+}
+
+// validateElasticSearchSpool validates the spool of results that could not be ingested into elasticsearch
+func validateElasticSearchSpool(spool config.ElasticSearchSpool, s3 *config.S3, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if len(spool.Dir) != 0 && len(spool.S3Prefix) != 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("s3Prefix"), "only one of dir and s3Prefix can be defined"))
+	}
+	if len(spool.S3Prefix) != 0 && s3 == nil {
+		allErrs = append(allErrs, field.Required(field.NewPath("s3Configuration"), "s3 config is required for a s3 spool"))
+	}
+	if spool.RetryInterval.Duration < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("retryInterval"), spool.RetryInterval.Duration.String(), "must not be negative"))
+	}
+	if spool.MaxRetryInterval.Duration != 0 && spool.MaxRetryInterval.Duration < spool.RetryInterval.Duration {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("maxRetryInterval"), spool.MaxRetryInterval.Duration.String(), "must not be smaller than the retry interval"))
+	}
 
 	return allErrs
 }
@@ -12,16 +12,113 @@ package config
 import (
 	time "time"
 
+	v1beta1 "github.com/gardener/test-infra/pkg/apis/testmachinery/v1beta1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActiveDeadlinePolicy) DeepCopyInto(out *ActiveDeadlinePolicy) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActiveDeadlinePolicy.
+func (in *ActiveDeadlinePolicy) DeepCopy() *ActiveDeadlinePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ActiveDeadlinePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AdmissionPolicies) DeepCopyInto(out *AdmissionPolicies) {
+	*out = *in
+	if in.Images != nil {
+		in, out := &in.Images, &out.Images
+		*out = new(ImagePolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ActiveDeadline != nil {
+		in, out := &in.ActiveDeadline, &out.ActiveDeadline
+		*out = new(ActiveDeadlinePolicy)
+		**out = **in
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = new(AnnotationPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UntrustedSteps != nil {
+		in, out := &in.UntrustedSteps, &out.UntrustedSteps
+		*out = new(UntrustedStepPolicy)
+		**out = **in
+	}
+	if in.Locations != nil {
+		in, out := &in.Locations, &out.Locations
+		*out = new(LocationPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdmissionPolicies.
+func (in *AdmissionPolicies) DeepCopy() *AdmissionPolicies {
+	if in == nil {
+		return nil
+	}
+	out := new(AdmissionPolicies)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Alerting) DeepCopyInto(out *Alerting) {
+	*out = *in
+	out.ElasticSearch = in.ElasticSearch
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Alerting.
+func (in *Alerting) DeepCopy() *Alerting {
+	if in == nil {
+		return nil
+	}
+	out := new(Alerting)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AnnotationPolicy) DeepCopyInto(out *AnnotationPolicy) {
+	*out = *in
+	if in.Required != nil {
+		in, out := &in.Required, &out.Required
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AnnotationPolicy.
+func (in *AnnotationPolicy) DeepCopy() *AnnotationPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(AnnotationPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BotConfiguration) DeepCopyInto(out *BotConfiguration) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	out.Webserver = in.Webserver
 	in.Dashboard.DeepCopyInto(&out.Dashboard)
-	out.GitHubBot = in.GitHubBot
+	in.GitHubBot.DeepCopyInto(&out.GitHubBot)
+	out.Alerting = in.Alerting
 	return
 }
 
@@ -59,11 +156,37 @@ func (in *Certificate) DeepCopy() *Certificate {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigSources) DeepCopyInto(out *ConfigSources) {
+	*out = *in
+	if in.AllowedSecretNamespaces != nil {
+		in, out := &in.AllowedSecretNamespaces, &out.AllowedSecretNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Vault != nil {
+		in, out := &in.Vault, &out.Vault
+		*out = new(VaultConfigSource)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigSources.
+func (in *ConfigSources) DeepCopy() *ConfigSources {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigSources)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Configuration) DeepCopyInto(out *Configuration) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
-	out.Controller = in.Controller
+	in.Controller.DeepCopyInto(&out.Controller)
 	in.TestMachinery.DeepCopyInto(&out.TestMachinery)
 	in.GitHub.DeepCopyInto(&out.GitHub)
 	if in.S3 != nil {
@@ -108,6 +231,16 @@ func (in *Controller) DeepCopyInto(out *Controller) {
 	out.TTLController = in.TTLController
 	out.WebhookConfig = in.WebhookConfig
 	out.DependencyHealthCheck = in.DependencyHealthCheck
+	if in.AdmissionPolicies != nil {
+		in, out := &in.AdmissionPolicies, &out.AdmissionPolicies
+		*out = new(AdmissionPolicies)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Defaulting != nil {
+		in, out := &in.Defaulting, &out.Defaulting
+		*out = new(Defaulting)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -159,9 +292,80 @@ func (in *DashboardAuthentication) DeepCopy() *DashboardAuthentication {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DefaultProfile) DeepCopyInto(out *DefaultProfile) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TTLSecondsAfterFinished != nil {
+		in, out := &in.TTLSecondsAfterFinished, &out.TTLSecondsAfterFinished
+		*out = new(int32)
+		**out = **in
+	}
+	if in.LocationSets != nil {
+		in, out := &in.LocationSets, &out.LocationSets
+		*out = make([]v1beta1.LocationSet, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = make([]v1beta1.ConfigElement, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DefaultProfile.
+func (in *DefaultProfile) DeepCopy() *DefaultProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(DefaultProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Defaulting) DeepCopyInto(out *Defaulting) {
+	*out = *in
+	if in.Profiles != nil {
+		in, out := &in.Profiles, &out.Profiles
+		*out = make([]DefaultProfile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Defaulting.
+func (in *Defaulting) DeepCopy() *Defaulting {
+	if in == nil {
+		return nil
+	}
+	out := new(Defaulting)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ElasticSearch) DeepCopyInto(out *ElasticSearch) {
 	*out = *in
+	out.Spool = in.Spool
 	return
 }
 
@@ -175,13 +379,31 @@ func (in *ElasticSearch) DeepCopy() *ElasticSearch {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticSearchSpool) DeepCopyInto(out *ElasticSearchSpool) {
+	*out = *in
+	out.RetryInterval = in.RetryInterval
+	out.MaxRetryInterval = in.MaxRetryInterval
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticSearchSpool.
+func (in *ElasticSearchSpool) DeepCopy() *ElasticSearchSpool {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticSearchSpool)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GitHub) DeepCopyInto(out *GitHub) {
 	*out = *in
 	if in.Cache != nil {
 		in, out := &in.Cache, &out.Cache
 		*out = new(GitHubCache)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	return
 }
@@ -220,7 +442,7 @@ func (in *GitHubAuthentication) DeepCopy() *GitHubAuthentication {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GitHubBot) DeepCopyInto(out *GitHubBot) {
 	*out = *in
-	out.GitHubCache = in.GitHubCache
+	in.GitHubCache.DeepCopyInto(&out.GitHubCache)
 	return
 }
 
@@ -237,6 +459,16 @@ func (in *GitHubBot) DeepCopy() *GitHubBot {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GitHubCache) DeepCopyInto(out *GitHubCache) {
 	*out = *in
+	if in.Shared != nil {
+		in, out := &in.Shared, &out.Shared
+		*out = new(SharedGitHubCache)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Server != nil {
+		in, out := &in.Server, &out.Server
+		*out = new(GitHubCacheServer)
+		**out = **in
+	}
 	return
 }
 
@@ -250,6 +482,54 @@ func (in *GitHubCache) DeepCopy() *GitHubCache {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitHubCacheServer) DeepCopyInto(out *GitHubCacheServer) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitHubCacheServer.
+func (in *GitHubCacheServer) DeepCopy() *GitHubCacheServer {
+	if in == nil {
+		return nil
+	}
+	out := new(GitHubCacheServer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitProvider) DeepCopyInto(out *GitProvider) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitProvider.
+func (in *GitProvider) DeepCopy() *GitProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(GitProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPGitHubCache) DeepCopyInto(out *HTTPGitHubCache) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPGitHubCache.
+func (in *HTTPGitHubCache) DeepCopy() *HTTPGitHubCache {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPGitHubCache)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HealthCheckTarget) DeepCopyInto(out *HealthCheckTarget) {
 	*out = *in
@@ -267,6 +547,45 @@ func (in *HealthCheckTarget) DeepCopy() *HealthCheckTarget {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImagePolicy) DeepCopyInto(out *ImagePolicy) {
+	*out = *in
+	if in.AllowedRegistries != nil {
+		in, out := &in.AllowedRegistries, &out.AllowedRegistries
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImagePolicy.
+func (in *ImagePolicy) DeepCopy() *ImagePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ImagePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeconfigPreflight) DeepCopyInto(out *KubeconfigPreflight) {
+	*out = *in
+	out.CertificateExpirationWarning = in.CertificateExpirationWarning
+	out.ProbeTimeout = in.ProbeTimeout
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeconfigPreflight.
+func (in *KubeconfigPreflight) DeepCopy() *KubeconfigPreflight {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeconfigPreflight)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LandscapeMapping) DeepCopyInto(out *LandscapeMapping) {
 	*out = *in
@@ -283,6 +602,27 @@ func (in *LandscapeMapping) DeepCopy() *LandscapeMapping {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LocationPolicy) DeepCopyInto(out *LocationPolicy) {
+	*out = *in
+	if in.AllowedDomains != nil {
+		in, out := &in.AllowedDomains, &out.AllowedDomains
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocationPolicy.
+func (in *LocationPolicy) DeepCopy() *LocationPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(LocationPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Locations) DeepCopyInto(out *Locations) {
 	*out = *in
@@ -291,6 +631,11 @@ func (in *Locations) DeepCopyInto(out *Locations) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.GitProviders != nil {
+		in, out := &in.GitProviders, &out.GitProviders
+		*out = make([]GitProvider, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -320,6 +665,84 @@ func (in *OAuth) DeepCopy() *OAuth {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedisGitHubCache) DeepCopyInto(out *RedisGitHubCache) {
+	*out = *in
+	out.TTL = in.TTL
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisGitHubCache.
+func (in *RedisGitHubCache) DeepCopy() *RedisGitHubCache {
+	if in == nil {
+		return nil
+	}
+	out := new(RedisGitHubCache)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepositoryCache) DeepCopyInto(out *RepositoryCache) {
+	*out = *in
+	if in.PVC != nil {
+		in, out := &in.PVC, &out.PVC
+		*out = new(RepositoryCachePVC)
+		**out = **in
+	}
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(RepositoryCacheS3)
+		**out = **in
+	}
+	out.MaxAge = in.MaxAge
+	out.LockTimeout = in.LockTimeout
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryCache.
+func (in *RepositoryCache) DeepCopy() *RepositoryCache {
+	if in == nil {
+		return nil
+	}
+	out := new(RepositoryCache)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepositoryCachePVC) DeepCopyInto(out *RepositoryCachePVC) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryCachePVC.
+func (in *RepositoryCachePVC) DeepCopy() *RepositoryCachePVC {
+	if in == nil {
+		return nil
+	}
+	out := new(RepositoryCachePVC)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepositoryCacheS3) DeepCopyInto(out *RepositoryCacheS3) {
+	*out = *in
+	out.Server = in.Server
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryCacheS3.
+func (in *RepositoryCacheS3) DeepCopy() *RepositoryCacheS3 {
+	if in == nil {
+		return nil
+	}
+	out := new(RepositoryCacheS3)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *S3) DeepCopyInto(out *S3) {
 	*out = *in
@@ -353,6 +776,32 @@ func (in *S3Server) DeepCopy() *S3Server {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SharedGitHubCache) DeepCopyInto(out *SharedGitHubCache) {
+	*out = *in
+	if in.Redis != nil {
+		in, out := &in.Redis, &out.Redis
+		*out = new(RedisGitHubCache)
+		**out = **in
+	}
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(HTTPGitHubCache)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SharedGitHubCache.
+func (in *SharedGitHubCache) DeepCopy() *SharedGitHubCache {
+	if in == nil {
+		return nil
+	}
+	out := new(SharedGitHubCache)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TTLController) DeepCopyInto(out *TTLController) {
 	*out = *in
@@ -383,6 +832,21 @@ func (in *TestMachinery) DeepCopyInto(out *TestMachinery) {
 		*out = make([]LandscapeMapping, len(*in))
 		copy(*out, *in)
 	}
+	if in.RepositoryCache != nil {
+		in, out := &in.RepositoryCache, &out.RepositoryCache
+		*out = new(RepositoryCache)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConfigSources != nil {
+		in, out := &in.ConfigSources, &out.ConfigSources
+		*out = new(ConfigSources)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.KubeconfigPreflight != nil {
+		in, out := &in.KubeconfigPreflight, &out.KubeconfigPreflight
+		*out = new(KubeconfigPreflight)
+		**out = **in
+	}
 	return
 }
 
@@ -396,6 +860,38 @@ func (in *TestMachinery) DeepCopy() *TestMachinery {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UntrustedStepPolicy) DeepCopyInto(out *UntrustedStepPolicy) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UntrustedStepPolicy.
+func (in *UntrustedStepPolicy) DeepCopy() *UntrustedStepPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(UntrustedStepPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VaultConfigSource) DeepCopyInto(out *VaultConfigSource) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultConfigSource.
+func (in *VaultConfigSource) DeepCopy() *VaultConfigSource {
+	if in == nil {
+		return nil
+	}
+	out := new(VaultConfigSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WebhookConfig) DeepCopyInto(out *WebhookConfig) {
 	*out = *in
@@ -10,6 +10,7 @@ import (
 	argov1 "github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	"github.com/gardener/test-infra/pkg/util/strconf"
@@ -122,7 +123,8 @@ type TestrunSpec struct {
 	LocationSets []LocationSet `json:"locationSets,omitempty"`
 
 	// Base64 encoded kubeconfigs that are mounted to every testflow step.
-	// They are available at $TM_KUBECONFIG_PATH/xxx.config, where xxx is either (gardener, seed or shoot).
+	// They are available at $TM_KUBECONFIG_PATH/xxx.config, where xxx is either (host, gardener, seed, shoot)
+	// or the name of an additional kubeconfig.
 	// +optional
 	Kubeconfigs TestrunKubeconfigs `json:"kubeconfigs,omitempty"`
 
@@ -169,20 +171,58 @@ type TestrunStatus struct {
 	// ObservedGeneration is the most recent generation observed for this testrun.
 	// +optional
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// ExportValidationErrors lists the steps whose exported documents did not match the export schema of their TestDefinition.
+	// Invalid documents are not ingested into their index but into the dead-letter index.
+	// +optional
+	ExportValidationErrors []ExportValidationError `json:"exportValidationErrors,omitempty"`
+
+	// ResolvedLocations contains the commits the revisions of all git locations were resolved to when the testrun was validated.
+	// All steps check out the resolved commit even if the revision is a branch that moves while the testrun is running.
+	// +optional
+	ResolvedLocations []ResolvedLocation `json:"resolvedLocations,omitempty"`
+}
+
+// ResolvedLocation is a git location whose revision is pinned to a commit.
+type ResolvedLocation struct {
+	// Repo is the url of the git repository.
+	Repo string `json:"repo"`
+	// Revision is the requested revision of the location, e.g. a branch, tag or commit.
+	Revision string `json:"revision"`
+	// Commit is the sha of the commit the revision was resolved to.
+	Commit string `json:"commit"`
+}
+
+// ExportValidationError describes the exported documents of a step that did not match the export schema.
+type ExportValidationError struct {
+	// Step is the name of the step that exported the documents.
+	Step string `json:"step"`
+	// SchemaVersion is the version of the export schema the documents were validated against.
+	SchemaVersion string `json:"schemaVersion,omitempty"`
+	// Count is the number of invalid documents.
+	Count int `json:"count"`
+	// Message is the validation error of the first invalid document.
+	Message string `json:"message"`
 }
 
 // StepStatus is the status of Testflow step
 type StepStatus struct {
-	Name              string                   `json:"name"`
-	Position          StepStatusPosition       `json:"position"`
-	TestDefinition    StepStatusTestDefinition `json:"testdefinition,omitempty"`
-	Annotations       map[string]string        `json:"annotations,omitempty"`
-	Phase             argov1.NodePhase         `json:"phase,omitempty"`
-	StartTime         *metav1.Time             `json:"startTime,omitempty"`
-	CompletionTime    *metav1.Time             `json:"completionTime,omitempty"`
-	Duration          int64                    `json:"duration,omitempty"`
-	ExportArtifactKey string                   `json:"exportArtifactKey"`
-	PodName           string                   `json:"podName"`
+	Name                 string                   `json:"name"`
+	Position             StepStatusPosition       `json:"position"`
+	TestDefinition       StepStatusTestDefinition `json:"testdefinition,omitempty"`
+	Annotations          map[string]string        `json:"annotations,omitempty"`
+	Phase                argov1.NodePhase         `json:"phase,omitempty"`
+	StartTime            *metav1.Time             `json:"startTime,omitempty"`
+	CompletionTime       *metav1.Time             `json:"completionTime,omitempty"`
+	Duration             int64                    `json:"duration,omitempty"`
+	ExportArtifactKey    string                   `json:"exportArtifactKey"`
+	TelemetryArtifactKey string                   `json:"telemetryArtifactKey,omitempty"`
+	PodName              string                   `json:"podName"`
+
+	// Kubeconfigs are the names of the testrun kubeconfigs that are available to the step
+	// at $TM_KUBECONFIG_PATH/<name>.config.
+	// +optional
+	Kubeconfigs []string `json:"kubeconfigs,omitempty"`
 }
 
 // StepStatusTestDefinition holds information about the used testdefinition and its location.
@@ -194,6 +234,7 @@ type StepStatusTestDefinition struct {
 	Owner                 string              `json:"owner,omitempty"`
 	RecipientsOnFailure   []string            `json:"recipientsOnFailure"`
 	ActiveDeadlineSeconds *intstr.IntOrString `json:"activeDeadlineSeconds"`
+	ExportSchema          *ExportSchema       `json:"exportSchema,omitempty"`
 }
 
 type StepStatusPosition struct {
@@ -234,6 +275,12 @@ type TestrunKubeconfigs struct {
 	Gardener *strconf.StringOrConfig `json:"gardener,omitempty"`
 	Seed     *strconf.StringOrConfig `json:"seed,omitempty"`
 	Shoot    *strconf.StringOrConfig `json:"shoot,omitempty"`
+
+	// Additional are kubeconfigs with custom names, e.g. for further clusters of multi-cluster scenarios.
+	// They are available at $TM_KUBECONFIG_PATH/<name>.config in all trusted steps.
+	// The names have to be DNS labels and must not be one of host, gardener, seed or shoot.
+	// +optional
+	Additional map[string]*strconf.StringOrConfig `json:"additional,omitempty"`
 }
 
 // ConfigElement is a parameter of a certain type which is passed to TestDefinitions.
@@ -258,6 +305,11 @@ type ConfigElement struct {
 	// Only for type=file. Path where the file should be mounted.
 	// +optional
 	Path string `json:"path,omitempty"`
+
+	// Sensitive indicates that the value must not be shown in the testrun status, logs and collected results.
+	// If not set, values of configs whose names indicate secrets like tokens or passwords are considered as sensitive.
+	// +optional
+	Sensitive *bool `json:"sensitive,omitempty"`
 }
 
 // TestFlow is a 2 dimensional array of testflow-steps which define the execution order of TestDefinitions.
@@ -271,6 +323,20 @@ type DAGStep struct {
 	ArtifactsFrom      string            `json:"artifactsFrom,omitempty"`
 	Pause              *Pause            `json:"pause,omitempty"`
 	Annotations        map[string]string `json:"annotations,omitempty"`
+	Telemetry          *Telemetry        `json:"telemetry,omitempty"`
+}
+
+// Telemetry configures the measurement of the availability of the shoot API server and other endpoints during a step.
+// The measured samples are stored as artifact and evaluated by the collector.
+type Telemetry struct {
+	// Endpoints are additional http(s) urls that are probed besides the shoot API server.
+	// +optional
+	Endpoints []string `json:"endpoints,omitempty"`
+
+	// Interval between two probes of an endpoint.
+	// Defaults to 5s.
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
 }
 
 // StepDefinition is a reference to one or more TestDefinitions to execute in a series of steps.StepDefinition
@@ -386,4 +452,21 @@ type TestDefSpec struct {
 	// Compute Resources required by this container.
 	// +optional
 	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+	// ExportSchema describes the documents the test exports to the export directory.
+	// Exported documents are validated against the schema before they are ingested.
+	// +optional
+	ExportSchema *ExportSchema `json:"exportSchema,omitempty"`
+}
+
+// ExportSchema references a JSON schema that exported documents of a test have to match.
+// Exactly one of url or inline has to be defined.
+type ExportSchema struct {
+	// Version of the schema which is added to every valid exported document as "schemaVersion".
+	Version string `json:"version"`
+	// URL of the JSON schema document.
+	// +optional
+	URL string `json:"url,omitempty"`
+	// Inline JSON schema document.
+	// +optional
+	Inline *runtime.RawExtension `json:"inline,omitempty"`
 }
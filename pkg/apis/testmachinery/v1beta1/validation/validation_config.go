@@ -10,6 +10,7 @@ import (
 	"reflect"
 	"strings"
 
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/client-go/tools/clientcmd"
@@ -73,6 +74,9 @@ func ValidateConfig(fldPath *field.Path, config tmv1beta1.ConfigElement) field.E
 	return allErrs
 }
 
+// reservedKubeconfigNames are the names of the predefined kubeconfigs that cannot be used for additional kubeconfigs.
+var reservedKubeconfigNames = sets.New("host", "gardener", "seed", "shoot")
+
 // ValidateKubeconfigs validates all testrun kubeconfigs
 func ValidateKubeconfigs(fldPath *field.Path, kubeconfigs tmv1beta1.TestrunKubeconfigs) field.ErrorList {
 	var allErrs field.ErrorList
@@ -80,7 +84,25 @@ func ValidateKubeconfigs(fldPath *field.Path, kubeconfigs tmv1beta1.TestrunKubec
 	k := reflect.ValueOf(kubeconfigs)
 	typeOfK := k.Type()
 	for i := 0; i < k.NumField(); i++ {
-		allErrs = append(allErrs, ValidateKubeconfig(fldPath.Child("strconf").Child(typeOfK.Field(i).Name), k.Field(i).Interface().(*strconf.StringOrConfig))...)
+		kubeconfig, ok := k.Field(i).Interface().(*strconf.StringOrConfig)
+		if !ok {
+			continue
+		}
+		allErrs = append(allErrs, ValidateKubeconfig(fldPath.Child("strconf").Child(typeOfK.Field(i).Name), kubeconfig)...)
+	}
+	for name, kubeconfig := range kubeconfigs.Additional {
+		namePath := fldPath.Child("additional").Key(name)
+		for _, msg := range validation.IsDNS1123Label(name) {
+			allErrs = append(allErrs, field.Invalid(namePath, name, msg))
+		}
+		if reservedKubeconfigNames.Has(name) {
+			allErrs = append(allErrs, field.Invalid(namePath, name, fmt.Sprintf("name must not be one of %s", strings.Join(sets.List(reservedKubeconfigNames), ", "))))
+		}
+		if kubeconfig == nil {
+			allErrs = append(allErrs, field.Required(namePath, "kubeconfig must be defined"))
+			continue
+		}
+		allErrs = append(allErrs, ValidateKubeconfig(namePath, kubeconfig)...)
 	}
 	return allErrs
 }
@@ -106,6 +128,9 @@ func ValidateKubeconfig(fldPath *field.Path, kubeconfig *strconf.StringOrConfig)
 		}
 	case strconf.Config:
 		allErrs = append(allErrs, strconf.Validate(fldPath, kubeconfig.Config())...)
+		if kubeconfig.Config().IsExternal() {
+			allErrs = append(allErrs, field.Forbidden(fldPath, "kubeconfigs can only be read from configmaps or secrets in the namespace of the testrun"))
+		}
 	default:
 		allErrs = append(allErrs, field.Invalid(fldPath.Child("type"), strconf.TypeToString(kubeconfig.Type), "Undefined StringSecType"))
 	}
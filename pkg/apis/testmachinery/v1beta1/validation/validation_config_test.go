@@ -73,6 +73,67 @@ var _ = Describe("Config", func() {
 			Expect(errList).To(BeEmpty())
 		})
 
+		It("should succeed when valueFrom is a template of other sources", func() {
+			elem := tmv1beta1.ConfigElement{
+				Name: "testConfig",
+				Type: "env",
+				ValueFrom: &strconf.ConfigSource{
+					Template: &strconf.TemplateSource{
+						Template: "{{ .user }}:{{ .password }}",
+						Sources: map[string]strconf.ConfigSource{
+							"user":     {NamespacedSecretKeyRef: &strconf.NamespacedSecretKeySelector{Namespace: "ns", Name: "creds", Key: "user"}},
+							"password": {Vault: &strconf.VaultSecretSelector{Mount: "secret", Path: "creds", Key: "password"}},
+						},
+					},
+				},
+			}
+			errList := validation.ValidateConfig(stdPath, elem)
+			Expect(errList).To(BeEmpty())
+		})
+
+		It("should fail when templates are nested", func() {
+			elem := tmv1beta1.ConfigElement{
+				Name: "testConfig",
+				Type: "env",
+				ValueFrom: &strconf.ConfigSource{
+					Template: &strconf.TemplateSource{
+						Template: "{{ .inner }}",
+						Sources: map[string]strconf.ConfigSource{
+							"inner": {Template: &strconf.TemplateSource{Template: "value"}},
+						},
+					},
+				},
+			}
+			errList := validation.ValidateConfig(stdPath, elem)
+			Expect(errList).To(ContainElement(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Type":  Equal(field.ErrorTypeForbidden),
+				"Field": Equal("identifier.valueFrom.template.sources[inner].template"),
+			}))))
+		})
+
+		It("should fail when a vault secret is combined with another source", func() {
+			elem := tmv1beta1.ConfigElement{
+				Name: "testConfig",
+				Type: "env",
+				ValueFrom: &strconf.ConfigSource{
+					SecretKeyRef: &corev1.SecretKeySelector{
+						Key:                  "test",
+						LocalObjectReference: corev1.LocalObjectReference{Name: "name"},
+					},
+					Vault: &strconf.VaultSecretSelector{Mount: "secret", Path: "creds"},
+				},
+			}
+			errList := validation.ValidateConfig(stdPath, elem)
+			Expect(errList).To(ContainElement(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Type":  Equal(field.ErrorTypeInvalid),
+				"Field": Equal("identifier.valueFrom"),
+			}))))
+			Expect(errList).To(ContainElement(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Type":  Equal(field.ErrorTypeRequired),
+				"Field": Equal("identifier.valueFrom.vault.key"),
+			}))))
+		})
+
 		It("should fail with unknown config type", func() {
 			elem := tmv1beta1.ConfigElement{
 				Name: "testConfig",
@@ -95,4 +156,54 @@ var _ = Describe("Config", func() {
 			Expect(errList).To(BeEmpty())
 		})
 	})
+
+	Context("Validating additional kubeconfigs", func() {
+		kubeconfig := strconf.FromConfig(strconf.ConfigSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "runtime"},
+				Key:                  "kubeconfig",
+			},
+		})
+
+		It("should succeed with valid names", func() {
+			errList := validation.ValidateKubeconfigs(stdPath, tmv1beta1.TestrunKubeconfigs{
+				Additional: map[string]*strconf.StringOrConfig{
+					"runtime":        kubeconfig,
+					"virtual-garden": kubeconfig,
+				},
+			})
+			Expect(errList).To(BeEmpty())
+		})
+
+		It("should fail with names that are no dns labels", func() {
+			errList := validation.ValidateKubeconfigs(stdPath, tmv1beta1.TestrunKubeconfigs{
+				Additional: map[string]*strconf.StringOrConfig{"virtual_garden": kubeconfig},
+			})
+			Expect(errList).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Type":  Equal(field.ErrorTypeInvalid),
+				"Field": Equal("identifier.additional[virtual_garden]"),
+			}))))
+		})
+
+		It("should fail with names of predefined kubeconfigs", func() {
+			errList := validation.ValidateKubeconfigs(stdPath, tmv1beta1.TestrunKubeconfigs{
+				Additional: map[string]*strconf.StringOrConfig{"shoot": kubeconfig},
+			})
+			Expect(errList).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Type":   Equal(field.ErrorTypeInvalid),
+				"Field":  Equal("identifier.additional[shoot]"),
+				"Detail": Equal("name must not be one of gardener, host, seed, shoot"),
+			}))))
+		})
+
+		It("should fail with undefined kubeconfigs", func() {
+			errList := validation.ValidateKubeconfigs(stdPath, tmv1beta1.TestrunKubeconfigs{
+				Additional: map[string]*strconf.StringOrConfig{"runtime": nil},
+			})
+			Expect(errList).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Type":  Equal(field.ErrorTypeRequired),
+				"Field": Equal("identifier.additional[runtime]"),
+			}))))
+		})
+	})
 })
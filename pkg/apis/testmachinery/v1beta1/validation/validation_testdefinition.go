@@ -5,6 +5,8 @@
 package validation
 
 import (
+	"encoding/json"
+	"net/url"
 	"regexp"
 	"strings"
 
@@ -40,6 +42,37 @@ func ValidateTestDefinition(fldPath *field.Path, td *tmv1beta1.TestDefinition) f
 		labelPath := specPath.Child("labels").Index(i)
 		allErrs = append(allErrs, ValidateLabelName(labelPath, label)...)
 	}
+
+	if td.Spec.ExportSchema != nil {
+		allErrs = append(allErrs, ValidateExportSchema(specPath.Child("exportSchema"), td.Spec.ExportSchema)...)
+	}
+	return allErrs
+}
+
+// ValidateExportSchema validates the reference to the JSON schema of exported documents.
+func ValidateExportSchema(fldPath *field.Path, schema *tmv1beta1.ExportSchema) field.ErrorList {
+	var allErrs field.ErrorList
+	if len(schema.Version) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("version"), "must be defined"))
+	}
+
+	hasInline := schema.Inline != nil && len(schema.Inline.Raw) != 0
+	switch {
+	case len(schema.URL) == 0 && !hasInline:
+		allErrs = append(allErrs, field.Required(fldPath, "either url or inline must be defined"))
+	case len(schema.URL) != 0 && hasInline:
+		allErrs = append(allErrs, field.Forbidden(fldPath, "only one of url or inline may be defined"))
+	case len(schema.URL) != 0:
+		u, err := url.Parse(schema.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || len(u.Host) == 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("url"), schema.URL, "must be a http or https url"))
+		}
+	default:
+		var doc map[string]interface{}
+		if err := json.Unmarshal(schema.Inline.Raw, &doc); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("inline"), string(schema.Inline.Raw), "must be a JSON schema object"))
+		}
+	}
 	return allErrs
 }
 
@@ -9,6 +9,7 @@ import (
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gstruct"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	tmv1beta1 "github.com/gardener/test-infra/pkg/apis/testmachinery/v1beta1"
@@ -114,5 +115,45 @@ var _ = Describe("TestDefinition Validation", func() {
 				"Field": Equal("identifier.spec.recipientsOnFailure"),
 			}))))
 		})
+		It("should succeed when an export schema is referenced by url", func() {
+			testdef.Spec.ExportSchema = &tmv1beta1.ExportSchema{Version: "v1", URL: "https://example.com/schema.json"}
+			Expect(validation.ValidateTestDefinition(stdPath, testdef)).To(BeEmpty())
+		})
+
+		It("should succeed when an export schema is defined inline", func() {
+			testdef.Spec.ExportSchema = &tmv1beta1.ExportSchema{Version: "v1", Inline: &runtime.RawExtension{Raw: []byte(`{"type":"object"}`)}}
+			Expect(validation.ValidateTestDefinition(stdPath, testdef)).To(BeEmpty())
+		})
+
+		It("should fail when the export schema has no version", func() {
+			testdef.Spec.ExportSchema = &tmv1beta1.ExportSchema{URL: "https://example.com/schema.json"}
+			errList := validation.ValidateTestDefinition(stdPath, testdef)
+			Expect(errList).To(ContainElement(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Type":  Equal(field.ErrorTypeRequired),
+				"Field": Equal("identifier.spec.exportSchema.version"),
+			}))))
+		})
+
+		It("should fail when the export schema defines a url and an inline schema", func() {
+			testdef.Spec.ExportSchema = &tmv1beta1.ExportSchema{
+				Version: "v1",
+				URL:     "https://example.com/schema.json",
+				Inline:  &runtime.RawExtension{Raw: []byte(`{"type":"object"}`)},
+			}
+			errList := validation.ValidateTestDefinition(stdPath, testdef)
+			Expect(errList).To(ContainElement(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Type":  Equal(field.ErrorTypeForbidden),
+				"Field": Equal("identifier.spec.exportSchema"),
+			}))))
+		})
+
+		It("should fail when the export schema url is not a http url", func() {
+			testdef.Spec.ExportSchema = &tmv1beta1.ExportSchema{Version: "v1", URL: "file:///schema.json"}
+			errList := validation.ValidateTestDefinition(stdPath, testdef)
+			Expect(errList).To(ContainElement(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Type":  Equal(field.ErrorTypeInvalid),
+				"Field": Equal("identifier.spec.exportSchema.url"),
+			}))))
+		})
 	})
 })
@@ -6,6 +6,8 @@ package validation
 
 import (
 	"fmt"
+	"net/url"
+	"time"
 
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation/field"
@@ -39,6 +41,10 @@ func ValidateTestFlow(fldPath *field.Path, testflow tmv1beta1.TestFlow) field.Er
 		if err := ValidateStep(stepPath, step.Definition); err != nil {
 			return err
 		}
+
+		if step.Telemetry != nil {
+			allErrs = append(allErrs, ValidateTelemetry(stepPath.Child("telemetry"), step.Telemetry)...)
+		}
 	}
 
 	valHelper := newTFValidationHelper(usedStepNames)
@@ -92,6 +98,21 @@ func ValidateStep(fldPath *field.Path, definition tmv1beta1.StepDefinition) fiel
 	return allErrs
 }
 
+// ValidateTelemetry validates the telemetry configuration of a step
+func ValidateTelemetry(fldPath *field.Path, telemetry *tmv1beta1.Telemetry) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, endpoint := range telemetry.Endpoints {
+		u, err := url.Parse(endpoint)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || len(u.Host) == 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("endpoints").Index(i), endpoint, "must be a http or https url"))
+		}
+	}
+	if telemetry.Interval != nil && telemetry.Interval.Duration < time.Second {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("interval"), telemetry.Interval.Duration.String(), "must be at least 1s"))
+	}
+	return allErrs
+}
+
 type testflowValidationHelper struct {
 	stepNameToStep    map[string]*tmv1beta1.DAGStep
 	dependentStepName string
@@ -5,9 +5,12 @@
 package validation_test
 
 import (
+	"time"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gstruct"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	tmv1beta1 "github.com/gardener/test-infra/pkg/apis/testmachinery/v1beta1"
@@ -229,4 +232,44 @@ var _ = Describe("testflow", func() {
 			"Field": Equal("identifier[2].artifactsFrom"),
 		}))))
 	})
+
+	It("should succeed when telemetry is configured with valid endpoints", func() {
+		tf := tmv1beta1.TestFlow{
+			&tmv1beta1.DAGStep{
+				Name: "int-test",
+				Definition: tmv1beta1.StepDefinition{
+					Name: "testdefname",
+				},
+				Telemetry: &tmv1beta1.Telemetry{
+					Endpoints: []string{"https://example.com/healthz"},
+					Interval:  &metav1.Duration{Duration: 10 * time.Second},
+				},
+			},
+		}
+		Expect(validation.ValidateTestFlow(stdPath, tf)).To(BeEmpty())
+	})
+
+	It("should fail when telemetry endpoints are no http urls or the interval is too short", func() {
+		tf := tmv1beta1.TestFlow{
+			&tmv1beta1.DAGStep{
+				Name: "int-test",
+				Definition: tmv1beta1.StepDefinition{
+					Name: "testdefname",
+				},
+				Telemetry: &tmv1beta1.Telemetry{
+					Endpoints: []string{"example.com"},
+					Interval:  &metav1.Duration{Duration: 100 * time.Millisecond},
+				},
+			},
+		}
+		errList := validation.ValidateTestFlow(stdPath, tf)
+		Expect(errList).To(ContainElement(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Type":  Equal(field.ErrorTypeInvalid),
+			"Field": Equal("identifier[0].telemetry.endpoints[0]"),
+		}))))
+		Expect(errList).To(ContainElement(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Type":  Equal(field.ErrorTypeInvalid),
+			"Field": Equal("identifier[0].telemetry.interval"),
+		}))))
+	})
 })
@@ -11,6 +11,7 @@ package v1beta1
 
 import (
 	strconf "github.com/gardener/test-infra/pkg/util/strconf"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	intstr "k8s.io/apimachinery/pkg/util/intstr"
 )
@@ -28,6 +29,11 @@ func (in *ConfigElement) DeepCopyInto(out *ConfigElement) {
 		*out = new(strconf.ConfigSource)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Sensitive != nil {
+		in, out := &in.Sensitive, &out.Sensitive
+		*out = new(bool)
+		**out = **in
+	}
 	return
 }
 
@@ -62,6 +68,11 @@ func (in *DAGStep) DeepCopyInto(out *DAGStep) {
 			(*out)[key] = val
 		}
 	}
+	if in.Telemetry != nil {
+		in, out := &in.Telemetry, &out.Telemetry
+		*out = new(Telemetry)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -75,6 +86,43 @@ func (in *DAGStep) DeepCopy() *DAGStep {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExportSchema) DeepCopyInto(out *ExportSchema) {
+	*out = *in
+	if in.Inline != nil {
+		in, out := &in.Inline, &out.Inline
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExportSchema.
+func (in *ExportSchema) DeepCopy() *ExportSchema {
+	if in == nil {
+		return nil
+	}
+	out := new(ExportSchema)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExportValidationError) DeepCopyInto(out *ExportValidationError) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExportValidationError.
+func (in *ExportValidationError) DeepCopy() *ExportValidationError {
+	if in == nil {
+		return nil
+	}
+	out := new(ExportValidationError)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LocationSet) DeepCopyInto(out *LocationSet) {
 	*out = *in
@@ -117,6 +165,22 @@ func (in *Pause) DeepCopy() *Pause {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResolvedLocation) DeepCopyInto(out *ResolvedLocation) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResolvedLocation.
+func (in *ResolvedLocation) DeepCopy() *ResolvedLocation {
+	if in == nil {
+		return nil
+	}
+	out := new(ResolvedLocation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StepDefinition) DeepCopyInto(out *StepDefinition) {
 	*out = *in
@@ -165,6 +229,11 @@ func (in *StepStatus) DeepCopyInto(out *StepStatus) {
 		in, out := &in.CompletionTime, &out.CompletionTime
 		*out = (*in).DeepCopy()
 	}
+	if in.Kubeconfigs != nil {
+		in, out := &in.Kubeconfigs, &out.Kubeconfigs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -229,6 +298,11 @@ func (in *StepStatusTestDefinition) DeepCopyInto(out *StepStatusTestDefinition)
 		*out = new(intstr.IntOrString)
 		**out = **in
 	}
+	if in.ExportSchema != nil {
+		in, out := &in.ExportSchema, &out.ExportSchema
+		*out = new(ExportSchema)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -242,6 +316,32 @@ func (in *StepStatusTestDefinition) DeepCopy() *StepStatusTestDefinition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Telemetry) DeepCopyInto(out *Telemetry) {
+	*out = *in
+	if in.Endpoints != nil {
+		in, out := &in.Endpoints, &out.Endpoints
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Telemetry.
+func (in *Telemetry) DeepCopy() *Telemetry {
+	if in == nil {
+		return nil
+	}
+	out := new(Telemetry)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TestDefSpec) DeepCopyInto(out *TestDefSpec) {
 	*out = *in
@@ -283,6 +383,11 @@ func (in *TestDefSpec) DeepCopyInto(out *TestDefSpec) {
 		}
 	}
 	in.Resources.DeepCopyInto(&out.Resources)
+	if in.ExportSchema != nil {
+		in, out := &in.ExportSchema, &out.ExportSchema
+		*out = new(ExportSchema)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -449,6 +554,22 @@ func (in *TestrunKubeconfigs) DeepCopyInto(out *TestrunKubeconfigs) {
 		*out = new(strconf.StringOrConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Additional != nil {
+		in, out := &in.Additional, &out.Additional
+		*out = make(map[string]*strconf.StringOrConfig, len(*in))
+		for key, val := range *in {
+			var outVal *strconf.StringOrConfig
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				inVal := (*in)[key]
+				in, out := &inVal, &outVal
+				*out = new(strconf.StringOrConfig)
+				(*in).DeepCopyInto(*out)
+			}
+			(*out)[key] = outVal
+		}
+	}
 	return
 }
 
@@ -580,6 +701,16 @@ func (in *TestrunStatus) DeepCopyInto(out *TestrunStatus) {
 			}
 		}
 	}
+	if in.ExportValidationErrors != nil {
+		in, out := &in.ExportValidationErrors, &out.ExportValidationErrors
+		*out = make([]ExportValidationError, len(*in))
+		copy(*out, *in)
+	}
+	if in.ResolvedLocations != nil {
+		in, out := &in.ResolvedLocations, &out.ResolvedLocations
+		*out = make([]ResolvedLocation, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -9,6 +9,9 @@ const (
 	// AnnotationResumeTestrun is the annotation name to trigger resume on the testrun
 	AnnotationResumeTestrun = "testmachinery.sapcloud.io/resume"
 
+	// AnnotationAppliedDefaults is the annotation that lists the fields of a testrun that were defaulted by the mutating webhook
+	AnnotationAppliedDefaults = "testmachinery.gardener.cloud/applied-defaults"
+
 	// AnnotationCollectTestrun is the annotation to trigger collection and persistence of testrun results
 	AnnotationCollectTestrun = "testmachinery.garden.cloud/collect"
 
@@ -71,6 +74,21 @@ const (
 	// AnnotationFlavorDescription is the annotation to describe the test flavor of the current run testrun
 	AnnotationFlavorDescription = "metadata.testmachinery.gardener.cloud/flavor-description"
 
+	// AnnotationFlavorSelectionStrategy is the annotation to specify the strategy that was used to select the shoot flavor of the testrun
+	AnnotationFlavorSelectionStrategy = "metadata.testmachinery.gardener.cloud/flavor-selection-strategy"
+
+	// AnnotationFlavorSelectionSeed is the annotation to specify the seed that was used to select the shoot flavor of the testrun
+	AnnotationFlavorSelectionSeed = "metadata.testmachinery.gardener.cloud/flavor-selection-seed"
+
+	// AnnotationFlavorSelectionDay is the annotation to specify the day that was used by the rotating strategy to select the shoot flavor of the testrun
+	AnnotationFlavorSelectionDay = "metadata.testmachinery.gardener.cloud/flavor-selection-day"
+
+	// AnnotationK8sUpgradeFromVersion is the annotation to specify the k8s version the shoot of the testrun is upgraded from
+	AnnotationK8sUpgradeFromVersion = "metadata.testmachinery.gardener.cloud/k8s-upgrade-from-version"
+
+	// AnnotationOperatingSystemUpgradeFromVersion is the annotation to specify the operating system version the shoot nodes of the testrun are upgraded from
+	AnnotationOperatingSystemUpgradeFromVersion = "metadata.testmachinery.gardener.cloud/operating-system-upgrade-from-version"
+
 	// AnnotationDimension is the annotation to specify the dimension the testrun is testing
 	AnnotationDimension = "metadata.testmachinery.gardener.cloud/dimension"
 
@@ -108,6 +126,11 @@ const (
 	PatternThreeMajorBeforeLatest = "threeMajorBeforeLatest"
 	PatternFourMajorBeforeLatest  = "fourMajorBeforeLatest"
 
+	// PatternLatestPatchOfPreviousMinor selects the latest patch version of the minor version before the target version of an upgrade.
+	PatternLatestPatchOfPreviousMinor = "latestPatchOfPreviousMinor"
+	// PatternLatestInPlaceUpdatable selects the latest machine image version that can be updated in-place to the target version of an upgrade.
+	PatternLatestInPlaceUpdatable = "latestInPlaceUpdatable"
+
 	// TM Dashboard
 	DashboardExecutionGroupParameter = "runID"
 
@@ -11,6 +11,11 @@ import (
 
 // ExtendedShootFlavors contains a list of extended shoot flavors
 type ExtendedShootFlavors struct {
+	// Selection configures which of the shoots that are described by the flavors are tested.
+	// All shoots are tested if no selection is defined.
+	// +optional
+	Selection *FlavorSelection `json:"selection,omitempty"`
+
 	Flavors []*ExtendedShootFlavor `json:"flavors"`
 }
 
@@ -18,12 +23,65 @@ type ExtendedShootFlavors struct {
 type ExtendedShoot struct {
 	Shoot
 	ExtendedShootConfiguration
+
+	// Selection describes how the shoot was selected from all shoots of the flavors.
+	// +optional
+	Selection *FlavorSelectionInfo
+
+	// Upgrade describes the versions the shoot is created with before it is upgraded to the versions of the shoot.
+	// Is only set for shoots that are generated from upgrade flavors.
+	// +optional
+	Upgrade *ShootUpgrade
 }
 
 // ExtendedShootFlavor is the shoot flavor with extended configuration
 type ExtendedShootFlavor struct {
 	ShootFlavor
 	ExtendedConfiguration
+
+	// Upgrades describes upgrade paths that should be tested.
+	// If upgrades are defined, one shoot per upgrade path is generated for every kubernetes version and worker flavor
+	// instead of one shoot that is created with the target versions.
+	// +optional
+	Upgrades []ShootUpgradeFlavor `json:"upgrades,omitempty"`
+}
+
+// ShootUpgradeFlavor describes an upgrade path of a shoot.
+// The shoot is created with the "from" versions and upgraded to the "to" versions.
+type ShootUpgradeFlavor struct {
+	// Kubernetes describes the upgrade path of the kubernetes version.
+	// The target versions default to the kubernetes versions of the flavor.
+	// +optional
+	Kubernetes *UpgradePathFlavor `json:"kubernetes,omitempty"`
+
+	// MachineImage describes the upgrade path of the machine image versions of all worker pools.
+	// The target versions default to the machine image versions of the worker pools.
+	// +optional
+	MachineImage *UpgradePathFlavor `json:"machineImage,omitempty"`
+}
+
+// UpgradePathFlavor describes the source and target version of an upgrade by version patterns.
+type UpgradePathFlavor struct {
+	// From is the pattern of the version the shoot is created with.
+	// For kubernetes, the latest version that matches the pattern and is lower than the target version is used.
+	// For machine images, the pattern is resolved like the machine image version of a worker pool
+	// and an error is returned if the resolved version is not lower than the target version.
+	From string `json:"from"`
+
+	// To is the pattern of the version the shoot is upgraded to.
+	// +optional
+	To *string `json:"to,omitempty"`
+}
+
+// ShootUpgrade describes the source versions of a shoot that is upgraded.
+type ShootUpgrade struct {
+	// KubernetesVersion is the kubernetes version the shoot is created with.
+	// +optional
+	KubernetesVersion *gardencorev1beta1.ExpirableVersion
+
+	// MachineImageVersions maps the name of a worker pool to the machine image version the worker pool is created with.
+	// +optional
+	MachineImageVersions map[string]string
 }
 
 // Shoot is the internal representation of one instance that is generated from a shoot flavor
@@ -140,3 +198,77 @@ type ExtendedShootConfiguration struct {
 	Cloudprofile gardencorev1beta1.CloudProfile `json:"-"`
 	ExtendedConfiguration
 }
+
+// FlavorSelectionStrategy describes how shoots are selected from all combinations that are described by shoot flavors.
+type FlavorSelectionStrategy string
+
+const (
+	// FlavorSelectionStrategyAll selects all shoots.
+	FlavorSelectionStrategyAll FlavorSelectionStrategy = "all"
+	// FlavorSelectionStrategyPairwise selects a minimal set of shoots that covers all pairs of dimension values (all-pairs testing).
+	FlavorSelectionStrategyPairwise FlavorSelectionStrategy = "pairwise"
+	// FlavorSelectionStrategyWeighted randomly samples shoots whereas the probability of a shoot is defined by the weights of its dimension values.
+	FlavorSelectionStrategyWeighted FlavorSelectionStrategy = "weighted"
+	// FlavorSelectionStrategyRotating selects a different part of all shoots every day so that all shoots are covered within the configured window.
+	FlavorSelectionStrategyRotating FlavorSelectionStrategy = "rotating"
+)
+
+// FlavorDimension is a dimension of the test matrix that is described by shoot flavors.
+type FlavorDimension string
+
+const (
+	// FlavorDimensionDescription is the description of the flavor.
+	FlavorDimensionDescription FlavorDimension = "description"
+	// FlavorDimensionProvider is the cloudprovider of the shoot.
+	FlavorDimensionProvider FlavorDimension = "provider"
+	// FlavorDimensionKubernetesVersion is the kubernetes version of the shoot.
+	FlavorDimensionKubernetesVersion FlavorDimension = "kubernetesVersion"
+	// FlavorDimensionMachineImage is the name of the machine images of all worker pools of the shoot.
+	FlavorDimensionMachineImage FlavorDimension = "machineImage"
+	// FlavorDimensionMachineImageVersion is the name and version of the machine images of all worker pools of the shoot.
+	FlavorDimensionMachineImageVersion FlavorDimension = "machineImageVersion"
+	// FlavorDimensionWorkerPools is the name of all worker pools of the shoot.
+	FlavorDimensionWorkerPools FlavorDimension = "workerPools"
+	// FlavorDimensionUpgrade is the upgrade path of the shoot.
+	FlavorDimensionUpgrade FlavorDimension = "upgrade"
+)
+
+// FlavorSelection configures the strategy that is used to select shoots from all combinations that are described by the flavors.
+type FlavorSelection struct {
+	// Strategy that is used to select the shoots.
+	// Defaults to "all".
+	// +optional
+	Strategy FlavorSelectionStrategy `json:"strategy,omitempty"`
+
+	// Size is the maximum number of shoots that are selected by the weighted strategy.
+	// All shoots with a weight greater than 0 are selected if no size is defined.
+	// +optional
+	Size int `json:"size,omitempty"`
+
+	// Weights defines the weights of specific values per dimension that are used by the weighted strategy.
+	// The weight of a shoot is the product of the weights of all its dimension values whereas values without a configured weight have a weight of 1.
+	// Shoots with a weight of 0 are never selected.
+	// +optional
+	Weights map[FlavorDimension]map[string]int `json:"weights,omitempty"`
+
+	// WindowDays is the number of days within which the rotating strategy covers all shoots.
+	// +optional
+	WindowDays int `json:"windowDays,omitempty"`
+
+	// Seed overwrites the seed that is used for the random selection.
+	// The weighted strategy defaults to a seed derived from the current date.
+	// The rotating strategy uses the seed to shuffle the shoots before they are split into daily parts and defaults to 0.
+	// +optional
+	Seed *int64 `json:"seed,omitempty"`
+}
+
+// FlavorSelectionInfo describes the selection that selected a shoot.
+type FlavorSelectionInfo struct {
+	// Strategy that was used to select the shoot.
+	Strategy FlavorSelectionStrategy
+	// Seed that was used to select the shoot.
+	// The rotating strategy uses the seed to shuffle the shoots.
+	Seed int64
+	// Day is the day (days since unix epoch) that was used by the rotating strategy to select the part of the shoots.
+	Day int64
+}
@@ -46,6 +46,19 @@ func ValidateExtendedFlavor(identifier string, flavor *common.ExtendedShootFlavo
 		allErrs = multierror.Append(allErrs, fmt.Errorf("%s.kubernetes : Kubernetes versions or a pattern has to be defined", identifier))
 	}
 
+	for i, upgrade := range flavor.Upgrades {
+		identifier := fmt.Sprintf("%s.upgrades[%d]", identifier, i)
+		if upgrade.Kubernetes == nil && upgrade.MachineImage == nil {
+			allErrs = multierror.Append(allErrs, fmt.Errorf("%s: a kubernetes or machineImage upgrade path has to be defined", identifier))
+		}
+		if upgrade.Kubernetes != nil && upgrade.Kubernetes.From == "" {
+			allErrs = multierror.Append(allErrs, fmt.Errorf("%s.kubernetes.from: value has to be defined", identifier))
+		}
+		if upgrade.MachineImage != nil && upgrade.MachineImage.From == "" {
+			allErrs = multierror.Append(allErrs, fmt.Errorf("%s.machineImage.from: value has to be defined", identifier))
+		}
+	}
+
 	if len(flavor.Workers) == 0 {
 		return util.ReturnMultiError(multierror.Append(allErrs, fmt.Errorf("%s.workers: at least one worker flavor has to be defined", identifier)))
 	}
@@ -90,32 +103,58 @@ func NewExtended(k8sClient client.Client, cloudProfiles map[string]gardencorev1b
 		if err != nil {
 			return nil, err
 		}
-		for _, k8sVersion := range versions {
-			addVersion(rawFlavor.Provider, k8sVersion)
 
-			for _, workers := range rawFlavor.Workers {
-				pools, err := SetupWorker(cloudprofile, workers.WorkerPools)
+		upgrades := rawFlavor.Upgrades
+		if len(upgrades) == 0 {
+			upgrades = []common.ShootUpgradeFlavor{{}}
+		}
+		for _, upgrade := range upgrades {
+			targetVersions := versions
+			if upgrade.Kubernetes != nil && upgrade.Kubernetes.To != nil {
+				targetVersions, err = util.GetK8sVersions(cloudprofile, common.ShootKubernetesVersionFlavor{
+					Pattern:             upgrade.Kubernetes.To,
+					FilterPatchVersions: rawFlavor.KubernetesVersions.FilterPatchVersions,
+				}, filterPatchVersions)
 				if err != nil {
 					return nil, err
 				}
-				shoots = append(shoots, &ExtendedFlavorInstance{
-					shoot: &common.ExtendedShoot{
-						Shoot: common.Shoot{
-							Description:           rawFlavor.Description,
-							AdditionalAnnotations: rawFlavor.AdditionalAnnotations,
-							AdditionalLocations:   rawFlavor.AdditionalLocations,
-							Provider:              rawFlavor.Provider,
-							KubernetesVersion:     k8sVersion,
-							Workers:               pools,
-						},
-						ExtendedShootConfiguration: common.ExtendedShootConfiguration{
-							Name:                  fmt.Sprintf("%s%s", shootPrefix, util.RandomString(3)),
-							Namespace:             fmt.Sprintf("garden-%s", rawFlavor.ProjectName),
-							Cloudprofile:          cloudprofile,
-							ExtendedConfiguration: rawFlavor.ExtendedConfiguration,
+			}
+
+			for _, k8sVersion := range targetVersions {
+				addVersion(rawFlavor.Provider, k8sVersion)
+
+				for _, workers := range rawFlavor.Workers {
+					pools, err := SetupWorker(cloudprofile, upgradeTargetWorkers(workers.WorkerPools, upgrade))
+					if err != nil {
+						return nil, err
+					}
+					shootUpgrade, err := newShootUpgrade(cloudprofile, k8sVersion, pools, upgrade)
+					if err != nil {
+						return nil, errors.Wrapf(err, "unable to resolve upgrade path of flavor %d", i)
+					}
+					if shootUpgrade != nil && shootUpgrade.KubernetesVersion != nil {
+						addVersion(rawFlavor.Provider, *shootUpgrade.KubernetesVersion)
+					}
+					shoots = append(shoots, &ExtendedFlavorInstance{
+						shoot: &common.ExtendedShoot{
+							Shoot: common.Shoot{
+								Description:           rawFlavor.Description,
+								AdditionalAnnotations: rawFlavor.AdditionalAnnotations,
+								AdditionalLocations:   rawFlavor.AdditionalLocations,
+								Provider:              rawFlavor.Provider,
+								KubernetesVersion:     k8sVersion,
+								Workers:               pools,
+							},
+							ExtendedShootConfiguration: common.ExtendedShootConfiguration{
+								Name:                  fmt.Sprintf("%s%s", shootPrefix, util.RandomString(3)),
+								Namespace:             fmt.Sprintf("garden-%s", rawFlavor.ProjectName),
+								Cloudprofile:          cloudprofile,
+								ExtendedConfiguration: rawFlavor.ExtendedConfiguration,
+							},
+							Upgrade: shootUpgrade,
 						},
-					},
-				})
+					})
+				}
 			}
 		}
 	}
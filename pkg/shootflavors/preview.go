@@ -0,0 +1,170 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package shootflavors
+
+import (
+	"fmt"
+	"time"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	"k8s.io/utils/ptr"
+
+	"github.com/gardener/test-infra/pkg/common"
+)
+
+// ShootPreview describes a shoot that is generated from extended shoot flavors
+// together with warnings about its configuration.
+type ShootPreview struct {
+	Description       string          `json:"description,omitempty"`
+	Provider          string          `json:"provider"`
+	Cloudprofile      string          `json:"cloudprofile"`
+	Region            string          `json:"region"`
+	KubernetesVersion string          `json:"kubernetesVersion"`
+	Upgrade           string          `json:"upgrade,omitempty"`
+	Workers           []WorkerPreview `json:"workers"`
+	Warnings          []string        `json:"warnings,omitempty"`
+}
+
+// WorkerPreview describes a worker pool of a previewed shoot with its resolved machine image version.
+type WorkerPreview struct {
+	Name                string `json:"name"`
+	MachineType         string `json:"machineType,omitempty"`
+	Architecture        string `json:"architecture,omitempty"`
+	MachineImage        string `json:"machineImage,omitempty"`
+	MachineImageVersion string `json:"machineImageVersion,omitempty"`
+	UpdateStrategy      string `json:"updateStrategy,omitempty"`
+}
+
+// SelectionPreview describes the shoots that are selected from all previewed shoots by a flavor selection.
+type SelectionPreview struct {
+	Strategy common.FlavorSelectionStrategy `json:"strategy"`
+	Seed     int64                          `json:"seed,omitempty"`
+	Day      int64                          `json:"day,omitempty"`
+	// Shoots are the indices of the selected shoots in the preview of all shoots.
+	Shoots []int `json:"shoots"`
+}
+
+// PreviewSelection applies the selection to the flavors and returns the indices of the selected shoots
+// in the preview of all shoots that has been created before the selection.
+// Nil is returned if the selection keeps all shoots.
+func (f *ExtendedFlavors) PreviewSelection(selection *common.FlavorSelection, date time.Time) (*SelectionPreview, error) {
+	indices := make(map[*ExtendedFlavorInstance]int, len(f.GetShoots()))
+	for i, instance := range f.GetShoots() {
+		indices[instance] = i
+	}
+	if err := f.Select(selection, date); err != nil {
+		return nil, err
+	}
+	if selection == nil || selection.Strategy == "" || selection.Strategy == common.FlavorSelectionStrategyAll {
+		return nil, nil
+	}
+
+	preview := &SelectionPreview{
+		Strategy: selection.Strategy,
+		Shoots:   make([]int, 0, len(f.GetShoots())),
+	}
+	for _, instance := range f.GetShoots() {
+		preview.Shoots = append(preview.Shoots, indices[instance])
+		if info := instance.Get().Selection; info != nil {
+			preview.Seed, preview.Day = info.Seed, info.Day
+		}
+	}
+	return preview, nil
+}
+
+// Preview returns a preview of all shoots of the flavors.
+// Kubernetes and machine image versions that are deprecated or expire before now+expirationWarningPeriod
+// as well as duplicated shoots are reported as warnings.
+func (f *ExtendedFlavors) Preview(now time.Time, expirationWarningPeriod time.Duration) []ShootPreview {
+	var (
+		previews = make([]ShootPreview, 0, len(f.GetShoots()))
+		seen     = make(map[string]int)
+	)
+	for i, instance := range f.GetShoots() {
+		shoot := instance.Get()
+		preview := ShootPreview{
+			Description:       shoot.Description,
+			Provider:          string(shoot.Provider),
+			Cloudprofile:      shoot.CloudprofileName,
+			Region:            shoot.Region,
+			KubernetesVersion: shoot.KubernetesVersion.Version,
+			Upgrade:           UpgradeDescription(shoot),
+			Workers:           make([]WorkerPreview, 0, len(shoot.Workers)),
+		}
+
+		preview.Warnings = append(preview.Warnings, kubernetesVersionWarnings(shoot.Cloudprofile, shoot.KubernetesVersion.Version, now, expirationWarningPeriod)...)
+		if shoot.Upgrade != nil && shoot.Upgrade.KubernetesVersion != nil {
+			preview.Warnings = append(preview.Warnings, kubernetesVersionWarnings(shoot.Cloudprofile, shoot.Upgrade.KubernetesVersion.Version, now, expirationWarningPeriod)...)
+		}
+		for _, worker := range shoot.Workers {
+			workerPreview := WorkerPreview{
+				Name:           worker.Name,
+				MachineType:    worker.Machine.Type,
+				Architecture:   ptr.Deref(worker.Machine.Architecture, ""),
+				UpdateStrategy: string(ptr.Deref(worker.UpdateStrategy, "")),
+			}
+			if worker.Machine.Image != nil {
+				workerPreview.MachineImage = worker.Machine.Image.Name
+				workerPreview.MachineImageVersion = ptr.Deref(worker.Machine.Image.Version, "")
+				preview.Warnings = append(preview.Warnings, machineImageVersionWarnings(shoot.Cloudprofile, workerPreview, now, expirationWarningPeriod)...)
+			}
+			preview.Workers = append(preview.Workers, workerPreview)
+		}
+
+		key := fmt.Sprintf("%s/%s/%v", shoot.CloudprofileName, shoot.Region, ShootDimensions(shoot))
+		if j, ok := seen[key]; ok {
+			preview.Warnings = append(preview.Warnings, fmt.Sprintf("duplicate of shoot %d", j))
+		} else {
+			seen[key] = i
+		}
+
+		previews = append(previews, preview)
+	}
+	return previews
+}
+
+func kubernetesVersionWarnings(cloudprofile gardencorev1beta1.CloudProfile, version string, now time.Time, expirationWarningPeriod time.Duration) []string {
+	for _, v := range cloudprofile.Spec.Kubernetes.Versions {
+		if v.Version == version {
+			return expirableVersionWarnings(fmt.Sprintf("kubernetes version %s", version), v, now, expirationWarningPeriod)
+		}
+	}
+	if cloudprofile.Name == "" {
+		return nil
+	}
+	return []string{fmt.Sprintf("kubernetes version %s is not defined in the cloudprofile %s", version, cloudprofile.Name)}
+}
+
+func machineImageVersionWarnings(cloudprofile gardencorev1beta1.CloudProfile, worker WorkerPreview, now time.Time, expirationWarningPeriod time.Duration) []string {
+	for _, image := range cloudprofile.Spec.MachineImages {
+		if image.Name != worker.MachineImage {
+			continue
+		}
+		for _, v := range image.Versions {
+			if v.Version == worker.MachineImageVersion {
+				return expirableVersionWarnings(fmt.Sprintf("machine image %s version %s of worker pool %s", worker.MachineImage, worker.MachineImageVersion, worker.Name), v.ExpirableVersion, now, expirationWarningPeriod)
+			}
+		}
+	}
+	if cloudprofile.Name == "" {
+		return nil
+	}
+	return []string{fmt.Sprintf("machine image %s version %s of worker pool %s is not defined in the cloudprofile %s", worker.MachineImage, worker.MachineImageVersion, worker.Name, cloudprofile.Name)}
+}
+
+func expirableVersionWarnings(name string, version gardencorev1beta1.ExpirableVersion, now time.Time, expirationWarningPeriod time.Duration) []string {
+	warnings := make([]string, 0)
+	if ptr.Deref(version.Classification, gardencorev1beta1.ClassificationSupported) == gardencorev1beta1.ClassificationDeprecated {
+		warnings = append(warnings, fmt.Sprintf("%s is deprecated", name))
+	}
+	if version.ExpirationDate != nil {
+		if version.ExpirationDate.Time.Before(now) {
+			warnings = append(warnings, fmt.Sprintf("%s expired on %s", name, version.ExpirationDate.Format(time.DateOnly)))
+		} else if version.ExpirationDate.Time.Before(now.Add(expirationWarningPeriod)) {
+			warnings = append(warnings, fmt.Sprintf("%s expires on %s", name, version.ExpirationDate.Format(time.DateOnly)))
+		}
+	}
+	return warnings
+}
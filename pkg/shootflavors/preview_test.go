@@ -0,0 +1,149 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package shootflavors
+
+import (
+	"time"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/gardener/test-infra/pkg/common"
+)
+
+var _ = Describe("flavor preview", func() {
+
+	var (
+		now          = time.Date(2024, 5, 17, 0, 0, 0, 0, time.UTC)
+		cloudprofile gardencorev1beta1.CloudProfile
+	)
+
+	BeforeEach(func() {
+		cloudprofile = gardencorev1beta1.CloudProfile{
+			ObjectMeta: metav1.ObjectMeta{Name: "gcp"},
+			Spec: gardencorev1beta1.CloudProfileSpec{
+				Kubernetes: gardencorev1beta1.KubernetesSettings{
+					Versions: []gardencorev1beta1.ExpirableVersion{
+						{Version: "1.31.0", Classification: ptr.To(gardencorev1beta1.ClassificationSupported)},
+						{Version: "1.30.0", Classification: ptr.To(gardencorev1beta1.ClassificationDeprecated), ExpirationDate: &metav1.Time{Time: now.AddDate(0, 0, 10)}},
+					},
+				},
+				MachineImages: []gardencorev1beta1.MachineImage{{
+					Name: "gardenlinux",
+					Versions: []gardencorev1beta1.MachineImageVersion{
+						{ExpirableVersion: gardencorev1beta1.ExpirableVersion{Version: "1.0.0", ExpirationDate: &metav1.Time{Time: now.AddDate(0, 0, -1)}}},
+						{ExpirableVersion: gardencorev1beta1.ExpirableVersion{Version: "2.0.0"}},
+					},
+				}},
+			},
+		}
+	})
+
+	newShoot := func(k8sVersion, imageVersion string) *ExtendedFlavorInstance {
+		return NewExtendedFlavorInstance(&common.ExtendedShoot{
+			Shoot: common.Shoot{
+				Provider:          common.CloudProviderGCP,
+				KubernetesVersion: gardencorev1beta1.ExpirableVersion{Version: k8sVersion},
+				Workers: []gardencorev1beta1.Worker{{
+					Name: "worker",
+					Machine: gardencorev1beta1.Machine{
+						Type:  "n1-standard-2",
+						Image: &gardencorev1beta1.ShootMachineImage{Name: "gardenlinux", Version: ptr.To(imageVersion)},
+					},
+				}},
+			},
+			ExtendedShootConfiguration: common.ExtendedShootConfiguration{
+				Cloudprofile: cloudprofile,
+				ExtendedConfiguration: common.ExtendedConfiguration{
+					CloudprofileName: "gcp",
+					Region:           "europe-west1",
+				},
+			},
+		})
+	}
+
+	It("should not report warnings for supported versions", func() {
+		flavors := &ExtendedFlavors{shoots: []*ExtendedFlavorInstance{newShoot("1.31.0", "2.0.0")}}
+		previews := flavors.Preview(now, 24*time.Hour)
+		Expect(previews).To(HaveLen(1))
+		Expect(previews[0].KubernetesVersion).To(Equal("1.31.0"))
+		Expect(previews[0].Workers).To(ConsistOf(WorkerPreview{
+			Name:                "worker",
+			MachineType:         "n1-standard-2",
+			MachineImage:        "gardenlinux",
+			MachineImageVersion: "2.0.0",
+		}))
+		Expect(previews[0].Warnings).To(BeEmpty())
+	})
+
+	It("should report deprecated, expiring and expired versions", func() {
+		flavors := &ExtendedFlavors{shoots: []*ExtendedFlavorInstance{newShoot("1.30.0", "1.0.0")}}
+		previews := flavors.Preview(now, 30*24*time.Hour)
+		Expect(previews[0].Warnings).To(ConsistOf(
+			"kubernetes version 1.30.0 is deprecated",
+			"kubernetes version 1.30.0 expires on 2024-05-27",
+			"machine image gardenlinux version 1.0.0 of worker pool worker expired on 2024-05-16",
+		))
+	})
+
+	It("should report versions that are not defined in the cloudprofile", func() {
+		flavors := &ExtendedFlavors{shoots: []*ExtendedFlavorInstance{newShoot("1.29.0", "3.0.0")}}
+		previews := flavors.Preview(now, 24*time.Hour)
+		Expect(previews[0].Warnings).To(ConsistOf(
+			"kubernetes version 1.29.0 is not defined in the cloudprofile gcp",
+			"machine image gardenlinux version 3.0.0 of worker pool worker is not defined in the cloudprofile gcp",
+		))
+	})
+
+	It("should report duplicated shoots", func() {
+		flavors := &ExtendedFlavors{shoots: []*ExtendedFlavorInstance{
+			newShoot("1.31.0", "2.0.0"),
+			newShoot("1.31.0", "2.0.0"),
+		}}
+		previews := flavors.Preview(now, 24*time.Hour)
+		Expect(previews[0].Warnings).To(BeEmpty())
+		Expect(previews[1].Warnings).To(ConsistOf("duplicate of shoot 0"))
+	})
+
+	Context("selection", func() {
+		var flavors *ExtendedFlavors
+
+		BeforeEach(func() {
+			flavors = &ExtendedFlavors{shoots: []*ExtendedFlavorInstance{
+				newShoot("1.31.0", "2.0.0"),
+				newShoot("1.31.0", "2.0.0"),
+				newShoot("1.30.0", "2.0.0"),
+				newShoot("1.30.0", "1.0.0"),
+			}}
+		})
+
+		It("should not preview a selection that keeps all shoots", func() {
+			selection, err := flavors.PreviewSelection(&common.FlavorSelection{Strategy: common.FlavorSelectionStrategyAll}, now)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(selection).To(BeNil())
+			Expect(flavors.GetShoots()).To(HaveLen(4))
+		})
+
+		It("should return the indices of the selected shoots in the preview of all shoots", func() {
+			all := flavors.GetShoots()
+			previews := flavors.Preview(now, 24*time.Hour)
+			Expect(previews).To(HaveLen(4))
+			Expect(previews[1].Warnings).To(ConsistOf("duplicate of shoot 0"))
+
+			selection, err := flavors.PreviewSelection(&common.FlavorSelection{Strategy: common.FlavorSelectionStrategyRotating, WindowDays: 2, Seed: ptr.To[int64](42)}, now)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(selection.Strategy).To(Equal(common.FlavorSelectionStrategyRotating))
+			Expect(selection.Seed).To(Equal(int64(42)))
+			Expect(selection.Day).To(Equal(int64(19860)))
+			Expect(selection.Shoots).To(HaveLen(2))
+			for i, index := range selection.Shoots {
+				Expect(flavors.GetShoots()[i]).To(BeIdenticalTo(all[index]))
+			}
+		})
+	})
+})
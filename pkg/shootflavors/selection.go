@@ -0,0 +1,252 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package shootflavors
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/gardener/test-infra/pkg/common"
+	"github.com/gardener/test-infra/pkg/util"
+)
+
+// selectionDimensions are all dimensions of a shoot that are considered by the selection strategies.
+var selectionDimensions = []common.FlavorDimension{
+	common.FlavorDimensionDescription,
+	common.FlavorDimensionProvider,
+	common.FlavorDimensionKubernetesVersion,
+	common.FlavorDimensionMachineImage,
+	common.FlavorDimensionMachineImageVersion,
+	common.FlavorDimensionWorkerPools,
+	common.FlavorDimensionUpgrade,
+}
+
+// ValidateSelection validates a flavor selection.
+func ValidateSelection(identifier string, selection *common.FlavorSelection) error {
+	if selection == nil {
+		return nil
+	}
+	var allErrs *multierror.Error
+
+	switch selection.Strategy {
+	case "", common.FlavorSelectionStrategyAll, common.FlavorSelectionStrategyPairwise, common.FlavorSelectionStrategyWeighted:
+	case common.FlavorSelectionStrategyRotating:
+		if selection.WindowDays < 1 {
+			allErrs = multierror.Append(allErrs, fmt.Errorf("%s.windowDays: value has to be greater than 0 for the rotating strategy", identifier))
+		}
+	default:
+		allErrs = multierror.Append(allErrs, fmt.Errorf("%s.strategy: unknown strategy %q", identifier, selection.Strategy))
+	}
+
+	if selection.Size < 0 {
+		allErrs = multierror.Append(allErrs, fmt.Errorf("%s.size: value must not be negative", identifier))
+	}
+
+	knownDimensions := sets.New(selectionDimensions...)
+	for dimension, weights := range selection.Weights {
+		if !knownDimensions.Has(dimension) {
+			allErrs = multierror.Append(allErrs, fmt.Errorf("%s.weights.%s: unknown dimension", identifier, dimension))
+		}
+		for value, weight := range weights {
+			if weight < 0 {
+				allErrs = multierror.Append(allErrs, fmt.Errorf("%s.weights.%s.%s: weight must not be negative", identifier, dimension, value))
+			}
+		}
+	}
+
+	return util.ReturnMultiError(allErrs)
+}
+
+// Select selects the shoots that should be tested according to the given selection.
+// The date is used to derive the seed of the random and rotating selection.
+// All shoots are kept if no selection is defined.
+// The selected strategy, the used seed and the day of the rotating strategy are added to every selected shoot.
+func (f *ExtendedFlavors) Select(selection *common.FlavorSelection, date time.Time) error {
+	if err := ValidateSelection("selection", selection); err != nil {
+		return err
+	}
+	if selection == nil || selection.Strategy == "" || selection.Strategy == common.FlavorSelectionStrategyAll {
+		return nil
+	}
+
+	info := &common.FlavorSelectionInfo{Strategy: selection.Strategy}
+	shoots := f.GetShoots()
+	switch selection.Strategy {
+	case common.FlavorSelectionStrategyPairwise:
+		shoots = selectPairwise(shoots)
+	case common.FlavorSelectionStrategyWeighted:
+		info.Seed = dateSeed(date)
+		if selection.Seed != nil {
+			info.Seed = *selection.Seed
+		}
+		shoots = selectWeighted(shoots, selection.Weights, selection.Size, info.Seed)
+	case common.FlavorSelectionStrategyRotating:
+		if selection.Seed != nil {
+			info.Seed = *selection.Seed
+		}
+		info.Day = date.UTC().Unix() / int64(24*time.Hour/time.Second)
+		shoots = selectRotating(shoots, selection.WindowDays, info.Day, info.Seed)
+	}
+
+	for _, shoot := range shoots {
+		shoot.Get().Selection = info
+	}
+	f.shoots = shoots
+	return nil
+}
+
+// ShootDimensions returns the values of all selection dimensions of a shoot.
+func ShootDimensions(shoot *common.ExtendedShoot) map[common.FlavorDimension]string {
+	var (
+		images        = make([]string, 0, len(shoot.Workers))
+		imageVersions = make([]string, 0, len(shoot.Workers))
+		pools         = make([]string, 0, len(shoot.Workers))
+	)
+	for _, worker := range shoot.Workers {
+		pools = append(pools, worker.Name)
+		if worker.Machine.Image == nil {
+			continue
+		}
+		images = append(images, worker.Machine.Image.Name)
+		version := common.PatternLatest
+		if worker.Machine.Image.Version != nil {
+			version = *worker.Machine.Image.Version
+		}
+		imageVersions = append(imageVersions, fmt.Sprintf("%s:%s", worker.Machine.Image.Name, version))
+	}
+
+	return map[common.FlavorDimension]string{
+		common.FlavorDimensionDescription:         shoot.Description,
+		common.FlavorDimensionProvider:            string(shoot.Provider),
+		common.FlavorDimensionKubernetesVersion:   shoot.KubernetesVersion.Version,
+		common.FlavorDimensionMachineImage:        strings.Join(sets.List(sets.New(images...)), ","),
+		common.FlavorDimensionMachineImageVersion: strings.Join(sets.List(sets.New(imageVersions...)), ","),
+		common.FlavorDimensionWorkerPools:         strings.Join(pools, ","),
+		common.FlavorDimensionUpgrade:             UpgradeDescription(shoot),
+	}
+}
+
+// selectPairwise greedily selects shoots until all pairs of dimension values that occur in the given shoots are covered.
+// The selected shoots keep their original order.
+func selectPairwise(shoots []*ExtendedFlavorInstance) []*ExtendedFlavorInstance {
+	shootPairs := make([][]string, len(shoots))
+	uncovered := sets.New[string]()
+	for i, shoot := range shoots {
+		dimensions := ShootDimensions(shoot.Get())
+		for a := 0; a < len(selectionDimensions); a++ {
+			for b := a + 1; b < len(selectionDimensions); b++ {
+				pair := fmt.Sprintf("%s=%s|%s=%s",
+					selectionDimensions[a], dimensions[selectionDimensions[a]],
+					selectionDimensions[b], dimensions[selectionDimensions[b]])
+				shootPairs[i] = append(shootPairs[i], pair)
+				uncovered.Insert(pair)
+			}
+		}
+	}
+
+	selected := sets.New[int]()
+	for uncovered.Len() != 0 {
+		best, bestCount := -1, 0
+		for i, pairs := range shootPairs {
+			if selected.Has(i) {
+				continue
+			}
+			count := 0
+			for _, pair := range pairs {
+				if uncovered.Has(pair) {
+					count++
+				}
+			}
+			if count > bestCount {
+				best, bestCount = i, count
+			}
+		}
+		if best == -1 {
+			break
+		}
+		selected.Insert(best)
+		uncovered.Delete(shootPairs[best]...)
+	}
+
+	result := make([]*ExtendedFlavorInstance, 0, selected.Len())
+	for _, i := range sets.List(selected) {
+		result = append(result, shoots[i])
+	}
+	return result
+}
+
+// selectWeighted samples size shoots without replacement whereas the probability of a shoot is proportional to its weight.
+// All shoots with a weight greater than 0 are selected if size is 0.
+// The selected shoots keep their original order.
+func selectWeighted(shoots []*ExtendedFlavorInstance, weights map[common.FlavorDimension]map[string]int, size int, seed int64) []*ExtendedFlavorInstance {
+	type weightedShoot struct {
+		index int
+		key   float64
+	}
+	rng := rand.New(rand.NewSource(seed)) // #nosec G404 -- the selection has to be reproducible and is not security relevant
+
+	candidates := make([]weightedShoot, 0, len(shoots))
+	for i, shoot := range shoots {
+		weight := 1
+		for dimension, value := range ShootDimensions(shoot.Get()) {
+			if w, ok := weights[dimension][value]; ok {
+				weight *= w
+			}
+		}
+		// draw a random number for every shoot to keep the sequence of random numbers independent of the weights.
+		r := rng.Float64()
+		if weight == 0 {
+			continue
+		}
+		// weighted random sampling without replacement (Efraimidis and Spirakis)
+		candidates = append(candidates, weightedShoot{index: i, key: math.Pow(r, 1/float64(weight))})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].key > candidates[j].key
+	})
+	if size != 0 && size < len(candidates) {
+		candidates = candidates[:size]
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].index < candidates[j].index
+	})
+
+	result := make([]*ExtendedFlavorInstance, 0, len(candidates))
+	for _, c := range candidates {
+		result = append(result, shoots[c.index])
+	}
+	return result
+}
+
+// selectRotating shuffles the shoots with the given seed and splits them into windowDays parts.
+// The part of the given day is selected so that every shoot is selected exactly once within windowDays consecutive days.
+// The selected shoots keep their original order.
+func selectRotating(shoots []*ExtendedFlavorInstance, windowDays int, day, seed int64) []*ExtendedFlavorInstance {
+	rng := rand.New(rand.NewSource(seed)) // #nosec G404 -- the selection has to be reproducible and is not security relevant
+	slot := int(day % int64(windowDays))
+
+	result := make([]*ExtendedFlavorInstance, 0)
+	for i, position := range rng.Perm(len(shoots)) {
+		if position%windowDays == slot {
+			result = append(result, shoots[i])
+		}
+	}
+	return result
+}
+
+// dateSeed returns a seed that is derived from the given date in the format YYYYMMDD.
+func dateSeed(date time.Time) int64 {
+	seed, _ := strconv.ParseInt(date.UTC().Format("20060102"), 10, 64)
+	return seed
+}
@@ -0,0 +1,154 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package shootflavors
+
+import (
+	"fmt"
+	"time"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/ptr"
+
+	"github.com/gardener/test-infra/pkg/common"
+)
+
+var _ = Describe("flavor selection", func() {
+
+	var date = time.Date(2024, 5, 17, 3, 0, 0, 0, time.UTC)
+
+	newFlavors := func() *ExtendedFlavors {
+		shoots := make([]*ExtendedFlavorInstance, 0)
+		for _, provider := range []common.CloudProvider{common.CloudProviderGCP, common.CloudProviderAWS, common.CloudProviderAzure} {
+			for _, version := range []string{"1.30.1", "1.31.2", "1.32.0"} {
+				for _, image := range []string{"gardenlinux", "suse-chost"} {
+					for _, pool := range []string{"small", "large"} {
+						shoots = append(shoots, NewExtendedFlavorInstance(&common.ExtendedShoot{
+							Shoot: common.Shoot{
+								Provider:          provider,
+								KubernetesVersion: gardencorev1beta1.ExpirableVersion{Version: version},
+								Workers: []gardencorev1beta1.Worker{{
+									Name: pool,
+									Machine: gardencorev1beta1.Machine{
+										Image: &gardencorev1beta1.ShootMachineImage{Name: image, Version: ptr.To("1.0.0")},
+									},
+								}},
+							},
+						}))
+					}
+				}
+			}
+		}
+		return &ExtendedFlavors{shoots: shoots}
+	}
+
+	It("should keep all shoots if no selection is defined", func() {
+		flavors := newFlavors()
+		Expect(flavors.Select(nil, date)).To(Succeed())
+		Expect(flavors.GetShoots()).To(HaveLen(36))
+		Expect(flavors.GetShoots()[0].Get().Selection).To(BeNil())
+	})
+
+	It("should fail for an invalid selection", func() {
+		flavors := newFlavors()
+		Expect(flavors.Select(&common.FlavorSelection{Strategy: "foo"}, date)).ToNot(Succeed())
+		Expect(flavors.Select(&common.FlavorSelection{Strategy: common.FlavorSelectionStrategyRotating}, date)).ToNot(Succeed())
+		Expect(flavors.Select(&common.FlavorSelection{
+			Strategy: common.FlavorSelectionStrategyWeighted,
+			Weights:  map[common.FlavorDimension]map[string]int{"foo": {"bar": 1}},
+		}, date)).ToNot(Succeed())
+	})
+
+	Context("pairwise", func() {
+		It("should select fewer shoots that cover all pairs of dimension values", func() {
+			all := newFlavors()
+			flavors := newFlavors()
+			Expect(flavors.Select(&common.FlavorSelection{Strategy: common.FlavorSelectionStrategyPairwise}, date)).To(Succeed())
+			Expect(len(flavors.GetShoots())).To(BeNumerically("<", len(all.GetShoots())))
+
+			covered := sets.New[string]()
+			for _, shoot := range flavors.GetShoots() {
+				Expect(shoot.Get().Selection).To(Equal(&common.FlavorSelectionInfo{Strategy: common.FlavorSelectionStrategyPairwise}))
+				d := ShootDimensions(shoot.Get())
+				covered.Insert(
+					fmt.Sprintf("%s/%s", d[common.FlavorDimensionProvider], d[common.FlavorDimensionKubernetesVersion]),
+					fmt.Sprintf("%s/%s", d[common.FlavorDimensionProvider], d[common.FlavorDimensionMachineImage]),
+					fmt.Sprintf("%s/%s", d[common.FlavorDimensionKubernetesVersion], d[common.FlavorDimensionWorkerPools]),
+				)
+			}
+			// 3 providers x 3 versions + 3 providers x 2 images + 3 versions x 2 pools
+			Expect(covered.Len()).To(Equal(9 + 6 + 6))
+		})
+	})
+
+	Context("weighted", func() {
+		It("should select the configured number of shoots reproducibly", func() {
+			selection := &common.FlavorSelection{Strategy: common.FlavorSelectionStrategyWeighted, Size: 5}
+			a := newFlavors()
+			Expect(a.Select(selection, date)).To(Succeed())
+			b := newFlavors()
+			Expect(b.Select(selection, date)).To(Succeed())
+
+			Expect(a.GetShoots()).To(HaveLen(5))
+			for i := range a.GetShoots() {
+				Expect(ShootDimensions(a.GetShoots()[i].Get())).To(Equal(ShootDimensions(b.GetShoots()[i].Get())))
+			}
+			Expect(a.GetShoots()[0].Get().Selection).To(Equal(&common.FlavorSelectionInfo{
+				Strategy: common.FlavorSelectionStrategyWeighted,
+				Seed:     20240517,
+			}))
+		})
+
+		It("should use the configured seed", func() {
+			flavors := newFlavors()
+			Expect(flavors.Select(&common.FlavorSelection{Strategy: common.FlavorSelectionStrategyWeighted, Size: 5, Seed: ptr.To[int64](42)}, date)).To(Succeed())
+			Expect(flavors.GetShoots()[0].Get().Selection.Seed).To(Equal(int64(42)))
+		})
+
+		It("should never select shoots with a weight of 0", func() {
+			flavors := newFlavors()
+			Expect(flavors.Select(&common.FlavorSelection{
+				Strategy: common.FlavorSelectionStrategyWeighted,
+				Weights: map[common.FlavorDimension]map[string]int{
+					common.FlavorDimensionProvider: {"aws": 0, "gcp": 5},
+				},
+			}, date)).To(Succeed())
+			Expect(flavors.GetShoots()).To(HaveLen(24))
+			for _, shoot := range flavors.GetShoots() {
+				Expect(shoot.Get().Provider).ToNot(Equal(common.CloudProviderAWS))
+			}
+		})
+	})
+
+	Context("rotating", func() {
+		It("should cover every shoot exactly once within the window", func() {
+			windowDays := 7
+			seen := make(map[string]int)
+			for day := 0; day < windowDays; day++ {
+				flavors := newFlavors()
+				Expect(flavors.Select(&common.FlavorSelection{Strategy: common.FlavorSelectionStrategyRotating, WindowDays: windowDays}, date.AddDate(0, 0, day))).To(Succeed())
+				for _, shoot := range flavors.GetShoots() {
+					seen[fmt.Sprint(ShootDimensions(shoot.Get()))]++
+				}
+			}
+			Expect(seen).To(HaveLen(36))
+			for _, n := range seen {
+				Expect(n).To(Equal(1))
+			}
+		})
+
+		It("should record the configured seed and the day", func() {
+			flavors := newFlavors()
+			Expect(flavors.Select(&common.FlavorSelection{Strategy: common.FlavorSelectionStrategyRotating, WindowDays: 7, Seed: ptr.To[int64](42)}, date)).To(Succeed())
+			Expect(flavors.GetShoots()[0].Get().Selection).To(Equal(&common.FlavorSelectionInfo{
+				Strategy: common.FlavorSelectionStrategyRotating,
+				Seed:     42,
+				Day:      19860,
+			}))
+		})
+	})
+})
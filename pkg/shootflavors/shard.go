@@ -0,0 +1,53 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package shootflavors
+
+import (
+	"fmt"
+	"hash/fnv"
+
+	"github.com/gardener/test-infra/pkg/common"
+)
+
+// ValidateShard validates that the given shard index and count describe a valid shard.
+func ValidateShard(index, count int) error {
+	if count < 1 {
+		return fmt.Errorf("shard count has to be greater than 0 but is %d", count)
+	}
+	if index < 0 || index >= count {
+		return fmt.Errorf("shard index has to be in the range [0, %d) but is %d", count, index)
+	}
+	return nil
+}
+
+// ShardKey returns the stable key of a shoot that is used to assign the shoot to a shard.
+// The key is computed from the flavor description, the cloudprovider and the kubernetes version
+// so that the assignment does not change between multiple invocations with the same flavor configuration.
+func ShardKey(shoot *common.ExtendedShoot) string {
+	return fmt.Sprintf("%s/%s/%s", shoot.Description, shoot.Provider, shoot.KubernetesVersion.Version)
+}
+
+// ShardIndex returns the index of the shard the given shoot belongs to.
+func ShardIndex(shoot *common.ExtendedShoot, count int) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(ShardKey(shoot)))
+	return int(h.Sum32() % uint32(count)) // #nosec G115 -- count is validated to be positive
+}
+
+// GetShootsForShard returns the shoots that belong to the shard with the given index out of count shards.
+// The shoots are deterministically partitioned so that every shoot is part of exactly one shard.
+func (f *ExtendedFlavors) GetShootsForShard(index, count int) ([]*ExtendedFlavorInstance, error) {
+	if err := ValidateShard(index, count); err != nil {
+		return nil, err
+	}
+
+	shoots := make([]*ExtendedFlavorInstance, 0)
+	for _, shoot := range f.GetShoots() {
+		if ShardIndex(shoot.Get(), count) == index {
+			shoots = append(shoots, shoot)
+		}
+	}
+	return shoots, nil
+}
@@ -0,0 +1,85 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package shootflavors
+
+import (
+	"fmt"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/gardener/test-infra/pkg/common"
+)
+
+var _ = Describe("flavor sharding", func() {
+
+	newShoot := func(description string, provider common.CloudProvider, version string) *ExtendedFlavorInstance {
+		return NewExtendedFlavorInstance(&common.ExtendedShoot{
+			Shoot: common.Shoot{
+				Description:       description,
+				Provider:          provider,
+				KubernetesVersion: gardencorev1beta1.ExpirableVersion{Version: version},
+			},
+		})
+	}
+
+	newFlavors := func() *ExtendedFlavors {
+		shoots := make([]*ExtendedFlavorInstance, 0)
+		for _, provider := range []common.CloudProvider{common.CloudProviderGCP, common.CloudProviderAWS, common.CloudProviderAzure} {
+			for minor := 25; minor < 35; minor++ {
+				shoots = append(shoots, newShoot("default", provider, fmt.Sprintf("1.%d.0", minor)))
+				shoots = append(shoots, newShoot("ha", provider, fmt.Sprintf("1.%d.0", minor)))
+			}
+		}
+		return &ExtendedFlavors{shoots: shoots}
+	}
+
+	It("should fail for an invalid shard configuration", func() {
+		flavors := newFlavors()
+		_, err := flavors.GetShootsForShard(0, 0)
+		Expect(err).To(HaveOccurred())
+		_, err = flavors.GetShootsForShard(2, 2)
+		Expect(err).To(HaveOccurred())
+		_, err = flavors.GetShootsForShard(-1, 2)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should return all shoots for a single shard", func() {
+		flavors := newFlavors()
+		shoots, err := flavors.GetShootsForShard(0, 1)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(shoots).To(Equal(flavors.GetShoots()))
+	})
+
+	It("should partition all shoots into disjoint shards", func() {
+		flavors := newFlavors()
+		count := 4
+		seen := make(map[*ExtendedFlavorInstance]int)
+		for i := 0; i < count; i++ {
+			shoots, err := flavors.GetShootsForShard(i, count)
+			Expect(err).ToNot(HaveOccurred())
+			for _, shoot := range shoots {
+				seen[shoot]++
+			}
+		}
+		Expect(seen).To(HaveLen(len(flavors.GetShoots())))
+		for _, n := range seen {
+			Expect(n).To(Equal(1))
+		}
+	})
+
+	It("should assign shoots deterministically", func() {
+		a, err := newFlavors().GetShootsForShard(1, 3)
+		Expect(err).ToNot(HaveOccurred())
+		b, err := newFlavors().GetShootsForShard(1, 3)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(a).To(HaveLen(len(b)))
+		for i := range a {
+			Expect(ShardKey(a[i].Get())).To(Equal(ShardKey(b[i].Get())))
+		}
+	})
+})
@@ -0,0 +1,102 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package shootflavors
+
+import (
+	"fmt"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+
+	"github.com/gardener/test-infra/pkg/common"
+	"github.com/gardener/test-infra/pkg/util"
+)
+
+// upgradeTargetWorkers returns the worker pools with the target machine image version of the upgrade path.
+// The worker pools are returned unchanged if the upgrade path does not define a target machine image version.
+func upgradeTargetWorkers(workers []gardencorev1beta1.Worker, upgrade common.ShootUpgradeFlavor) []gardencorev1beta1.Worker {
+	if upgrade.MachineImage == nil || upgrade.MachineImage.To == nil {
+		return workers
+	}
+	res := make([]gardencorev1beta1.Worker, len(workers))
+	for i, w := range workers {
+		worker := w.DeepCopy()
+		if worker.Machine.Image != nil {
+			worker.Machine.Image.Version = upgrade.MachineImage.To
+		}
+		res[i] = *worker
+	}
+	return res
+}
+
+// newShootUpgrade resolves the source versions of an upgrade path for a shoot with the given target kubernetes version and worker pools.
+// Nil is returned if no upgrade path is defined.
+func newShootUpgrade(cloudprofile gardencorev1beta1.CloudProfile, k8sVersion gardencorev1beta1.ExpirableVersion, workers []gardencorev1beta1.Worker, upgrade common.ShootUpgradeFlavor) (*common.ShootUpgrade, error) {
+	if upgrade.Kubernetes == nil && upgrade.MachineImage == nil {
+		return nil, nil
+	}
+
+	shootUpgrade := &common.ShootUpgrade{}
+	if upgrade.Kubernetes != nil {
+		source, err := util.GetKubernetesUpgradeSourceVersion(cloudprofile, upgrade.Kubernetes.From, k8sVersion)
+		if err != nil {
+			return nil, err
+		}
+		shootUpgrade.KubernetesVersion = &source
+	}
+	if upgrade.MachineImage != nil {
+		shootUpgrade.MachineImageVersions = make(map[string]string, len(workers))
+		for _, worker := range workers {
+			if worker.Machine.Image == nil {
+				continue
+			}
+			source, err := util.GetMachineImageUpgradeSourceVersion(cloudprofile, worker, upgrade.MachineImage.From)
+			if err != nil {
+				return nil, err
+			}
+			shootUpgrade.MachineImageVersions[worker.Name] = source
+		}
+	}
+	return shootUpgrade, nil
+}
+
+// UpgradeSourceWorkers returns the worker pools of a shoot with the machine image versions the shoot is created with.
+// The worker pools of the shoot are returned if the shoot is not upgraded.
+func UpgradeSourceWorkers(shoot *common.ExtendedShoot) []gardencorev1beta1.Worker {
+	if shoot.Upgrade == nil || len(shoot.Upgrade.MachineImageVersions) == 0 {
+		return shoot.Workers
+	}
+	res := make([]gardencorev1beta1.Worker, len(shoot.Workers))
+	for i, w := range shoot.Workers {
+		worker := w.DeepCopy()
+		if version, ok := shoot.Upgrade.MachineImageVersions[worker.Name]; ok && worker.Machine.Image != nil {
+			worker.Machine.Image.Version = &version
+		}
+		res[i] = *worker
+	}
+	return res
+}
+
+// UpgradeDescription returns a human readable description of the upgrade path of a shoot.
+// An empty string is returned if the shoot is not upgraded.
+func UpgradeDescription(shoot *common.ExtendedShoot) string {
+	if shoot.Upgrade == nil {
+		return ""
+	}
+	desc := ""
+	if shoot.Upgrade.KubernetesVersion != nil {
+		desc = fmt.Sprintf("k8s %s->%s", shoot.Upgrade.KubernetesVersion.Version, shoot.KubernetesVersion.Version)
+	}
+	for _, worker := range shoot.Workers {
+		version, ok := shoot.Upgrade.MachineImageVersions[worker.Name]
+		if !ok || worker.Machine.Image == nil || worker.Machine.Image.Version == nil {
+			continue
+		}
+		if desc != "" {
+			desc += ","
+		}
+		desc += fmt.Sprintf("%s %s->%s", worker.Name, version, *worker.Machine.Image.Version)
+	}
+	return desc
+}
@@ -0,0 +1,125 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package shootflavors
+
+import (
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/ptr"
+
+	"github.com/gardener/test-infra/pkg/common"
+	"github.com/gardener/test-infra/pkg/util"
+)
+
+var _ = Describe("upgrade flavors", func() {
+	var (
+		cloudProfiles map[string]gardencorev1beta1.CloudProfile
+		flavor        *common.ExtendedShootFlavor
+	)
+
+	BeforeEach(func() {
+		cloudProfiles = map[string]gardencorev1beta1.CloudProfile{
+			"test-profile": {
+				Spec: gardencorev1beta1.CloudProfileSpec{
+					Kubernetes: gardencorev1beta1.KubernetesSettings{
+						Versions: []gardencorev1beta1.ExpirableVersion{
+							{Version: "1.16.1"},
+							{Version: "1.15.2"},
+							{Version: "1.15.1"},
+						},
+					},
+					MachineImages: []gardencorev1beta1.MachineImage{{
+						Name:     "test-os",
+						Versions: MachineImageVersions(map[string][]string{"1.0.0": {"amd64"}, "0.0.1": {"amd64"}}),
+					}},
+					ProviderConfig: util.BuildCapabilityProviderConfig(util.ArchsByImage{
+						"test-os": {"1.0.0": {"amd64"}, "0.0.1": {"amd64"}},
+					}),
+				},
+			},
+		}
+		flavor = &common.ExtendedShootFlavor{
+			ShootFlavor: common.ShootFlavor{
+				Provider: common.CloudProviderGCP,
+				KubernetesVersions: common.ShootKubernetesVersionFlavor{
+					Pattern: ptr.To(common.PatternLatest),
+				},
+				Workers: []common.ShootWorkerFlavor{{
+					WorkerPools: []gardencorev1beta1.Worker{{
+						Name: "pool",
+						Machine: gardencorev1beta1.Machine{
+							Image: &gardencorev1beta1.ShootMachineImage{Name: "test-os", Version: ptr.To(common.PatternLatest)},
+						},
+					}},
+				}},
+			},
+			ExtendedConfiguration: common.ExtendedConfiguration{
+				ProjectName:      "test",
+				CloudprofileName: "test-profile",
+				SecretBinding:    "sb-test",
+				Region:           "test-region",
+			},
+		}
+	})
+
+	It("should create a shoot for every upgrade path", func() {
+		flavor.Upgrades = []common.ShootUpgradeFlavor{
+			{Kubernetes: &common.UpgradePathFlavor{From: common.PatternLatestPatchOfPreviousMinor}},
+			{
+				Kubernetes:   &common.UpgradePathFlavor{From: "1.15.1", To: ptr.To("1.15.2")},
+				MachineImage: &common.UpgradePathFlavor{From: "0.0.1"},
+			},
+		}
+		flavors, err := NewExtended(nil, cloudProfiles, []*common.ExtendedShootFlavor{flavor}, "pref", false)
+		Expect(err).ToNot(HaveOccurred())
+
+		shoots := flavors.GetShoots()
+		Expect(shoots).To(HaveLen(2))
+
+		Expect(shoots[0].Get().KubernetesVersion.Version).To(Equal("1.16.1"))
+		Expect(shoots[0].Get().Upgrade).To(Equal(&common.ShootUpgrade{
+			KubernetesVersion: &gardencorev1beta1.ExpirableVersion{Version: "1.15.2"},
+		}))
+		Expect(UpgradeDescription(shoots[0].Get())).To(Equal("k8s 1.15.2->1.16.1"))
+
+		Expect(shoots[1].Get().KubernetesVersion.Version).To(Equal("1.15.2"))
+		Expect(shoots[1].Get().Upgrade).To(Equal(&common.ShootUpgrade{
+			KubernetesVersion:    &gardencorev1beta1.ExpirableVersion{Version: "1.15.1"},
+			MachineImageVersions: map[string]string{"pool": "0.0.1"},
+		}))
+		Expect(*shoots[1].Get().Workers[0].Machine.Image.Version).To(Equal("1.0.0"))
+		Expect(*UpgradeSourceWorkers(shoots[1].Get())[0].Machine.Image.Version).To(Equal("0.0.1"))
+		Expect(UpgradeDescription(shoots[1].Get())).To(Equal("k8s 1.15.1->1.15.2,pool 0.0.1->1.0.0"))
+
+		Expect(flavors.GetUsedKubernetesVersions()[common.CloudProviderGCP].Versions).To(ConsistOf(
+			gardencorev1beta1.ExpirableVersion{Version: "1.16.1"},
+			gardencorev1beta1.ExpirableVersion{Version: "1.15.2"},
+			gardencorev1beta1.ExpirableVersion{Version: "1.15.1"},
+		))
+	})
+
+	It("should not set an upgrade if no upgrade paths are defined", func() {
+		flavors, err := NewExtended(nil, cloudProfiles, []*common.ExtendedShootFlavor{flavor}, "pref", false)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(flavors.GetShoots()).To(HaveLen(1))
+		Expect(flavors.GetShoots()[0].Get().Upgrade).To(BeNil())
+		Expect(UpgradeSourceWorkers(flavors.GetShoots()[0].Get())).To(Equal(flavors.GetShoots()[0].Get().Workers))
+	})
+
+	It("should fail if no source version can be found", func() {
+		flavor.Upgrades = []common.ShootUpgradeFlavor{{Kubernetes: &common.UpgradePathFlavor{From: common.PatternLatest}}}
+		_, err := NewExtended(nil, cloudProfiles, []*common.ExtendedShootFlavor{flavor}, "pref", false)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should fail for an invalid upgrade path", func() {
+		flavor.Upgrades = []common.ShootUpgradeFlavor{{}, {MachineImage: &common.UpgradePathFlavor{}}}
+		Expect(ValidateExtendedFlavor("flavor", flavor)).To(MatchError(And(
+			ContainSubstring("flavor.upgrades[0]: a kubernetes or machineImage upgrade path has to be defined"),
+			ContainSubstring("flavor.upgrades[1].machineImage.from: value has to be defined"),
+		)))
+	})
+})
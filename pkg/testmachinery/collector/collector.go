@@ -5,7 +5,9 @@
 package collector
 
 import (
+	"context"
 	"os"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
@@ -26,6 +28,8 @@ import (
 type Interface interface {
 	GetMetadata(tr *tmv1beta1.Testrun) (*metadata.Metadata, error)
 	Collect(tr *tmv1beta1.Testrun, metadata *metadata.Metadata) error
+	// Start retries the ingestion of spooled results until the context is cancelled.
+	Start(ctx context.Context) error
 }
 
 type collector struct {
@@ -36,6 +40,12 @@ type collector struct {
 	esClient elasticsearch.Client
 	s3Config *config.S3
 	s3Client s3.Client
+
+	spool                 spool
+	spoolRetryInterval    time.Duration
+	spoolMaxRetryInterval time.Duration
+
+	schemas schemaCache
 }
 
 func New(log logr.Logger, k8sClient client.Client, esConfig *config.ElasticSearch, s3Config *config.S3) (Interface, error) {
@@ -60,6 +70,19 @@ func New(log logr.Logger, k8sClient client.Client, esConfig *config.ElasticSearc
 			return nil, err
 		}
 		c.esClient = esClient
+
+		c.spool, err = newSpool(esConfig.Spool, c.s3Client)
+		if err != nil {
+			return nil, err
+		}
+		c.spoolRetryInterval = esConfig.Spool.RetryInterval.Duration
+		if c.spoolRetryInterval == 0 {
+			c.spoolRetryInterval = defaultSpoolRetryInterval
+		}
+		c.spoolMaxRetryInterval = esConfig.Spool.MaxRetryInterval.Duration
+		if c.spoolMaxRetryInterval == 0 {
+			c.spoolMaxRetryInterval = defaultSpoolMaxRetryInterval
+		}
 	}
 
 	return c, nil
@@ -94,11 +117,6 @@ func (c *collector) Collect(tr *tmv1beta1.Testrun, metadata *metadata.Metadata)
 		}
 	}()
 
-	// add telemetry results to metadata
-	//if telemetryData := c.getTelemetryResultsForRun(run); telemetryData != nil {
-	//	run.Metadata.TelemetryData = telemetryData
-	//}
-
 	if err := c.collectSummaryAndExports(tmpDir, tr, metadata); err != nil {
 		return err
 	}
@@ -164,7 +164,7 @@ var _ = Describe("collector summary", func() {
 		// esClient.Request(http.MethodGet, "/testmachinery-*/_search", strings.NewReader(payload))
 		hits := `{ "hits": { "total": { "value": 0 } } }`
 		esClient.EXPECT().Request(http.MethodGet, "/testmachinery-*/_search", gomock.Any()).Return([]byte(hits), nil)
-		esClient.EXPECT().BulkFromFile(gomock.AssignableToTypeOf("")).Return(nil)
+		esClient.EXPECT().Bulk(gomock.Any()).Return(nil)
 
 		err = c.Collect(tr, &metadata.Metadata{Testrun: metadata.TestrunMetadata{ID: tr.Name}})
 		Expect(err).ToNot(HaveOccurred())
@@ -5,12 +5,25 @@
 package collector
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"net/http"
 	"os"
 	"path/filepath"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
 
 	tmv1beta1 "github.com/gardener/test-infra/pkg/apis/testmachinery/v1beta1"
 	"github.com/gardener/test-infra/pkg/util"
+	"github.com/gardener/test-infra/pkg/util/elasticsearch"
+)
+
+const (
+	defaultSpoolRetryInterval    = 30 * time.Second
+	defaultSpoolMaxRetryInterval = 10 * time.Minute
 )
 
 func (c *collector) ingestIntoElasticsearch(path string, tr *tmv1beta1.Testrun) error {
@@ -28,14 +41,176 @@ func (c *collector) ingestIntoElasticsearch(path string, tr *tmv1beta1.Testrun)
 	if err != nil {
 		return fmt.Errorf("cannot read directory '%s'd: %s", path, err.Error())
 	}
-	for _, file := range files {
-		if !file.IsDir() {
-			if err := c.esClient.BulkFromFile(filepath.Join(path, file.Name())); err != nil {
-				return err
-			}
+	for i, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(path, file.Name()))
+		if err != nil {
+			return err
+		}
+		remaining, err := c.ingest(data)
+		if err == nil {
+			continue
+		}
+		if len(remaining) == 0 {
+			// the documents were rejected permanently and would be rejected on every retry
+			c.log.Error(err, "unable to ingest documents", "name", tr.Name, "namespace", tr.Namespace)
+			continue
 		}
+		if c.spool == nil {
+			return err
+		}
+
+		c.log.Info("elasticsearch is not available, results are spooled", "name", tr.Name, "namespace", tr.Namespace, "error", err.Error())
+		if err := c.spool.Add(spoolName(tr.Name, i), remaining); err != nil {
+			return errors.Wrap(err, "unable to spool results")
+		}
+		c.updateSpoolDepth()
 	}
 
 	tr.Status.Collected = true
 	return nil
 }
+
+// ingest writes a bulk payload to elasticsearch.
+// Payloads that exceed the maximum request size are split and documents that are rejected permanently are dropped.
+// The returned payload contains all documents that could not be ingested but may be ingested by a retry.
+func (c *collector) ingest(data []byte) ([]byte, error) {
+	err := c.esClient.Bulk(data)
+	if err == nil {
+		return nil, nil
+	}
+
+	var (
+		statusErr *elasticsearch.StatusError
+		bulkErr   *elasticsearch.BulkError
+	)
+	switch {
+	case errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusRequestEntityTooLarge:
+		actions := splitBulkActions(data)
+		if len(actions) < 2 {
+			return nil, errors.Wrap(err, "a single document exceeds the maximum request size")
+		}
+		c.log.V(3).Info("bulk request too large, split payload", "actions", len(actions))
+		var (
+			remaining []byte
+			allErrs   *multierror.Error
+		)
+		half := len(actions) / 2
+		for _, part := range [][][]byte{actions[:half], actions[half:]} {
+			r, err := c.ingest(bytes.Join(part, nil))
+			if err != nil {
+				allErrs = multierror.Append(allErrs, err)
+			}
+			remaining = append(remaining, r...)
+		}
+		return remaining, util.ReturnMultiError(allErrs)
+	case errors.As(err, &bulkErr):
+		actions := splitBulkActions(data)
+		var remaining []byte
+		for _, item := range bulkErr.Items {
+			if !item.Retryable() || item.Position >= len(actions) {
+				c.log.Info("document rejected by elasticsearch", "index", item.Index, "status", item.Status, "error", item.Error)
+				continue
+			}
+			remaining = append(remaining, actions[item.Position]...)
+		}
+		return remaining, err
+	case errors.As(err, &statusErr) && statusErr.StatusCode != http.StatusTooManyRequests && statusErr.StatusCode < http.StatusInternalServerError:
+		return nil, err
+	}
+	return data, err
+}
+
+// splitBulkActions splits a bulk payload into its actions.
+// Every action consists of the action line and the document line except for deletions.
+func splitBulkActions(data []byte) [][]byte {
+	lines := bytes.SplitAfter(data, []byte("\n"))
+	actions := make([][]byte, 0, len(lines)/2)
+	for i := 0; i < len(lines); i++ {
+		if len(bytes.TrimSpace(lines[i])) == 0 {
+			continue
+		}
+		action := lines[i]
+		if !bytes.HasPrefix(bytes.TrimSpace(action), []byte(`{"delete"`)) && i+1 < len(lines) {
+			i++
+			action = append(append([]byte{}, action...), lines[i]...)
+		}
+		if !bytes.HasSuffix(action, []byte("\n")) {
+			action = append(action, '\n')
+		}
+		actions = append(actions, action)
+	}
+	return actions
+}
+
+// Start retries the ingestion of spooled results with an exponential backoff until the context is cancelled.
+func (c *collector) Start(ctx context.Context) error {
+	if c.spool == nil || c.esClient == nil {
+		return nil
+	}
+	c.updateSpoolDepth()
+
+	interval := c.spoolRetryInterval
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+
+		if err := c.drainSpool(); err != nil {
+			interval *= 2
+			if interval > c.spoolMaxRetryInterval {
+				interval = c.spoolMaxRetryInterval
+			}
+			c.log.Info("unable to ingest spooled results", "retry", interval.String(), "error", err.Error())
+			continue
+		}
+		interval = c.spoolRetryInterval
+	}
+}
+
+// drainSpool ingests all spooled payloads in the order they were added.
+// It stops at the first payload that could not be ingested as elasticsearch is probably still unavailable.
+func (c *collector) drainSpool() error {
+	defer c.updateSpoolDepth()
+	names, err := c.spool.List()
+	if err != nil {
+		return errors.Wrap(err, "unable to list spooled results")
+	}
+	for _, name := range names {
+		data, err := c.spool.Read(name)
+		if err != nil {
+			return errors.Wrapf(err, "unable to read spooled results %s", name)
+		}
+		remaining, ingestErr := c.ingest(data)
+		if len(remaining) == 0 {
+			if ingestErr != nil {
+				c.log.Error(ingestErr, "unable to ingest spooled documents", "name", name)
+			}
+			if err := c.spool.Remove(name); err != nil {
+				return errors.Wrapf(err, "unable to remove spooled results %s", name)
+			}
+			continue
+		}
+		if !bytes.Equal(remaining, data) {
+			// only keep the documents that still have to be ingested
+			if err := c.spool.Add(name, remaining); err != nil {
+				return errors.Wrapf(err, "unable to update spooled results %s", name)
+			}
+		}
+		return ingestErr
+	}
+	return nil
+}
+
+func (c *collector) updateSpoolDepth() {
+	names, err := c.spool.List()
+	if err != nil {
+		c.log.V(3).Info("unable to list spooled results", "error", err.Error())
+		return
+	}
+	spoolDepth.Set(float64(len(names)))
+}
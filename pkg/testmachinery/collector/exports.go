@@ -18,8 +18,11 @@ import (
 	"github.com/gardener/test-infra/pkg/util/elasticsearch/bulk"
 )
 
-func (c *collector) getExportedDocuments(status tmv1beta1.TestrunStatus, meta *metadata.Metadata) bulk.BulkList {
+// getExportedDocuments reads the documents that were exported by the steps of a testrun.
+// Documents of steps with an export schema are validated and the validation errors of all steps are returned.
+func (c *collector) getExportedDocuments(status tmv1beta1.TestrunStatus, meta *metadata.Metadata) (bulk.BulkList, []tmv1beta1.ExportValidationError) {
 	bulks := make(bulk.BulkList, 0)
+	var validationErrs []tmv1beta1.ExportValidationError
 	for _, step := range status.Steps {
 		if step.Phase != argov1.NodeSkipped && step.ExportArtifactKey != "" {
 			stepMeta := &metadata.StepExportMetadata{
@@ -63,16 +66,25 @@ func (c *collector) getExportedDocuments(status tmv1beta1.TestrunStatus, meta *m
 					continue
 				}
 
+				stepBulks := make(bulk.BulkList, 0)
 				for _, doc := range files {
-					bulks = append(bulks, bulk.ParseExportedFiles(c.log, strings.ToLower(step.TestDefinition.Name), stepMeta, doc)...)
+					stepBulks = append(stepBulks, bulk.ParseExportedFiles(c.log, strings.ToLower(step.TestDefinition.Name), stepMeta, doc)...)
 				}
+				if step.TestDefinition.ExportSchema != nil {
+					var validationErr *tmv1beta1.ExportValidationError
+					stepBulks, validationErr = c.validateExportedDocuments(step, stepBulks)
+					if validationErr != nil {
+						validationErrs = append(validationErrs, *validationErr)
+					}
+				}
+				bulks = append(bulks, stepBulks...)
 			}
 			if err := reader.Close(); err != nil {
 				c.log.Info(fmt.Sprintf("cannot close reader after artifact error: %v", err), "artifact", step.ExportArtifactKey)
 			}
 		}
 	}
-	return bulks
+	return bulks, validationErrs
 }
 
 func getFilesFromTar(r io.Reader) ([][]byte, error) {
@@ -6,8 +6,11 @@ package collector
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 
@@ -15,9 +18,11 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"go.uber.org/mock/gomock"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/json"
 
 	"github.com/gardener/test-infra/pkg/apis/config"
+	tmv1beta1 "github.com/gardener/test-infra/pkg/apis/testmachinery/v1beta1"
 	"github.com/gardener/test-infra/pkg/testmachinery"
 	"github.com/gardener/test-infra/pkg/testmachinery/metadata"
 	mock_elasticsearch "github.com/gardener/test-infra/pkg/util/elasticsearch/mocks"
@@ -104,4 +109,85 @@ var _ = Describe("collector summary", func() {
 		Expect(lastDocument["name"]).To(Equal("test-export"))
 	})
 
+	Context("export schema", func() {
+		var tr *tmv1beta1.Testrun
+
+		BeforeEach(func() {
+			var err error
+			tr, err = testmachinery.ParseTestrunFromFile(filepath.Join(testdataDir, "02_testrun_export.yaml"))
+			Expect(err).ToNot(HaveOccurred())
+
+			s3Object, err := mock_collector.CreateS3ObjectFromFile(filepath.Join(testdataDir, "11_export_artifact.tar.gz"))
+			Expect(err).ToNot(HaveOccurred())
+			s3Client.EXPECT().GetObject("testbucket", "/testing/my/export.tar.gz").Return(s3Object, nil)
+		})
+
+		It("should attach the schema version to valid documents", func() {
+			tr.Status.Steps[1].TestDefinition.ExportSchema = &tmv1beta1.ExportSchema{
+				Version: "v1",
+				Inline:  &runtime.RawExtension{Raw: []byte(`{"type":"object","required":["name"],"properties":{"name":{"type":"string"}}}`)},
+			}
+			Expect(c.collectSummaryAndExports(tmpDir, tr, &metadata.Metadata{Testrun: metadata.TestrunMetadata{ID: tr.Name}})).To(Succeed())
+			Expect(tr.Status.ExportValidationErrors).To(BeEmpty())
+
+			documents := readBulkDocuments(tmpDir)
+			Expect(documents[len(documents)-2]["index"].(map[string]interface{})["_index"]).To(Equal("tm-delete-shoot"))
+			lastDocument := documents[len(documents)-1]
+			Expect(lastDocument["name"]).To(Equal("test-export"))
+			Expect(lastDocument[SchemaVersionField]).To(Equal("v1"))
+			Expect(lastDocument["tm"]).ToNot(BeNil())
+		})
+
+		It("should route invalid documents to the dead-letter index", func() {
+			tr.Status.Steps[1].TestDefinition.ExportSchema = &tmv1beta1.ExportSchema{
+				Version: "v2",
+				Inline:  &runtime.RawExtension{Raw: []byte(`{"type":"object","required":["version"]}`)},
+			}
+			Expect(c.collectSummaryAndExports(tmpDir, tr, &metadata.Metadata{Testrun: metadata.TestrunMetadata{ID: tr.Name}})).To(Succeed())
+			Expect(tr.Status.ExportValidationErrors).To(HaveLen(1))
+			Expect(tr.Status.ExportValidationErrors[0].Step).To(Equal("delete-shoot-delete-testflow"))
+			Expect(tr.Status.ExportValidationErrors[0].SchemaVersion).To(Equal("v2"))
+			Expect(tr.Status.ExportValidationErrors[0].Count).To(Equal(1))
+			Expect(tr.Status.ExportValidationErrors[0].Message).To(ContainSubstring("version"))
+
+			documents := readBulkDocuments(tmpDir)
+			Expect(documents[len(documents)-2]["index"].(map[string]interface{})["_index"]).To(Equal(DeadLetterIndex))
+			lastDocument := documents[len(documents)-1]
+			Expect(lastDocument["index"]).To(Equal("tm-delete-shoot"))
+			Expect(lastDocument["error"]).To(ContainSubstring("version"))
+			Expect(lastDocument[SchemaVersionField]).To(Equal("v2"))
+			Expect(lastDocument["document"]).To(MatchJSON(`{"name":"test-export"}`))
+			Expect(lastDocument["tm"]).ToNot(BeNil())
+		})
+
+		It("should validate documents against a schema that is referenced by an url", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(`{"type":"object","properties":{"name":{"type":"integer"}}}`))
+			}))
+			defer server.Close()
+			tr.Status.Steps[1].TestDefinition.ExportSchema = &tmv1beta1.ExportSchema{Version: "v1", URL: server.URL}
+
+			Expect(c.collectSummaryAndExports(tmpDir, tr, &metadata.Metadata{Testrun: metadata.TestrunMetadata{ID: tr.Name}})).To(Succeed())
+			Expect(tr.Status.ExportValidationErrors).To(HaveLen(1))
+			Expect(tr.Status.ExportValidationErrors[0].Message).To(ContainSubstring("name"))
+		})
+	})
+
 })
+
+func readBulkDocuments(dir string) []map[string]interface{} {
+	files, err := os.ReadDir(dir)
+	ExpectWithOffset(1, err).ToNot(HaveOccurred())
+	ExpectWithOffset(1, files).To(HaveLen(1))
+
+	data, err := os.ReadFile(filepath.Join(dir, files[0].Name()))
+	ExpectWithOffset(1, err).ToNot(HaveOccurred())
+	documents := []map[string]interface{}{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		var doc map[string]interface{}
+		ExpectWithOffset(1, json.Unmarshal(scanner.Bytes(), &doc)).To(Succeed())
+		documents = append(documents, doc)
+	}
+	return documents
+}
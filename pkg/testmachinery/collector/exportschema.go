@@ -0,0 +1,203 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"k8s.io/kube-openapi/pkg/validation/strfmt"
+	"k8s.io/kube-openapi/pkg/validation/validate"
+
+	tmv1beta1 "github.com/gardener/test-infra/pkg/apis/testmachinery/v1beta1"
+	"github.com/gardener/test-infra/pkg/util"
+	"github.com/gardener/test-infra/pkg/util/elasticsearch/bulk"
+)
+
+const (
+	// DeadLetterIndex is the index where exported documents are written to that do not match the export schema of their TestDefinition.
+	DeadLetterIndex = "tm-dead-letter"
+
+	// SchemaVersionField is the field that is added to every exported document that was validated against an export schema.
+	SchemaVersionField = "schemaVersion"
+
+	schemaDownloadTimeout = 30 * time.Second
+	maxSchemaSize         = 1024 * 1024
+)
+
+// schemaCache caches export schemas that are referenced by a url.
+// The zero value is ready to use.
+type schemaCache struct {
+	mux     sync.Mutex
+	schemas map[string]*spec.Schema
+}
+
+// get returns the parsed json schema of an export schema.
+func (sc *schemaCache) get(exportSchema *tmv1beta1.ExportSchema) (*spec.Schema, error) {
+	if exportSchema.Inline != nil && len(exportSchema.Inline.Raw) != 0 {
+		return parseSchema(exportSchema.Inline.Raw)
+	}
+	if len(exportSchema.URL) == 0 {
+		return nil, errors.New("neither an url nor an inline schema is defined")
+	}
+
+	// the version is part of the key so that an updated schema behind the same url is fetched again
+	key := exportSchema.Version + "@" + exportSchema.URL
+	sc.mux.Lock()
+	defer sc.mux.Unlock()
+	if schema, ok := sc.schemas[key]; ok {
+		return schema, nil
+	}
+	data, err := downloadSchema(exportSchema.URL)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := parseSchema(data)
+	if err != nil {
+		return nil, err
+	}
+	if sc.schemas == nil {
+		sc.schemas = make(map[string]*spec.Schema)
+	}
+	sc.schemas[key] = schema
+	return schema, nil
+}
+
+func downloadSchema(url string) ([]byte, error) {
+	client := &http.Client{Timeout: schemaDownloadTimeout}
+	res, err := client.Get(url)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to get export schema from %s", url)
+	}
+	defer func() { _ = res.Body.Close() }()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unable to get export schema from %s: status %d", url, res.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(res.Body, maxSchemaSize))
+}
+
+func parseSchema(data []byte) (*spec.Schema, error) {
+	schema := &spec.Schema{}
+	if err := json.Unmarshal(data, schema); err != nil {
+		return nil, errors.Wrap(err, "unable to parse export schema")
+	}
+	return schema, nil
+}
+
+// validateDocument validates a document against a json schema.
+// References to other schemas are not supported and result in an error.
+func validateDocument(schema *spec.Schema, doc map[string]interface{}) (err error) {
+	defer func() {
+		// the validator panics on references that cannot be resolved
+		if r := recover(); r != nil {
+			err = fmt.Errorf("unable to validate document: %v", r)
+		}
+	}()
+	res := validate.NewSchemaValidator(schema, nil, "", strfmt.Default).Validate(doc)
+	if !res.HasErrors() {
+		return nil
+	}
+	msgs := make([]string, len(res.Errors))
+	for i, e := range res.Errors {
+		msgs[i] = e.Error()
+	}
+	return errors.New(strings.Join(msgs, "; "))
+}
+
+// validateExportedDocuments validates the exported documents of a step against the export schema of its TestDefinition.
+// Valid documents get the schema version attached, invalid ones are rewritten to documents of the dead-letter index
+// that contain the original document and the validation error.
+// A validation error is returned if at least one document is invalid.
+func (c *collector) validateExportedDocuments(step *tmv1beta1.StepStatus, bulks bulk.BulkList) (bulk.BulkList, *tmv1beta1.ExportValidationError) {
+	exportSchema := step.TestDefinition.ExportSchema
+	schema, schemaErr := c.schemas.get(exportSchema)
+	if schemaErr != nil {
+		// documents cannot be validated so they are kept in the dead-letter index until the schema is fixed
+		c.log.Info("unable to load export schema", "step", step.Name, "error", schemaErr.Error())
+	}
+
+	var validationErr *tmv1beta1.ExportValidationError
+	result := make(bulk.BulkList, 0, len(bulks))
+	for _, b := range bulks {
+		var doc map[string]interface{}
+		if err := json.Unmarshal(b.Source, &doc); err != nil {
+			c.log.V(3).Info("cannot unmarshal exported document", "step", step.Name, "error", err.Error())
+			continue
+		}
+		tm := doc["tm"]
+		delete(doc, "tm")
+
+		docErr := schemaErr
+		if docErr == nil {
+			docErr = validateDocument(schema, doc)
+		}
+		if docErr == nil {
+			doc["tm"] = tm
+			doc[SchemaVersionField] = exportSchema.Version
+			source, err := util.MarshalNoHTMLEscape(doc)
+			if err != nil {
+				c.log.V(3).Info("cannot marshal exported document", "step", step.Name, "error", err.Error())
+				continue
+			}
+			result = append(result, &bulk.Bulk{Metadata: b.Metadata, Source: source})
+			continue
+		}
+
+		if validationErr == nil {
+			validationErr = &tmv1beta1.ExportValidationError{
+				Step:          step.Name,
+				SchemaVersion: exportSchema.Version,
+				Message:       docErr.Error(),
+			}
+		}
+		validationErr.Count++
+
+		original, err := json.Marshal(doc)
+		if err != nil {
+			c.log.V(3).Info("cannot marshal exported document", "step", step.Name, "error", err.Error())
+			continue
+		}
+		source, err := util.MarshalNoHTMLEscape(map[string]interface{}{
+			"tm":               tm,
+			"index":            bulkIndex(b),
+			"error":            docErr.Error(),
+			SchemaVersionField: exportSchema.Version,
+			"document":         string(original),
+		})
+		if err != nil {
+			c.log.V(3).Info("cannot marshal dead-letter document", "step", step.Name, "error", err.Error())
+			continue
+		}
+		result = append(result, &bulk.Bulk{
+			Metadata: bulk.ESMetadata{Index: bulk.ESIndex{Index: DeadLetterIndex}},
+			Source:   source,
+		})
+	}
+	return result, validationErr
+}
+
+// bulkIndex returns the index a bulk document is written to.
+func bulkIndex(b *bulk.Bulk) string {
+	switch meta := b.Metadata.(type) {
+	case bulk.ESMetadata:
+		return meta.Index.Index
+	case map[string]interface{}:
+		for _, action := range meta {
+			if a, ok := action.(map[string]interface{}); ok {
+				if index, ok := a["_index"].(string); ok {
+					return index
+				}
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,21 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var spoolDepth = prometheus.NewGauge(prometheus.GaugeOpts{
+	Namespace: "testmachinery",
+	Subsystem: "collector",
+	Name:      "spool_depth",
+	Help:      "Number of spooled bulk payloads that still have to be ingested into elasticsearch.",
+})
+
+func init() {
+	metrics.Registry.MustRegister(spoolDepth)
+}
@@ -0,0 +1,132 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/gardener/test-infra/pkg/apis/config"
+	"github.com/gardener/test-infra/pkg/util/s3"
+)
+
+// spool durably stores bulk payloads that could not be ingested into elasticsearch until they are retried
+type spool interface {
+	// Add stores a bulk payload with the given name
+	Add(name string, data []byte) error
+	// List returns the names of all stored payloads in the order they were added
+	List() ([]string, error)
+	Read(name string) ([]byte, error)
+	Remove(name string) error
+}
+
+// newSpool creates the spool that is configured in the elasticsearch configuration.
+// Nil is returned if no spool is configured.
+func newSpool(cfg config.ElasticSearchSpool, s3Client s3.Client) (spool, error) {
+	switch {
+	case len(cfg.Dir) != 0:
+		if err := os.MkdirAll(cfg.Dir, 0750); err != nil {
+			return nil, errors.Wrapf(err, "unable to create spool directory %s", cfg.Dir)
+		}
+		return &dirSpool{dir: cfg.Dir}, nil
+	case len(cfg.S3Prefix) != 0:
+		if s3Client == nil {
+			return nil, errors.New("a s3 configuration is required for a s3 spool")
+		}
+		return &s3Spool{client: s3Client, prefix: strings.TrimSuffix(cfg.S3Prefix, "/")}, nil
+	}
+	return nil, nil
+}
+
+// dirSpool stores the payloads as files in a directory which is expected to be on a persistent volume
+type dirSpool struct {
+	dir string
+}
+
+func (s *dirSpool) Add(name string, data []byte) error {
+	// write to a temporary file first so that a partially written payload is never ingested
+	tmp := filepath.Join(s.dir, "."+name)
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(s.dir, name))
+}
+
+func (s *dirSpool) List() ([]string, error) {
+	files, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(files))
+	for _, file := range files {
+		if file.IsDir() || strings.HasPrefix(file.Name(), ".") {
+			continue
+		}
+		names = append(names, file.Name())
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (s *dirSpool) Read(name string) ([]byte, error) {
+	return os.ReadFile(filepath.Join(s.dir, filepath.Clean(name)))
+}
+
+func (s *dirSpool) Remove(name string) error {
+	err := os.Remove(filepath.Join(s.dir, filepath.Clean(name)))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// s3Spool stores the payloads as objects with a common prefix in the default bucket
+type s3Spool struct {
+	client s3.Client
+	prefix string
+}
+
+func (s *s3Spool) Add(name string, data []byte) error {
+	return s.client.PutObject("", path.Join(s.prefix, name), data)
+}
+
+func (s *s3Spool) List() ([]string, error) {
+	keys, err := s.client.ListObjects("", s.prefix+"/")
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(keys))
+	for _, key := range keys {
+		names = append(names, strings.TrimPrefix(key, s.prefix+"/"))
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (s *s3Spool) Read(name string) ([]byte, error) {
+	obj, err := s.client.GetObject("", path.Join(s.prefix, name))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = obj.Close() }()
+	return io.ReadAll(obj)
+}
+
+func (s *s3Spool) Remove(name string) error {
+	return s.client.RemoveObject("", path.Join(s.prefix, name))
+}
+
+// spoolName returns a name for a payload that sorts the payloads by the time they were added
+func spoolName(id string, n int) string {
+	return fmt.Sprintf("%s-%s-%d", time.Now().UTC().Format("20060102T150405.000000000"), id, n)
+}
@@ -0,0 +1,129 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/gardener/test-infra/pkg/apis/config"
+	tmv1beta1 "github.com/gardener/test-infra/pkg/apis/testmachinery/v1beta1"
+	"github.com/gardener/test-infra/pkg/util/elasticsearch"
+	mock_elasticsearch "github.com/gardener/test-infra/pkg/util/elasticsearch/mocks"
+	mock_s3 "github.com/gardener/test-infra/pkg/util/s3/mocks"
+)
+
+var _ = Describe("collector spool", func() {
+
+	var (
+		esCtrl   *gomock.Controller
+		esClient *mock_elasticsearch.MockClient
+		spoolDir string
+		c        *collector
+	)
+
+	BeforeEach(func() {
+		esCtrl = gomock.NewController(GinkgoT())
+		esClient = mock_elasticsearch.NewMockClient(esCtrl)
+		spoolDir = GinkgoT().TempDir()
+		c = &collector{
+			log:      logr.Discard(),
+			esClient: esClient,
+			spool:    &dirSpool{dir: spoolDir},
+		}
+	})
+
+	AfterEach(func() {
+		esCtrl.Finish()
+	})
+
+	It("should split bulk requests that are too large", func() {
+		var ingested [][]byte
+		esClient.EXPECT().Bulk(gomock.Any()).DoAndReturn(func(data []byte) error {
+			if len(splitBulkActions(data)) > 1 {
+				return &elasticsearch.StatusError{StatusCode: http.StatusRequestEntityTooLarge}
+			}
+			ingested = append(ingested, data)
+			return nil
+		}).AnyTimes()
+
+		remaining, err := c.ingest(bulkPayload(3))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(remaining).To(BeEmpty())
+		Expect(ingested).To(HaveLen(3))
+		Expect(bytes.Join(ingested, nil)).To(Equal(bulkPayload(3)))
+	})
+
+	It("should only retry documents that were rejected temporarily", func() {
+		esClient.EXPECT().Bulk(gomock.Any()).Return(&elasticsearch.BulkError{Items: []elasticsearch.BulkItemError{
+			{Position: 0, BulkResponseItem: elasticsearch.BulkResponseItem{Status: http.StatusBadRequest}},
+			{Position: 2, BulkResponseItem: elasticsearch.BulkResponseItem{Status: http.StatusTooManyRequests}},
+		}})
+
+		remaining, err := c.ingest(bulkPayload(3))
+		Expect(err).To(HaveOccurred())
+		Expect(remaining).To(Equal(splitBulkActions(bulkPayload(3))[2]))
+	})
+
+	It("should spool results while elasticsearch is unavailable and ingest them later", func() {
+		resultDir := GinkgoT().TempDir()
+		Expect(os.WriteFile(filepath.Join(resultDir, "res-1"), bulkPayload(2), 0600)).To(Succeed())
+		tr := &tmv1beta1.Testrun{}
+		tr.Name = "tr"
+		tr.Status.StartTime = &metav1.Time{Time: time.Now()}
+
+		esClient.EXPECT().Request(http.MethodGet, "/testmachinery-*/_search", gomock.Any()).Return([]byte(`{ "hits": { "total": { "value": 0 } } }`), nil)
+		esClient.EXPECT().Bulk(gomock.Any()).Return(&elasticsearch.StatusError{StatusCode: http.StatusServiceUnavailable})
+		Expect(c.ingestIntoElasticsearch(resultDir, tr)).To(Succeed())
+		Expect(tr.Status.Collected).To(BeTrue())
+
+		names, err := c.spool.List()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(names).To(HaveLen(1))
+
+		esClient.EXPECT().Bulk(gomock.Any()).Return(&elasticsearch.StatusError{StatusCode: http.StatusServiceUnavailable})
+		Expect(c.drainSpool()).ToNot(Succeed())
+		Expect(os.ReadFile(filepath.Join(spoolDir, names[0]))).To(Equal(bulkPayload(2)))
+
+		esClient.EXPECT().Bulk(bulkPayload(2)).Return(nil)
+		Expect(c.drainSpool()).To(Succeed())
+		Expect(c.spool.List()).To(BeEmpty())
+	})
+
+	It("should list the payloads of a s3 spool whose prefix ends with a slash", func() {
+		s3Ctrl := gomock.NewController(GinkgoT())
+		s3Client := mock_s3.NewMockClient(s3Ctrl)
+		s, err := newSpool(config.ElasticSearchSpool{S3Prefix: "spool/"}, s3Client)
+		Expect(err).ToNot(HaveOccurred())
+
+		s3Client.EXPECT().PutObject("", "spool/a", []byte("data")).Return(nil)
+		Expect(s.Add("a", []byte("data"))).To(Succeed())
+
+		s3Client.EXPECT().ListObjects("", "spool/").Return([]string{"spool/a"}, nil)
+		Expect(s.List()).To(Equal([]string{"a"}))
+
+		s3Client.EXPECT().RemoveObject("", "spool/a").Return(nil)
+		Expect(s.Remove("a")).To(Succeed())
+	})
+})
+
+func bulkPayload(n int) []byte {
+	var buf bytes.Buffer
+	for i := 0; i < n; i++ {
+		buf.WriteString("{\"index\":{\"_index\":\"testmachinery\"}}\n")
+		buf.WriteString(fmt.Sprintf("{\"name\":\"doc-%d\"}\n", i))
+	}
+	return buf.Bytes()
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/gardener/test-infra/pkg/testmachinery/metadata"
 	"github.com/gardener/test-infra/pkg/util"
 	"github.com/gardener/test-infra/pkg/util/elasticsearch/bulk"
+	"github.com/gardener/test-infra/pkg/util/redact"
 )
 
 // collectSummaryAndExports takes a completed testrun status and writes the results to elastic search bulk json files.
@@ -24,7 +25,15 @@ func (c *collector) collectSummaryAndExports(path string, tr *tmv1beta1.Testrun,
 	meta.Testrun.StartTime = tr.Status.StartTime
 	meta.Annotations = tr.Annotations
 
+	var stepTelemetry map[string]*metadata.TelemetryData
+	if c.s3Client != nil {
+		stepTelemetry, meta.TelemetryData = c.getTelemetryData(tr.Status)
+	}
+
 	trSummary, summaries := c.generateSummary(tr, meta)
+	for i, step := range tr.Status.Steps {
+		summaries[i].TelemetryData = stepTelemetry[step.Name]
+	}
 	trStatusSummaries, err := marshalAndAppendSummaries(trSummary, summaries)
 	if err != nil {
 		return err
@@ -38,8 +47,9 @@ func (c *collector) collectSummaryAndExports(path string, tr *tmv1beta1.Testrun,
 	summaryBulk := bulk.NewList(summaryMetadata, trStatusSummaries)
 
 	if c.s3Client != nil {
-		exportedDocumentsBulk := c.getExportedDocuments(tr.Status, meta)
+		exportedDocumentsBulk, validationErrs := c.getExportedDocuments(tr.Status, meta)
 		summaryBulk = append(summaryBulk, exportedDocumentsBulk...)
+		tr.Status.ExportValidationErrors = validationErrs
 	}
 
 	summary, err := summaryBulk.Marshal()
@@ -68,6 +78,16 @@ func (c *collector) collectSummaryAndExports(path string, tr *tmv1beta1.Testrun,
 
 // generateSummary parses a testruns status and returns
 func (c *collector) generateSummary(tr *tmv1beta1.Testrun, meta *metadata.Metadata) (metadata.TestrunSummary, []metadata.StepSummary) {
+	clusterDomain, err := util.GetClusterDomainURL(c.client)
+	if err != nil {
+		c.log.Error(err, "Could not obtain cluster domain URL, will not pre compute dependent fields (argo-, grafana-url)")
+	}
+	return GenerateSummary(tr, meta, clusterDomain)
+}
+
+// GenerateSummary parses a testruns status and returns the summary of the testrun and of all its steps.
+// The cluster domain is used to precompute the argo and grafana urls of the steps.
+func GenerateSummary(tr *tmv1beta1.Testrun, meta *metadata.Metadata, clusterDomain string) (metadata.TestrunSummary, []metadata.StepSummary) {
 	status := tr.Status
 	testsRun := 0
 	summaries := make([]metadata.StepSummary, 0)
@@ -82,13 +102,13 @@ func (c *collector) generateSummary(tr *tmv1beta1.Testrun, meta *metadata.Metada
 		stepMetadata.Annotations = utils.MergeStringMaps(stepMetadata.Annotations, step.Annotations)
 		for _, elem := range step.TestDefinition.Config {
 			if elem.Type == tmv1beta1.ConfigTypeEnv && elem.Value != "" {
-				stepMetadata.Configuration[elem.Name] = elem.Value
+				stepMetadata.Configuration[elem.Name] = redact.ConfigElement(elem).Value
 			}
 		}
 		if meta.Testrun.ExecutionGroup != "" {
 			stepMetadata.Annotations[common.LabelTestrunExecutionGroup] = meta.Testrun.ExecutionGroup
 		}
-		pre := c.preComputeTeststepFields(step, stepMetadata.Metadata)
+		pre := PreComputeTeststepFields(step.Phase, stepMetadata.Metadata, clusterDomain)
 
 		summary := metadata.StepSummary{
 			Metadata:    stepMetadata,
@@ -100,6 +120,8 @@ func (c *collector) generateSummary(tr *tmv1beta1.Testrun, meta *metadata.Metada
 			Duration:    step.Duration,
 			PreComputed: pre,
 			Labels:      step.TestDefinition.Labels,
+			Owner:       step.TestDefinition.Owner,
+			Recipients:  step.TestDefinition.RecipientsOnFailure,
 		}
 
 		summaries = append(summaries, summary)
@@ -120,13 +142,3 @@ func (c *collector) generateSummary(tr *tmv1beta1.Testrun, meta *metadata.Metada
 
 	return trSummary, summaries
 }
-
-// preComputeTeststepFields precomputes fields for elasticsearch that are otherwise hard to add at runtime (i.e. as grafana does not support scripted fields)
-func (c *collector) preComputeTeststepFields(stepStatus *tmv1beta1.StepStatus, meta metadata.Metadata) *metadata.StepPreComputed {
-	clusterDomain, err := util.GetClusterDomainURL(c.client)
-	if err != nil {
-		c.log.Error(err, "Could not obtain cluster domain URL, will not pre compute dependent fields (argo-, grafana-url)")
-	}
-
-	return PreComputeTeststepFields(stepStatus.Phase, meta, clusterDomain)
-}
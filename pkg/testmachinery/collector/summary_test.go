@@ -8,9 +8,11 @@ import (
 	"github.com/go-logr/logr"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"k8s.io/utils/ptr"
 
 	"github.com/gardener/test-infra/pkg/apis/testmachinery/v1beta1"
 	"github.com/gardener/test-infra/pkg/testmachinery/metadata"
+	"github.com/gardener/test-infra/pkg/util/redact"
 	"github.com/gardener/test-infra/test/resources"
 )
 
@@ -52,6 +54,31 @@ var _ = Describe("output generation tests", func() {
 			Expect(summaries[0].Metadata.Configuration).To(HaveKey(configElement.Name))
 			Expect(summaries[0].Metadata.Configuration[configElement.Name]).To(Equal(configElement.Value))
 		})
+
+		It("should redact sensitive configuration in the metadata", func() {
+			tr := resources.GetBasicTestrun("", "")
+			tr.Status = v1beta1.TestrunStatus{
+				Steps: []*v1beta1.StepStatus{
+					{
+						TestDefinition: v1beta1.StepStatusTestDefinition{
+							Config: []*v1beta1.ConfigElement{
+								{Type: v1beta1.ConfigTypeEnv, Name: "GITHUB_TOKEN", Value: "abcdef"},
+								{Type: v1beta1.ConfigTypeEnv, Name: "custom", Value: "secret-value", Sensitive: ptr.To(true)},
+								{Type: v1beta1.ConfigTypeEnv, Name: "SHOOT_NAME", Value: "my-shoot"},
+							},
+						},
+					},
+				},
+			}
+
+			_, summaries := c.generateSummary(tr, &metadata.Metadata{})
+			Expect(summaries).To(HaveLen(1))
+			Expect(summaries[0].Metadata.Configuration).To(Equal(map[string]string{
+				"GITHUB_TOKEN": redact.Placeholder,
+				"custom":       redact.Placeholder,
+				"SHOOT_NAME":   "my-shoot",
+			}))
+		})
 	})
 
 	It("should exclude file variable configuration from in the metadata", func() {
@@ -0,0 +1,64 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"bytes"
+
+	argov1 "github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
+
+	tmv1beta1 "github.com/gardener/test-infra/pkg/apis/testmachinery/v1beta1"
+	"github.com/gardener/test-infra/pkg/testmachinery/metadata"
+	"github.com/gardener/test-infra/pkg/testmachinery/telemetry"
+)
+
+// getTelemetryData reads the telemetry samples of all steps and computes the telemetry data of every step and of the whole testrun.
+// The telemetry data of the steps is returned by the name of the step.
+func (c *collector) getTelemetryData(status tmv1beta1.TestrunStatus) (map[string]*metadata.TelemetryData, *metadata.TelemetryData) {
+	stepData := make(map[string]*metadata.TelemetryData)
+	stepSamples := make([][]telemetry.Sample, 0, len(status.Steps))
+	for _, step := range status.Steps {
+		if step.Phase == argov1.NodeSkipped || step.TelemetryArtifactKey == "" {
+			continue
+		}
+		samples, err := c.getTelemetrySamples(step.TelemetryArtifactKey)
+		if err != nil {
+			c.log.Info("unable to read telemetry samples", "artifact", step.TelemetryArtifactKey, "error", err.Error())
+			continue
+		}
+		if data := telemetry.Compute(samples); data != nil {
+			stepData[step.Name] = data
+		}
+		stepSamples = append(stepSamples, samples)
+	}
+	// the downtimes are computed per step as the steps do not probe the targets between each other
+	return stepData, telemetry.ComputeGroups(stepSamples)
+}
+
+func (c *collector) getTelemetrySamples(key string) ([]telemetry.Sample, error) {
+	reader, err := c.s3Client.GetObject(c.s3Config.BucketName, key)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := reader.Close(); err != nil {
+			c.log.Info("cannot close reader of telemetry artifact", "artifact", key, "error", err.Error())
+		}
+	}()
+
+	files, err := getFilesFromTar(reader)
+	if err != nil {
+		return nil, err
+	}
+	samples := make([]telemetry.Sample, 0)
+	for _, file := range files {
+		fileSamples, err := telemetry.ReadSamples(bytes.NewReader(file))
+		if err != nil {
+			return nil, err
+		}
+		samples = append(samples, fileSamples...)
+	}
+	return samples, nil
+}
@@ -0,0 +1,119 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+
+	argov1 "github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+
+	"github.com/gardener/test-infra/pkg/apis/config"
+	tmv1beta1 "github.com/gardener/test-infra/pkg/apis/testmachinery/v1beta1"
+	"github.com/gardener/test-infra/pkg/util/s3"
+	mock_s3 "github.com/gardener/test-infra/pkg/util/s3/mocks"
+)
+
+var _ = Describe("collector telemetry", func() {
+
+	var (
+		s3Ctrl   *gomock.Controller
+		s3Client *mock_s3.MockClient
+		c        *collector
+	)
+
+	BeforeEach(func() {
+		s3Ctrl = gomock.NewController(GinkgoT())
+		s3Client = mock_s3.NewMockClient(s3Ctrl)
+		c = &collector{
+			log:      logr.Discard(),
+			s3Client: s3Client,
+			s3Config: &config.S3{BucketName: "testbucket"},
+		}
+	})
+
+	AfterEach(func() {
+		s3Ctrl.Finish()
+	})
+
+	It("should compute the telemetry data of every step and of the testrun", func() {
+		s3Client.EXPECT().GetObject("testbucket", "step-a").Return(telemetryArtifact(
+			`{"time":"2024-01-01T10:00:00Z","target":"shoot-apiserver","up":true,"responseTime":10}`,
+			`{"time":"2024-01-01T10:00:05Z","target":"shoot-apiserver","up":false}`,
+			`{"time":"2024-01-01T10:00:15Z","target":"shoot-apiserver","up":true,"responseTime":30}`,
+		), nil)
+		s3Client.EXPECT().GetObject("testbucket", "step-b").Return(telemetryArtifact(
+			`{"time":"2024-01-01T11:00:00Z","target":"shoot-apiserver","up":true,"responseTime":50}`,
+		), nil)
+
+		steps, testrun := c.getTelemetryData(tmv1beta1.TestrunStatus{
+			Steps: []*tmv1beta1.StepStatus{
+				{Name: "a", Phase: argov1.NodeSucceeded, TelemetryArtifactKey: "step-a"},
+				{Name: "b", Phase: argov1.NodeFailed, TelemetryArtifactKey: "step-b"},
+				{Name: "c", Phase: argov1.NodeSucceeded},
+			},
+		})
+		Expect(steps).To(HaveLen(2))
+		Expect(steps["a"].ResponseTime.Avg).To(Equal(int64(20)))
+		Expect(steps["a"].DowntimePeriods.Max).To(Equal(int64(10)))
+		Expect(steps["b"].ResponseTime.Avg).To(Equal(int64(50)))
+		Expect(steps["b"].DowntimePeriods.Max).To(Equal(int64(0)))
+		Expect(testrun.ResponseTime.Min).To(Equal(10))
+		Expect(testrun.ResponseTime.Max).To(Equal(50))
+		Expect(testrun.DowntimePeriods.Max).To(Equal(int64(10)))
+	})
+
+	It("should not count the gap between steps as downtime", func() {
+		s3Client.EXPECT().GetObject("testbucket", "step-a").Return(telemetryArtifact(
+			`{"time":"2024-01-01T10:00:00Z","target":"shoot-apiserver","up":true,"responseTime":10}`,
+			`{"time":"2024-01-01T10:00:20Z","target":"shoot-apiserver","up":false}`,
+		), nil)
+		s3Client.EXPECT().GetObject("testbucket", "step-b").Return(telemetryArtifact(
+			`{"time":"2024-01-01T11:00:00Z","target":"shoot-apiserver","up":false}`,
+			`{"time":"2024-01-01T11:00:05Z","target":"shoot-apiserver","up":true,"responseTime":20}`,
+		), nil)
+
+		steps, testrun := c.getTelemetryData(tmv1beta1.TestrunStatus{
+			Steps: []*tmv1beta1.StepStatus{
+				{Name: "a", Phase: argov1.NodeSucceeded, TelemetryArtifactKey: "step-a"},
+				{Name: "b", Phase: argov1.NodeSucceeded, TelemetryArtifactKey: "step-b"},
+			},
+		})
+		Expect(steps["a"].DowntimePeriods.Max).To(Equal(int64(0)))
+		Expect(steps["b"].DowntimePeriods.Max).To(Equal(int64(5)))
+		Expect(testrun.DowntimePeriods.Min).To(Equal(int64(0)))
+		Expect(testrun.DowntimePeriods.Max).To(Equal(int64(5)))
+		Expect(testrun.ResponseTime.Avg).To(Equal(int64(15)))
+	})
+})
+
+func telemetryArtifact(samples ...string) s3.Object {
+	var content bytes.Buffer
+	for _, s := range samples {
+		content.WriteString(s + "\n")
+	}
+
+	var buf bytes.Buffer
+	gzw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gzw)
+	ExpectWithOffset(1, tw.WriteHeader(&tar.Header{Name: "samples.json", Mode: 0644, Size: int64(content.Len()), Typeflag: tar.TypeReg})).To(Succeed())
+	_, err := tw.Write(content.Bytes())
+	ExpectWithOffset(1, err).ToNot(HaveOccurred())
+	ExpectWithOffset(1, tw.Close()).To(Succeed())
+	ExpectWithOffset(1, gzw.Close()).To(Succeed())
+
+	file := filepath.Join(GinkgoT().TempDir(), "telemetry.tar.gz")
+	ExpectWithOffset(1, os.WriteFile(file, buf.Bytes(), 0600)).To(Succeed())
+	obj, err := mock_s3.CreateS3ObjectFromFile(file)
+	ExpectWithOffset(1, err).ToNot(HaveOccurred())
+	return obj
+}
@@ -46,6 +46,9 @@ const (
 	// TM_REPO_PATH is the path where the repo/location is mounted to the tests.
 	TM_REPO_PATH = "/src"
 
+	// TM_REPO_CACHE_PATH is the path where the shared repository cache is located in the prepare step.
+	TM_REPO_CACHE_PATH = "/tmp/tm/repo-cache"
+
 	// TM_PHASE_NAME is the name of the environment variable that holds the Test Machinery phase
 	TM_PHASE_NAME = "TM_PHASE"
 
@@ -58,6 +61,15 @@ const (
 	// ExportArtifact is the name of the output artifact where results are stored.
 	ExportArtifact = "ExportArtifact"
 
+	// TM_TELEMETRY_PATH is the path where the telemetry sidecar writes the measured samples.
+	TM_TELEMETRY_PATH = "/tmp/tm/telemetry"
+
+	// TelemetrySamplesFile is the name of the file in the telemetry path that contains the measured samples.
+	TelemetrySamplesFile = "samples.json"
+
+	// TelemetryArtifact is the name of the output artifact where the telemetry samples are stored.
+	TelemetryArtifact = "TelemetryArtifact"
+
 	// TM_TESTRUN_ID_NAME is the name of the environment variable that holds the current testrun id
 	TM_TESTRUN_ID_NAME = "TM_TESTRUN_ID"
 
@@ -0,0 +1,149 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package configsource
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"text/template"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/gardener/test-infra/pkg/apis/config"
+	"github.com/gardener/test-infra/pkg/util/strconf"
+)
+
+// Resolver resolves the values of config sources of testruns.
+// Secrets of other namespaces, Vault secrets and templates cannot be referenced by the pods of a testrun
+// and have to be resolved by the testmachinery.
+type Resolver struct {
+	reader            client.Reader
+	namespace         string
+	allowedNamespaces sets.Set[string]
+	vault             *vaultClient
+
+	// vaultSecrets caches the read vault secrets by their mount and path
+	vaultSecrets map[string]map[string]interface{}
+}
+
+// NewResolver creates a new resolver for config sources of testruns in the given namespace.
+// Only secrets of the testrun namespace can be read if no additional sources are configured.
+func NewResolver(reader client.Reader, namespace string, cfg *config.ConfigSources) *Resolver {
+	r := &Resolver{
+		reader:            reader,
+		namespace:         namespace,
+		allowedNamespaces: sets.New[string](),
+		vaultSecrets:      make(map[string]map[string]interface{}),
+	}
+	if cfg != nil {
+		r.allowedNamespaces.Insert(cfg.AllowedSecretNamespaces...)
+		if cfg.Vault != nil {
+			r.vault = newVaultClient(cfg.Vault)
+		}
+	}
+	return r
+}
+
+// Resolve returns the value of a config source.
+func (r *Resolver) Resolve(ctx context.Context, source *strconf.ConfigSource) ([]byte, error) {
+	switch {
+	case source.ConfigMapKeyRef != nil:
+		return r.resolveConfigMapKey(ctx, source.ConfigMapKeyRef)
+	case source.SecretKeyRef != nil:
+		return r.resolveSecretKey(ctx, r.namespace, source.SecretKeyRef.Name, source.SecretKeyRef.Key, source.SecretKeyRef.Optional)
+	case source.NamespacedSecretKeyRef != nil:
+		ref := source.NamespacedSecretKeyRef
+		if ref.Namespace != r.namespace && !r.allowedNamespaces.Has(ref.Namespace) {
+			return nil, fmt.Errorf("secrets of namespace %q are not allowed to be referenced", ref.Namespace)
+		}
+		return r.resolveSecretKey(ctx, ref.Namespace, ref.Name, ref.Key, nil)
+	case source.Vault != nil:
+		return r.resolveVaultSecret(ctx, source.Vault)
+	case source.Template != nil:
+		return r.resolveTemplate(ctx, source.Template)
+	default:
+		return nil, errors.New("no source defined")
+	}
+}
+
+func (r *Resolver) resolveConfigMapKey(ctx context.Context, ref *corev1.ConfigMapKeySelector) ([]byte, error) {
+	cm := &corev1.ConfigMap{}
+	if err := r.reader.Get(ctx, client.ObjectKey{Name: ref.Name, Namespace: r.namespace}, cm); err != nil {
+		return nil, errors.Wrapf(err, "unable to get configmap %s/%s", r.namespace, ref.Name)
+	}
+	if value, ok := cm.Data[ref.Key]; ok {
+		return []byte(value), nil
+	}
+	if value, ok := cm.BinaryData[ref.Key]; ok {
+		return value, nil
+	}
+	if ref.Optional != nil && *ref.Optional {
+		return nil, nil
+	}
+	return nil, fmt.Errorf("key %q not found in configmap %s/%s", ref.Key, r.namespace, ref.Name)
+}
+
+func (r *Resolver) resolveSecretKey(ctx context.Context, namespace, name, key string, optional *bool) ([]byte, error) {
+	secret := &corev1.Secret{}
+	if err := r.reader.Get(ctx, client.ObjectKey{Name: name, Namespace: namespace}, secret); err != nil {
+		return nil, errors.Wrapf(err, "unable to get secret %s/%s", namespace, name)
+	}
+	if value, ok := secret.Data[key]; ok {
+		return value, nil
+	}
+	if optional != nil && *optional {
+		return nil, nil
+	}
+	return nil, fmt.Errorf("key %q not found in secret %s/%s", key, namespace, name)
+}
+
+func (r *Resolver) resolveVaultSecret(ctx context.Context, ref *strconf.VaultSecretSelector) ([]byte, error) {
+	if r.vault == nil {
+		return nil, errors.New("no vault is configured for the testmachinery")
+	}
+	cacheKey := ref.Mount + "/" + ref.Path
+	data, ok := r.vaultSecrets[cacheKey]
+	if !ok {
+		var err error
+		data, err = r.vault.read(ctx, ref.Mount, ref.Path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to read vault secret %s", cacheKey)
+		}
+		r.vaultSecrets[cacheKey] = data
+	}
+	value, ok := data[ref.Key]
+	if !ok {
+		return nil, fmt.Errorf("key %q not found in vault secret %s", ref.Key, cacheKey)
+	}
+	return vaultValue(value)
+}
+
+func (r *Resolver) resolveTemplate(ctx context.Context, source *strconf.TemplateSource) ([]byte, error) {
+	tmpl, err := template.New("config").Option("missingkey=error").Parse(source.Template)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to parse template")
+	}
+	values := make(map[string]string, len(source.Sources))
+	for name, src := range source.Sources {
+		if src.Template != nil {
+			return nil, fmt.Errorf("source %q: templates cannot be nested", name)
+		}
+		value, err := r.Resolve(ctx, &src)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to resolve source %q", name)
+		}
+		values[name] = string(value)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, values); err != nil {
+		return nil, errors.Wrap(err, "unable to render template")
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,40 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package configsource
+
+import (
+	"sort"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/gardener/test-infra/pkg/apis/config"
+	"github.com/gardener/test-infra/pkg/util/strconf"
+)
+
+// Validate validates that a config source of a testrun in the given namespace is allowed by the configured sources.
+func Validate(fldPath *field.Path, source *strconf.ConfigSource, namespace string, cfg *config.ConfigSources) field.ErrorList {
+	var allErrs field.ErrorList
+	if source.NamespacedSecretKeyRef != nil && source.NamespacedSecretKeyRef.Namespace != namespace {
+		if cfg == nil || !sets.New[string](cfg.AllowedSecretNamespaces...).Has(source.NamespacedSecretKeyRef.Namespace) {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("namespacedSecretKeyRef", "namespace"), "secrets of the namespace are not allowed to be referenced"))
+		}
+	}
+	if source.Vault != nil && (cfg == nil || cfg.Vault == nil) {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("vault"), "no vault is configured for the testmachinery"))
+	}
+	if source.Template != nil {
+		names := make([]string, 0, len(source.Template.Sources))
+		for name := range source.Template.Sources {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			src := source.Template.Sources[name]
+			allErrs = append(allErrs, Validate(fldPath.Child("template", "sources").Key(name), &src, namespace, cfg)...)
+		}
+	}
+	return allErrs
+}
@@ -0,0 +1,96 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package configsource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/gardener/test-infra/pkg/apis/config"
+)
+
+// vaultClient reads secrets of a Vault compatible KV secrets engine via its HTTP API.
+type vaultClient struct {
+	address    string
+	tokenPath  string
+	namespace  string
+	kvVersion  int
+	httpClient *http.Client
+}
+
+func newVaultClient(cfg *config.VaultConfigSource) *vaultClient {
+	return &vaultClient{
+		address:    strings.TrimSuffix(cfg.Address, "/"),
+		tokenPath:  cfg.TokenPath,
+		namespace:  cfg.Namespace,
+		kvVersion:  cfg.KVVersion,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// read returns the data of the secret at the given path of the secrets engine mounted at mount.
+func (c *vaultClient) read(ctx context.Context, mount, path string) (map[string]interface{}, error) {
+	// the token is read on every request as it might be rotated
+	token, err := os.ReadFile(c.tokenPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read vault token")
+	}
+
+	url := fmt.Sprintf("%s/v1/%s/%s", c.address, strings.Trim(mount, "/"), strings.Trim(path, "/"))
+	if c.kvVersion != 1 {
+		url = fmt.Sprintf("%s/v1/%s/data/%s", c.address, strings.Trim(mount, "/"), strings.Trim(path, "/"))
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", strings.TrimSpace(string(token)))
+	if len(c.namespace) != 0 {
+		req.Header.Set("X-Vault-Namespace", c.namespace)
+	}
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = res.Body.Close() }()
+	if res.StatusCode != http.StatusOK {
+		// the body is not returned as it might contain sensitive information
+		_, _ = io.Copy(io.Discard, res.Body)
+		return nil, fmt.Errorf("unexpected status code %d", res.StatusCode)
+	}
+
+	var secret struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&secret); err != nil {
+		return nil, errors.Wrap(err, "unable to decode response")
+	}
+	if c.kvVersion == 1 {
+		return secret.Data, nil
+	}
+	data, ok := secret.Data["data"].(map[string]interface{})
+	if !ok {
+		return nil, errors.New("response does not contain the data of a kv version 2 secret")
+	}
+	return data, nil
+}
+
+// vaultValue returns the value of a secret key.
+// Strings are returned as they are, all other values are encoded as json.
+func vaultValue(value interface{}) ([]byte, error) {
+	if s, ok := value.(string); ok {
+		return []byte(s), nil
+	}
+	return json.Marshal(value)
+}
@@ -0,0 +1,115 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/go-logr/logr"
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/gardener/test-infra/pkg/apis/config"
+	tmv1beta1 "github.com/gardener/test-infra/pkg/apis/testmachinery/v1beta1"
+	"github.com/gardener/test-infra/pkg/common"
+	"github.com/gardener/test-infra/pkg/testmachinery"
+)
+
+// TestRunCustomDefaulter applies the default profiles of the testmachinery configuration to new testruns.
+type TestRunCustomDefaulter struct {
+	Log logr.Logger
+}
+
+func (d *TestRunCustomDefaulter) Default(ctx context.Context, tr *tmv1beta1.Testrun) error {
+	defaulting := testmachinery.Defaulting()
+	if defaulting == nil {
+		return nil
+	}
+
+	var username string
+	if req, err := admission.RequestFromContext(ctx); err == nil {
+		// the spec of existing testruns must not change
+		if req.Operation != admissionv1.Create {
+			return nil
+		}
+		username = req.UserInfo.Username
+	}
+
+	applied := map[string]string{}
+	for _, profile := range defaulting.Profiles {
+		if len(profile.Namespaces) != 0 && !slices.Contains(profile.Namespaces, tr.Namespace) {
+			continue
+		}
+		for _, fld := range applyDefaultProfile(tr, profile, username) {
+			applied[fld] = profile.Name
+		}
+	}
+	if len(applied) == 0 {
+		return nil
+	}
+
+	raw, err := json.Marshal(applied)
+	if err != nil {
+		return fmt.Errorf("unable to record applied defaults: %w", err)
+	}
+	if tr.Annotations == nil {
+		tr.Annotations = map[string]string{}
+	}
+	tr.Annotations[common.AnnotationAppliedDefaults] = string(raw)
+	d.Log.V(5).Info("applied defaults", "testrun", tr.Name, "namespace", tr.Namespace, "defaults", string(raw))
+	return nil
+}
+
+// applyDefaultProfile sets all fields of the testrun that are defined by the profile and not yet set by the testrun.
+// Returns the paths of the defaulted fields.
+func applyDefaultProfile(tr *tmv1beta1.Testrun, profile config.DefaultProfile, username string) []string {
+	var (
+		applied  []string
+		specPath = field.NewPath("spec")
+	)
+
+	if tr.Spec.TTLSecondsAfterFinished == nil && profile.TTLSecondsAfterFinished != nil {
+		ttl := *profile.TTLSecondsAfterFinished
+		tr.Spec.TTLSecondsAfterFinished = &ttl
+		applied = append(applied, specPath.Child("ttlSecondsAfterFinished").String())
+	}
+
+	if len(tr.Spec.Creator) == 0 && profile.DefaultCreator && len(username) != 0 {
+		tr.Spec.Creator = username
+		applied = append(applied, specPath.Child("creator").String())
+	}
+
+	if len(tr.Spec.LocationSets) == 0 && len(tr.Spec.TestLocations) == 0 && len(profile.LocationSets) != 0 {
+		for _, set := range profile.LocationSets {
+			tr.Spec.LocationSets = append(tr.Spec.LocationSets, *set.DeepCopy())
+		}
+		applied = append(applied, specPath.Child("locationSets").String())
+	}
+
+	for _, cfg := range profile.Config {
+		if slices.ContainsFunc(tr.Spec.Config, func(e tmv1beta1.ConfigElement) bool { return e.Name == cfg.Name }) {
+			continue
+		}
+		tr.Spec.Config = append(tr.Spec.Config, *cfg.DeepCopy())
+		applied = append(applied, specPath.Child("config").Key(cfg.Name).String())
+	}
+
+	for key, value := range profile.Annotations {
+		if _, ok := tr.Annotations[key]; ok {
+			continue
+		}
+		if tr.Annotations == nil {
+			tr.Annotations = map[string]string{}
+		}
+		tr.Annotations[key] = value
+		applied = append(applied, field.NewPath("metadata", "annotations").Key(key).String())
+	}
+
+	return applied
+}
@@ -0,0 +1,143 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package webhooks_test
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/gardener/test-infra/pkg/apis/config"
+	tmv1beta1 "github.com/gardener/test-infra/pkg/apis/testmachinery/v1beta1"
+	"github.com/gardener/test-infra/pkg/common"
+	"github.com/gardener/test-infra/pkg/testmachinery"
+	"github.com/gardener/test-infra/pkg/testmachinery/controller/admission/webhooks"
+	"github.com/gardener/test-infra/test/resources"
+)
+
+var _ = Describe("Testrun defaulting tests", func() {
+
+	var (
+		ctx       context.Context
+		defaulter = webhooks.TestRunCustomDefaulter{Log: logr.Discard()}
+		profiles  []config.DefaultProfile
+	)
+
+	BeforeEach(func() {
+		ctx = admission.NewContextWithRequest(context.Background(), admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				UserInfo:  authenticationv1.UserInfo{Username: "jane"},
+			},
+		})
+		profiles = []config.DefaultProfile{
+			{
+				Name:                    "default",
+				TTLSecondsAfterFinished: ptr.To[int32](3600),
+				DefaultCreator:          true,
+				Annotations:             map[string]string{"team": "gardener"},
+				Config: []tmv1beta1.ConfigElement{
+					{Type: tmv1beta1.ConfigTypeEnv, Name: "LANDSCAPE", Value: "dev"},
+				},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		Expect(testmachinery.Setup(&config.Configuration{
+			Controller: config.Controller{Defaulting: &config.Defaulting{Profiles: profiles}},
+		})).To(Succeed())
+		DeferCleanup(func() {
+			Expect(testmachinery.Setup(&config.Configuration{})).To(Succeed())
+		})
+	})
+
+	It("should apply the defaults of a profile and record them", func() {
+		tr := resources.GetBasicTestrun(namespace, commitSha)
+		tr.Spec.Creator = ""
+
+		Expect(defaulter.Default(ctx, tr)).To(Succeed())
+		Expect(tr.Spec.TTLSecondsAfterFinished).To(Equal(ptr.To[int32](3600)))
+		Expect(tr.Spec.Creator).To(Equal("jane"))
+		Expect(tr.Spec.Config).To(ContainElement(HaveField("Name", "LANDSCAPE")))
+		Expect(tr.Annotations).To(HaveKeyWithValue("team", "gardener"))
+		Expect(tr.Annotations).To(HaveKeyWithValue(common.AnnotationAppliedDefaults, MatchJSON(`{
+			"spec.ttlSecondsAfterFinished": "default",
+			"spec.creator": "default",
+			"spec.config[LANDSCAPE]": "default",
+			"metadata.annotations[team]": "default"
+		}`)))
+	})
+
+	It("should not overwrite fields that are set by the testrun", func() {
+		tr := resources.GetBasicTestrun(namespace, commitSha)
+		tr.Spec.TTLSecondsAfterFinished = ptr.To[int32](60)
+		tr.Spec.Creator = "john"
+		tr.Spec.Config = []tmv1beta1.ConfigElement{{Type: tmv1beta1.ConfigTypeEnv, Name: "LANDSCAPE", Value: "prod"}}
+		tr.Annotations = map[string]string{"team": "other"}
+
+		Expect(defaulter.Default(ctx, tr)).To(Succeed())
+		Expect(tr.Spec.TTLSecondsAfterFinished).To(Equal(ptr.To[int32](60)))
+		Expect(tr.Spec.Creator).To(Equal("john"))
+		Expect(tr.Spec.Config).To(ConsistOf(HaveField("Value", "prod")))
+		Expect(tr.Annotations).To(Equal(map[string]string{"team": "other"}))
+	})
+
+	It("should only default location sets of testruns without locations", func() {
+		profiles[0].LocationSets = []tmv1beta1.LocationSet{{
+			Name:      "default",
+			Default:   true,
+			Locations: []tmv1beta1.TestLocation{{Type: tmv1beta1.LocationTypeGit, Repo: "https://github.com/gardener/gardener.git", Revision: "master"}},
+		}}
+		tr := resources.GetBasicTestrun(namespace, commitSha)
+		Expect(defaulter.Default(ctx, tr)).To(Succeed())
+		Expect(tr.Spec.LocationSets[0].Locations[0].Repo).To(Equal("https://github.com/gardener/test-infra.git"))
+
+		tr.Spec.LocationSets = nil
+		Expect(defaulter.Default(ctx, tr)).To(Succeed())
+		Expect(tr.Spec.LocationSets).To(Equal(profiles[0].LocationSets))
+	})
+
+	Context("multiple profiles", func() {
+		BeforeEach(func() {
+			profiles = append([]config.DefaultProfile{{
+				Name:                    "other-namespace",
+				Namespaces:              []string{"other"},
+				TTLSecondsAfterFinished: ptr.To[int32](60),
+			}, {
+				Name:                    "namespace",
+				Namespaces:              []string{namespace},
+				TTLSecondsAfterFinished: ptr.To[int32](600),
+			}}, profiles...)
+		})
+
+		It("should apply the first matching profile that defines a field", func() {
+			tr := resources.GetBasicTestrun(namespace, commitSha)
+			tr.Spec.Creator = ""
+
+			Expect(defaulter.Default(ctx, tr)).To(Succeed())
+			Expect(tr.Spec.TTLSecondsAfterFinished).To(Equal(ptr.To[int32](600)))
+			Expect(tr.Spec.Creator).To(Equal("jane"))
+			Expect(tr.Annotations[common.AnnotationAppliedDefaults]).To(ContainSubstring(`"spec.ttlSecondsAfterFinished":"namespace"`))
+		})
+	})
+
+	It("should not default testruns on update", func() {
+		ctx = admission.NewContextWithRequest(context.Background(), admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{Operation: admissionv1.Update},
+		})
+		tr := resources.GetBasicTestrun(namespace, commitSha)
+
+		Expect(defaulter.Default(ctx, tr)).To(Succeed())
+		Expect(tr.Spec.TTLSecondsAfterFinished).To(BeNil())
+		Expect(tr.Annotations).To(BeEmpty())
+	})
+})
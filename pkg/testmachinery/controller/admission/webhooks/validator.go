@@ -22,6 +22,9 @@ import (
 
 	tmv1beta1 "github.com/gardener/test-infra/pkg/apis/testmachinery/v1beta1"
 	"github.com/gardener/test-infra/pkg/apis/testmachinery/v1beta1/validation"
+	"github.com/gardener/test-infra/pkg/testmachinery"
+	"github.com/gardener/test-infra/pkg/testmachinery/policy"
+	"github.com/gardener/test-infra/pkg/testmachinery/testrun"
 	kutil "github.com/gardener/test-infra/pkg/util/kubernetes"
 )
 
@@ -62,6 +65,9 @@ func checkDeploymentHealth(ctx context.Context, reader client.Reader, namespace
 
 type TestRunCustomValidator struct {
 	Log logr.Logger
+	// Reader is used to read the kubeconfigs of testruns that are referenced from secrets and configmaps.
+	// The kubeconfigs are not checked if no reader is defined.
+	Reader client.Reader
 }
 
 func (v *TestRunCustomValidator) ValidateCreate(ctx context.Context, testrun *tmv1beta1.Testrun) (warnings admission.Warnings, err error) {
@@ -77,7 +83,37 @@ func (v *TestRunCustomValidator) ValidateCreate(ctx context.Context, testrun *tm
 		v.Log.V(5).Info(fmt.Sprintf("invalid testrun %s: %s", testrun.Name, err.Error()))
 		return nil, err
 	}
-	return nil, nil
+
+	result := policy.EvaluateTestrun(testmachinery.AdmissionPolicies(), testrun)
+	if len(result.Errors) != 0 {
+		v.Log.V(5).Info(fmt.Sprintf("testrun %s violates admission policies: %s", testrun.Name, result.Errors.ToAggregate().Error()))
+		return result.Warnings, errors.NewInvalid(
+			schema.GroupKind{
+				Group: tmv1beta1.SchemeGroupVersion.Group,
+				Kind:  testrun.GetObjectKind().GroupVersionKind().Kind},
+			testrun.Name,
+			result.Errors,
+		)
+	}
+	return append(result.Warnings, v.preflightKubeconfigs(ctx, testrun)...), nil
+}
+
+// preflightKubeconfigs checks the kubeconfigs of the testrun and returns the found problems as warnings.
+// Testruns are not rejected as the controller fails them with the same errors.
+// The clusters are not probed to keep the admission fast.
+func (v *TestRunCustomValidator) preflightKubeconfigs(ctx context.Context, tr *tmv1beta1.Testrun) admission.Warnings {
+	preflight := testmachinery.KubeconfigPreflight()
+	if preflight == nil || v.Reader == nil {
+		return nil
+	}
+	preflight = preflight.DeepCopy()
+	preflight.ProbeServer = false
+
+	allErrs, warnings, _ := testrun.PreflightKubeconfigs(ctx, v.Reader, tr, preflight)
+	for _, err := range allErrs {
+		warnings = append(warnings, fmt.Sprintf("kubeconfig preflight failed: %s", err.Error()))
+	}
+	return warnings
 }
 
 func (v *TestRunCustomValidator) ValidateUpdate(ctx context.Context, oldTestrun, newTestrun *tmv1beta1.Testrun) (warnings admission.Warnings, err error) {
@@ -9,9 +9,13 @@ import (
 	"github.com/go-logr/logr"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
+	"github.com/gardener/test-infra/pkg/apis/config"
 	tmv1beta1 "github.com/gardener/test-infra/pkg/apis/testmachinery/v1beta1"
+	"github.com/gardener/test-infra/pkg/testmachinery"
 	"github.com/gardener/test-infra/pkg/testmachinery/controller/admission/webhooks"
 	"github.com/gardener/test-infra/pkg/util/strconf"
 	"github.com/gardener/test-infra/test/resources"
@@ -100,6 +104,65 @@ var _ = Describe("Testrun validation tests", func() {
 
 			Expect(err.Error()).To(ContainSubstring("Cannot build config"))
 		})
+
+		It("should warn when a kubeconfig fails the preflight", func() {
+			Expect(testmachinery.Setup(&config.Configuration{
+				TestMachinery: config.TestMachinery{KubeconfigPreflight: &config.KubeconfigPreflight{}},
+			})).To(Succeed())
+			DeferCleanup(func() {
+				Expect(testmachinery.Setup(&config.Configuration{})).To(Succeed())
+			})
+			validator := webhooks.TestRunCustomValidator{Log: logr.Discard(), Reader: fake.NewClientBuilder().Build()}
+			tr := resources.GetBasicTestrun(namespace, commitSha)
+			tr.Spec.Kubeconfigs.Shoot = strconf.FromConfig(strconf.ConfigSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "shoot-kubeconfig"},
+					Key:                  "kubeconfig",
+				},
+			})
+
+			warnings, err := validator.ValidateCreate(context.TODO(), tr)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(warnings).To(ConsistOf(ContainSubstring("kubeconfig preflight failed: spec.kubeconfigs.shoot.secretKeyRef.name: Not found")))
+		})
+	})
+
+	Context("AdmissionPolicies", func() {
+		BeforeEach(func() {
+			Expect(testmachinery.Setup(&config.Configuration{
+				Controller: config.Controller{AdmissionPolicies: &config.AdmissionPolicies{
+					Annotations: &config.AnnotationPolicy{
+						Enforcement: config.PolicyEnforcementDeny,
+						Required:    []string{"owner"},
+					},
+					Locations: &config.LocationPolicy{
+						Enforcement:    config.PolicyEnforcementWarn,
+						AllowedDomains: []string{"example.com"},
+					},
+				}},
+			})).To(Succeed())
+			DeferCleanup(func() {
+				Expect(testmachinery.Setup(&config.Configuration{})).To(Succeed())
+			})
+		})
+
+		It("should reject a testrun that violates a denying policy", func() {
+			tr := resources.GetBasicTestrun(namespace, commitSha)
+
+			warnings, err := testRunValidator.ValidateCreate(context.TODO(), tr)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("metadata.annotations[owner]: Required value"))
+			Expect(warnings).To(ConsistOf(ContainSubstring("spec.locationSets[0].locations[0].repo")))
+		})
+
+		It("should admit a testrun that only violates warning policies", func() {
+			tr := resources.GetBasicTestrun(namespace, commitSha)
+			tr.Annotations = map[string]string{"owner": "me"}
+
+			warnings, err := testRunValidator.ValidateCreate(context.TODO(), tr)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(warnings).To(ConsistOf(ContainSubstring("repository has to be hosted on one of example.com")))
+		})
 	})
 
 	Context("OnExit", func() {
@@ -83,17 +83,16 @@ func (r *TestmachineryReconciler) Reconcile(ctx context.Context, request reconci
 		}
 
 		if err, retry := testrun.Validate(log, rCtx.tr); err != nil {
-			if !retry || RetryTimeoutExceeded(rCtx.tr) {
-				rCtx.tr.Status.Phase = tmv1beta1.RunPhaseError
-				t := metav1.Now()
-				rCtx.tr.Status.CompletionTime = &t
+			return r.failValidation(ctx, rCtx, log, fmt.Sprintf("validation failed: %s", err.Error()), err, retry)
+		}
+		if preflight := testmachinery.KubeconfigPreflight(); preflight != nil {
+			allErrs, warnings, retry := testrun.PreflightKubeconfigs(ctx, r.Client, rCtx.tr, preflight)
+			for _, warning := range warnings {
+				log.Info("kubeconfig preflight", "warning", warning)
 			}
-			rCtx.tr.Status.State = fmt.Sprintf("validation failed: %s", err.Error())
-			if err := r.Status().Update(ctx, rCtx.tr); err != nil {
-				log.Error(err, "unable to update testrun status")
-				return reconcile.Result{}, err
+			if err := allErrs.ToAggregate(); err != nil {
+				return r.failValidation(ctx, rCtx, log, fmt.Sprintf("kubeconfig preflight failed: %s", err.Error()), err, retry)
 			}
-			return reconcile.Result{}, err
 		}
 
 		if res, err := r.createWorkflow(ctx, rCtx, log); err != nil {
@@ -116,6 +115,22 @@ func (r *TestmachineryReconciler) Reconcile(ctx context.Context, request reconci
 	return r.updateStatus(ctx, rCtx)
 }
 
+// failValidation sets the state of a testrun that could not be validated.
+// The testrun is failed if the validation cannot be retried.
+func (r *TestmachineryReconciler) failValidation(ctx context.Context, rCtx *reconcileContext, log logr.Logger, state string, err error, retry bool) (reconcile.Result, error) {
+	if !retry || RetryTimeoutExceeded(rCtx.tr) {
+		rCtx.tr.Status.Phase = tmv1beta1.RunPhaseError
+		t := metav1.Now()
+		rCtx.tr.Status.CompletionTime = &t
+	}
+	rCtx.tr.Status.State = state
+	if err := r.Status().Update(ctx, rCtx.tr); err != nil {
+		log.Error(err, "unable to update testrun status")
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{}, err
+}
+
 func (r *TestmachineryReconciler) createWorkflow(ctx context.Context, rCtx *reconcileContext, log logr.Logger) (reconcile.Result, error) {
 	log.V(5).Info("generate workflow")
 	var (
@@ -195,7 +210,7 @@ func (r *TestmachineryReconciler) generateWorkflow(ctx context.Context, testrunD
 		wf.Finalizers = wfFinalizers.UnsortedList()
 	}
 
-	testrunDef.Status.Steps = tr.Testflow.Flow.GetStatuses()
+	testrunDef.Status.Steps = tr.GetStepStatuses()
 
 	return wf, tr.HelperResources, nil
 }
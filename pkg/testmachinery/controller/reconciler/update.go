@@ -143,7 +143,8 @@ func (r *TestmachineryReconciler) updateStepsStatus(rCtx *reconcileContext) {
 		}
 
 		step.Phase = argoNodeStatus.Phase
-		step.ExportArtifactKey = getNodeExportKey(argoNodeStatus.Outputs)
+		step.ExportArtifactKey = getNodeArtifactKey(argoNodeStatus.Outputs, testmachinery.ExportArtifact)
+		step.TelemetryArtifactKey = getNodeArtifactKey(argoNodeStatus.Outputs, testmachinery.TelemetryArtifact)
 		step.PodName = argoNodeStatus.ID
 
 		if !argoNodeStatus.StartedAt.IsZero() {
@@ -182,12 +183,12 @@ func getNodeStatusByName(wf *argov1.Workflow, templateName string) *argov1.NodeS
 	return nil
 }
 
-func getNodeExportKey(outputs *argov1.Outputs) string {
+func getNodeArtifactKey(outputs *argov1.Outputs, name string) string {
 	if outputs == nil {
 		return ""
 	}
 	for _, artifact := range outputs.Artifacts {
-		if artifact.Name == testmachinery.ExportArtifact && artifact.S3 != nil {
+		if artifact.Name == name && artifact.S3 != nil {
 			return artifact.S3.Key
 		}
 	}
@@ -43,6 +43,9 @@ func RegisterTestMachineryController(mgr manager.Manager, log logr.Logger, confi
 		if err != nil {
 			return fmt.Errorf("unable to setup collector: %w", err)
 		}
+		if err := mgr.Add(collect); err != nil {
+			return fmt.Errorf("unable to add collector to manager: %w", err)
+		}
 	}
 
 	if !config.TestMachinery.Local {
@@ -68,6 +71,15 @@ func RegisterTestMachineryController(mgr manager.Manager, log logr.Logger, confi
 	}
 
 	ghcache.InitGitHubCache(config.GitHub.Cache)
+	if config.GitHub.Cache != nil && config.GitHub.Cache.Server != nil {
+		server, err := ghcache.NewServer(log.WithName("ghcache"), config.GitHub.Cache.Server)
+		if err != nil {
+			return fmt.Errorf("unable to setup github cache server: %w", err)
+		}
+		if err := mgr.Add(server); err != nil {
+			return fmt.Errorf("unable to add github cache server to manager: %w", err)
+		}
+	}
 
 	if !config.Controller.TTLController.Disable {
 		return ttl.AddControllerToManager(log, mgr, config.Controller.TTLController)
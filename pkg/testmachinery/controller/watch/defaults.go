@@ -31,5 +31,9 @@ func applyDefaultOptions(opts *Options) *Options {
 		d := 1 * time.Minute
 		opts.PollInterval = &d
 	}
+	if opts.ReconnectInterval == nil {
+		d := 5 * time.Second
+		opts.ReconnectInterval = &d
+	}
 	return opts
 }
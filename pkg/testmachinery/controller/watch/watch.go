@@ -11,6 +11,7 @@ import (
 	"github.com/go-logr/logr"
 	"github.com/hashicorp/go-multierror"
 	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -74,6 +75,14 @@ type Options struct {
 	// Namespace restrict the namespace to watch.
 	// Leave this value empty to watch all namespaces.
 	Namespace string
+
+	// LabelSelector restricts the testruns that are watched.
+	// Only relevant if the watch informer is used.
+	LabelSelector labels.Selector
+
+	// ReconnectInterval is the time to wait before the watch is reestablished after it was closed.
+	// Only relevant if the watch informer is used.
+	ReconnectInterval *time.Duration
 }
 
 type watch struct {
@@ -124,6 +133,12 @@ func New(log logr.Logger, config *rest.Config, options *Options) (Watch, error)
 			return nil, err
 		}
 		inf = pInf
+	case WatchInformerType:
+		wInf, err := newWatchInformer(log, config, options)
+		if err != nil {
+			return nil, err
+		}
+		inf = wInf
 	default:
 		return nil, errors.Errorf("unknown infromer type %s", options.InformerType)
 	}
@@ -0,0 +1,244 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package watch
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	watchapi "k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	tmv1beta1 "github.com/gardener/test-infra/pkg/apis/testmachinery/v1beta1"
+)
+
+// WatchInformerType specifies the informer type that uses a server-side watch.
+const WatchInformerType InformerType = "watch"
+
+// Statistics describes the connection statistics of an informer.
+type Statistics struct {
+	// Events is the number of received testrun events.
+	Events int `json:"events"`
+	// Reconnects is the number of times the watch was reestablished after it was closed or failed.
+	Reconnects int `json:"reconnects"`
+	// Relists is the number of times the testruns had to be listed because the last resource version was too old.
+	Relists int `json:"relists"`
+	// LastReconnect is the time of the last reconnect.
+	LastReconnect *time.Time `json:"lastReconnect,omitempty"`
+}
+
+// StatisticsReporter is implemented by informers that report connection statistics.
+type StatisticsReporter interface {
+	Statistics() Statistics
+}
+
+// InformerStatistics returns the connection statistics of the informer of a watch.
+// False is returned if the informer does not report statistics.
+func InformerStatistics(w Watch) (Statistics, bool) {
+	wa, ok := w.(*watch)
+	if !ok {
+		return Statistics{}, false
+	}
+	reporter, ok := wa.informer.(StatisticsReporter)
+	if !ok {
+		return Statistics{}, false
+	}
+	return reporter.Statistics(), true
+}
+
+// watchInformer watches testruns with a server-side watch that is restricted by a namespace and a label selector.
+// After a disconnect the watch is resumed from the last seen resource version.
+// If the resource version is too old (410 Gone), all testruns are listed again.
+type watchInformer struct {
+	log               logr.Logger
+	client            client.WithWatch
+	eventbus          EventBus
+	namespace         string
+	selector          labels.Selector
+	reconnectInterval time.Duration
+
+	resourceVersion string
+	synced          chan struct{}
+	syncOnce        sync.Once
+
+	mux   sync.RWMutex
+	stats Statistics
+}
+
+func newWatchInformer(log logr.Logger, config *rest.Config, options *Options) (Informer, error) {
+	c, err := client.NewWithWatch(config, client.Options{
+		Scheme: options.Scheme,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &watchInformer{
+		log:               log,
+		client:            c,
+		namespace:         options.Namespace,
+		selector:          options.LabelSelector,
+		reconnectInterval: *options.ReconnectInterval,
+		synced:            make(chan struct{}),
+	}, nil
+}
+
+// Start lists all testruns and watches them until the context is canceled.
+func (w *watchInformer) Start(ctx context.Context) error {
+	for {
+		if w.resourceVersion == "" {
+			if err := w.list(ctx); err != nil {
+				w.log.Error(err, "unable to list testruns")
+				if !w.waitForReconnect(ctx) {
+					return nil
+				}
+				continue
+			}
+		}
+
+		if err := w.watch(ctx); err != nil {
+			if isResourceVersionTooOld(err) {
+				w.log.V(3).Info("resource version is too old, listing testruns", "resourceVersion", w.resourceVersion)
+				w.resourceVersion = ""
+				w.mux.Lock()
+				w.stats.Relists++
+				w.mux.Unlock()
+			} else {
+				w.log.Error(err, "watch failed", "resourceVersion", w.resourceVersion)
+			}
+		}
+
+		if !w.waitForReconnect(ctx) {
+			return nil
+		}
+		w.recordReconnect()
+	}
+}
+
+// list lists all testruns, publishes them and remembers the resource version of the list.
+func (w *watchInformer) list(ctx context.Context) error {
+	list := &tmv1beta1.TestrunList{}
+	if err := w.client.List(ctx, list, w.listOptions()...); err != nil {
+		return err
+	}
+	for i := range list.Items {
+		w.publish(&list.Items[i])
+	}
+	w.resourceVersion = list.ResourceVersion
+	w.syncOnce.Do(func() { close(w.synced) })
+	return nil
+}
+
+// watch watches the testruns starting from the last seen resource version until the watch is closed or fails.
+func (w *watchInformer) watch(ctx context.Context) error {
+	opts := append(w.listOptions(), &client.ListOptions{Raw: &metav1.ListOptions{
+		ResourceVersion:     w.resourceVersion,
+		AllowWatchBookmarks: true,
+	}})
+	watcher, err := w.client.Watch(ctx, &tmv1beta1.TestrunList{}, opts...)
+	if err != nil {
+		return err
+	}
+	defer watcher.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				w.log.V(3).Info("watch closed", "resourceVersion", w.resourceVersion)
+				return nil
+			}
+			switch event.Type {
+			case watchapi.Error:
+				return apierrors.FromObject(event.Object)
+			case watchapi.Bookmark:
+				if accessor, err := meta.Accessor(event.Object); err == nil {
+					w.resourceVersion = accessor.GetResourceVersion()
+				}
+			case watchapi.Added, watchapi.Modified, watchapi.Deleted:
+				tr, ok := event.Object.(*tmv1beta1.Testrun)
+				if !ok {
+					continue
+				}
+				w.resourceVersion = tr.ResourceVersion
+				w.publish(tr)
+			}
+		}
+	}
+}
+
+func (w *watchInformer) listOptions() []client.ListOption {
+	opts := []client.ListOption{client.InNamespace(w.namespace)}
+	if w.selector != nil {
+		opts = append(opts, client.MatchingLabelsSelector{Selector: w.selector})
+	}
+	return opts
+}
+
+func (w *watchInformer) publish(tr *tmv1beta1.Testrun) {
+	w.mux.Lock()
+	w.stats.Events++
+	w.mux.Unlock()
+	w.eventbus.Publish(keyOfTestrun(tr), tr)
+}
+
+func (w *watchInformer) recordReconnect() {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	now := time.Now()
+	w.stats.Reconnects++
+	w.stats.LastReconnect = &now
+	w.log.V(3).Info("reconnecting watch", "reconnects", w.stats.Reconnects, "relists", w.stats.Relists)
+}
+
+// waitForReconnect waits for the reconnect interval and returns false if the context was canceled in the meantime.
+func (w *watchInformer) waitForReconnect(ctx context.Context) bool {
+	timer := time.NewTimer(w.reconnectInterval)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// WaitForCacheSync waits until the testruns have been listed initially.
+func (w *watchInformer) WaitForCacheSync(ctx context.Context) bool {
+	select {
+	case <-w.synced:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (w *watchInformer) InjectEventBus(eb EventBus) {
+	w.eventbus = eb
+}
+
+func (w *watchInformer) Client() client.Client {
+	return w.client
+}
+
+// Statistics returns the connection statistics of the watch.
+func (w *watchInformer) Statistics() Statistics {
+	w.mux.RLock()
+	defer w.mux.RUnlock()
+	return w.stats
+}
+
+// isResourceVersionTooOld checks whether the error indicates that the requested resource version is no longer available.
+func isResourceVersionTooOld(err error) bool {
+	return apierrors.IsResourceExpired(err) || apierrors.IsGone(err)
+}
@@ -0,0 +1,19 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package watchinformer_test tests the watch informer against a fake api server.
+// The specs are separated from the watch suite as they do not need a test environment.
+package watchinformer_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestWatchInformer(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Testmachinery Watch Informer Suite")
+}
@@ -0,0 +1,308 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package watchinformer_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	watchapi "k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/rest"
+
+	"github.com/gardener/test-infra/pkg/apis/testmachinery"
+	tmv1beta1 "github.com/gardener/test-infra/pkg/apis/testmachinery/v1beta1"
+	"github.com/gardener/test-infra/pkg/testmachinery/controller/watch"
+)
+
+var _ = Describe("Watch Informer", func() {
+
+	var (
+		server *fakeAPIServer
+		ctx    context.Context
+		cancel context.CancelFunc
+		wg     sync.WaitGroup
+		w      watch.Watch
+	)
+
+	BeforeEach(func() {
+		ctx, cancel = context.WithCancel(context.Background())
+		server = newFakeAPIServer()
+
+		reconnectInterval := 10 * time.Millisecond
+		var err error
+		w, err = watch.New(logr.Discard(), &rest.Config{Host: server.URL}, &watch.Options{
+			InformerType:      watch.WatchInformerType,
+			Namespace:         "test",
+			LabelSelector:     labels.SelectorFromSet(labels.Set{"session": "a"}),
+			ReconnectInterval: &reconnectInterval,
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		wg = sync.WaitGroup{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer GinkgoRecover()
+			Expect(w.Start(ctx)).To(Succeed())
+		}()
+		Expect(watch.WaitForCacheSyncWithTimeout(w, 10*time.Second)).To(Succeed())
+		Eventually(server.watching).Should(BeTrue())
+	})
+
+	AfterEach(func() {
+		cancel()
+		server.closeWatch()
+		wg.Wait()
+		server.Close()
+	})
+
+	waitForAnnotation := func(value string) {
+		err := w.WatchUntil(10*time.Second, "test", "test", func(tr *tmv1beta1.Testrun) (bool, error) {
+			return tr.Annotations["rec"] == value, nil
+		})
+		Expect(err).ToNot(HaveOccurred())
+	}
+
+	It("should list and watch only the testruns of the label selector", func() {
+		Expect(server.requests()).To(ConsistOf(
+			"list session=a",
+			"watch session=a 10",
+		))
+
+		go func() {
+			time.Sleep(100 * time.Millisecond)
+			server.update("1")
+		}()
+		waitForAnnotation("1")
+	})
+
+	It("should resume from the last resource version after a disconnect", func() {
+		go func() {
+			time.Sleep(100 * time.Millisecond)
+			server.update("1")
+		}()
+		waitForAnnotation("1")
+
+		server.closeWatch()
+		Eventually(server.requests).Should(ContainElement("watch session=a 11"))
+		Eventually(server.watching).Should(BeTrue())
+
+		go func() {
+			time.Sleep(100 * time.Millisecond)
+			server.update("2")
+		}()
+		waitForAnnotation("2")
+
+		stats, ok := watch.InformerStatistics(w)
+		Expect(ok).To(BeTrue())
+		Expect(stats.Reconnects).To(Equal(1))
+		Expect(stats.Relists).To(Equal(0))
+		Expect(stats.LastReconnect).ToNot(BeNil())
+	})
+
+	It("should list all testruns if the resource version is too old", func() {
+		server.expire()
+		Eventually(server.requests).Should(Equal([]string{
+			"list session=a",
+			"watch session=a 10",
+			"watch session=a 10",
+			"list session=a",
+			"watch session=a 10",
+		}))
+		Eventually(server.watching).Should(BeTrue())
+
+		go func() {
+			time.Sleep(100 * time.Millisecond)
+			server.update("1")
+		}()
+		waitForAnnotation("1")
+
+		stats, ok := watch.InformerStatistics(w)
+		Expect(ok).To(BeTrue())
+		Expect(stats.Relists).To(Equal(1))
+	})
+})
+
+// fakeAPIServer is a minimal kubernetes api server that serves testruns of the namespace "test".
+type fakeAPIServer struct {
+	*httptest.Server
+
+	mux      sync.Mutex
+	testrun  tmv1beta1.Testrun
+	reqs     []string
+	events   chan watchapi.Event
+	expired  bool
+	isActive bool
+}
+
+func newFakeAPIServer() *fakeAPIServer {
+	s := &fakeAPIServer{
+		testrun: tmv1beta1.Testrun{
+			TypeMeta: metav1.TypeMeta{Kind: "Testrun", APIVersion: tmv1beta1.SchemeGroupVersion.String()},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "test",
+				Namespace:       "test",
+				ResourceVersion: "10",
+				Labels:          map[string]string{"session": "a"},
+				Annotations:     map[string]string{},
+			},
+		},
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api", func(rw http.ResponseWriter, _ *http.Request) {
+		writeJSON(rw, http.StatusOK, &metav1.APIVersions{
+			TypeMeta: metav1.TypeMeta{Kind: "APIVersions"},
+			Versions: []string{"v1"},
+		})
+	})
+	mux.HandleFunc("/apis", func(rw http.ResponseWriter, _ *http.Request) {
+		gv := metav1.GroupVersionForDiscovery{GroupVersion: tmv1beta1.SchemeGroupVersion.String(), Version: "v1beta1"}
+		writeJSON(rw, http.StatusOK, &metav1.APIGroupList{
+			TypeMeta: metav1.TypeMeta{Kind: "APIGroupList", APIVersion: "v1"},
+			Groups: []metav1.APIGroup{{
+				Name:             testmachinery.GroupName,
+				Versions:         []metav1.GroupVersionForDiscovery{gv},
+				PreferredVersion: gv,
+			}},
+		})
+	})
+	mux.HandleFunc("/apis/"+tmv1beta1.SchemeGroupVersion.String(), func(rw http.ResponseWriter, _ *http.Request) {
+		writeJSON(rw, http.StatusOK, &metav1.APIResourceList{
+			TypeMeta:     metav1.TypeMeta{Kind: "APIResourceList", APIVersion: "v1"},
+			GroupVersion: tmv1beta1.SchemeGroupVersion.String(),
+			APIResources: []metav1.APIResource{{
+				Name:       "testruns",
+				Kind:       "Testrun",
+				Namespaced: true,
+				Verbs:      metav1.Verbs{"get", "list", "watch"},
+			}},
+		})
+	})
+	mux.HandleFunc("/apis/"+tmv1beta1.SchemeGroupVersion.String()+"/namespaces/test/testruns", func(rw http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("watch") == "true" {
+			s.serveWatch(rw, r)
+			return
+		}
+		s.serveList(rw, r)
+	})
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+func (s *fakeAPIServer) serveList(rw http.ResponseWriter, r *http.Request) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.reqs = append(s.reqs, "list "+r.URL.Query().Get("labelSelector"))
+	s.expired = false
+	writeJSON(rw, http.StatusOK, &tmv1beta1.TestrunList{
+		TypeMeta: metav1.TypeMeta{Kind: "TestrunList", APIVersion: tmv1beta1.SchemeGroupVersion.String()},
+		ListMeta: metav1.ListMeta{ResourceVersion: s.testrun.ResourceVersion},
+		Items:    []tmv1beta1.Testrun{*s.testrun.DeepCopy()},
+	})
+}
+
+func (s *fakeAPIServer) serveWatch(rw http.ResponseWriter, r *http.Request) {
+	s.mux.Lock()
+	s.reqs = append(s.reqs, "watch "+r.URL.Query().Get("labelSelector")+" "+r.URL.Query().Get("resourceVersion"))
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
+	rw.(http.Flusher).Flush()
+	if s.expired {
+		s.mux.Unlock()
+		writeEvent(rw, watchapi.Event{Type: watchapi.Error, Object: &metav1.Status{
+			TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"},
+			Status:   metav1.StatusFailure,
+			Reason:   metav1.StatusReasonExpired,
+			Code:     http.StatusGone,
+			Message:  "too old resource version",
+		}})
+		return
+	}
+	events := make(chan watchapi.Event)
+	s.events = events
+	s.isActive = true
+	s.mux.Unlock()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			writeEvent(rw, event)
+		}
+	}
+}
+
+// update sets the "rec" annotation of the testrun and sends a modified event to the current watch.
+func (s *fakeAPIServer) update(value string) {
+	s.mux.Lock()
+	rv, _ := strconv.Atoi(s.testrun.ResourceVersion)
+	s.testrun.ResourceVersion = strconv.Itoa(rv + 1)
+	s.testrun.Annotations["rec"] = value
+	tr := s.testrun.DeepCopy()
+	events := s.events
+	s.mux.Unlock()
+	events <- watchapi.Event{Type: watchapi.Modified, Object: tr}
+}
+
+// closeWatch closes the current watch connection.
+func (s *fakeAPIServer) closeWatch() {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if s.isActive {
+		close(s.events)
+		s.isActive = false
+	}
+}
+
+// expire closes the current watch and lets the next watch fail with 410 Gone until the testruns are listed again.
+func (s *fakeAPIServer) expire() {
+	s.mux.Lock()
+	s.expired = true
+	s.mux.Unlock()
+	s.closeWatch()
+}
+
+func (s *fakeAPIServer) watching() bool {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return s.isActive
+}
+
+func (s *fakeAPIServer) requests() []string {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return append([]string{}, s.reqs...)
+}
+
+func writeJSON(rw http.ResponseWriter, code int, obj interface{}) {
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(code)
+	Expect(json.NewEncoder(rw).Encode(obj)).To(Succeed())
+}
+
+func writeEvent(rw http.ResponseWriter, event watchapi.Event) {
+	raw, err := json.Marshal(event.Object)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(json.NewEncoder(rw).Encode(&metav1.WatchEvent{
+		Type:   string(event.Type),
+		Object: runtime.RawExtension{Raw: raw},
+	})).To(Succeed())
+	rw.(http.Flusher).Flush()
+}
@@ -0,0 +1,130 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ghcache
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gregjones/httpcache"
+)
+
+// budgets accounts the rate limit of all installations of this process.
+var budgets = &rateLimitBudgets{}
+
+// rateLimitBudgets keeps track of the rate limit of installations as reported by the github api.
+type rateLimitBudgets struct {
+	mux    sync.Mutex
+	limits map[string]rateLimit
+}
+
+// rateLimit is the rate limit of one resource of an installation.
+type rateLimit struct {
+	limit     int
+	remaining int
+	reset     time.Time
+}
+
+// update updates the rate limit of an installation with the rate limit headers of a github api response.
+func (b *rateLimitBudgets) update(installation string, res *http.Response) {
+	if res == nil {
+		return
+	}
+	limit, err := strconv.Atoi(res.Header.Get("X-RateLimit-Limit"))
+	if err != nil {
+		return
+	}
+	remaining, err := strconv.Atoi(res.Header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+	reset, err := strconv.ParseInt(res.Header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return
+	}
+	resource := res.Header.Get("X-RateLimit-Resource")
+	if resource == "" && res.Request != nil {
+		resource = rateLimitResource(res.Request.URL.Path)
+	}
+	if resource == "" {
+		resource = "core"
+	}
+
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	if b.limits == nil {
+		b.limits = make(map[string]rateLimit)
+	}
+	b.limits[installation+"/"+resource] = rateLimit{
+		limit:     limit,
+		remaining: remaining,
+		reset:     time.Unix(reset, 0),
+	}
+	rateLimitLimit.WithLabelValues(installation, resource).Set(float64(limit))
+	rateLimitRemaining.WithLabelValues(installation, resource).Set(float64(remaining))
+}
+
+// exhausted returns whether the remaining rate limit of an installation has dropped below the reserve.
+func (b *rateLimitBudgets) exhausted(installation, resource string, reserve int) bool {
+	if reserve <= 0 {
+		return false
+	}
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	limit, ok := b.limits[installation+"/"+resource]
+	if !ok || time.Now().After(limit.reset) {
+		return false
+	}
+	return limit.remaining < reserve
+}
+
+// rateLimitResource returns the github rate limit resource a request path counts against.
+func rateLimitResource(path string) string {
+	switch {
+	case strings.HasPrefix(path, "/search/") || strings.Contains(path, "/api/v3/search/"):
+		return "search"
+	case strings.HasSuffix(path, "/graphql"):
+		return "graphql"
+	default:
+		return "core"
+	}
+}
+
+type requestResultKey struct{}
+
+// requestResult records whether a request was sent to the github api.
+type requestResult struct {
+	upstream    bool
+	revalidated bool
+}
+
+func withRequestResult(ctx context.Context, result *requestResult) context.Context {
+	return context.WithValue(ctx, requestResultKey{}, result)
+}
+
+func requestResultFrom(ctx context.Context) *requestResult {
+	result, _ := ctx.Value(requestResultKey{}).(*requestResult)
+	return result
+}
+
+// label returns the cache result of a request.
+func (r *requestResult) label(res *http.Response) string {
+	switch {
+	case r.revalidated:
+		return "revalidated"
+	case r.upstream:
+		return "miss"
+	case res.Header.Get("Warning") != "":
+		return "stale"
+	case res.Header.Get(httpcache.XFromCache) != "":
+		return "hit"
+	default:
+		return "miss"
+	}
+}
@@ -7,23 +7,34 @@ package ghcache
 import (
 	"fmt"
 	"net/http"
+
+	"github.com/gregjones/httpcache"
 )
 
 // cache extends the default http caching by adding caching behavior to errornous responses like 404.
 // Note that this will reduce the correctness of all responses.
+// It is called for every request that is sent to the github api and
+// therefore also accounts the rate limit budget of the installation.
 type cache struct {
 	delegate      http.RoundTripper
 	maxAgeSeconds int
+	installation  string
 }
 
 func (c *cache) RoundTrip(req *http.Request) (*http.Response, error) {
-	if c.maxAgeSeconds <= 0 {
-		return c.delegate.RoundTrip(req)
-	}
 	res, err := c.delegate.RoundTrip(req)
+	upstreamRequests.WithLabelValues(c.installation).Inc()
 	if err != nil {
 		return res, err
 	}
+	if result := requestResultFrom(req.Context()); result != nil {
+		result.upstream = true
+		result.revalidated = res.StatusCode == http.StatusNotModified
+	}
+	budgets.update(c.installation, res)
+	if c.maxAgeSeconds <= 0 {
+		return res, err
+	}
 	if req.Method == http.MethodGet {
 		if res.StatusCode == http.StatusNotFound {
 			res.Header.Set("Cache-Control", fmt.Sprintf("max-age=%d", c.maxAgeSeconds))
@@ -31,3 +42,23 @@ func (c *cache) RoundTrip(req *http.Request) (*http.Response, error) {
 	}
 	return res, err
 }
+
+// namespacedCache prefixes all keys of a cache with a namespace.
+type namespacedCache struct {
+	namespace string
+	delegate  httpcache.Cache
+}
+
+var _ httpcache.Cache = &namespacedCache{}
+
+func (c *namespacedCache) Get(key string) ([]byte, bool) {
+	return c.delegate.Get(c.namespace + key)
+}
+
+func (c *namespacedCache) Set(key string, data []byte) {
+	c.delegate.Set(c.namespace+key, data)
+}
+
+func (c *namespacedCache) Delete(key string) {
+	c.delegate.Delete(c.namespace + key)
+}
@@ -18,36 +18,47 @@ import (
 	flag "github.com/spf13/pflag"
 
 	"github.com/gardener/test-infra/pkg/apis/config"
+	"github.com/gardener/test-infra/pkg/logger"
 )
 
 var (
-	ghCache       httpcache.Cache
-	maxAgeSeconds int
-	initOnce      sync.Once
+	ghCache          httpcache.Cache
+	maxAgeSeconds    int
+	rateLimitReserve int
+	initOnce         sync.Once
 )
 
 // WithRateLimitControlCache adds the central GitHub cache to a http client.
+// Cached responses are stored per installation, i.e. per set of credentials that is used by the client,
+// so that responses are never shared between installations.
+// The installation must not contain any credentials as it is part of the cache keys and metrics.
 // Call InitGitHubCache in advance for bootstrapping the cache
-func WithRateLimitControlCache(log logr.Logger, delegate http.RoundTripper) (http.RoundTripper, error) {
+func WithRateLimitControlCache(log logr.Logger, installation string, delegate http.RoundTripper) (http.RoundTripper, error) {
 	if ghCache == nil {
 		return nil, errors.New("cache has not been initialized yet")
 	}
-
-	cachedTransport := httpcache.NewTransport(ghCache)
+	installationCache := &namespacedCache{
+		namespace: installation + "/",
+		delegate:  ghCache,
+	}
+	cachedTransport := httpcache.NewTransport(installationCache)
 	cachedTransport.Transport = &cache{
 		delegate:      delegate,
 		maxAgeSeconds: maxAgeSeconds,
+		installation:  installation,
 	}
-
 	return &rateLimitControl{
-		log:      log,
-		delegate: cachedTransport,
-		cache:    ghCache,
+		log:          log,
+		installation: installation,
+		reserve:      rateLimitReserve,
+		delegate:     cachedTransport,
+		cache:        installationCache,
 	}, nil
 }
 
 // InitGitHubCache initializes a central cache exactly once
-// It returns a mem cache by default and a disk cache if a directory is defined
+// It returns a mem cache by default, a disk cache if a directory is defined
+// and a redis or http cache if a shared backend is defined.
 func InitGitHubCache(cfg *config.GitHubCache) {
 	initOnce.Do(func() {
 		if cfg == nil && internalConfig == nil {
@@ -56,21 +67,30 @@ func InitGitHubCache(cfg *config.GitHubCache) {
 		if cfg == nil {
 			cfg = internalConfig
 		}
-
 		maxAgeSeconds = cfg.MaxAgeSeconds
-
+		rateLimitReserve = cfg.RateLimitReserve
+		log := logger.Log.WithName("ghcache")
+		if cfg.Shared != nil {
+			switch {
+			case cfg.Shared.Redis != nil:
+				ghCache = newRedisCache(log, cfg.Shared.Redis)
+			case cfg.Shared.HTTP != nil:
+				ghCache = newHTTPCache(log, cfg.Shared.HTTP)
+			default:
+				panic("no backend is defined for the shared github cache")
+			}
+			return
+		}
 		if cfg.CacheDir == "" {
 			ghCache = httpcache.NewMemoryCache()
 			return
 		}
-
 		if err := os.MkdirAll(cfg.CacheDir, 0750); err != nil {
 			panic(err)
 		}
 		if cfg.CacheDiskSizeGB == 0 {
 			panic("disk cache size ha to be grater than 0")
 		}
-
 		ghCache = diskcache.NewWithDiskv(
 			diskv.New(diskv.Options{
 				BasePath:     path.Join(cfg.CacheDir, "data"),
@@ -0,0 +1,307 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ghcache
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/gregjones/httpcache"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/gardener/test-infra/pkg/apis/config"
+)
+
+var _ = Describe("GitHub cache", func() {
+
+	Context("rate limit control", func() {
+		var (
+			requests     []*http.Request
+			remaining    string
+			github       *httptest.Server
+			previous     httpcache.Cache
+			installation string
+		)
+
+		BeforeEach(func() {
+			requests = nil
+			remaining = "4000"
+			installation = "test-" + CurrentSpecReport().LeafNodeText
+			github = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests = append(requests, r.Clone(r.Context()))
+				w.Header().Set("Cache-Control", "private, max-age=0")
+				w.Header().Set("Vary", "Accept, Authorization")
+				w.Header().Set("ETag", `"abc"`)
+				w.Header().Set("X-RateLimit-Limit", "5000")
+				w.Header().Set("X-RateLimit-Remaining", remaining)
+				w.Header().Set("X-RateLimit-Reset", "4102444800")
+				if r.Header.Get("If-None-Match") == `"abc"` {
+					w.WriteHeader(http.StatusNotModified)
+					return
+				}
+				_, _ = w.Write([]byte("content"))
+			}))
+			previous = ghCache
+			ghCache = httpcache.NewMemoryCache()
+		})
+
+		AfterEach(func() {
+			github.Close()
+			ghCache = previous
+			rateLimitReserve = 0
+		})
+
+		get := func(trp http.RoundTripper, token string) *http.Response {
+			req, err := http.NewRequest(http.MethodGet, github.URL+"/repos/gardener/test-infra", nil)
+			Expect(err).ToNot(HaveOccurred())
+			req.Header.Set("Authorization", "token "+token)
+			res, err := trp.RoundTrip(req)
+			Expect(err).ToNot(HaveOccurred())
+			data, err := io.ReadAll(res.Body)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.Body.Close()).To(Succeed())
+			Expect(string(data)).To(Equal("content"))
+			return res
+		}
+
+		It("should revalidate cached responses with the etag after the token of the installation changed", func() {
+			trp, err := WithRateLimitControlCache(logr.Discard(), installation, http.DefaultTransport)
+			Expect(err).ToNot(HaveOccurred())
+
+			get(trp, "first")
+			res := get(trp, "rotated")
+			Expect(res.Header.Get(httpcache.XFromCache)).To(Equal("1"))
+			Expect(requests).To(HaveLen(2))
+			Expect(requests[1].Header.Get("If-None-Match")).To(Equal(`"abc"`))
+		})
+
+		It("should not share cached responses between installations", func() {
+			trp, err := WithRateLimitControlCache(logr.Discard(), installation, http.DefaultTransport)
+			Expect(err).ToNot(HaveOccurred())
+			other, err := WithRateLimitControlCache(logr.Discard(), installation+"-other", http.DefaultTransport)
+			Expect(err).ToNot(HaveOccurred())
+
+			get(trp, "first")
+			get(other, "second")
+			Expect(requests).To(HaveLen(2))
+			Expect(requests[1].Header.Get("If-None-Match")).To(BeEmpty())
+		})
+
+		It("should return stale responses once the rate limit budget is exhausted", func() {
+			rateLimitReserve = 100
+			remaining = "50"
+			trp, err := WithRateLimitControlCache(logr.Discard(), installation, http.DefaultTransport)
+			Expect(err).ToNot(HaveOccurred())
+
+			get(trp, "first")
+			res := get(trp, "first")
+			Expect(requests).To(HaveLen(1))
+			Expect(res.Header.Get("Warning")).ToNot(BeEmpty())
+			Expect(budgets.exhausted(installation, "core", 100)).To(BeTrue())
+			Expect(budgets.exhausted(installation, "core", 10)).To(BeFalse())
+		})
+	})
+
+	Context("redis", func() {
+		It("should store, read and delete responses", func() {
+			server := newFakeRedis("secret")
+			defer server.Close()
+
+			cache := newRedisCache(logr.Discard(), &config.RedisGitHubCache{
+				Address:   server.Addr(),
+				Password:  "secret",
+				KeyPrefix: "ghcache:",
+				TTL:       metav1.Duration{Duration: time.Hour},
+			})
+			_, ok := cache.Get("key")
+			Expect(ok).To(BeFalse())
+
+			cache.Set("key", []byte("response\r\nwith newlines"))
+			data, ok := cache.Get("key")
+			Expect(ok).To(BeTrue())
+			Expect(string(data)).To(Equal("response\r\nwith newlines"))
+			Expect(server.commands).To(ContainElement("SET ghcache:key response\r\nwith newlines PX 3600000"))
+
+			cache.Delete("key")
+			_, ok = cache.Get("key")
+			Expect(ok).To(BeFalse())
+		})
+
+		It("should not return responses if the authentication fails", func() {
+			server := newFakeRedis("secret")
+			defer server.Close()
+
+			cache := newRedisCache(logr.Discard(), &config.RedisGitHubCache{Address: server.Addr(), Password: "wrong"})
+			cache.Set("key", []byte("response"))
+			_, ok := cache.Get("key")
+			Expect(ok).To(BeFalse())
+			Expect(server.data).To(BeEmpty())
+		})
+	})
+
+	Context("http cache service", func() {
+		var (
+			previous httpcache.Cache
+			server   *httptest.Server
+		)
+
+		BeforeEach(func() {
+			previous = ghCache
+			ghCache = httpcache.NewMemoryCache()
+			cacheServer, err := NewServer(logr.Discard(), &config.GitHubCacheServer{BindAddress: ":0", Token: "token"})
+			Expect(err).ToNot(HaveOccurred())
+			server = httptest.NewServer(cacheServer)
+		})
+
+		AfterEach(func() {
+			server.Close()
+			ghCache = previous
+		})
+
+		It("should store, read and delete responses", func() {
+			cache := newHTTPCache(logr.Discard(), &config.HTTPGitHubCache{URL: server.URL, Token: "token"})
+			cache.Set("installation/https://api.github.com/repos?page=1&per_page=100", []byte("response"))
+
+			data, ok := ghCache.Get("installation/https://api.github.com/repos?page=1&per_page=100")
+			Expect(ok).To(BeTrue())
+			Expect(string(data)).To(Equal("response"))
+
+			data, ok = cache.Get("installation/https://api.github.com/repos?page=1&per_page=100")
+			Expect(ok).To(BeTrue())
+			Expect(string(data)).To(Equal("response"))
+
+			cache.Delete("installation/https://api.github.com/repos?page=1&per_page=100")
+			_, ok = cache.Get("installation/https://api.github.com/repos?page=1&per_page=100")
+			Expect(ok).To(BeFalse())
+		})
+
+		It("should not serve the cache without a token", func() {
+			_, err := NewServer(logr.Discard(), &config.GitHubCacheServer{BindAddress: ":0"})
+			Expect(err).To(MatchError(ContainSubstring("a token is required")))
+		})
+
+		It("should read the token from a file", func() {
+			tokenPath := filepath.Join(GinkgoT().TempDir(), "token")
+			Expect(os.WriteFile(tokenPath, []byte("file-token\n"), 0600)).To(Succeed())
+			cacheServer, err := NewServer(logr.Discard(), &config.GitHubCacheServer{BindAddress: ":0", TokenPath: tokenPath})
+			Expect(err).ToNot(HaveOccurred())
+			fileServer := httptest.NewServer(cacheServer)
+			defer fileServer.Close()
+
+			ghCache.Set("key", []byte("response"))
+			_, ok := newHTTPCache(logr.Discard(), &config.HTTPGitHubCache{URL: fileServer.URL, Token: "token"}).Get("key")
+			Expect(ok).To(BeFalse())
+			data, ok := newHTTPCache(logr.Discard(), &config.HTTPGitHubCache{URL: fileServer.URL, Token: "file-token"}).Get("key")
+			Expect(ok).To(BeTrue())
+			Expect(string(data)).To(Equal("response"))
+		})
+
+		It("should reject clients without a token", func() {
+			ghCache.Set("key", []byte("response"))
+			cache := newHTTPCache(logr.Discard(), &config.HTTPGitHubCache{URL: server.URL})
+			_, ok := cache.Get("key")
+			Expect(ok).To(BeFalse())
+		})
+
+		It("should reject clients with an invalid token", func() {
+			ghCache.Set("key", []byte("response"))
+			cache := newHTTPCache(logr.Discard(), &config.HTTPGitHubCache{URL: server.URL, Token: "wrong"})
+			_, ok := cache.Get("key")
+			Expect(ok).To(BeFalse())
+		})
+	})
+})
+
+// fakeRedis is a minimal redis server that supports the commands used by the cache.
+type fakeRedis struct {
+	net.Listener
+	password string
+
+	mux      sync.Mutex
+	data     map[string][]byte
+	commands []string
+}
+
+func newFakeRedis(password string) *fakeRedis {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).ToNot(HaveOccurred())
+	s := &fakeRedis{Listener: l, password: password, data: map[string][]byte{}}
+	go s.serve()
+	return s
+}
+
+func (s *fakeRedis) Addr() string {
+	return s.Listener.Addr().String()
+}
+
+func (s *fakeRedis) serve() {
+	for {
+		conn, err := s.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *fakeRedis) handle(conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+	rd := bufio.NewReader(conn)
+	authenticated := s.password == ""
+	for {
+		reply, err := readRedisReply(rd)
+		if err != nil {
+			return
+		}
+		var args []string
+		for _, arg := range reply.([]interface{}) {
+			args = append(args, string(arg.([]byte)))
+		}
+
+		s.mux.Lock()
+		s.commands = append(s.commands, strings.Join(args, " "))
+		var res string
+		switch {
+		case args[0] == "AUTH":
+			authenticated = args[1] == s.password
+			res = "+OK\r\n"
+			if !authenticated {
+				res = "-WRONGPASS invalid password\r\n"
+			}
+		case !authenticated:
+			res = "-NOAUTH Authentication required\r\n"
+		case args[0] == "GET":
+			data, ok := s.data[args[1]]
+			res = "$-1\r\n"
+			if ok {
+				res = "$" + strconv.Itoa(len(data)) + "\r\n" + string(data) + "\r\n"
+			}
+		case args[0] == "SET":
+			s.data[args[1]] = []byte(args[2])
+			res = "+OK\r\n"
+		case args[0] == "DEL":
+			delete(s.data, args[1])
+			res = ":1\r\n"
+		default:
+			res = "-ERR unknown command\r\n"
+		}
+		s.mux.Unlock()
+		if _, err := conn.Write([]byte(res)); err != nil {
+			return
+		}
+	}
+}
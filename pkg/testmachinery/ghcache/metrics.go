@@ -0,0 +1,53 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ghcache
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	// cacheRequests counts the requests of github clients by their cache result.
+	// The hit ratio is the rate of hit, revalidated and stale results divided by the rate of all results.
+	cacheRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "testmachinery",
+		Subsystem: "github_cache",
+		Name:      "requests_total",
+		Help:      "Number of github api requests by cache result (hit, revalidated, stale or miss).",
+	}, []string{"result"})
+
+	upstreamRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "testmachinery",
+		Subsystem: "github_cache",
+		Name:      "upstream_requests_total",
+		Help:      "Number of requests that were sent to the github api per installation.",
+	}, []string{"installation"})
+
+	backendErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "testmachinery",
+		Subsystem: "github_cache",
+		Name:      "backend_errors_total",
+		Help:      "Number of failed operations of the shared cache backend.",
+	}, []string{"backend"})
+
+	rateLimitRemaining = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "testmachinery",
+		Subsystem: "github",
+		Name:      "rate_limit_remaining",
+		Help:      "Remaining requests of the github rate limit per installation and resource.",
+	}, []string{"installation", "resource"})
+
+	rateLimitLimit = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "testmachinery",
+		Subsystem: "github",
+		Name:      "rate_limit_limit",
+		Help:      "Github rate limit per installation and resource.",
+	}, []string{"installation", "resource"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(cacheRequests, upstreamRequests, backendErrors, rateLimitRemaining, rateLimitLimit)
+}
@@ -7,6 +7,7 @@ package ghcache
 import (
 	"context"
 	"net/http"
+	"strings"
 	"sync"
 	"time"
 
@@ -22,13 +23,25 @@ var (
 )
 
 type rateLimitControl struct {
-	log      logr.Logger
-	delegate http.RoundTripper
-	cache    httpcache.Cache
+	log          logr.Logger
+	installation string
+	reserve      int
+	delegate     http.RoundTripper
+	cache        httpcache.Cache
 }
 
 func (l *rateLimitControl) RoundTrip(req *http.Request) (*http.Response, error) {
-	key := req.URL.String()
+	result := &requestResult{}
+	req = req.WithContext(withRequestResult(req.Context(), result))
+	res, err := l.roundTrip(req)
+	if err == nil {
+		cacheRequests.WithLabelValues(result.label(res)).Inc()
+	}
+	return res, err
+}
+
+func (l *rateLimitControl) roundTrip(req *http.Request) (*http.Response, error) {
+	key := l.installation + "/" + req.URL.String()
 	// avoid parallel requests in case the response is not yet cached
 	// only GET and HEAD requests are cachable, thus we care only about those here
 	if req.Method == http.MethodGet || req.Method == http.MethodHead {
@@ -36,6 +49,14 @@ func (l *rateLimitControl) RoundTrip(req *http.Request) (*http.Response, error)
 		if err != nil {
 			return nil, err
 		}
+		if isCached != nil {
+			// do not spend the reserved rate limit budget of the installation on revalidations
+			if budgets.exhausted(l.installation, rateLimitResource(req.URL.Path), l.reserve) {
+				l.log.V(3).Info("Rate limit budget exhausted, returning stale response", "key", key)
+				return staleResponse(isCached), nil
+			}
+			req = withValidators(req, isCached)
+		}
 
 		// in case of a cache miss, we check for parallel requests
 		// if there is an ongoing request, wait on the Condition and proceed to get the response via the cache
@@ -89,6 +110,49 @@ func (l *rateLimitControl) RoundTrip(req *http.Request) (*http.Response, error)
 	return resp, nil
 }
 
+// withValidators adds the etag of a cached response to a request if the cached response was
+// requested with other credentials of the same installation, e.g. a rotated installation token.
+// The http cache only revalidates responses that vary in no header, which would result in a full request
+// that counts against the rate limit although the response has not changed.
+func withValidators(req *http.Request, cached *http.Response) *http.Request {
+	etag := cached.Header.Get("ETag")
+	if etag == "" || req.Header.Get("If-None-Match") != "" {
+		return req
+	}
+	for _, varyKey := range headerValues(cached.Header, "Vary") {
+		varyKey = http.CanonicalHeaderKey(varyKey)
+		if varyKey == "Authorization" {
+			continue
+		}
+		if cached.Header.Get("X-Varied-"+varyKey) != req.Header.Get(varyKey) {
+			// the cached response is a different representation
+			return req
+		}
+	}
+	req2 := req.Clone(req.Context())
+	req2.Header.Set("If-None-Match", etag)
+	return req2
+}
+
+// staleResponse marks a cached response as stale.
+func staleResponse(res *http.Response) *http.Response {
+	res.Header.Set(httpcache.XFromCache, "1")
+	res.Header.Set("Warning", `110 - "Response is Stale"`)
+	return res
+}
+
+func headerValues(header http.Header, key string) []string {
+	var values []string
+	for _, line := range header.Values(key) {
+		for _, v := range strings.Split(line, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+	}
+	return values
+}
+
 func (l *rateLimitControl) logRateLimitInfo(req *http.Request, resp *http.Response) {
 	if req == nil {
 		l.log.V(2).Info("Rate limit logger: Request is nil")
@@ -0,0 +1,228 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ghcache
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/gregjones/httpcache"
+	"github.com/pkg/errors"
+
+	"github.com/gardener/test-infra/pkg/apis/config"
+)
+
+const (
+	redisTimeout      = 5 * time.Second
+	redisMaxIdleConns = 8
+)
+
+// redisCache is a http cache that stores responses in a redis compatible server.
+// Only the few commands of the RESP protocol that are needed by the cache are implemented.
+type redisCache struct {
+	log       logr.Logger
+	address   string
+	password  string
+	db        int
+	keyPrefix string
+	ttl       time.Duration
+
+	idle chan *redisConn
+}
+
+var _ httpcache.Cache = &redisCache{}
+
+func newRedisCache(log logr.Logger, cfg *config.RedisGitHubCache) *redisCache {
+	return &redisCache{
+		log:       log.WithName("redis"),
+		address:   cfg.Address,
+		password:  cfg.Password,
+		db:        cfg.DB,
+		keyPrefix: cfg.KeyPrefix,
+		ttl:       cfg.TTL.Duration,
+		idle:      make(chan *redisConn, redisMaxIdleConns),
+	}
+}
+
+// Get returns the cached response of a key.
+func (c *redisCache) Get(key string) ([]byte, bool) {
+	reply, err := c.do("GET", c.keyPrefix+key)
+	if err != nil {
+		c.handleError(err, "unable to get cached response", key)
+		return nil, false
+	}
+	data, ok := reply.([]byte)
+	return data, ok
+}
+
+// Set stores a response with the configured ttl.
+func (c *redisCache) Set(key string, data []byte) {
+	args := []interface{}{"SET", c.keyPrefix + key, data}
+	if c.ttl > 0 {
+		args = append(args, "PX", strconv.FormatInt(c.ttl.Milliseconds(), 10))
+	}
+	if _, err := c.do(args...); err != nil {
+		c.handleError(err, "unable to cache response", key)
+	}
+}
+
+// Delete removes a cached response.
+func (c *redisCache) Delete(key string) {
+	if _, err := c.do("DEL", c.keyPrefix+key); err != nil {
+		c.handleError(err, "unable to delete cached response", key)
+	}
+}
+
+func (c *redisCache) handleError(err error, msg, key string) {
+	backendErrors.WithLabelValues("redis").Inc()
+	c.log.V(3).Info(msg, "key", key, "error", err.Error())
+}
+
+// do runs a command and returns its reply.
+// The connection is reused for later commands unless the command failed due to a connection error.
+func (c *redisCache) do(args ...interface{}) (interface{}, error) {
+	conn, err := c.conn()
+	if err != nil {
+		return nil, err
+	}
+	reply, err := conn.do(args...)
+	if err != nil {
+		if _, ok := err.(redisError); !ok {
+			_ = conn.Close()
+			return nil, err
+		}
+	}
+	select {
+	case c.idle <- conn:
+	default:
+		_ = conn.Close()
+	}
+	return reply, err
+}
+
+// conn returns an idle connection or dials a new one.
+func (c *redisCache) conn() (*redisConn, error) {
+	select {
+	case conn := <-c.idle:
+		return conn, nil
+	default:
+	}
+
+	netConn, err := net.DialTimeout("tcp", c.address, redisTimeout)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to connect to redis %s", c.address)
+	}
+	conn := &redisConn{Conn: netConn, rd: bufio.NewReader(netConn)}
+	if len(c.password) != 0 {
+		if _, err := conn.do("AUTH", c.password); err != nil {
+			_ = conn.Close()
+			return nil, errors.Wrap(err, "unable to authenticate to redis")
+		}
+	}
+	if c.db != 0 {
+		if _, err := conn.do("SELECT", strconv.Itoa(c.db)); err != nil {
+			_ = conn.Close()
+			return nil, errors.Wrapf(err, "unable to select redis database %d", c.db)
+		}
+	}
+	return conn, nil
+}
+
+// redisError is an error reply of the redis server.
+type redisError string
+
+func (e redisError) Error() string {
+	return string(e)
+}
+
+type redisConn struct {
+	net.Conn
+	rd *bufio.Reader
+}
+
+// do writes a command and reads its reply.
+// Arguments have to be strings or byte slices.
+func (c *redisConn) do(args ...interface{}) (interface{}, error) {
+	if err := c.SetDeadline(time.Now().Add(redisTimeout)); err != nil {
+		return nil, err
+	}
+	buf := []byte(fmt.Sprintf("*%d\r\n", len(args)))
+	for _, arg := range args {
+		var data []byte
+		switch a := arg.(type) {
+		case string:
+			data = []byte(a)
+		case []byte:
+			data = a
+		default:
+			return nil, fmt.Errorf("unsupported argument type %T", arg)
+		}
+		buf = append(buf, fmt.Sprintf("$%d\r\n", len(data))...)
+		buf = append(buf, data...)
+		buf = append(buf, '\r', '\n')
+	}
+	if _, err := c.Write(buf); err != nil {
+		return nil, err
+	}
+	return readRedisReply(c.rd)
+}
+
+// readRedisReply reads a reply of the RESP protocol.
+// Bulk strings are returned as byte slices and nil replies as nil.
+func readRedisReply(rd *bufio.Reader) (interface{}, error) {
+	line, err := rd.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, fmt.Errorf("malformed redis reply %q", line)
+	}
+	line = line[:len(line)-2]
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, redisError(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		data := make([]byte, n+2)
+		if _, err := io.ReadFull(rd, data); err != nil {
+			return nil, err
+		}
+		return data[:n], nil
+	case '*':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		elements := make([]interface{}, n)
+		for i := range elements {
+			if elements[i], err = readRedisReply(rd); err != nil {
+				if _, ok := err.(redisError); !ok {
+					return nil, err
+				}
+			}
+		}
+		return elements, nil
+	default:
+		return nil, fmt.Errorf("unknown redis reply type %q", line[0])
+	}
+}
@@ -0,0 +1,115 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ghcache
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/gregjones/httpcache"
+
+	"github.com/gardener/test-infra/pkg/apis/config"
+)
+
+const (
+	// cachePath is the path the cache service serves the cached responses at.
+	cachePath = "/v1/cache"
+
+	remoteTimeout = 10 * time.Second
+	maxEntrySize  = 32 * 1024 * 1024
+)
+
+// httpCache is a http cache that stores responses in a cache service that is served by another process.
+type httpCache struct {
+	log    logr.Logger
+	url    string
+	token  string
+	client *http.Client
+}
+
+var _ httpcache.Cache = &httpCache{}
+
+func newHTTPCache(log logr.Logger, cfg *config.HTTPGitHubCache) *httpCache {
+	return &httpCache{
+		log:    log.WithName("http"),
+		url:    strings.TrimSuffix(cfg.URL, "/") + cachePath,
+		token:  cfg.Token,
+		client: &http.Client{Timeout: remoteTimeout},
+	}
+}
+
+// Get returns the cached response of a key.
+func (c *httpCache) Get(key string) ([]byte, bool) {
+	res, err := c.do(http.MethodGet, key, nil)
+	if err != nil {
+		c.handleError(err, "unable to get cached response", key)
+		return nil, false
+	}
+	defer func() { _ = res.Body.Close() }()
+	if res.StatusCode == http.StatusNotFound {
+		return nil, false
+	}
+	if res.StatusCode != http.StatusOK {
+		c.handleError(fmt.Errorf("unexpected status %d", res.StatusCode), "unable to get cached response", key)
+		return nil, false
+	}
+	data, err := io.ReadAll(io.LimitReader(res.Body, maxEntrySize))
+	if err != nil {
+		c.handleError(err, "unable to read cached response", key)
+		return nil, false
+	}
+	return data, true
+}
+
+// Set stores a response in the cache service.
+func (c *httpCache) Set(key string, data []byte) {
+	if len(data) > maxEntrySize {
+		return
+	}
+	c.send(http.MethodPut, key, data, "unable to cache response")
+}
+
+// Delete removes a cached response from the cache service.
+func (c *httpCache) Delete(key string) {
+	c.send(http.MethodDelete, key, nil, "unable to delete cached response")
+}
+
+func (c *httpCache) send(method, key string, data []byte, msg string) {
+	res, err := c.do(method, key, data)
+	if err != nil {
+		c.handleError(err, msg, key)
+		return
+	}
+	_ = res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		c.handleError(fmt.Errorf("unexpected status %d", res.StatusCode), msg, key)
+	}
+}
+
+func (c *httpCache) do(method, key string, data []byte) (*http.Response, error) {
+	var body io.Reader
+	if data != nil {
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, c.url+"?key="+url.QueryEscape(key), body)
+	if err != nil {
+		return nil, err
+	}
+	if len(c.token) != 0 {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	return c.client.Do(req)
+}
+
+func (c *httpCache) handleError(err error, msg, key string) {
+	backendErrors.WithLabelValues("http").Inc()
+	c.log.V(3).Info(msg, "key", key, "error", err.Error())
+}
@@ -0,0 +1,124 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ghcache
+
+import (
+	"context"
+	"crypto/subtle"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/gregjones/httpcache"
+	"github.com/pkg/errors"
+
+	"github.com/gardener/test-infra/pkg/apis/config"
+)
+
+// Server serves the github cache of this process as http cache service,
+// so that other processes can use it as shared backend.
+type Server struct {
+	log         logr.Logger
+	bindAddress string
+	token       string
+	cache       httpcache.Cache
+}
+
+// NewServer creates a cache service for the central github cache.
+// Call InitGitHubCache in advance for bootstrapping the cache
+func NewServer(log logr.Logger, cfg *config.GitHubCacheServer) (*Server, error) {
+	if ghCache == nil {
+		return nil, errors.New("cache has not been initialized yet")
+	}
+	token := cfg.Token
+	if len(cfg.TokenPath) != 0 {
+		data, err := os.ReadFile(cfg.TokenPath)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to read token from %s", cfg.TokenPath)
+		}
+		token = strings.TrimSpace(string(data))
+	}
+	// the cache contains responses of private repositories and is used to resolve TestDefinitions,
+	// so it must never be served without authentication.
+	if len(token) == 0 {
+		return nil, errors.New("a token is required to serve the cache")
+	}
+	return &Server{
+		log:         log,
+		bindAddress: cfg.BindAddress,
+		token:       token,
+		cache:       ghCache,
+	}, nil
+}
+
+// Start serves the cache until the context is canceled.
+func (s *Server) Start(ctx context.Context) error {
+	mux := http.NewServeMux()
+	mux.Handle(cachePath, s)
+	srv := &http.Server{
+		Addr:              s.bindAddress,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+
+	s.log.Info("serving github cache", "address", s.bindAddress)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// NeedLeaderElection implements the LeaderElectionRunnable interface
+// so that the cache is served by all replicas.
+func (s *Server) NeedLeaderElection() bool {
+	return false
+}
+
+// ServeHTTP serves a cached response with GET, stores it with PUT and removes it with DELETE.
+// The key of the response is passed as "key" query parameter.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte("Bearer "+s.token)) != 1 {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	key := r.URL.Query().Get("key")
+	if len(key) == 0 {
+		http.Error(w, "key must be defined", http.StatusBadRequest)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		data, ok := s.cache.Get(key)
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/octet-stream")
+		_, _ = w.Write(data)
+	case http.MethodPut:
+		data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxEntrySize))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		s.cache.Set(key, data)
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodDelete:
+		s.cache.Delete(key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
@@ -6,35 +6,34 @@ package location
 
 import (
 	"context"
-	"crypto/tls"
 	"fmt"
-	"net/http"
 	"net/url"
+	"path"
+	"strings"
 
 	argov1 "github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
 	"github.com/go-logr/logr"
-	"github.com/google/go-github/v83/github"
 	"github.com/pkg/errors"
 
+	"github.com/gardener/test-infra/pkg/apis/config"
 	tmv1beta1 "github.com/gardener/test-infra/pkg/apis/testmachinery/v1beta1"
 	"github.com/gardener/test-infra/pkg/testmachinery"
-	"github.com/gardener/test-infra/pkg/testmachinery/ghcache"
 	"github.com/gardener/test-infra/pkg/testmachinery/testdefinition"
 	"github.com/gardener/test-infra/pkg/util"
 )
 
-var githubContentTypeFile = "file"
-
 // GitLocation represents the testDefLocation of type "git".
 type GitLocation struct {
 	log  logr.Logger
 	Info *tmv1beta1.TestLocation
 
-	config    *testmachinery.GitHubInstanceConfig
-	repoOwner string
-	repoName  string
-	repoURL   *url.URL
-	gitInfo   testdefinition.GitInfo
+	provider     gitProvider
+	providerType config.GitProviderType
+	repoOwner    string
+	repoName     string
+	repoURL      *url.URL
+	pinnedCommit string
+	gitInfo      testdefinition.GitInfo
 }
 
 // NewGitLocation creates a TestDefLocation of type git.
@@ -43,19 +42,23 @@ func NewGitLocation(log logr.Logger, testDefLocation *tmv1beta1.TestLocation) (t
 	if err != nil {
 		return nil, fmt.Errorf("unable to parse url %s", testDefLocation.Repo)
 	}
-	config := getGitConfig(log, repoURL)
-	repoOwner, repoName, err := util.ParseRepoURL(repoURL)
+	provider, providerType, err := newGitProvider(log, repoURL, getGitConfig(log, repoURL))
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to create git provider for %s", repoURL)
+	}
+	repoOwner, repoName, err := parseRepoURL(repoURL, providerType)
 	if err != nil {
 		return nil, errors.Wrapf(err, "unable to parse repo url %s", repoURL)
 	}
 
 	return &GitLocation{
-		Info:      testDefLocation,
-		log:       log,
-		config:    config,
-		repoOwner: repoOwner,
-		repoName:  repoName,
-		repoURL:   repoURL,
+		Info:         testDefLocation,
+		log:          log,
+		provider:     provider,
+		providerType: providerType,
+		repoOwner:    repoOwner,
+		repoName:     repoName,
+		repoURL:      repoURL,
 	}, nil
 }
 
@@ -98,126 +101,64 @@ func (l *GitLocation) Type() tmv1beta1.LocationType {
 	return tmv1beta1.LocationTypeGit
 }
 
+// Provider returns the type of the git provider that is used to read the repository.
+func (l *GitLocation) Provider() config.GitProviderType {
+	return l.providerType
+}
+
+// Pin pins the location to a commit that the revision was previously resolved to.
+// The TestDefinitions are then read from the commit instead of the current state of the revision.
+func (l *GitLocation) Pin(commit string) {
+	l.pinnedCommit = commit
+}
+
+// Commit returns the commit sha the revision was resolved to.
+// It is empty until the TestDefinitions of the location are read.
+func (l *GitLocation) Commit() string {
+	return l.gitInfo.SHA
+}
+
 // GitInfo returns the git info for the current test location.
 func (l *GitLocation) GitInfo() testdefinition.GitInfo {
 	return l.gitInfo
 }
 
 func (l *GitLocation) getTestDefs() ([]*testdefinition.TestDefinition, error) {
-	ctx := context.Background()
-	defer ctx.Done()
 	var definitions []*testdefinition.TestDefinition
 
-	client, httpClient, err := l.getGitHubClient()
-	if err != nil {
-		return nil, fmt.Errorf("unable to create github client for %s: %s", l.Info.Repo, err.Error())
+	revision := l.Info.Revision
+	if len(l.pinnedCommit) != 0 {
+		revision = l.pinnedCommit
 	}
-
-	tree, _, err := client.Git.GetTree(ctx, l.repoOwner, l.repoName, l.Info.Revision, false)
+	sha, files, err := l.provider.GetTestDefinitionFiles(context.Background(), revision)
 	if err != nil {
-		return nil, fmt.Errorf("unable to get git tree for revision %s: %w", l.Info.Revision, err)
+		return nil, fmt.Errorf("unable to get testdefinitions of %s: %w", l.Info.Repo, err)
 	}
-	l.gitInfo.SHA = tree.GetSHA()
+	l.gitInfo.SHA = sha
 	if l.Info.Revision != l.gitInfo.SHA {
 		l.gitInfo.Ref = l.Info.Revision
 	}
 
-	_, directoryContent, _, err := client.Repositories.GetContents(ctx, l.repoOwner, l.repoName,
-		testmachinery.TestDefPath(), &github.RepositoryContentGetOptions{Ref: l.Info.Revision})
-	if err != nil {
-		return nil, fmt.Errorf("no testdefinitions can be found in %s: %s", l.Info.Repo, err.Error())
-	}
-
-	for _, file := range directoryContent {
-		if *file.Type == githubContentTypeFile {
-			l.log.V(5).Info("found file", "filename", *file.Name, "path", *file.Path)
-			data, err := util.DownloadFile(httpClient, file.GetDownloadURL())
-			if err != nil {
-				return nil, err
-			}
-			def, err := util.ParseTestDef(data)
+	for _, file := range files {
+		def, err := util.ParseTestDef(file.data)
+		if err != nil {
+			l.log.V(5).Info(fmt.Sprintf("ignoring file: %s", err.Error()), "filename", file.name)
+			continue
+		}
+		if def.Kind == tmv1beta1.TestDefinitionName && def.Name != "" {
+			definition, err := testdefinition.New(&def, l, file.name)
 			if err != nil {
-				l.log.V(5).Info(fmt.Sprintf("ignoring file: %s", err.Error()), "filename", *file.Name)
+				l.log.Info(fmt.Sprintf("unable to build testdefinition: %s", err.Error()), "filename", file.name)
 				continue
 			}
-			if def.Kind == tmv1beta1.TestDefinitionName && def.Name != "" {
-				definition, err := testdefinition.New(&def, l, file.GetName())
-				if err != nil {
-					l.log.Info(fmt.Sprintf("unable to build testdefinition: %s", err.Error()), "filename", *file.Name)
-					continue
-				}
-				definitions = append(definitions, definition)
-				l.log.V(3).Info(fmt.Sprintf("found TestDefinition %s", def.Name))
-			}
+			definitions = append(definitions, definition)
+			l.log.V(3).Info(fmt.Sprintf("found TestDefinition %s", def.Name))
 		}
 	}
 
 	return definitions, nil
 }
 
-func (l *GitLocation) getGitHubClient() (*github.Client, *http.Client, error) {
-	httpClient, err := l.getHTTPClient()
-	if err != nil {
-		return nil, nil, err
-	}
-	client, err := github.NewClient(httpClient).WithEnterpriseURLs(l.getGitHubAPI(), "")
-	if err != nil {
-		return nil, nil, err
-	}
-	return client, httpClient, nil
-}
-
-func (l *GitLocation) getHTTPClient() (*http.Client, error) {
-	if l.config != nil {
-		trp, err := ghcache.WithRateLimitControlCache(l.log.WithName("ghCache"), &http.Transport{
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: l.config.SkipTls, // #nosec G402 -- option defaults to false, otherwise it is a user's conscious decision.
-				MinVersion:         tls.VersionTLS12,
-			},
-		})
-		if err != nil {
-			return nil, err
-		}
-
-		basicAuth := github.BasicAuthTransport{
-			Username:  l.config.TechnicalUser.Username,
-			Password:  l.config.TechnicalUser.AuthToken,
-			Transport: trp,
-		}
-		l.log.V(3).Info(fmt.Sprintf("used gitconfig for %s to authenticate", l.config.HttpUrl))
-		return basicAuth.Client(), nil
-	}
-
-	l.log.V(3).Info("unauthenticated git connection is used")
-	trp, err := ghcache.WithRateLimitControlCache(l.log.WithName("ghCache"), &http.Transport{
-		TLSClientConfig: &tls.Config{
-			InsecureSkipVerify: false,
-			MinVersion:         tls.VersionTLS12,
-		},
-	})
-	if err != nil {
-		return nil, err
-	}
-
-	return &http.Client{
-		Transport: trp,
-	}, nil
-}
-
-// Legacy function. Maybe can be removed in the future when git config is necessary.
-func (l *GitLocation) getGitHubAPI() string {
-	if l.config != nil {
-		return l.config.ApiUrl
-	}
-	var apiURL string
-	if l.repoURL.Hostname() == "github.com" {
-		apiURL = "https://api." + l.repoURL.Hostname()
-	} else {
-		apiURL = "https://" + l.repoURL.Hostname() + "/api/v3"
-	}
-	return apiURL
-}
-
 func getGitConfig(log logr.Logger, gitURL *url.URL) *testmachinery.GitHubInstanceConfig {
 	httpURL := fmt.Sprintf("%s://%s", gitURL.Scheme, gitURL.Host)
 	if testmachinery.GetConfig() == nil {
@@ -232,3 +173,17 @@ func getGitConfig(log logr.Logger, gitURL *url.URL) *testmachinery.GitHubInstanc
 	}
 	return nil
 }
+
+// parseRepoURL returns the owner and the name of a repository.
+// Repositories of GitLab and plain git servers may be nested in multiple groups so that all but the last path element are the owner.
+func parseRepoURL(repoURL *url.URL, providerType config.GitProviderType) (string, string, error) {
+	if providerType == config.GitProviderGitHub {
+		return util.ParseRepoURL(repoURL)
+	}
+	repoPath := repositoryPath(repoURL)
+	owner, name := path.Split(repoPath)
+	if len(owner) == 0 || len(name) == 0 {
+		return "", "", fmt.Errorf("repository path %q has to contain an owner and a name", repoPath)
+	}
+	return strings.TrimSuffix(owner, "/"), name, nil
+}
@@ -0,0 +1,103 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package location
+
+import (
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/gardener/test-infra/pkg/apis/config"
+	tmv1beta1 "github.com/gardener/test-infra/pkg/apis/testmachinery/v1beta1"
+	"github.com/gardener/test-infra/pkg/testmachinery"
+	"github.com/gardener/test-infra/pkg/testmachinery/testdefinition"
+)
+
+var _ = Describe("Git location", func() {
+	var (
+		repoDir string
+		git     func(args ...string) string
+	)
+
+	BeforeEach(func() {
+		if _, err := exec.LookPath("git"); err != nil {
+			Skip("git is not installed")
+		}
+		Expect(testmachinery.Setup(&config.Configuration{
+			TestMachinery: config.TestMachinery{
+				TestDefPath: ".test-defs",
+			},
+		})).To(Succeed())
+
+		repoDir = GinkgoT().TempDir()
+		git = func(args ...string) string {
+			cmd := exec.Command("git", args...)
+			cmd.Dir = repoDir
+			cmd.Env = append(os.Environ(),
+				"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+				"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com")
+			out, err := cmd.CombinedOutput()
+			Expect(err).ToNot(HaveOccurred(), string(out))
+			return strings.TrimSpace(string(out))
+		}
+		git("init", "-q", "-b", "main")
+		Expect(os.MkdirAll(filepath.Join(repoDir, ".test-defs"), 0750)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(testmachinery.Setup(&config.Configuration{})).To(Succeed())
+	})
+
+	commitTestDefinition := func(name string) string {
+		def := strings.Replace(testDefinitionFile, "name: integration", "name: "+name, 1)
+		def += "  owner: dev@example.com\n"
+		Expect(os.WriteFile(filepath.Join(repoDir, ".test-defs", "test.yaml"), []byte(def), 0600)).To(Succeed())
+		git("add", "-A")
+		git("commit", "-q", "-m", name)
+		return git("rev-parse", "HEAD")
+	}
+
+	newLocation := func() *GitLocation {
+		u, _ := url.Parse("file://" + repoDir)
+		return &GitLocation{
+			log:          logr.Discard(),
+			Info:         &tmv1beta1.TestLocation{Type: tmv1beta1.LocationTypeGit, Repo: u.String(), Revision: "main"},
+			provider:     newPlainGitProvider(logr.Discard(), u, nil),
+			providerType: config.GitProviderGit,
+			repoURL:      u,
+		}
+	}
+
+	It("should resolve the revision to its current commit", func() {
+		commitTestDefinition("old")
+		head := commitTestDefinition("new")
+
+		loc := newLocation()
+		testDefs := map[string]*testdefinition.TestDefinition{}
+		Expect(loc.SetTestDefs(testDefs)).To(Succeed())
+		Expect(loc.Commit()).To(Equal(head))
+		Expect(loc.GitInfo().Ref).To(Equal("main"))
+		Expect(testDefs).To(HaveKey("new"))
+	})
+
+	It("should read the TestDefinitions of the pinned commit", func() {
+		pinned := commitTestDefinition("old")
+		commitTestDefinition("new")
+
+		loc := newLocation()
+		loc.Pin(pinned)
+		testDefs := map[string]*testdefinition.TestDefinition{}
+		Expect(loc.SetTestDefs(testDefs)).To(Succeed())
+		Expect(loc.Commit()).To(Equal(pinned))
+		Expect(testDefs).To(HaveKey("old"))
+		Expect(testDefs).ToNot(HaveKey("new"))
+	})
+})
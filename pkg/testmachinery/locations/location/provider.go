@@ -0,0 +1,64 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package location
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/go-logr/logr"
+
+	"github.com/gardener/test-infra/pkg/apis/config"
+	"github.com/gardener/test-infra/pkg/testmachinery"
+	"github.com/gardener/test-infra/pkg/util"
+)
+
+// gitProvider reads the TestDefinition files of a git repository.
+type gitProvider interface {
+	// GetTestDefinitionFiles returns the commit sha of the revision and all files of the TestDefinition directory.
+	GetTestDefinitionFiles(ctx context.Context, revision string) (string, []file, error)
+}
+
+// file is a file of the TestDefinition directory.
+type file struct {
+	name string
+	data []byte
+}
+
+// newGitProvider creates the provider that is configured for the domain of the repository.
+// Repositories of domains without a configured provider are read via the GitHub API.
+func newGitProvider(log logr.Logger, repoURL *url.URL, cfg *testmachinery.GitHubInstanceConfig) (gitProvider, config.GitProviderType, error) {
+	providerConfig := getGitProviderConfig(repoURL, testmachinery.Locations().GitProviders)
+	switch providerConfig.Type {
+	case config.GitProviderGitLab:
+		p, err := newGitLabProvider(log, repoURL, cfg, providerConfig.APIURL)
+		return p, providerConfig.Type, err
+	case config.GitProviderGit:
+		return newPlainGitProvider(log, repoURL, cfg), providerConfig.Type, nil
+	case config.GitProviderGitHub:
+		p, err := newGitHubProvider(log, repoURL, cfg, providerConfig.APIURL)
+		return p, providerConfig.Type, err
+	default:
+		return nil, "", fmt.Errorf("unsupported git provider %q for %s", providerConfig.Type, repoURL.Hostname())
+	}
+}
+
+// getGitProviderConfig returns the provider configuration of the most specific domain that matches the repository.
+func getGitProviderConfig(repoURL *url.URL, providers []config.GitProvider) config.GitProvider {
+	result := config.GitProvider{Type: config.GitProviderGitHub}
+	for _, provider := range providers {
+		if util.DomainMatches(repoURL.Hostname(), provider.Domain) && len(provider.Domain) > len(result.Domain) {
+			result = provider
+		}
+	}
+	return result
+}
+
+// repositoryPath returns the path of the repository without leading slash and without the .git suffix.
+func repositoryPath(repoURL *url.URL) string {
+	return strings.TrimSuffix(strings.Trim(repoURL.Path, "/"), ".git")
+}
@@ -0,0 +1,120 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package location
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/go-logr/logr"
+
+	"github.com/gardener/test-infra/pkg/testmachinery"
+)
+
+const gitObjectTypeBlob = "blob"
+
+// plainGitProvider reads TestDefinitions with a shallow fetch of the repository using the git cli.
+// It is used for git servers that do not offer a supported api.
+type plainGitProvider struct {
+	log    logr.Logger
+	repo   string
+	config *testmachinery.GitHubInstanceConfig
+}
+
+func newPlainGitProvider(log logr.Logger, repoURL *url.URL, config *testmachinery.GitHubInstanceConfig) *plainGitProvider {
+	return &plainGitProvider{
+		log:    log,
+		repo:   repoURL.String(),
+		config: config,
+	}
+}
+
+func (p *plainGitProvider) GetTestDefinitionFiles(ctx context.Context, revision string) (string, []file, error) {
+	dir, err := os.MkdirTemp("", "tm-git-")
+	if err != nil {
+		return "", nil, err
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	if _, err := p.git(ctx, dir, "init", "-q"); err != nil {
+		return "", nil, err
+	}
+	if _, err := p.git(ctx, dir, "remote", "add", "origin", p.repo); err != nil {
+		return "", nil, err
+	}
+	if _, err := p.git(ctx, dir, "fetch", "-q", "--depth=1", "--filter=blob:none", "origin", revision); err != nil {
+		return "", nil, fmt.Errorf("unable to fetch revision %s: %w", revision, err)
+	}
+	sha, err := p.git(ctx, dir, "rev-parse", "FETCH_HEAD")
+	if err != nil {
+		return "", nil, err
+	}
+	sha = bytes.TrimSpace(sha)
+
+	tree, err := p.git(ctx, dir, "ls-tree", string(sha), "--", path.Clean(testmachinery.TestDefPath())+"/")
+	if err != nil {
+		return "", nil, fmt.Errorf("no testdefinitions can be found: %w", err)
+	}
+
+	files := make([]file, 0)
+	scanner := bufio.NewScanner(bytes.NewReader(tree))
+	for scanner.Scan() {
+		// each line has the format "<mode> SP <type> SP <object> TAB <file>"
+		meta, filePath, found := strings.Cut(scanner.Text(), "\t")
+		fields := strings.Fields(meta)
+		if !found || len(fields) != 3 || fields[1] != gitObjectTypeBlob {
+			continue
+		}
+		p.log.V(5).Info("found file", "filename", path.Base(filePath), "path", filePath)
+		data, err := p.git(ctx, dir, "cat-file", gitObjectTypeBlob, fields[2])
+		if err != nil {
+			return "", nil, fmt.Errorf("unable to read file %s: %w", filePath, err)
+		}
+		files = append(files, file{name: path.Base(filePath), data: data})
+	}
+	return string(sha), files, scanner.Err()
+}
+
+// git runs a git command in the given directory and returns its output.
+// Credentials of the git config are passed as http header so that they are not written to disk.
+func (p *plainGitProvider) git(ctx context.Context, dir string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+
+	gitConfig := make([]string, 0)
+	if p.config != nil {
+		if len(p.config.TechnicalUser.AuthToken) != 0 {
+			auth := base64.StdEncoding.EncodeToString([]byte(p.config.TechnicalUser.Username + ":" + p.config.TechnicalUser.AuthToken))
+			gitConfig = append(gitConfig, "http.extraHeader", "Authorization: Basic "+auth)
+		}
+		if p.config.SkipTls {
+			gitConfig = append(gitConfig, "http.sslVerify", "false")
+		}
+	}
+	cmd.Env = append(cmd.Env, "GIT_CONFIG_COUNT="+strconv.Itoa(len(gitConfig)/2))
+	for i := 0; i < len(gitConfig)/2; i++ {
+		cmd.Env = append(cmd.Env,
+			fmt.Sprintf("GIT_CONFIG_KEY_%d=%s", i, gitConfig[2*i]),
+			fmt.Sprintf("GIT_CONFIG_VALUE_%d=%s", i, gitConfig[2*i+1]))
+	}
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
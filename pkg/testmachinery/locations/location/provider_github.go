@@ -0,0 +1,136 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package location
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/go-logr/logr"
+	"github.com/google/go-github/v83/github"
+	"github.com/pkg/errors"
+
+	"github.com/gardener/test-infra/pkg/testmachinery"
+	"github.com/gardener/test-infra/pkg/testmachinery/ghcache"
+	"github.com/gardener/test-infra/pkg/util"
+)
+
+var githubContentTypeFile = "file"
+
+// githubProvider reads TestDefinitions via the GitHub API.
+type githubProvider struct {
+	log        logr.Logger
+	client     *github.Client
+	httpClient *http.Client
+	repoOwner  string
+	repoName   string
+}
+
+func newGitHubProvider(log logr.Logger, repoURL *url.URL, config *testmachinery.GitHubInstanceConfig, apiURL string) (*githubProvider, error) {
+	repoOwner, repoName, err := util.ParseRepoURL(repoURL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to parse repo url %s", repoURL)
+	}
+	httpClient, err := getGitHubHTTPClient(log, config)
+	if err != nil {
+		return nil, err
+	}
+	if len(apiURL) == 0 {
+		apiURL = getGitHubAPI(repoURL, config)
+	}
+	client, err := github.NewClient(httpClient).WithEnterpriseURLs(apiURL, "")
+	if err != nil {
+		return nil, err
+	}
+	return &githubProvider{
+		log:        log,
+		client:     client,
+		httpClient: httpClient,
+		repoOwner:  repoOwner,
+		repoName:   repoName,
+	}, nil
+}
+
+func (p *githubProvider) GetTestDefinitionFiles(ctx context.Context, revision string) (string, []file, error) {
+	sha, _, err := p.client.Repositories.GetCommitSHA1(ctx, p.repoOwner, p.repoName, revision, "")
+	if err != nil {
+		return "", nil, fmt.Errorf("unable to get commit for revision %s: %w", revision, err)
+	}
+
+	_, directoryContent, _, err := p.client.Repositories.GetContents(ctx, p.repoOwner, p.repoName,
+		testmachinery.TestDefPath(), &github.RepositoryContentGetOptions{Ref: sha})
+	if err != nil {
+		return "", nil, fmt.Errorf("no testdefinitions can be found: %s", err.Error())
+	}
+
+	files := make([]file, 0, len(directoryContent))
+	for _, content := range directoryContent {
+		if content.GetType() != githubContentTypeFile {
+			continue
+		}
+		p.log.V(5).Info("found file", "filename", content.GetName(), "path", content.GetPath())
+		data, err := util.DownloadFile(p.httpClient, content.GetDownloadURL())
+		if err != nil {
+			return "", nil, err
+		}
+		files = append(files, file{name: content.GetName(), data: data})
+	}
+	return sha, files, nil
+}
+
+func getGitHubHTTPClient(log logr.Logger, config *testmachinery.GitHubInstanceConfig) (*http.Client, error) {
+	if config != nil {
+		installation := fmt.Sprintf("%s@%s", config.TechnicalUser.Username, config.HttpUrl)
+		trp, err := ghcache.WithRateLimitControlCache(log.WithName("ghCache"), installation, &http.Transport{
+			TLSClientConfig: &tls.Config{
+				InsecureSkipVerify: config.SkipTls, // #nosec G402 -- option defaults to false, otherwise it is a user's conscious decision.
+				MinVersion:         tls.VersionTLS12,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		basicAuth := github.BasicAuthTransport{
+			Username:  config.TechnicalUser.Username,
+			Password:  config.TechnicalUser.AuthToken,
+			Transport: trp,
+		}
+		log.V(3).Info(fmt.Sprintf("used gitconfig for %s to authenticate", config.HttpUrl))
+		return basicAuth.Client(), nil
+	}
+
+	log.V(3).Info("unauthenticated git connection is used")
+	trp, err := ghcache.WithRateLimitControlCache(log.WithName("ghCache"), "anonymous", &http.Transport{
+		TLSClientConfig: &tls.Config{
+			InsecureSkipVerify: false,
+			MinVersion:         tls.VersionTLS12,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Client{
+		Transport: trp,
+	}, nil
+}
+
+// Legacy function. Maybe can be removed in the future when git config is necessary.
+func getGitHubAPI(repoURL *url.URL, config *testmachinery.GitHubInstanceConfig) string {
+	if config != nil {
+		return config.ApiUrl
+	}
+	var apiURL string
+	if repoURL.Hostname() == "github.com" {
+		apiURL = "https://api." + repoURL.Hostname()
+	} else {
+		apiURL = "https://" + repoURL.Hostname() + "/api/v3"
+	}
+	return apiURL
+}
@@ -0,0 +1,150 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package location
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	"github.com/gardener/test-infra/pkg/testmachinery"
+)
+
+const (
+	gitlabTreeTypeBlob = "blob"
+	gitlabPageSize     = 100
+	gitlabTimeout      = 30 * time.Second
+)
+
+// gitlabProvider reads TestDefinitions via the GitLab REST API.
+type gitlabProvider struct {
+	log        logr.Logger
+	httpClient *http.Client
+	apiURL     string
+	project    string
+	token      string
+}
+
+type gitlabCommit struct {
+	ID string `json:"id"`
+}
+
+type gitlabTreeEntry struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	Path string `json:"path"`
+}
+
+func newGitLabProvider(log logr.Logger, repoURL *url.URL, config *testmachinery.GitHubInstanceConfig, apiURL string) (*gitlabProvider, error) {
+	if len(apiURL) == 0 && config != nil {
+		apiURL = config.ApiUrl
+	}
+	if len(apiURL) == 0 {
+		apiURL = fmt.Sprintf("%s://%s/api/v4", repoURL.Scheme, repoURL.Host)
+	}
+	p := &gitlabProvider{
+		log:     log,
+		apiURL:  apiURL,
+		project: repositoryPath(repoURL),
+		httpClient: &http.Client{
+			Timeout: gitlabTimeout,
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{
+					InsecureSkipVerify: config != nil && config.SkipTls, // #nosec G402 -- option defaults to false, otherwise it is a user's conscious decision.
+					MinVersion:         tls.VersionTLS12,
+				},
+			},
+		},
+	}
+	if config != nil {
+		p.token = config.TechnicalUser.AuthToken
+		log.V(3).Info(fmt.Sprintf("used gitconfig for %s to authenticate", config.HttpUrl))
+	}
+	return p, nil
+}
+
+func (p *gitlabProvider) GetTestDefinitionFiles(ctx context.Context, revision string) (string, []file, error) {
+	projectPath := "/projects/" + url.PathEscape(p.project)
+
+	commit := gitlabCommit{}
+	if _, err := p.get(ctx, projectPath+"/repository/commits/"+url.PathEscape(revision), nil, &commit); err != nil {
+		return "", nil, fmt.Errorf("unable to get commit for revision %s: %w", revision, err)
+	}
+
+	entries := make([]gitlabTreeEntry, 0)
+	for page := "1"; page != ""; {
+		query := url.Values{
+			"path":     []string{testmachinery.TestDefPath()},
+			"ref":      []string{commit.ID},
+			"per_page": []string{strconv.Itoa(gitlabPageSize)},
+			"page":     []string{page},
+		}
+		var pageEntries []gitlabTreeEntry
+		header, err := p.get(ctx, projectPath+"/repository/tree", query, &pageEntries)
+		if err != nil {
+			return "", nil, fmt.Errorf("no testdefinitions can be found: %w", err)
+		}
+		entries = append(entries, pageEntries...)
+		page = header.Get("X-Next-Page")
+	}
+
+	files := make([]file, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Type != gitlabTreeTypeBlob {
+			continue
+		}
+		p.log.V(5).Info("found file", "filename", entry.Name, "path", entry.Path)
+		var data []byte
+		filePath := projectPath + "/repository/files/" + url.PathEscape(path.Clean(entry.Path)) + "/raw"
+		if _, err := p.get(ctx, filePath, url.Values{"ref": []string{commit.ID}}, &data); err != nil {
+			return "", nil, fmt.Errorf("unable to download file %s: %w", entry.Path, err)
+		}
+		files = append(files, file{name: entry.Name, data: data})
+	}
+	return commit.ID, files, nil
+}
+
+// get sends a get request to the GitLab API and decodes the json response into the result.
+// A byte slice result is filled with the raw response.
+func (p *gitlabProvider) get(ctx context.Context, apiPath string, query url.Values, result interface{}) (http.Header, error) {
+	u := p.apiURL + apiPath
+	if len(query) != 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(p.token) != 0 {
+		req.Header.Set("PRIVATE-TOKEN", p.token)
+	}
+	res, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("gitlab responded with %d: %s", res.StatusCode, string(data))
+	}
+	if raw, ok := result.(*[]byte); ok {
+		*raw = data
+		return res.Header, nil
+	}
+	return res.Header, json.Unmarshal(data, result)
+}
@@ -0,0 +1,146 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package location
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/gardener/test-infra/pkg/apis/config"
+	"github.com/gardener/test-infra/pkg/testmachinery"
+)
+
+const testDefinitionFile = `apiVersion: testmachinery.sapcloud.io
+kind: TestDefinition
+metadata:
+  name: integration
+spec:
+  command: [bash, -c]
+  args: [echo test]
+`
+
+var _ = Describe("Git provider", func() {
+
+	BeforeEach(func() {
+		Expect(testmachinery.Setup(&config.Configuration{
+			TestMachinery: config.TestMachinery{
+				TestDefPath: ".test-defs",
+			},
+		})).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(testmachinery.Setup(&config.Configuration{})).To(Succeed())
+	})
+
+	Context("provider config", func() {
+		It("should default to github if no provider matches the domain", func() {
+			u, _ := url.Parse("https://github.com/gardener/test-infra.git")
+			cfg := getGitProviderConfig(u, []config.GitProvider{{Domain: "gitlab.com", Type: config.GitProviderGitLab}})
+			Expect(cfg.Type).To(Equal(config.GitProviderGitHub))
+		})
+
+		It("should choose the most specific domain", func() {
+			u, _ := url.Parse("https://git.internal.example.com/team/repo")
+			cfg := getGitProviderConfig(u, []config.GitProvider{
+				{Domain: "example.com", Type: config.GitProviderGitLab},
+				{Domain: "internal.example.com", Type: config.GitProviderGit},
+			})
+			Expect(cfg.Type).To(Equal(config.GitProviderGit))
+		})
+
+		It("should parse nested repository paths of gitlab repositories", func() {
+			u, _ := url.Parse("https://gitlab.com/group/subgroup/repo.git")
+			owner, name, err := parseRepoURL(u, config.GitProviderGitLab)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(owner).To(Equal("group/subgroup"))
+			Expect(name).To(Equal("repo"))
+		})
+	})
+
+	Context("gitlab", func() {
+		It("should read the TestDefinition files of a revision", func() {
+			var requestedPaths []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				defer GinkgoRecover()
+				requestedPaths = append(requestedPaths, r.URL.EscapedPath())
+				Expect(r.Header.Get("PRIVATE-TOKEN")).To(Equal("token"))
+				switch r.URL.EscapedPath() {
+				case "/api/v4/projects/group%2Frepo/repository/commits/master":
+					_ = json.NewEncoder(w).Encode(gitlabCommit{ID: "abc"})
+				case "/api/v4/projects/group%2Frepo/repository/tree":
+					Expect(r.URL.Query().Get("ref")).To(Equal("abc"))
+					Expect(r.URL.Query().Get("path")).To(Equal(".test-defs"))
+					if r.URL.Query().Get("page") == "1" {
+						w.Header().Set("X-Next-Page", "2")
+						_ = json.NewEncoder(w).Encode([]gitlabTreeEntry{{Name: "sub", Type: "tree", Path: ".test-defs/sub"}})
+						return
+					}
+					_ = json.NewEncoder(w).Encode([]gitlabTreeEntry{{Name: "test.yaml", Type: "blob", Path: ".test-defs/test.yaml"}})
+				case "/api/v4/projects/group%2Frepo/repository/files/.test-defs%2Ftest.yaml/raw":
+					_, _ = w.Write([]byte(testDefinitionFile))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+
+			u, _ := url.Parse("https://gitlab.example.com/group/repo.git")
+			p, err := newGitLabProvider(logr.Discard(), u, &testmachinery.GitHubInstanceConfig{
+				TechnicalUser: testmachinery.TechnicalUser{AuthToken: "token"},
+			}, server.URL+"/api/v4")
+			Expect(err).ToNot(HaveOccurred())
+
+			sha, files, err := p.GetTestDefinitionFiles(context.Background(), "master")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sha).To(Equal("abc"))
+			Expect(files).To(ConsistOf(file{name: "test.yaml", data: []byte(testDefinitionFile)}))
+			Expect(requestedPaths).To(HaveLen(4))
+		})
+	})
+
+	Context("plain git", func() {
+		It("should read the TestDefinition files of a revision", func() {
+			if _, err := exec.LookPath("git"); err != nil {
+				Skip("git is not installed")
+			}
+			repoDir := GinkgoT().TempDir()
+			git := func(args ...string) string {
+				cmd := exec.Command("git", args...)
+				cmd.Dir = repoDir
+				cmd.Env = append(os.Environ(),
+					"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+					"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com")
+				out, err := cmd.CombinedOutput()
+				Expect(err).ToNot(HaveOccurred(), string(out))
+				return string(out)
+			}
+			git("init", "-q", "-b", "main")
+			Expect(os.MkdirAll(filepath.Join(repoDir, ".test-defs", "sub"), 0750)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(repoDir, ".test-defs", "test.yaml"), []byte(testDefinitionFile), 0600)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(repoDir, ".test-defs", "sub", "other.yaml"), []byte("other"), 0600)).To(Succeed())
+			git("add", "-A")
+			git("commit", "-q", "-m", "init")
+			expectedSHA := git("rev-parse", "HEAD")
+
+			u, _ := url.Parse("file://" + repoDir)
+			p := newPlainGitProvider(logr.Discard(), u, nil)
+			sha, files, err := p.GetTestDefinitionFiles(context.Background(), "main")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sha + "\n").To(Equal(expectedSHA))
+			Expect(files).To(ConsistOf(file{name: "test.yaml", data: []byte(testDefinitionFile)}))
+		})
+	})
+})
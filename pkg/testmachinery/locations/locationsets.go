@@ -2,6 +2,7 @@ package locations
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
@@ -10,26 +11,27 @@ import (
 	"github.com/gardener/test-infra/pkg/testmachinery/testdefinition"
 )
 
-// NewLocations returns locations interface for a testrun
-func NewLocations(log logr.Logger, spec tmv1beta1.TestrunSpec) (Locations, error) {
+// NewLocations returns locations interface for a testrun.
+// Git locations whose revision has already been resolved are pinned to the resolved commit.
+func NewLocations(log logr.Logger, spec tmv1beta1.TestrunSpec, resolved []tmv1beta1.ResolvedLocation) (Locations, error) {
 	if spec.LocationSets != nil {
-		return NewSetLocations(log, spec.LocationSets)
+		return NewSetLocations(log, spec.LocationSets, resolved)
 	}
 
 	if len(spec.TestLocations) > 0 {
-		return NewTestLocations(log, spec.TestLocations)
+		return NewTestLocations(log, spec.TestLocations, resolved)
 	}
 
 	return nil, errors.New("no test locations defined")
 }
 
-func NewSetLocations(log logr.Logger, sets []tmv1beta1.LocationSet) (Locations, error) {
+func NewSetLocations(log logr.Logger, sets []tmv1beta1.LocationSet, resolved []tmv1beta1.ResolvedLocation) (Locations, error) {
 	locSets := &Sets{
 		Sets: make(map[string]*Set),
 	}
 	var firstSet *Set
 	for _, set := range sets {
-		testlocation, err := NewTestLocations(log, set.Locations)
+		testlocation, err := NewTestLocations(log, set.Locations, resolved)
 		if err != nil {
 			return nil, err
 		}
@@ -63,3 +65,20 @@ func (s *Sets) GetTestDefinitions(step tmv1beta1.StepDefinition) ([]*testdefinit
 
 	return s.Sets[*step.LocationSet].TestLocation.GetTestDefinitions(step)
 }
+
+// ResolvedLocations returns the resolved git locations of all sets.
+func (s *Sets) ResolvedLocations() []tmv1beta1.ResolvedLocation {
+	result := make([]tmv1beta1.ResolvedLocation, 0)
+	for _, set := range s.Sets {
+		for _, resolved := range set.TestLocation.ResolvedLocations() {
+			result = appendResolvedLocation(result, resolved)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Repo != result[j].Repo {
+			return result[i].Repo < result[j].Repo
+		}
+		return result[i].Revision < result[j].Revision
+	})
+	return result
+}
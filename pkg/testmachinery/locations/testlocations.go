@@ -18,8 +18,10 @@ import (
 )
 
 // NewTestLocations takes the parsed CRD Locations and fetches all TestDefintions from all locations.
-func NewTestLocations(log logr.Logger, testLocations []tmv1beta1.TestLocation) (Locations, error) {
+// Git locations whose revision has already been resolved are pinned to the resolved commit.
+func NewTestLocations(log logr.Logger, testLocations []tmv1beta1.TestLocation, resolved []tmv1beta1.ResolvedLocation) (Locations, error) {
 	testDefs := map[string]*testdefinition.TestDefinition{}
+	resolvedLocations := make([]tmv1beta1.ResolvedLocation, 0)
 
 	if len(testLocations) == 0 {
 		return nil, errors.New("no test locations defined")
@@ -39,11 +41,22 @@ func NewTestLocations(log logr.Logger, testLocations []tmv1beta1.TestLocation) (
 				locationLog.Error(err, "unable to create git testlocation")
 				continue
 			}
+			gitLoc := loc.(*location.GitLocation)
+			if commit := resolvedCommit(resolved, t); len(commit) != 0 {
+				gitLoc.Pin(commit)
+			}
 			err = loc.SetTestDefs(testDefs)
 			if err != nil {
 				locationLog.Info("unable to get testdefinitions", "error", err.Error())
 				continue
 			}
+			if len(gitLoc.Commit()) != 0 {
+				resolvedLocations = appendResolvedLocation(resolvedLocations, tmv1beta1.ResolvedLocation{
+					Repo:     t.Repo,
+					Revision: t.Revision,
+					Commit:   gitLoc.Commit(),
+				})
+			}
 		}
 		if testLocation.Type == tmv1beta1.LocationTypeLocal {
 			loc := location.NewLocalLocation(locationLog, &t)
@@ -53,7 +66,32 @@ func NewTestLocations(log logr.Logger, testLocations []tmv1beta1.TestLocation) (
 			}
 		}
 	}
-	return &testLocation{testLocations, testDefs}, nil
+	return &testLocation{testLocations, testDefs, resolvedLocations}, nil
+}
+
+// ResolvedLocations returns the resolved git locations.
+func (l *testLocation) ResolvedLocations() []tmv1beta1.ResolvedLocation {
+	return l.Resolved
+}
+
+// resolvedCommit returns the commit a location was already resolved to.
+func resolvedCommit(resolved []tmv1beta1.ResolvedLocation, loc tmv1beta1.TestLocation) string {
+	for _, r := range resolved {
+		if r.Repo == loc.Repo && r.Revision == loc.Revision {
+			return r.Commit
+		}
+	}
+	return ""
+}
+
+// appendResolvedLocation adds a resolved location if the same repository and revision is not yet part of the list.
+func appendResolvedLocation(list []tmv1beta1.ResolvedLocation, loc tmv1beta1.ResolvedLocation) []tmv1beta1.ResolvedLocation {
+	for _, r := range list {
+		if r.Repo == loc.Repo && r.Revision == loc.Revision {
+			return list
+		}
+	}
+	return append(list, loc)
 }
 
 // GetTestDefinitions returns all TestDefinitions of a StepDefinition with their location.GetTestDefinitions
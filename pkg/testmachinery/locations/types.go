@@ -8,6 +8,9 @@ import (
 // Locations is an interface which provides functions for receiving TestDefinitions that are fetched from testDefLocations.
 type Locations interface {
 	GetTestDefinitions(definition tmv1beta1.StepDefinition) ([]*testdefinition.TestDefinition, error)
+
+	// ResolvedLocations returns the commits the revisions of all git locations were resolved to.
+	ResolvedLocations() []tmv1beta1.ResolvedLocation
 }
 
 type Sets struct {
@@ -23,4 +26,5 @@ type Set struct {
 type testLocation struct {
 	Info            []tmv1beta1.TestLocation
 	TestDefinitions map[string]*testdefinition.TestDefinition
+	Resolved        []tmv1beta1.ResolvedLocation
 }
@@ -29,12 +29,29 @@ func (m *Metadata) CreateAnnotations() map[string]string {
 		common.AnnotationRetries:                strconv.Itoa(m.Retries),
 		common.AnnotationShootAnnotations:       util.MarshalMap(m.Annotations),
 	}
+	if m.FlavorSelectionStrategy != "" {
+		annotations[common.AnnotationFlavorSelectionStrategy] = m.FlavorSelectionStrategy
+		annotations[common.AnnotationFlavorSelectionSeed] = m.FlavorSelectionSeed
+	}
+	if m.FlavorSelectionDay != "" {
+		annotations[common.AnnotationFlavorSelectionDay] = m.FlavorSelectionDay
+	}
+	if m.KubernetesUpgradeFromVersion != "" {
+		annotations[common.AnnotationK8sUpgradeFromVersion] = m.KubernetesUpgradeFromVersion
+	}
+	if m.OperatingSystemUpgradeFromVersion != "" {
+		annotations[common.AnnotationOperatingSystemUpgradeFromVersion] = m.OperatingSystemUpgradeFromVersion
+	}
 	return annotations
 }
 
 // GetDimensionFromMetadata returns a string describing the dimension of the metadata
 func (m *Metadata) GetDimensionFromMetadata(sep string) string {
-	d := fmt.Sprintf("%s"+sep+"%s"+sep+"%s", m.CloudProvider, m.KubernetesVersion, m.OperatingSystem)
+	k8sVersion := m.KubernetesVersion
+	if m.KubernetesUpgradeFromVersion != "" {
+		k8sVersion = fmt.Sprintf("%s->%s", m.KubernetesUpgradeFromVersion, m.KubernetesVersion)
+	}
+	d := fmt.Sprintf("%s"+sep+"%s"+sep+"%s", m.CloudProvider, k8sVersion, m.OperatingSystem)
 	if m.FlavorDescription != "" {
 		d = fmt.Sprintf("%s"+sep+"%s", d, m.FlavorDescription)
 	}
@@ -57,22 +74,34 @@ func FromTestrun(tr *tmv1beta1.Testrun) *Metadata {
 		shootAnnotations["error"] = err.Error()
 	}
 	metadata := &Metadata{
-		Landscape:              tr.Annotations[common.AnnotationLandscape],
-		KubernetesVersion:      tr.Annotations[common.AnnotationK8sVersion],
-		CloudProvider:          tr.Annotations[common.AnnotationCloudProvider],
-		OperatingSystem:        tr.Annotations[common.AnnotationOperatingSystem],
-		OperatingSystemVersion: tr.Annotations[common.AnnotationOperatingSystemVersion],
-		ContainerRuntime:       tr.Annotations[common.AnnotationContainerRuntime],
-		Region:                 tr.Annotations[common.AnnotationRegion],
-		Zone:                   tr.Annotations[common.AnnotationZone],
-		FlavorDescription:      tr.Annotations[common.AnnotationFlavorDescription],
-		ShootAnnotations:       shootAnnotations,
-		Retries:                retries,
+		Landscape:                         tr.Annotations[common.AnnotationLandscape],
+		KubernetesVersion:                 tr.Annotations[common.AnnotationK8sVersion],
+		CloudProvider:                     tr.Annotations[common.AnnotationCloudProvider],
+		OperatingSystem:                   tr.Annotations[common.AnnotationOperatingSystem],
+		OperatingSystemVersion:            tr.Annotations[common.AnnotationOperatingSystemVersion],
+		ContainerRuntime:                  tr.Annotations[common.AnnotationContainerRuntime],
+		Region:                            tr.Annotations[common.AnnotationRegion],
+		Zone:                              tr.Annotations[common.AnnotationZone],
+		FlavorDescription:                 tr.Annotations[common.AnnotationFlavorDescription],
+		FlavorSelectionStrategy:           tr.Annotations[common.AnnotationFlavorSelectionStrategy],
+		FlavorSelectionSeed:               tr.Annotations[common.AnnotationFlavorSelectionSeed],
+		FlavorSelectionDay:                tr.Annotations[common.AnnotationFlavorSelectionDay],
+		KubernetesUpgradeFromVersion:      tr.Annotations[common.AnnotationK8sUpgradeFromVersion],
+		OperatingSystemUpgradeFromVersion: tr.Annotations[common.AnnotationOperatingSystemUpgradeFromVersion],
+		ShootAnnotations:                  shootAnnotations,
+		Retries:                           retries,
 		Testrun: TestrunMetadata{
 			ID:             tr.Name,
 			StartTime:      tr.Status.StartTime,
 			ExecutionGroup: tr.Labels[common.LabelTestrunExecutionGroup],
 		},
 	}
+	for _, loc := range tr.Status.ResolvedLocations {
+		metadata.Locations = append(metadata.Locations, LocationMetadata{
+			Repo:     loc.Repo,
+			Revision: loc.Revision,
+			Commit:   loc.Commit,
+		})
+	}
 	return metadata
 }
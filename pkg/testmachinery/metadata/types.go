@@ -19,12 +19,26 @@ type Metadata struct {
 	// Short description of the flavor
 	FlavorDescription string `json:"flavor_description,omitempty"`
 
+	// FlavorSelectionStrategy is the strategy that was used to select the flavor from all flavor combinations.
+	FlavorSelectionStrategy string `json:"flavor_selection_strategy,omitempty"`
+	// FlavorSelectionSeed is the seed that was used to select the flavor from all flavor combinations.
+	FlavorSelectionSeed string `json:"flavor_selection_seed,omitempty"`
+	// FlavorSelectionDay is the day (days since unix epoch) that was used by the rotating strategy to select the flavor.
+	FlavorSelectionDay string `json:"flavor_selection_day,omitempty"`
+
 	// Landscape describes the current dev,staging,canary,office or live.
 	Landscape         string `json:"landscape,omitempty"`
 	CloudProvider     string `json:"cloudprovider,omitempty"`
 	KubernetesVersion string `json:"k8s_version,omitempty"`
 	Region            string `json:"region,omitempty"`
 
+	// KubernetesUpgradeFromVersion is the kubernetes version the shoot is upgraded from.
+	// Is only set if the flavor describes an upgrade path.
+	KubernetesUpgradeFromVersion string `json:"k8s_upgrade_from_version,omitempty"`
+	// OperatingSystemUpgradeFromVersion is the operating system version the shoot nodes are upgraded from.
+	// Is only set if the flavor describes an upgrade path.
+	OperatingSystemUpgradeFromVersion string `json:"operating_system_upgrade_from_version,omitempty"`
+
 	// todo: schrodit - add support to better persist multiple worker pools with multiple oss, versions and zones
 	OperatingSystem        string            `json:"operating_system,omitempty"`
 	OperatingSystemVersion string            `json:"operating_system_version,omitempty"`
@@ -48,6 +62,9 @@ type Metadata struct {
 	// Represents how many retries the testrun had
 	Retries int `json:"retries,omitempty"`
 
+	// Locations contains the requested revisions of the git locations and the commits they were resolved to.
+	Locations []LocationMetadata `json:"locations,omitempty"`
+
 	// Contains the measured telemetry data
 	// Is only used for internal sharing.
 	TelemetryData *TelemetryData `json:"-"`
@@ -65,6 +82,13 @@ type TestrunMetadata struct {
 	StartTime *v1.Time `json:"startTime"`
 }
 
+// LocationMetadata describes a git location of a testrun.
+type LocationMetadata struct {
+	Repo     string `json:"repo"`
+	Revision string `json:"revision"`
+	Commit   string `json:"commit"`
+}
+
 // StepExportMetadata is the metadata of one step of a testrun.
 type StepExportMetadata struct {
 	StepSummaryMetadata
@@ -94,15 +118,18 @@ type StepSummaryMetadata struct {
 
 // StepSummary is the result of a specific step.
 type StepSummary struct {
-	Metadata    *StepSummaryMetadata `json:"tm,omitempty"`
-	Type        SummaryType          `json:"type,omitempty"`
-	Name        string               `json:"name,omitempty"`
-	StepName    string               `json:"stepName,omitempty"`
-	Labels      []string             `json:"labels,omitempty"`
-	Phase       v1alpha1.NodePhase   `json:"phase,omitempty"`
-	StartTime   *v1.Time             `json:"startTime,omitempty"`
-	Duration    int64                `json:"duration,omitempty"`
-	PreComputed *StepPreComputed     `json:"pre,omitempty"`
+	Metadata      *StepSummaryMetadata `json:"tm,omitempty"`
+	Type          SummaryType          `json:"type,omitempty"`
+	Name          string               `json:"name,omitempty"`
+	StepName      string               `json:"stepName,omitempty"`
+	Labels        []string             `json:"labels,omitempty"`
+	Owner         string               `json:"owner,omitempty"`
+	Recipients    []string             `json:"recipientsOnFailure,omitempty"`
+	Phase         v1alpha1.NodePhase   `json:"phase,omitempty"`
+	StartTime     *v1.Time             `json:"startTime,omitempty"`
+	Duration      int64                `json:"duration,omitempty"`
+	PreComputed   *StepPreComputed     `json:"pre,omitempty"`
+	TelemetryData *TelemetryData       `json:"telemetry,omitempty"`
 }
 
 // StepPreComputed contains fields that could be created at runtime via scripted fields, but are created statically for better performance and better support of grafana
@@ -0,0 +1,173 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/gardener/test-infra/pkg/apis/config"
+	tmv1beta1 "github.com/gardener/test-infra/pkg/apis/testmachinery/v1beta1"
+)
+
+// Result contains the violations of admission policies.
+type Result struct {
+	// Errors are the violations of policies that deny testruns.
+	Errors field.ErrorList
+	// Warnings are the violations of policies that only warn.
+	Warnings []string
+}
+
+// add adds a violation as error or warning depending on the enforcement of its policy.
+func (r *Result) add(enforcement config.PolicyEnforcement, err *field.Error) {
+	if enforcement == config.PolicyEnforcementWarn {
+		r.Warnings = append(r.Warnings, err.Error())
+		return
+	}
+	r.Errors = append(r.Errors, err)
+}
+
+// EvaluateTestrun evaluates the policies that only depend on the testrun itself.
+// Policies for TestDefinitions are evaluated with EvaluateTestDefinition once the locations of the testrun are resolved.
+func EvaluateTestrun(policies *config.AdmissionPolicies, tr *tmv1beta1.Testrun) Result {
+	result := Result{}
+	if policies == nil {
+		return result
+	}
+
+	if p := policies.Annotations; p != nil {
+		fldPath := field.NewPath("metadata", "annotations")
+		for _, key := range p.Required {
+			if len(tr.Annotations[key]) == 0 {
+				result.add(p.Enforcement, field.Required(fldPath.Key(key), "annotation is required"))
+			}
+		}
+	}
+
+	if p := policies.UntrustedSteps; p != nil {
+		if trusted := trustedKubeconfigs(tr); len(trusted) != 0 {
+			msg := fmt.Sprintf("untrusted steps are not allowed in testruns with the kubeconfigs %s", strings.Join(trusted, ", "))
+			evaluateUntrustedSteps(&result, p.Enforcement, field.NewPath("spec", "testflow"), tr.Spec.TestFlow, msg)
+			evaluateUntrustedSteps(&result, p.Enforcement, field.NewPath("spec", "onExit"), tr.Spec.OnExit, msg)
+		}
+	}
+
+	if p := policies.Locations; p != nil {
+		for i, set := range tr.Spec.LocationSets {
+			for j, loc := range set.Locations {
+				evaluateLocation(&result, p, field.NewPath("spec", "locationSets").Index(i).Child("locations").Index(j), loc)
+			}
+		}
+		for i, loc := range tr.Spec.TestLocations {
+			evaluateLocation(&result, p, field.NewPath("spec", "testLocations").Index(i), loc)
+		}
+	}
+
+	return result
+}
+
+// EvaluateTestDefinition evaluates the policies for a TestDefinition that is used by a testrun.
+func EvaluateTestDefinition(fldPath *field.Path, policies *config.AdmissionPolicies, td *tmv1beta1.TestDefinition) Result {
+	result := Result{}
+	if policies == nil {
+		return result
+	}
+
+	if p := policies.Images; p != nil && len(td.Spec.Image) != 0 {
+		if !imageAllowed(td.Spec.Image, p.AllowedRegistries) {
+			result.add(p.Enforcement, field.Invalid(fldPath.Child("spec", "image"), td.Spec.Image,
+				fmt.Sprintf("image has to be pulled from one of %s", strings.Join(p.AllowedRegistries, ", "))))
+		}
+	}
+
+	if p := policies.ActiveDeadline; p != nil && td.Spec.ActiveDeadlineSeconds != nil {
+		if seconds := int64(td.Spec.ActiveDeadlineSeconds.IntValue()); seconds > p.MaxSeconds {
+			result.add(p.Enforcement, field.Invalid(fldPath.Child("spec", "activeDeadlineSeconds"), seconds,
+				fmt.Sprintf("must not be greater than %d", p.MaxSeconds)))
+		}
+	}
+
+	return result
+}
+
+// trustedKubeconfigs returns the names of all kubeconfigs of the testrun that are only mounted into trusted steps.
+func trustedKubeconfigs(tr *tmv1beta1.Testrun) []string {
+	var names []string
+	if tr.Spec.Kubeconfigs.Host != nil {
+		names = append(names, "host")
+	}
+	if tr.Spec.Kubeconfigs.Gardener != nil {
+		names = append(names, "gardener")
+	}
+	if tr.Spec.Kubeconfigs.Seed != nil {
+		names = append(names, "seed")
+	}
+	if len(tr.Spec.Kubeconfigs.Additional) != 0 {
+		names = append(names, "additional")
+	}
+	return names
+}
+
+func evaluateUntrustedSteps(result *Result, enforcement config.PolicyEnforcement, fldPath *field.Path, flow tmv1beta1.TestFlow, msg string) {
+	for i, step := range flow {
+		if step.Definition.Untrusted {
+			result.add(enforcement, field.Forbidden(fldPath.Index(i).Child("definition", "untrusted"), msg))
+		}
+	}
+}
+
+func evaluateLocation(result *Result, p *config.LocationPolicy, fldPath *field.Path, loc tmv1beta1.TestLocation) {
+	if loc.Type != tmv1beta1.LocationTypeGit {
+		return
+	}
+	u, err := url.Parse(loc.Repo)
+	if err != nil || len(u.Hostname()) == 0 {
+		result.add(p.Enforcement, field.Invalid(fldPath.Child("repo"), loc.Repo, "unable to determine the domain of the repository"))
+		return
+	}
+	if !domainAllowed(u.Hostname(), p.AllowedDomains) {
+		result.add(p.Enforcement, field.Invalid(fldPath.Child("repo"), loc.Repo,
+			fmt.Sprintf("repository has to be hosted on one of %s", strings.Join(p.AllowedDomains, ", "))))
+	}
+}
+
+// domainAllowed checks whether a host is one of the allowed domains or one of their subdomains.
+func domainAllowed(host string, allowed []string) bool {
+	host = strings.ToLower(host)
+	for _, domain := range allowed {
+		domain = strings.ToLower(strings.TrimPrefix(domain, "."))
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// imageAllowed checks whether an image is pulled from one of the allowed registries or repository prefixes.
+func imageAllowed(image string, allowed []string) bool {
+	image = normalizeImage(image)
+	for _, prefix := range allowed {
+		prefix = strings.TrimSuffix(prefix, "/")
+		if image == prefix || strings.HasPrefix(image, prefix+"/") || strings.HasPrefix(image, prefix+":") || strings.HasPrefix(image, prefix+"@") {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeImage adds the implicit docker hub registry to images without a registry.
+func normalizeImage(image string) string {
+	parts := strings.SplitN(image, "/", 2)
+	if len(parts) == 2 && (strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		return image
+	}
+	if len(parts) == 1 {
+		return "docker.io/library/" + image
+	}
+	return "docker.io/" + image
+}
@@ -0,0 +1,180 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package policy_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/gardener/test-infra/pkg/apis/config"
+	tmv1beta1 "github.com/gardener/test-infra/pkg/apis/testmachinery/v1beta1"
+	"github.com/gardener/test-infra/pkg/testmachinery/policy"
+	"github.com/gardener/test-infra/pkg/util/strconf"
+)
+
+var _ = Describe("admission policies", func() {
+
+	Context("testrun", func() {
+		var tr *tmv1beta1.Testrun
+
+		BeforeEach(func() {
+			tr = &tmv1beta1.Testrun{
+				ObjectMeta: metav1.ObjectMeta{Name: "test"},
+				Spec: tmv1beta1.TestrunSpec{
+					LocationSets: []tmv1beta1.LocationSet{{
+						Name: "default",
+						Locations: []tmv1beta1.TestLocation{
+							{Type: tmv1beta1.LocationTypeGit, Repo: "https://github.com/gardener/test-infra.git", Revision: "master"},
+							{Type: tmv1beta1.LocationTypeGit, Repo: "https://example.com/org/repo.git", Revision: "master"},
+						},
+					}},
+					TestFlow: tmv1beta1.TestFlow{
+						{Name: "trusted", Definition: tmv1beta1.StepDefinition{Name: "a"}},
+						{Name: "untrusted", Definition: tmv1beta1.StepDefinition{Name: "b", Untrusted: true}},
+					},
+				},
+			}
+		})
+
+		It("should not evaluate undefined policies", func() {
+			Expect(policy.EvaluateTestrun(nil, tr)).To(Equal(policy.Result{}))
+			Expect(policy.EvaluateTestrun(&config.AdmissionPolicies{}, tr)).To(Equal(policy.Result{}))
+		})
+
+		It("should deny testruns without required annotations", func() {
+			tr.Annotations = map[string]string{"owner": "me", "team": ""}
+			result := policy.EvaluateTestrun(&config.AdmissionPolicies{
+				Annotations: &config.AnnotationPolicy{
+					Enforcement: config.PolicyEnforcementDeny,
+					Required:    []string{"owner", "team", "purpose"},
+				},
+			}, tr)
+			Expect(result.Warnings).To(BeEmpty())
+			Expect(result.Errors).To(ConsistOf(
+				HaveField("Field", "metadata.annotations[team]"),
+				HaveField("Field", "metadata.annotations[purpose]"),
+			))
+		})
+
+		It("should warn about missing annotations", func() {
+			result := policy.EvaluateTestrun(&config.AdmissionPolicies{
+				Annotations: &config.AnnotationPolicy{
+					Enforcement: config.PolicyEnforcementWarn,
+					Required:    []string{"owner"},
+				},
+			}, tr)
+			Expect(result.Errors).To(BeEmpty())
+			Expect(result.Warnings).To(ConsistOf("metadata.annotations[owner]: Required value: annotation is required"))
+		})
+
+		It("should allow untrusted steps if only the shoot kubeconfig is defined", func() {
+			tr.Spec.Kubeconfigs.Shoot = strconf.FromString("abc")
+			result := policy.EvaluateTestrun(&config.AdmissionPolicies{
+				UntrustedSteps: &config.UntrustedStepPolicy{Enforcement: config.PolicyEnforcementDeny},
+			}, tr)
+			Expect(result.Errors).To(BeEmpty())
+		})
+
+		It("should deny untrusted steps if trusted kubeconfigs are defined", func() {
+			tr.Spec.Kubeconfigs.Gardener = strconf.FromString("abc")
+			tr.Spec.OnExit = tmv1beta1.TestFlow{
+				{Name: "exit", Definition: tmv1beta1.StepDefinition{Name: "c", Untrusted: true}},
+			}
+			result := policy.EvaluateTestrun(&config.AdmissionPolicies{
+				UntrustedSteps: &config.UntrustedStepPolicy{Enforcement: config.PolicyEnforcementDeny},
+			}, tr)
+			Expect(result.Errors).To(ConsistOf(
+				And(HaveField("Type", field.ErrorTypeForbidden), HaveField("Field", "spec.testflow[1].definition.untrusted")),
+				HaveField("Field", "spec.onExit[0].definition.untrusted"),
+			))
+			Expect(result.Errors.ToAggregate().Error()).To(ContainSubstring("with the kubeconfigs gardener"))
+		})
+
+		It("should deny git locations of domains that are not allowed", func() {
+			tr.Spec.TestLocations = []tmv1beta1.TestLocation{
+				{Type: tmv1beta1.LocationTypeGit, Repo: "https://github.tools.example.com/org/repo.git"},
+				{Type: tmv1beta1.LocationTypeGit, Repo: "https://evilgithub.com/org/repo.git"},
+				{Type: tmv1beta1.LocationTypeGit, Repo: "https://api.GitHub.com/org/repo.git"},
+				{Type: tmv1beta1.LocationTypeLocal, HostPath: "/tmp"},
+			}
+			result := policy.EvaluateTestrun(&config.AdmissionPolicies{
+				Locations: &config.LocationPolicy{
+					Enforcement:    config.PolicyEnforcementDeny,
+					AllowedDomains: []string{"github.com"},
+				},
+			}, tr)
+			Expect(result.Errors).To(ConsistOf(
+				HaveField("Field", "spec.locationSets[0].locations[1].repo"),
+				HaveField("Field", "spec.testLocations[0].repo"),
+				HaveField("Field", "spec.testLocations[1].repo"),
+			))
+		})
+	})
+
+	Context("testdefinition", func() {
+		var (
+			td      *tmv1beta1.TestDefinition
+			fldPath = field.NewPath("spec", "testflow").Index(0).Child("definition").Key("td")
+		)
+
+		BeforeEach(func() {
+			deadline := intstr.FromInt32(3600)
+			td = &tmv1beta1.TestDefinition{
+				ObjectMeta: metav1.ObjectMeta{Name: "td"},
+				Spec: tmv1beta1.TestDefSpec{
+					Image:                 "europe-docker.pkg.dev/gardener-project/releases/testmachinery/base-step:1.0.0",
+					ActiveDeadlineSeconds: &deadline,
+				},
+			}
+		})
+
+		DescribeTable("images",
+			func(image string, allowed []string, match bool) {
+				td.Spec.Image = image
+				result := policy.EvaluateTestDefinition(fldPath, &config.AdmissionPolicies{
+					Images: &config.ImagePolicy{Enforcement: config.PolicyEnforcementDeny, AllowedRegistries: allowed},
+				}, td)
+				if match {
+					Expect(result.Errors).To(BeEmpty())
+					return
+				}
+				Expect(result.Errors).To(ConsistOf(HaveField("Field", "spec.testflow[0].definition[td].spec.image")))
+			},
+			Entry("registry", "europe-docker.pkg.dev/gardener-project/test:1.0.0", []string{"europe-docker.pkg.dev"}, true),
+			Entry("repository prefix", "europe-docker.pkg.dev/gardener-project/test:1.0.0", []string{"europe-docker.pkg.dev/gardener-project/"}, true),
+			Entry("repository", "europe-docker.pkg.dev/gardener-project/test:1.0.0", []string{"europe-docker.pkg.dev/gardener-project/test"}, true),
+			Entry("partial repository name", "europe-docker.pkg.dev/gardener-project-fork/test", []string{"europe-docker.pkg.dev/gardener-project"}, false),
+			Entry("other registry", "eu.gcr.io/test:1.0.0", []string{"europe-docker.pkg.dev"}, false),
+			Entry("docker hub library image", "golang:1.22", []string{"docker.io/library"}, true),
+			Entry("docker hub image", "gardener/test", []string{"docker.io/library"}, false),
+			Entry("no image", "", []string{"europe-docker.pkg.dev"}, true),
+		)
+
+		It("should deny activeDeadlineSeconds greater than the maximum", func() {
+			result := policy.EvaluateTestDefinition(fldPath, &config.AdmissionPolicies{
+				ActiveDeadline: &config.ActiveDeadlinePolicy{Enforcement: config.PolicyEnforcementDeny, MaxSeconds: 1800},
+			}, td)
+			Expect(result.Errors).To(ConsistOf(HaveField("Field", "spec.testflow[0].definition[td].spec.activeDeadlineSeconds")))
+		})
+
+		It("should warn about activeDeadlineSeconds greater than the maximum", func() {
+			result := policy.EvaluateTestDefinition(fldPath, &config.AdmissionPolicies{
+				ActiveDeadline: &config.ActiveDeadlinePolicy{Enforcement: config.PolicyEnforcementWarn, MaxSeconds: 1800},
+			}, td)
+			Expect(result.Errors).To(BeEmpty())
+			Expect(result.Warnings).To(ConsistOf(ContainSubstring("must not be greater than 1800")))
+		})
+
+		It("should allow activeDeadlineSeconds up to the maximum", func() {
+			result := policy.EvaluateTestDefinition(fldPath, &config.AdmissionPolicies{
+				ActiveDeadline: &config.ActiveDeadlinePolicy{Enforcement: config.PolicyEnforcementDeny, MaxSeconds: 3600},
+			}, td)
+			Expect(result.Errors).To(BeEmpty())
+		})
+	})
+})
@@ -14,14 +14,18 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/gardener/test-infra/pkg/apis/config"
 	tmv1beta1 "github.com/gardener/test-infra/pkg/apis/testmachinery/v1beta1"
 	"github.com/gardener/test-infra/pkg/common"
 	"github.com/gardener/test-infra/pkg/testmachinery"
 	"github.com/gardener/test-infra/pkg/testmachinery/locations/location"
+	"github.com/gardener/test-infra/pkg/testmachinery/prepare/repocache"
 	"github.com/gardener/test-infra/pkg/testmachinery/testdefinition"
 	"github.com/gardener/test-infra/pkg/util"
 )
 
+const repositoryCacheVolumeName = "repo-cache"
+
 // New creates the TM prepare step
 // The step clones all needed github config and outputs these repos as argo artifacts with the name "repoOwner-repoName-revision".
 func New(name string, addGlobalInput, addGlobalOutput bool) (*Definition, error) {
@@ -95,7 +99,13 @@ func (p *Definition) AddLocation(loc testdefinition.Location) {
 		return
 	}
 	gitLoc := loc.(*location.GitLocation)
-	p.config.Repositories[loc.Name()] = &Repository{Name: loc.Name(), URL: gitLoc.Info.Repo, Revision: gitLoc.Info.Revision}
+	p.config.Repositories[loc.Name()] = &Repository{
+		Name:     loc.Name(),
+		URL:      gitLoc.Info.Repo,
+		Revision: gitLoc.Info.Revision,
+		Provider: string(gitLoc.Provider()),
+		Commit:   gitLoc.Commit(),
+	}
 
 	p.TestDefinition.AddOutputArtifacts(argov1.Artifact{
 		Name:       loc.Name(),
@@ -106,6 +116,9 @@ func (p *Definition) AddLocation(loc testdefinition.Location) {
 
 // AddRepositoriesAsArtifacts adds all git config to be cloned as json array to the prepare step.
 func (p *Definition) AddRepositoriesAsArtifacts() error {
+	if len(p.config.Repositories) != 0 {
+		p.addRepositoryCache(testmachinery.RepositoryCache())
+	}
 	repoJSON, err := json.Marshal(p.config)
 	if err != nil {
 		return fmt.Errorf("cannot add config to prepare step: %s", err.Error())
@@ -123,6 +136,53 @@ func (p *Definition) AddRepositoriesAsArtifacts() error {
 	return nil
 }
 
+// addRepositoryCache configures the prepare step to check out the repositories from the shared repository cache.
+func (p *Definition) addRepositoryCache(cache *config.RepositoryCache) {
+	if cache == nil || p.config.RepositoryCache != nil {
+		return
+	}
+	p.config.RepositoryCache = &repocache.Config{
+		Dir:          testmachinery.TM_REPO_CACHE_PATH,
+		MaxAge:       cache.MaxAge,
+		MaxSizeBytes: int64(cache.MaxSizeGB) << 30,
+		LockTimeout:  cache.LockTimeout,
+	}
+
+	if cache.PVC != nil {
+		p.TestDefinition.AddVolume(corev1.Volume{
+			Name: repositoryCacheVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: cache.PVC.ClaimName},
+			},
+		})
+		p.TestDefinition.AddVolumeMount(repositoryCacheVolumeName, testmachinery.TM_REPO_CACHE_PATH, "", false)
+		return
+	}
+
+	p.config.RepositoryCache.S3 = &repocache.S3Config{
+		Endpoint:   cache.S3.Server.Endpoint,
+		SSL:        cache.S3.Server.SSL,
+		BucketName: cache.S3.BucketName,
+		Prefix:     cache.S3.Prefix,
+	}
+	p.TestDefinition.AddEnvVars(
+		secretKeyEnvVar(repocache.AccessKeyEnv, cache.S3.SecretName, "accessKey"),
+		secretKeyEnvVar(repocache.SecretKeyEnv, cache.S3.SecretName, "secretKey"),
+	)
+}
+
+func secretKeyEnvVar(name, secretName, key string) corev1.EnvVar {
+	return corev1.EnvVar{
+		Name: name,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+				Key:                  key,
+			},
+		},
+	}
+}
+
 func (p *Definition) addNetrcFile() error {
 	netrc := ""
 
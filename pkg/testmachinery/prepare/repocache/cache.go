@@ -0,0 +1,266 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package repocache
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+
+	"github.com/gardener/test-infra/pkg/util/s3"
+)
+
+// Cache is a shared cache of bare git mirrors.
+// Repositories are checked out from their mirror which is updated incrementally from the remote.
+type Cache struct {
+	log     logr.Logger
+	config  Config
+	backend backend
+}
+
+// New creates a new repository cache.
+func New(log logr.Logger, config Config) (*Cache, error) {
+	if err := os.MkdirAll(config.Dir, 0750); err != nil {
+		return nil, errors.Wrapf(err, "unable to create cache directory %s", config.Dir)
+	}
+	if config.S3 == nil {
+		return newCache(log, config, newPVCBackend(log, config)), nil
+	}
+
+	client, err := s3.New(&s3.Config{
+		Endpoint:   config.S3.Endpoint,
+		SSL:        config.S3.SSL,
+		BucketName: config.S3.BucketName,
+		AccessKey:  os.Getenv(AccessKeyEnv),
+		SecretKey:  os.Getenv(SecretKeyEnv),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return newCache(log, config, newS3Backend(log, client, config)), nil
+}
+
+func newCache(log logr.Logger, config Config, backend backend) *Cache {
+	return &Cache{
+		log:     log,
+		config:  config,
+		backend: backend,
+	}
+}
+
+// Checkout checks out the revision of a repository into the directory.
+// The mirror of the repository is created or updated from the remote before.
+// A corrupted mirror is recreated once.
+// An error is returned if the cache cannot be used so that the repository has to be cloned directly from the remote.
+func (c *Cache) Checkout(ctx context.Context, repoURL, revision, dir string) error {
+	key := mirrorKey(repoURL)
+	log := c.log.WithValues("repo", repoURL, "mirror", key)
+
+	unlock, readOnly, err := c.backend.lock(ctx, key)
+	if err != nil {
+		return errors.Wrapf(err, "unable to lock mirror of %s", repoURL)
+	}
+	defer unlock()
+
+	mirrorDir := filepath.Join(c.config.Dir, key+".git")
+	lastUsed, err := c.backend.restore(ctx, key, mirrorDir)
+	if err != nil {
+		log.Info("unable to restore mirror", "error", err.Error())
+		lastUsed = time.Time{}
+	}
+
+	sha, changed, err := syncMirror(ctx, mirrorDir, repoURL, revision, !lastUsed.IsZero())
+	if err != nil && !lastUsed.IsZero() {
+		if verifyErr := verifyMirror(ctx, mirrorDir); verifyErr == nil {
+			// the mirror is intact so the remote is most likely not reachable
+			return err
+		}
+		log.Info("mirror is corrupted, recreating it", "error", err.Error())
+		sha, changed, err = syncMirror(ctx, mirrorDir, repoURL, revision, false)
+	}
+	if err != nil {
+		_ = os.RemoveAll(mirrorDir)
+		return err
+	}
+
+	if err := checkout(ctx, mirrorDir, sha, dir); err != nil {
+		_ = os.RemoveAll(mirrorDir)
+		return err
+	}
+	log.Info("checked out repository from cache", "revision", revision, "sha", sha)
+
+	if readOnly {
+		return nil
+	}
+	if err := c.backend.save(ctx, key, mirrorDir, changed, lastUsed); err != nil {
+		log.Info("unable to save mirror", "error", err.Error())
+	}
+	return nil
+}
+
+// Evict removes all mirrors that have not been used within the maximum age
+// and the least recently used mirrors if the maximum size of the cache is exceeded.
+func (c *Cache) Evict(ctx context.Context) error {
+	return c.backend.evict(ctx)
+}
+
+// mirrorKey returns the key of the mirror of a repository.
+// Urls that only differ in a trailing slash or the .git suffix share the same mirror.
+func mirrorKey(repoURL string) string {
+	normalized := strings.TrimSuffix(strings.TrimSuffix(repoURL, "/"), ".git")
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])[:20]
+}
+
+// syncMirror creates or updates the bare mirror and returns the commit sha of the revision.
+// Revisions that are not a branch or tag (e.g. pull request refs) are fetched separately and kept in the mirror.
+func syncMirror(ctx context.Context, mirrorDir, repoURL, revision string, exists bool) (string, bool, error) {
+	if !exists {
+		if err := os.RemoveAll(mirrorDir); err != nil {
+			return "", false, err
+		}
+		if _, err := runGit(ctx, "", "init", "-q", "--bare", mirrorDir); err != nil {
+			return "", false, err
+		}
+	}
+	if err := configureRemote(ctx, mirrorDir, repoURL); err != nil {
+		return "", false, err
+	}
+
+	before, err := runGit(ctx, "", "--git-dir="+mirrorDir, "for-each-ref")
+	if err != nil {
+		return "", false, err
+	}
+	if _, err := runGit(ctx, "", "--git-dir="+mirrorDir, "fetch", "-q", "--prune", "origin"); err != nil {
+		return "", false, err
+	}
+	out, err := runGit(ctx, "", "--git-dir="+mirrorDir, "rev-parse", "--verify", "-q", revision+"^{commit}")
+	if err != nil {
+		if _, err := runGit(ctx, "", "--git-dir="+mirrorDir, "fetch", "-q", "origin", revision); err != nil {
+			return "", false, errors.Wrapf(err, "unable to fetch revision %s", revision)
+		}
+		out, err = runGit(ctx, "", "--git-dir="+mirrorDir, "rev-parse", "--verify", "FETCH_HEAD^{commit}")
+		if err != nil {
+			return "", false, err
+		}
+		sha := strings.TrimSpace(string(out))
+		if _, err := runGit(ctx, "", "--git-dir="+mirrorDir, "update-ref", "refs/tm/"+sha, sha); err != nil {
+			return "", false, err
+		}
+	}
+	after, err := runGit(ctx, "", "--git-dir="+mirrorDir, "for-each-ref")
+	if err != nil {
+		return "", false, err
+	}
+	return strings.TrimSpace(string(out)), !bytes.Equal(before, after), nil
+}
+
+// verifyMirror checks that all objects of the mirror's refs are available.
+func verifyMirror(ctx context.Context, mirrorDir string) error {
+	_, err := runGit(ctx, "", "--git-dir="+mirrorDir, "fsck", "--connectivity-only", "--no-dangling", "--no-progress")
+	return err
+}
+
+// configureRemote points the origin of the mirror to the repository.
+// Only branches and tags are mirrored to keep the mirror small.
+func configureRemote(ctx context.Context, mirrorDir, repoURL string) error {
+	if _, err := runGit(ctx, "", "--git-dir="+mirrorDir, "config", "remote.origin.url", repoURL); err != nil {
+		return err
+	}
+	if _, err := runGit(ctx, "", "--git-dir="+mirrorDir, "config", "--replace-all", "remote.origin.fetch", "+refs/heads/*:refs/heads/*"); err != nil {
+		return err
+	}
+	_, err := runGit(ctx, "", "--git-dir="+mirrorDir, "config", "--add", "remote.origin.fetch", "+refs/tags/*:refs/tags/*")
+	return err
+}
+
+// checkout clones the mirror into the directory and checks out the commit.
+func checkout(ctx context.Context, mirrorDir, sha, dir string) error {
+	if _, err := runGit(ctx, "", "clone", "-q", "--no-checkout", mirrorDir, dir); err != nil {
+		_ = os.RemoveAll(dir)
+		return err
+	}
+	if _, err := runGit(ctx, dir, "checkout", "-q", "--detach", sha); err != nil {
+		_ = os.RemoveAll(dir)
+		return err
+	}
+	return nil
+}
+
+// runGit runs a git command in the given directory and returns its output.
+func runGit(ctx context.Context, dir string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+// selectEvictions returns the mirrors that have not been used within the maximum age
+// and the least recently used mirrors that exceed the maximum size.
+func selectEvictions(mirrors []mirror, maxAge time.Duration, maxSize int64, now time.Time) []mirror {
+	sorted := make([]mirror, len(mirrors))
+	copy(sorted, mirrors)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].lastUsed.Before(sorted[j].lastUsed)
+	})
+
+	var total int64
+	for _, m := range sorted {
+		total += m.size
+	}
+	evictions := make([]mirror, 0)
+	for _, m := range sorted {
+		expired := maxAge > 0 && now.Sub(m.lastUsed) > maxAge
+		oversized := maxSize > 0 && total > maxSize
+		if !expired && !oversized {
+			continue
+		}
+		evictions = append(evictions, m)
+		total -= m.size
+	}
+	return evictions
+}
+
+// heartbeat calls refresh in the given interval until the returned stop function is called.
+func heartbeat(interval time.Duration, refresh func()) func() {
+	if interval < time.Second {
+		interval = time.Second
+	}
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				refresh()
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() { close(done) })
+	}
+}
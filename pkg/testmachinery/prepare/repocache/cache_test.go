@@ -0,0 +1,295 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package repocache
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/minio/minio-go"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/gardener/test-infra/pkg/util/s3"
+)
+
+var _ = Describe("Repository cache", func() {
+	var (
+		ctx      context.Context
+		remote   string
+		cacheDir string
+		config   Config
+	)
+
+	git := func(dir string, args ...string) string {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com")
+		out, err := cmd.CombinedOutput()
+		Expect(err).ToNot(HaveOccurred(), string(out))
+		return string(bytes.TrimSpace(out))
+	}
+	commit := func(file, content string) string {
+		Expect(os.WriteFile(filepath.Join(remote, file), []byte(content), 0600)).To(Succeed())
+		git(remote, "add", "-A")
+		git(remote, "commit", "-q", "-m", file)
+		return git(remote, "rev-parse", "HEAD")
+	}
+	readFile := func(dir, file string) string {
+		data, err := os.ReadFile(filepath.Join(dir, file))
+		Expect(err).ToNot(HaveOccurred())
+		return string(data)
+	}
+
+	BeforeEach(func() {
+		if _, err := exec.LookPath("git"); err != nil {
+			Skip("git is not installed")
+		}
+		ctx = context.Background()
+		remote = GinkgoT().TempDir()
+		cacheDir = GinkgoT().TempDir()
+		git(remote, "init", "-q", "-b", "main")
+		commit("file", "v1")
+		config = Config{
+			Dir:         cacheDir,
+			MaxAge:      metav1.Duration{Duration: time.Hour},
+			LockTimeout: metav1.Duration{Duration: time.Minute},
+		}
+	})
+
+	Context("pvc", func() {
+		It("should check out a branch and update the mirror incrementally", func() {
+			cache := newCache(logr.Discard(), config, newPVCBackend(logr.Discard(), config))
+
+			dir := filepath.Join(GinkgoT().TempDir(), "repo")
+			Expect(cache.Checkout(ctx, remote, "main", dir)).To(Succeed())
+			Expect(readFile(dir, "file")).To(Equal("v1"))
+			Expect(filepath.Join(cacheDir, mirrorKey(remote)+".git")).To(BeADirectory())
+
+			commit("file", "v2")
+			dir = filepath.Join(GinkgoT().TempDir(), "repo")
+			Expect(cache.Checkout(ctx, remote, "main", dir)).To(Succeed())
+			Expect(readFile(dir, "file")).To(Equal("v2"))
+		})
+
+		It("should check out a commit that is not part of a branch", func() {
+			sha := commit("file", "detached")
+			git(remote, "reset", "-q", "--hard", "HEAD~1")
+			git(remote, "config", "uploadpack.allowAnySHA1InWant", "true")
+			cache := newCache(logr.Discard(), config, newPVCBackend(logr.Discard(), config))
+
+			dir := filepath.Join(GinkgoT().TempDir(), "repo")
+			Expect(cache.Checkout(ctx, remote, sha, dir)).To(Succeed())
+			Expect(readFile(dir, "file")).To(Equal("detached"))
+		})
+
+		It("should recreate a corrupted mirror", func() {
+			cache := newCache(logr.Discard(), config, newPVCBackend(logr.Discard(), config))
+			Expect(cache.Checkout(ctx, remote, "main", filepath.Join(GinkgoT().TempDir(), "repo"))).To(Succeed())
+
+			mirrorDir := filepath.Join(cacheDir, mirrorKey(remote)+".git")
+			Expect(os.RemoveAll(filepath.Join(mirrorDir, "objects"))).To(Succeed())
+			Expect(os.MkdirAll(filepath.Join(mirrorDir, "objects"), 0750)).To(Succeed())
+			commit("file", "v2")
+
+			dir := filepath.Join(GinkgoT().TempDir(), "repo")
+			Expect(cache.Checkout(ctx, remote, "main", dir)).To(Succeed())
+			Expect(readFile(dir, "file")).To(Equal("v2"))
+		})
+
+		It("should time out if the mirror is locked by another prepare step", func() {
+			config.LockTimeout = metav1.Duration{Duration: 100 * time.Millisecond}
+			backend := newPVCBackend(logr.Discard(), config)
+			backend.pollInterval = 10 * time.Millisecond
+
+			// keep the lock fresh so that it is not removed as stale lock
+			lockFile := backend.lockFile(mirrorKey(remote))
+			Expect(os.WriteFile(lockFile, []byte("other"), 0600)).To(Succeed())
+			stop := heartbeat(time.Millisecond, func() {
+				now := time.Now().Add(time.Hour)
+				_ = os.Chtimes(lockFile, now, now)
+			})
+			defer stop()
+
+			cache := newCache(logr.Discard(), config, backend)
+			err := cache.Checkout(ctx, remote, "main", filepath.Join(GinkgoT().TempDir(), "repo"))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("timed out waiting for lock"))
+		})
+
+		It("should remove stale locks", func() {
+			backend := newPVCBackend(logr.Discard(), config)
+			backend.pollInterval = 10 * time.Millisecond
+			lockFile := backend.lockFile(mirrorKey(remote))
+			Expect(os.WriteFile(lockFile, []byte("other"), 0600)).To(Succeed())
+			old := time.Now().Add(-2 * time.Minute)
+			Expect(os.Chtimes(lockFile, old, old)).To(Succeed())
+
+			cache := newCache(logr.Discard(), config, backend)
+			Expect(cache.Checkout(ctx, remote, "main", filepath.Join(GinkgoT().TempDir(), "repo"))).To(Succeed())
+			Expect(lockFile).ToNot(BeAnExistingFile())
+		})
+
+		It("should evict mirrors that have not been used within the maximum age", func() {
+			backend := newPVCBackend(logr.Discard(), config)
+			cache := newCache(logr.Discard(), config, backend)
+			Expect(cache.Checkout(ctx, remote, "main", filepath.Join(GinkgoT().TempDir(), "repo"))).To(Succeed())
+
+			mirrorDir := filepath.Join(cacheDir, mirrorKey(remote)+".git")
+			Expect(cache.Evict(ctx)).To(Succeed())
+			Expect(mirrorDir).To(BeADirectory())
+
+			old := time.Now().Add(-2 * time.Hour)
+			Expect(os.Chtimes(mirrorDir, old, old)).To(Succeed())
+			Expect(cache.Evict(ctx)).To(Succeed())
+			Expect(mirrorDir).ToNot(BeAnExistingFile())
+		})
+	})
+
+	Context("s3", func() {
+		It("should save the mirror as bundle and restore it", func() {
+			config.S3 = &S3Config{Prefix: "cache"}
+			client := newFakeS3Client()
+			cache := newCache(logr.Discard(), config, newS3Backend(logr.Discard(), client, config))
+
+			Expect(cache.Checkout(ctx, remote, "main", filepath.Join(GinkgoT().TempDir(), "repo"))).To(Succeed())
+			bundleKey := "cache/" + mirrorKey(remote) + ".bundle"
+			Expect(client.objects).To(HaveKey(bundleKey))
+			Expect(client.streams).To(Equal(1), "the bundle should be streamed from disk")
+			Expect(client.objects).ToNot(HaveKey("cache/" + mirrorKey(remote) + ".lock"))
+
+			// a prepare step on another node starts with an empty local directory
+			config.Dir = GinkgoT().TempDir()
+			cache = newCache(logr.Discard(), config, newS3Backend(logr.Discard(), client, config))
+			client.puts = 0
+			dir := filepath.Join(GinkgoT().TempDir(), "repo")
+			Expect(cache.Checkout(ctx, remote, "main", dir)).To(Succeed())
+			Expect(readFile(dir, "file")).To(Equal("v1"))
+			Expect(client.puts).To(Equal(1), "only the lock should be written for an unchanged mirror")
+		})
+
+		It("should not save a mirror that is locked by another prepare step", func() {
+			config.S3 = &S3Config{Prefix: "cache"}
+			client := newFakeS3Client()
+			Expect(client.PutObject("", "cache/"+mirrorKey(remote)+".lock", []byte("other"))).To(Succeed())
+			cache := newCache(logr.Discard(), config, newS3Backend(logr.Discard(), client, config))
+
+			Expect(cache.Checkout(ctx, remote, "main", filepath.Join(GinkgoT().TempDir(), "repo"))).To(Succeed())
+			Expect(client.objects).ToNot(HaveKey("cache/" + mirrorKey(remote) + ".bundle"))
+		})
+	})
+
+	Context("eviction", func() {
+		It("should select expired and least recently used mirrors", func() {
+			now := time.Now()
+			mirrors := []mirror{
+				{key: "new", lastUsed: now.Add(-time.Minute), size: 10},
+				{key: "expired", lastUsed: now.Add(-2 * time.Hour), size: 10},
+				{key: "old", lastUsed: now.Add(-30 * time.Minute), size: 10},
+				{key: "newest", lastUsed: now, size: 10},
+			}
+			evictions := selectEvictions(mirrors, time.Hour, 20, now)
+			Expect(evictions).To(HaveLen(2))
+			Expect(evictions[0].key).To(Equal("expired"))
+			Expect(evictions[1].key).To(Equal("old"))
+		})
+
+		It("should not evict mirrors without limits", func() {
+			mirrors := []mirror{{key: "a", lastUsed: time.Now().Add(-time.Hour), size: 10}}
+			Expect(selectEvictions(mirrors, 0, 0, time.Now())).To(BeEmpty())
+		})
+	})
+
+	It("should use the same mirror for equivalent urls", func() {
+		Expect(mirrorKey("https://github.com/gardener/test-infra.git")).To(Equal(mirrorKey("https://github.com/gardener/test-infra")))
+		Expect(mirrorKey("https://github.com/gardener/test-infra/")).To(Equal(mirrorKey("https://github.com/gardener/test-infra")))
+		Expect(mirrorKey("https://github.com/gardener/gardener")).ToNot(Equal(mirrorKey("https://github.com/gardener/test-infra")))
+	})
+})
+
+type fakeS3Client struct {
+	mux     sync.Mutex
+	objects map[string]fakeS3Object
+	puts    int
+	streams int
+}
+
+var _ s3.Client = &fakeS3Client{}
+
+type fakeS3Object struct {
+	*bytes.Reader
+	info minio.ObjectInfo
+	err  error
+}
+
+func (o fakeS3Object) Stat() (minio.ObjectInfo, error) { return o.info, o.err }
+func (o fakeS3Object) Close() error                    { return nil }
+
+func newFakeS3Client() *fakeS3Client {
+	return &fakeS3Client{objects: make(map[string]fakeS3Object)}
+}
+
+func (c *fakeS3Client) GetObject(_, key string) (s3.Object, error) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	obj, ok := c.objects[key]
+	if !ok {
+		return fakeS3Object{Reader: bytes.NewReader(nil), err: minio.ErrorResponse{Code: "NoSuchKey"}}, nil
+	}
+	data := make([]byte, obj.Len())
+	_, _ = obj.ReadAt(data, 0)
+	return fakeS3Object{Reader: bytes.NewReader(data), info: obj.info}, nil
+}
+
+func (c *fakeS3Client) ListObjects(_, prefix string) ([]string, error) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	keys := make([]string, 0)
+	for key := range c.objects {
+		if len(key) >= len(prefix) && key[:len(prefix)] == prefix {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+func (c *fakeS3Client) PutObject(_, key string, data []byte) error {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.puts++
+	c.objects[key] = fakeS3Object{
+		Reader: bytes.NewReader(data),
+		info:   minio.ObjectInfo{Key: key, Size: int64(len(data)), LastModified: time.Now()},
+	}
+	return nil
+}
+
+func (c *fakeS3Client) PutObjectStream(bucket, key string, reader io.Reader, _ int64) error {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+	c.mux.Lock()
+	c.streams++
+	c.mux.Unlock()
+	return c.PutObject(bucket, key, data)
+}
+
+func (c *fakeS3Client) RemoveObject(_, key string) error {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	delete(c.objects, key)
+	return nil
+}
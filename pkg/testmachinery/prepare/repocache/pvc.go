@@ -0,0 +1,177 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package repocache
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+const lockPollInterval = time.Second
+
+// pvcBackend stores the mirrors in a directory that is shared between all prepare steps.
+// Mirrors are locked with a lock file next to the mirror that is refreshed while the lock is held.
+type pvcBackend struct {
+	log          logr.Logger
+	dir          string
+	maxAge       time.Duration
+	maxSize      int64
+	lockTimeout  time.Duration
+	pollInterval time.Duration
+}
+
+func newPVCBackend(log logr.Logger, config Config) *pvcBackend {
+	return &pvcBackend{
+		log:          log,
+		dir:          config.Dir,
+		maxAge:       config.MaxAge.Duration,
+		maxSize:      config.MaxSizeBytes,
+		lockTimeout:  config.LockTimeout.Duration,
+		pollInterval: lockPollInterval,
+	}
+}
+
+func (b *pvcBackend) lock(ctx context.Context, key string) (func(), bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, b.lockTimeout)
+	defer cancel()
+	lockFile := b.lockFile(key)
+	for {
+		locked, err := b.tryLock(lockFile)
+		if err != nil {
+			return nil, false, err
+		}
+		if locked {
+			stop := heartbeat(b.lockTimeout/3, func() {
+				now := time.Now()
+				_ = os.Chtimes(lockFile, now, now)
+			})
+			return func() {
+				stop()
+				_ = os.Remove(lockFile)
+			}, false, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, false, fmt.Errorf("timed out waiting for lock %s", lockFile)
+		case <-time.After(b.pollInterval):
+		}
+	}
+}
+
+// tryLock creates the lock file if it does not exist.
+// A lock that has not been refreshed within the lock timeout belongs to a prepare step that was terminated and is removed.
+func (b *pvcBackend) tryLock(lockFile string) (bool, error) {
+	f, err := os.OpenFile(lockFile, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err == nil {
+		hostname, _ := os.Hostname()
+		_, _ = f.WriteString(hostname)
+		return true, f.Close()
+	}
+	if !os.IsExist(err) {
+		return false, err
+	}
+
+	info, err := os.Stat(lockFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	if time.Since(info.ModTime()) > b.lockTimeout {
+		b.log.Info("removing stale lock", "lock", lockFile)
+		if err := os.Remove(lockFile); err != nil && !os.IsNotExist(err) {
+			return false, err
+		}
+	}
+	return false, nil
+}
+
+func (b *pvcBackend) restore(_ context.Context, _, dir string) (time.Time, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return time.Time{}, nil
+		}
+		return time.Time{}, err
+	}
+	return info.ModTime(), nil
+}
+
+func (b *pvcBackend) save(_ context.Context, _, dir string, _ bool, _ time.Time) error {
+	now := time.Now()
+	return os.Chtimes(dir, now, now)
+}
+
+func (b *pvcBackend) evict(_ context.Context) error {
+	entries, err := os.ReadDir(b.dir)
+	if err != nil {
+		return err
+	}
+	mirrors := make([]mirror, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() || !strings.HasSuffix(entry.Name(), ".git") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		size, err := dirSize(filepath.Join(b.dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		mirrors = append(mirrors, mirror{
+			key:      strings.TrimSuffix(entry.Name(), ".git"),
+			lastUsed: info.ModTime(),
+			size:     size,
+		})
+	}
+
+	for _, m := range selectEvictions(mirrors, b.maxAge, b.maxSize, time.Now()) {
+		lockFile := b.lockFile(m.key)
+		locked, err := b.tryLock(lockFile)
+		if err != nil || !locked {
+			// the mirror is currently used
+			continue
+		}
+		b.log.Info("evicting mirror", "mirror", m.key, "lastUsed", m.lastUsed, "size", m.size)
+		err = os.RemoveAll(filepath.Join(b.dir, m.key+".git"))
+		_ = os.Remove(lockFile)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *pvcBackend) lockFile(key string) string {
+	return filepath.Join(b.dir, key+".lock")
+}
+
+func dirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
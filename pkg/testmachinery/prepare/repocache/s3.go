@@ -0,0 +1,197 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package repocache
+
+import (
+	"context"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/minio/minio-go"
+
+	"github.com/gardener/test-infra/pkg/util/s3"
+)
+
+const (
+	bundleSuffix = ".bundle"
+	lockSuffix   = ".lock"
+)
+
+// s3Backend stores the mirrors as git bundles in a s3 bucket.
+// As s3 offers no atomic operations, locks are only used to prevent concurrent uploads of the same mirror.
+// A prepare step that finds a mirror locked uses it without saving it.
+type s3Backend struct {
+	log         logr.Logger
+	client      s3.Client
+	prefix      string
+	maxAge      time.Duration
+	maxSize     int64
+	lockTimeout time.Duration
+}
+
+func newS3Backend(log logr.Logger, client s3.Client, config Config) *s3Backend {
+	return &s3Backend{
+		log:         log,
+		client:      client,
+		prefix:      config.S3.Prefix,
+		maxAge:      config.MaxAge.Duration,
+		maxSize:     config.MaxSizeBytes,
+		lockTimeout: config.LockTimeout.Duration,
+	}
+}
+
+func (b *s3Backend) lock(_ context.Context, key string) (func(), bool, error) {
+	lockKey := b.objectKey(key, lockSuffix)
+	if b.isLocked(lockKey) {
+		b.log.Info("mirror is used by another prepare step and is not saved", "mirror", key)
+		return func() {}, true, nil
+	}
+	hostname, _ := os.Hostname()
+	if err := b.client.PutObject("", lockKey, []byte(hostname)); err != nil {
+		return nil, false, err
+	}
+	stop := heartbeat(b.lockTimeout/3, func() {
+		_ = b.client.PutObject("", lockKey, []byte(hostname))
+	})
+	return func() {
+		stop()
+		_ = b.client.RemoveObject("", lockKey)
+	}, false, nil
+}
+
+// isLocked checks whether a lock exists that has been refreshed within the lock timeout.
+func (b *s3Backend) isLocked(lockKey string) bool {
+	info, err := b.stat(lockKey)
+	if err != nil || info == nil {
+		return false
+	}
+	return time.Since(info.LastModified) <= b.lockTimeout
+}
+
+func (b *s3Backend) restore(ctx context.Context, key, dir string) (time.Time, error) {
+	if err := os.RemoveAll(dir); err != nil {
+		return time.Time{}, err
+	}
+	obj, err := b.client.GetObject("", b.objectKey(key, bundleSuffix))
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer func() { _ = obj.Close() }()
+	info, err := obj.Stat()
+	if err != nil {
+		if isNotFound(err) {
+			return time.Time{}, nil
+		}
+		return time.Time{}, err
+	}
+
+	bundleFile := dir + bundleSuffix
+	defer func() { _ = os.Remove(bundleFile) }()
+	f, err := os.Create(bundleFile)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if _, err := io.Copy(f, obj); err != nil {
+		_ = f.Close()
+		return time.Time{}, err
+	}
+	if err := f.Close(); err != nil {
+		return time.Time{}, err
+	}
+
+	if _, err := runGit(ctx, "", "init", "-q", "--bare", dir); err != nil {
+		return time.Time{}, err
+	}
+	if _, err := runGit(ctx, "", "--git-dir="+dir, "fetch", "-q", bundleFile, "+refs/*:refs/*"); err != nil {
+		return time.Time{}, err
+	}
+	return info.LastModified, nil
+}
+
+// save uploads the mirror if it has changed.
+// Unchanged mirrors are uploaded again after half of the maximum age so that mirrors that are still used are not evicted.
+func (b *s3Backend) save(ctx context.Context, key, dir string, changed bool, lastUsed time.Time) error {
+	if !changed && time.Since(lastUsed) < b.maxAge/2 {
+		return nil
+	}
+	bundleFile := dir + bundleSuffix
+	defer func() { _ = os.Remove(bundleFile) }()
+	if _, err := runGit(ctx, "", "--git-dir="+dir, "bundle", "create", "-q", bundleFile, "--all"); err != nil {
+		return err
+	}
+	f, err := os.Open(bundleFile) // #nosec G304 -- the file is created by the cache itself
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	return b.client.PutObjectStream("", b.objectKey(key, bundleSuffix), f, info.Size())
+}
+
+func (b *s3Backend) evict(_ context.Context) error {
+	keys, err := b.client.ListObjects("", b.prefix+"/")
+	if err != nil {
+		return err
+	}
+	mirrors := make([]mirror, 0, len(keys))
+	for _, objectKey := range keys {
+		if !strings.HasSuffix(objectKey, bundleSuffix) {
+			continue
+		}
+		info, err := b.stat(objectKey)
+		if err != nil || info == nil {
+			continue
+		}
+		mirrors = append(mirrors, mirror{
+			key:      strings.TrimSuffix(path.Base(objectKey), bundleSuffix),
+			lastUsed: info.LastModified,
+			size:     info.Size,
+		})
+	}
+
+	for _, m := range selectEvictions(mirrors, b.maxAge, b.maxSize, time.Now()) {
+		if b.isLocked(b.objectKey(m.key, lockSuffix)) {
+			continue
+		}
+		b.log.Info("evicting mirror", "mirror", m.key, "lastUsed", m.lastUsed, "size", m.size)
+		if err := b.client.RemoveObject("", b.objectKey(m.key, bundleSuffix)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// stat returns the info of an object.
+// Nil is returned if the object does not exist.
+func (b *s3Backend) stat(objectKey string) (*minio.ObjectInfo, error) {
+	obj, err := b.client.GetObject("", objectKey)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = obj.Close() }()
+	info, err := obj.Stat()
+	if err != nil {
+		if isNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &info, nil
+}
+
+func (b *s3Backend) objectKey(key, suffix string) string {
+	return path.Join(b.prefix, key+suffix)
+}
+
+func isNotFound(err error) bool {
+	return minio.ToErrorResponse(err).Code == "NoSuchKey"
+}
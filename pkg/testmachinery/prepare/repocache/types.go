@@ -0,0 +1,73 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package repocache
+
+import (
+	"context"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// AccessKeyEnv is the name of the environment variable that holds the access key of the s3 cache.
+	AccessKeyEnv = "TM_REPO_CACHE_S3_ACCESS_KEY"
+
+	// SecretKeyEnv is the name of the environment variable that holds the secret key of the s3 cache.
+	SecretKeyEnv = "TM_REPO_CACHE_S3_SECRET_KEY"
+)
+
+// Config is the configuration of the repository cache that is passed to the prepare step.
+type Config struct {
+	// Dir is the directory that contains the mirrors.
+	// If the mirrors are stored in s3, the directory is used as local working directory.
+	Dir string `json:"dir"`
+
+	// S3 stores the mirrors as git bundles in a s3 bucket.
+	// The credentials are read from the environment.
+	S3 *S3Config `json:"s3,omitempty"`
+
+	// MaxAge is the duration after which unused mirrors are evicted.
+	MaxAge metav1.Duration `json:"maxAge"`
+
+	// MaxSizeBytes is the maximum size of all mirrors.
+	// No size based eviction is done if the size is 0.
+	MaxSizeBytes int64 `json:"maxSizeBytes,omitempty"`
+
+	// LockTimeout is the duration to wait for a mirror that is locked by another prepare step.
+	LockTimeout metav1.Duration `json:"lockTimeout"`
+}
+
+// S3Config describes the bucket that stores the mirrors.
+type S3Config struct {
+	Endpoint   string `json:"endpoint"`
+	SSL        bool   `json:"ssl,omitempty"`
+	BucketName string `json:"bucketName"`
+	Prefix     string `json:"prefix"`
+}
+
+// backend persists the bare mirrors of the cache.
+type backend interface {
+	// lock locks the mirror with the given key.
+	// If readOnly is returned, the mirror is used by another prepare step and must not be saved.
+	lock(ctx context.Context, key string) (unlock func(), readOnly bool, err error)
+
+	// restore makes the mirror available in the local directory and returns the time it was last used.
+	// The zero time is returned if the mirror does not exist.
+	restore(ctx context.Context, key, dir string) (time.Time, error)
+
+	// save persists the local mirror and marks it as used.
+	save(ctx context.Context, key, dir string, changed bool, lastUsed time.Time) error
+
+	// evict removes all mirrors that exceed the maximum age or the maximum size of the cache.
+	evict(ctx context.Context) error
+}
+
+// mirror describes a persisted mirror for the eviction.
+type mirror struct {
+	key      string
+	lastUsed time.Time
+	size     int64
+}
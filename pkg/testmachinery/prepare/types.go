@@ -1,6 +1,9 @@
 package prepare
 
-import "github.com/gardener/test-infra/pkg/testmachinery/testdefinition"
+import (
+	"github.com/gardener/test-infra/pkg/testmachinery/prepare/repocache"
+	"github.com/gardener/test-infra/pkg/testmachinery/testdefinition"
+)
 
 const (
 	PrepareConfigPath = "/tm/config.json"
@@ -18,6 +21,10 @@ type Definition struct {
 type Config struct {
 	Directories  []string               `json:"directories"`
 	Repositories map[string]*Repository `json:"repositories"`
+
+	// RepositoryCache is the shared cache that repositories are checked out from.
+	// Repositories are cloned directly if no cache is defined.
+	RepositoryCache *repocache.Config `json:"repositoryCache,omitempty"`
 }
 
 // PrepareRepository is passed as a json array to the prepare step.
@@ -25,4 +32,9 @@ type Repository struct {
 	Name     string `json:"name"`
 	URL      string `json:"url"`
 	Revision string `json:"revision"`
+	// Provider is the type of the git provider that serves the repository.
+	Provider string `json:"provider,omitempty"`
+	// Commit is the commit the revision was resolved to during validation.
+	// The commit is checked out instead of the revision if defined.
+	Commit string `json:"commit,omitempty"`
 }
@@ -0,0 +1,159 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package telemetry
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const (
+	// DefaultInterval is the default interval between two probes of a target.
+	DefaultInterval = 5 * time.Second
+
+	probeTimeout = 5 * time.Second
+)
+
+// Prober periodically probes the shoot API server and additional endpoints and writes the results as samples.
+type Prober struct {
+	log      logr.Logger
+	writer   *SampleWriter
+	interval time.Duration
+
+	kubeconfigPath string
+	endpoints      []string
+
+	// kubeconfig caches the client of the shoot kubeconfig as long as the file does not change.
+	kubeconfigModTime time.Time
+	kubeconfigClient  *http.Client
+	kubeconfigHost    string
+}
+
+// NewProber creates a new prober.
+// The shoot API server is only probed if the kubeconfig path is set and the kubeconfig exists.
+func NewProber(log logr.Logger, writer *SampleWriter, interval time.Duration, kubeconfigPath string, endpoints []string) *Prober {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	return &Prober{
+		log:            log,
+		writer:         writer,
+		interval:       interval,
+		kubeconfigPath: kubeconfigPath,
+		endpoints:      endpoints,
+	}
+}
+
+// Run probes all targets until the context is cancelled.
+func (p *Prober) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		p.probeAll(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *Prober) probeAll(ctx context.Context) {
+	var wg sync.WaitGroup
+	probe := func(target, url string, client *http.Client) {
+		defer wg.Done()
+		sample := Probe(ctx, client, target, url)
+		if ctx.Err() != nil {
+			// probes that are interrupted by the termination of the step are no downtime
+			return
+		}
+		if err := p.writer.Write(sample); err != nil {
+			p.log.Error(err, "unable to write sample", "target", target)
+		}
+	}
+
+	// the shoot kubeconfig is read every time as it may be written or rotated while the step is running.
+	client, host, err := p.shootClient()
+	if err != nil {
+		p.log.V(3).Info("unable to probe shoot API server", "error", err.Error())
+	} else if client != nil {
+		wg.Add(1)
+		go probe(ShootAPIServerTarget, strings.TrimSuffix(host, "/")+"/healthz", client)
+	}
+	for _, endpoint := range p.endpoints {
+		wg.Add(1)
+		go probe(endpoint, endpoint, &http.Client{Timeout: probeTimeout})
+	}
+	wg.Wait()
+}
+
+// shootClient returns a http client that is authenticated against the shoot API server.
+// Nil is returned if no shoot kubeconfig exists.
+func (p *Prober) shootClient() (*http.Client, string, error) {
+	if len(p.kubeconfigPath) == 0 {
+		return nil, "", nil
+	}
+	info, err := os.Stat(p.kubeconfigPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, "", nil
+		}
+		return nil, "", err
+	}
+	if p.kubeconfigClient != nil && info.ModTime().Equal(p.kubeconfigModTime) {
+		return p.kubeconfigClient, p.kubeconfigHost, nil
+	}
+
+	cfg, err := clientcmd.BuildConfigFromFlags("", p.kubeconfigPath)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "unable to read shoot kubeconfig")
+	}
+	cfg.Timeout = probeTimeout
+	client, err := rest.HTTPClientFor(cfg)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "unable to create shoot client")
+	}
+	p.kubeconfigClient = client
+	p.kubeconfigHost = cfg.Host
+	p.kubeconfigModTime = info.ModTime()
+	return client, cfg.Host, nil
+}
+
+// Probe sends a get request to the url and returns the result as sample.
+func Probe(ctx context.Context, client *http.Client, target, url string) Sample {
+	sample := Sample{
+		Time:   time.Now(),
+		Target: target,
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		sample.Error = err.Error()
+		return sample
+	}
+	res, err := client.Do(req)
+	sample.ResponseTime = time.Since(sample.Time).Milliseconds()
+	if err != nil {
+		sample.Error = err.Error()
+		return sample
+	}
+	_, _ = io.Copy(io.Discard, res.Body)
+	_ = res.Body.Close()
+	sample.StatusCode = res.StatusCode
+	sample.Up = res.StatusCode < http.StatusInternalServerError
+	if !sample.Up {
+		sample.Error = res.Status
+	}
+	return sample
+}
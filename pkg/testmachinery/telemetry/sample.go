@@ -0,0 +1,91 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package telemetry
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ShootAPIServerTarget is the name of the target that probes the API server of the shoot kubeconfig.
+const ShootAPIServerTarget = "shoot-apiserver"
+
+// Sample is the result of a single probe of a target.
+type Sample struct {
+	// Time when the probe was started.
+	Time time.Time `json:"time"`
+	// Target is the name of the probed target.
+	Target string `json:"target"`
+	// Up is true if the target responded with a status code lower than 500.
+	Up bool `json:"up"`
+	// ResponseTime of the target in milliseconds.
+	ResponseTime int64 `json:"responseTime"`
+	// StatusCode of the response.
+	// +optional
+	StatusCode int `json:"statusCode,omitempty"`
+	// Error describes why the target did not respond.
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// SampleWriter appends samples as newline delimited json to a file.
+// Every sample is written immediately so that the file is always complete when the step is terminated.
+type SampleWriter struct {
+	mux  sync.Mutex
+	file *os.File
+}
+
+// NewSampleWriter creates a writer that appends samples to the given file.
+func NewSampleWriter(path string) (*SampleWriter, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to open sample file %s", path)
+	}
+	return &SampleWriter{file: file}, nil
+}
+
+// Write appends a sample to the file.
+func (w *SampleWriter) Write(sample Sample) error {
+	data, err := json.Marshal(sample)
+	if err != nil {
+		return err
+	}
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	_, err = w.file.Write(append(data, '\n'))
+	return err
+}
+
+// Close closes the underlying file.
+func (w *SampleWriter) Close() error {
+	return w.file.Close()
+}
+
+// ReadSamples reads newline delimited json samples.
+// Lines that cannot be parsed are skipped as the last sample might be incomplete.
+func ReadSamples(r io.Reader) ([]Sample, error) {
+	samples := make([]Sample, 0)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var sample Sample
+		if err := json.Unmarshal(scanner.Bytes(), &sample); err != nil {
+			continue
+		}
+		samples = append(samples, sample)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "unable to read samples")
+	}
+	return samples, nil
+}
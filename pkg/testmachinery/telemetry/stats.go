@@ -0,0 +1,131 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package telemetry
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/gardener/test-infra/pkg/testmachinery/metadata"
+)
+
+// Compute calculates the response time and downtime statistics of samples.
+// Response times are calculated in milliseconds of all successful probes.
+// A downtime period of a target starts with its first failed probe and ends with the next successful probe
+// or with its last probe if the target did not recover. The periods are calculated in seconds.
+// Nil is returned if there are no samples.
+func Compute(samples []Sample) *metadata.TelemetryData {
+	return ComputeGroups([][]Sample{samples})
+}
+
+// ComputeGroups calculates the statistics of several groups of samples that have been probed independently, e.g. by the steps of a testrun.
+// The response times of all groups are combined whereas the downtime periods are calculated per group,
+// so that a period does not span the time between two groups where nothing has been probed.
+// Nil is returned if there are no samples.
+func ComputeGroups(groups [][]Sample) *metadata.TelemetryData {
+	responseTimes := make([]int64, 0)
+	downtimes := make([]int64, 0)
+	empty := true
+	for _, samples := range groups {
+		byTarget := make(map[string][]Sample)
+		for _, sample := range samples {
+			empty = false
+			byTarget[sample.Target] = append(byTarget[sample.Target], sample)
+			if sample.Up {
+				responseTimes = append(responseTimes, sample.ResponseTime)
+			}
+		}
+		for _, targetSamples := range byTarget {
+			downtimes = append(downtimes, downtimePeriods(targetSamples)...)
+		}
+	}
+	if empty {
+		return nil
+	}
+
+	data := &metadata.TelemetryData{}
+	if len(responseTimes) != 0 {
+		s := computeStatistics(responseTimes)
+		data.ResponseTime = &metadata.TelemetryResponseTimeDuration{
+			Min:    int(s.min),
+			Max:    int(s.max),
+			Avg:    s.avg,
+			Median: s.median,
+			Std:    s.std,
+		}
+	}
+	data.DowntimePeriods = &metadata.TelemetryDowntimePeriods{}
+	if len(downtimes) != 0 {
+		s := computeStatistics(downtimes)
+		data.DowntimePeriods = &metadata.TelemetryDowntimePeriods{
+			Min:    s.min,
+			Max:    s.max,
+			Avg:    s.avg,
+			Median: s.median,
+			Std:    s.std,
+		}
+	}
+	return data
+}
+
+// downtimePeriods returns the duration in seconds of all periods where the target was down.
+func downtimePeriods(samples []Sample) []int64 {
+	sort.SliceStable(samples, func(i, j int) bool {
+		return samples[i].Time.Before(samples[j].Time)
+	})
+
+	periods := make([]int64, 0)
+	var downSince *time.Time
+	for i := range samples {
+		sample := samples[i]
+		if !sample.Up && downSince == nil {
+			downSince = &samples[i].Time
+			continue
+		}
+		if sample.Up && downSince != nil {
+			periods = append(periods, int64(sample.Time.Sub(*downSince).Seconds()))
+			downSince = nil
+		}
+	}
+	if downSince != nil {
+		periods = append(periods, int64(samples[len(samples)-1].Time.Sub(*downSince).Seconds()))
+	}
+	return periods
+}
+
+type statistics struct {
+	min, max, avg, median, std int64
+}
+
+func computeStatistics(values []int64) statistics {
+	sorted := append([]int64{}, values...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var sum int64
+	for _, v := range sorted {
+		sum += v
+	}
+	avg := float64(sum) / float64(len(sorted))
+
+	var variance float64
+	for _, v := range sorted {
+		variance += math.Pow(float64(v)-avg, 2)
+	}
+	variance /= float64(len(sorted))
+
+	median := sorted[len(sorted)/2]
+	if len(sorted)%2 == 0 {
+		median = (sorted[len(sorted)/2-1] + sorted[len(sorted)/2]) / 2
+	}
+
+	return statistics{
+		min:    sorted[0],
+		max:    sorted[len(sorted)-1],
+		avg:    int64(math.Round(avg)),
+		median: median,
+		std:    int64(math.Round(math.Sqrt(variance))),
+	}
+}
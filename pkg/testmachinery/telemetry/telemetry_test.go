@@ -0,0 +1,130 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package telemetry_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/gardener/test-infra/pkg/testmachinery/metadata"
+	"github.com/gardener/test-infra/pkg/testmachinery/telemetry"
+)
+
+var _ = Describe("telemetry", func() {
+
+	Context("statistics", func() {
+		start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+		sample := func(offset int, target string, up bool, responseTime int64) telemetry.Sample {
+			return telemetry.Sample{
+				Time:         start.Add(time.Duration(offset) * time.Second),
+				Target:       target,
+				Up:           up,
+				ResponseTime: responseTime,
+			}
+		}
+
+		It("should return nil if there are no samples", func() {
+			Expect(telemetry.Compute(nil)).To(BeNil())
+		})
+
+		It("should compute the response times of successful probes", func() {
+			data := telemetry.Compute([]telemetry.Sample{
+				sample(0, "a", true, 10),
+				sample(5, "a", true, 20),
+				sample(10, "a", false, 5000),
+				sample(15, "a", true, 60),
+			})
+			Expect(data.ResponseTime).To(Equal(&metadata.TelemetryResponseTimeDuration{
+				Min:    10,
+				Max:    60,
+				Avg:    30,
+				Median: 20,
+				Std:    22,
+			}))
+		})
+
+		It("should compute the downtime periods per target", func() {
+			data := telemetry.Compute([]telemetry.Sample{
+				sample(0, "a", true, 10),
+				sample(5, "a", false, 0),
+				sample(10, "a", false, 0),
+				sample(15, "a", true, 10),
+				sample(0, "b", false, 0),
+				sample(5, "b", true, 10),
+				sample(10, "b", false, 0),
+				sample(30, "b", false, 0),
+			})
+			// periods: a=10s, b=5s, b=20s (not recovered)
+			Expect(data.DowntimePeriods).To(Equal(&metadata.TelemetryDowntimePeriods{
+				Min:    5,
+				Max:    20,
+				Avg:    12,
+				Median: 10,
+				Std:    6,
+			}))
+		})
+
+		It("should report no downtime if all probes succeeded", func() {
+			data := telemetry.Compute([]telemetry.Sample{sample(0, "a", true, 10)})
+			Expect(data.DowntimePeriods).To(Equal(&metadata.TelemetryDowntimePeriods{}))
+		})
+	})
+
+	Context("prober", func() {
+		It("should probe endpoints and write the samples", func() {
+			up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer up.Close()
+			down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}))
+			defer down.Close()
+
+			output := filepath.Join(GinkgoT().TempDir(), "samples.json")
+			writer, err := telemetry.NewSampleWriter(output)
+			Expect(err).ToNot(HaveOccurred())
+
+			ctx, cancel := context.WithTimeout(context.Background(), 250*time.Millisecond)
+			defer cancel()
+			prober := telemetry.NewProber(logr.Discard(), writer, 100*time.Millisecond, filepath.Join(GinkgoT().TempDir(), "missing.config"), []string{up.URL, down.URL})
+			prober.Run(ctx)
+			Expect(writer.Close()).To(Succeed())
+
+			file, err := os.Open(output)
+			Expect(err).ToNot(HaveOccurred())
+			defer func() { _ = file.Close() }()
+			samples, err := telemetry.ReadSamples(file)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(len(samples)).To(BeNumerically(">=", 4))
+			for _, s := range samples {
+				Expect(s.Target).ToNot(Equal(telemetry.ShootAPIServerTarget))
+				if s.Target == up.URL {
+					Expect(s.Up).To(BeTrue())
+					Expect(s.StatusCode).To(Equal(http.StatusOK))
+				} else {
+					Expect(s.Up).To(BeFalse())
+					Expect(s.StatusCode).To(Equal(http.StatusServiceUnavailable))
+				}
+			}
+		})
+
+		It("should skip incomplete samples", func() {
+			samples, err := telemetry.ReadSamples(strings.NewReader("{\"target\":\"a\",\"up\":true}\n{\"target\":\"b\",\"u"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(samples).To(HaveLen(1))
+			Expect(samples[0].Target).To(Equal("a"))
+		})
+	})
+})
@@ -21,14 +21,19 @@ import (
 	"github.com/gardener/test-infra/pkg/common"
 	"github.com/gardener/test-infra/pkg/testmachinery"
 	"github.com/gardener/test-infra/pkg/testmachinery/config"
+	tmtelemetry "github.com/gardener/test-infra/pkg/testmachinery/telemetry"
 	"github.com/gardener/test-infra/pkg/util"
+	"github.com/gardener/test-infra/pkg/util/redact"
 )
 
 var (
 	DefaultActiveDeadlineSeconds = intstr.FromInt(600)
 	archiveLogs                  = true
+	mirrorVolumeMounts           = true
 )
 
+const telemetryVolumeName = "tm-telemetry"
+
 // New takes a CRD TestDefinition and its locations, and creates a TestDefinition object.
 func New(def *tmv1beta1.TestDefinition, loc Location, fileName string) (*TestDefinition, error) {
 	if err := validation.ValidateTestDefinition(field.NewPath(fmt.Sprintf("Location: \"%s\"; File: \"%s\"", loc.Name(), fileName)), def); len(err) != 0 {
@@ -256,6 +261,44 @@ func (td *TestDefinition) AddStdOutput(global bool) {
 	td.AddOutputArtifacts(GetStdOutputArtifacts(global)...)
 }
 
+// AddTelemetry adds a sidecar to the TestDefinitions's template that probes the shoot API server and the configured endpoints
+// while the test is running. The measured samples are stored as telemetry output artifact.
+func (td *TestDefinition) AddTelemetry(telemetry *tmv1beta1.Telemetry) {
+	interval := tmtelemetry.DefaultInterval
+	if telemetry.Interval != nil {
+		interval = telemetry.Interval.Duration
+	}
+	args := []string{
+		"--kubeconfig", path.Join(testmachinery.TM_KUBECONFIG_PATH, tmv1beta1.ShootKubeconfigName),
+		"--output", path.Join(testmachinery.TM_TELEMETRY_PATH, testmachinery.TelemetrySamplesFile),
+		"--interval", interval.String(),
+	}
+	for _, endpoint := range telemetry.Endpoints {
+		args = append(args, "--endpoint", endpoint)
+	}
+
+	// the sidecar mirrors the volumes of the test container so that it can read the kubeconfigs and write to the telemetry volume.
+	td.AddVolume(corev1.Volume{
+		Name:         telemetryVolumeName,
+		VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+	})
+	td.AddVolumeMount(telemetryVolumeName, testmachinery.TM_TELEMETRY_PATH, "", false)
+	td.Template.Sidecars = append(td.Template.Sidecars, argov1alpha1.UserContainer{
+		Container: corev1.Container{
+			Name:    "telemetry",
+			Image:   testmachinery.PrepareImage(),
+			Command: []string{"/tm/telemetry"},
+			Args:    args,
+		},
+		MirrorVolumeMounts: &mirrorVolumeMounts,
+	})
+	td.AddOutputArtifacts(argov1alpha1.Artifact{
+		Name:     testmachinery.TelemetryArtifact,
+		Path:     testmachinery.TM_TELEMETRY_PATH,
+		Optional: true,
+	})
+}
+
 func (td *TestDefinition) GetConfig() config.Set {
 	return td.config
 }
@@ -270,7 +313,7 @@ func (td *TestDefinition) AddConfig(configs []*config.Element) {
 func (td *TestDefinition) addConfigAsEnv(element *config.Element) {
 	if element.Info.Value != "" {
 		// add as input parameter to see parameters in argo ui
-		td.AddInputParameter(element.Name(), fmt.Sprintf("%s: %s", element.Info.Name, element.Info.Value))
+		td.AddInputParameter(element.Name(), fmt.Sprintf("%s: %s", element.Info.Name, redact.ConfigElement(element.Info).Value))
 		td.AddEnvVars(corev1.EnvVar{Name: element.Info.Name, Value: element.Info.Value})
 	} else {
 		// add as input parameter to see parameters in argo ui
@@ -5,10 +5,15 @@
 package testdefinition_test
 
 import (
+	"time"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	tmv1beta1 "github.com/gardener/test-infra/pkg/apis/testmachinery/v1beta1"
+	"github.com/gardener/test-infra/pkg/testmachinery"
 	"github.com/gardener/test-infra/pkg/testmachinery/testdefinition"
 )
 
@@ -43,4 +48,22 @@ var _ = Describe("TestDefinition", func() {
 		})
 
 	})
+
+	Context("telemetry", func() {
+		It("should add a telemetry sidecar and its output artifact", func() {
+			td := testdefinition.NewEmpty()
+			td.Template.Container = &corev1.Container{}
+			td.AddTelemetry(&tmv1beta1.Telemetry{
+				Endpoints: []string{"https://example.com"},
+				Interval:  &metav1.Duration{Duration: 10 * time.Second},
+			})
+			Expect(td.Template.Sidecars).To(HaveLen(1))
+			sidecar := td.Template.Sidecars[0]
+			Expect(sidecar.Command).To(Equal([]string{"/tm/telemetry"}))
+			Expect(sidecar.Args).To(ContainElements("--interval", "10s", "--endpoint", "https://example.com"))
+			Expect(*sidecar.MirrorVolumeMounts).To(BeTrue())
+			Expect(td.Template.Container.VolumeMounts).To(ContainElement(HaveField("MountPath", testmachinery.TM_TELEMETRY_PATH)))
+			Expect(td.Template.Outputs.Artifacts).To(ContainElement(HaveField("Name", testmachinery.TelemetryArtifact)))
+		})
+	})
 })
@@ -16,6 +16,7 @@ import (
 	"github.com/gardener/test-infra/pkg/testmachinery/config"
 	"github.com/gardener/test-infra/pkg/testmachinery/locations"
 	"github.com/gardener/test-infra/pkg/testmachinery/testdefinition"
+	"github.com/gardener/test-infra/pkg/util/redact"
 )
 
 // CreateNodesFromStep creates new nodes from a step and adds default configuration
@@ -53,6 +54,9 @@ func NewNode(td *testdefinition.TestDefinition, step *tmv1beta1.DAGStep, flow st
 	if td.HasBehavior(tmv1beta1.DisruptiveBehavior) {
 		node.step.Definition.ContinueOnError = false
 	}
+	if step.Telemetry != nil {
+		td.AddTelemetry(step.Telemetry)
+	}
 
 	return node
 }
@@ -173,10 +177,11 @@ func (n *Node) Status() *tmv1beta1.StepStatus {
 		TestDefinition: tmv1beta1.StepStatusTestDefinition{
 			Name:                  td.Info.Name,
 			Owner:                 td.Info.Spec.Owner,
-			Config:                td.GetConfig().RawList(),
+			Config:                redact.ConfigElements(td.GetConfig().RawList()),
 			Labels:                td.Info.Spec.Labels,
 			RecipientsOnFailure:   td.Info.Spec.RecipientsOnFailure,
 			ActiveDeadlineSeconds: td.Info.Spec.ActiveDeadlineSeconds,
+			ExportSchema:          td.Info.Spec.ExportSchema,
 		},
 	}
 	if n.step != nil {
@@ -84,6 +84,36 @@ func Locations() config.Locations {
 	return tmConfig.TestMachinery.Locations
 }
 
+// RepositoryCache returns the configuration of the shared repository cache of the prepare step.
+// Nil is returned if no cache is configured.
+func RepositoryCache() *config.RepositoryCache {
+	return tmConfig.TestMachinery.RepositoryCache
+}
+
+// ConfigSources returns the configuration of the additional sources of config element values.
+// Nil is returned if no additional sources are configured.
+func ConfigSources() *config.ConfigSources {
+	return tmConfig.TestMachinery.ConfigSources
+}
+
+// KubeconfigPreflight returns the configuration of the checks of testrun kubeconfigs.
+// Nil is returned if the kubeconfigs should not be checked.
+func KubeconfigPreflight() *config.KubeconfigPreflight {
+	return tmConfig.TestMachinery.KubeconfigPreflight
+}
+
+// AdmissionPolicies returns the policies that testruns have to comply with.
+// Nil is returned if no policies are configured.
+func AdmissionPolicies() *config.AdmissionPolicies {
+	return tmConfig.Controller.AdmissionPolicies
+}
+
+// Defaulting returns the default profiles of testruns.
+// Nil is returned if no defaults are configured.
+func Defaulting() *config.Defaulting {
+	return tmConfig.Controller.Defaulting
+}
+
 // Prepare Image returns the image of the prepare step.
 func PrepareImage() string {
 	return tmConfig.TestMachinery.PrepareImage
@@ -0,0 +1,87 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package testrun
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	tmv1beta1 "github.com/gardener/test-infra/pkg/apis/testmachinery/v1beta1"
+	"github.com/gardener/test-infra/pkg/testmachinery"
+	"github.com/gardener/test-infra/pkg/testmachinery/config"
+	"github.com/gardener/test-infra/pkg/testmachinery/configsource"
+	"github.com/gardener/test-infra/pkg/testmachinery/testflow"
+	"github.com/gardener/test-infra/pkg/util/strconf"
+)
+
+// ResolveConfigSources resolves all config elements of the testflows whose values cannot be referenced by the pods of the testrun,
+// e.g. secrets of other namespaces, vault secrets and templates.
+// The resolved values are stored in a helper secret of the testrun that is returned
+// and the config elements are changed to reference that secret.
+// Therefore, the values are neither part of the workflow nor of the testrun status.
+func ResolveConfigSources(ctx context.Context, reader client.Reader, tr *tmv1beta1.Testrun, flows ...*testflow.Testflow) ([]client.Object, error) {
+	elements := make([]*config.Element, 0)
+	for _, flow := range flows {
+		for n := range flow.Flow.Iterate() {
+			for _, element := range n.TestDefinition.GetConfig() {
+				if element.Info.ValueFrom != nil && element.Info.ValueFrom.IsExternal() {
+					elements = append(elements, element)
+				}
+			}
+		}
+	}
+	if len(elements) == 0 {
+		return nil, nil
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-config-sources", tr.Name),
+			Namespace: tr.Namespace,
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion:         tr.GroupVersionKind().GroupVersion().String(),
+					Kind:               tr.Kind,
+					Name:               tr.GetName(),
+					UID:                tr.GetUID(),
+					BlockOwnerDeletion: ptr.To(true),
+					Controller:         ptr.To(false),
+				},
+			},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: make(map[string][]byte),
+	}
+
+	resolver := configsource.NewResolver(reader, tr.Namespace, testmachinery.ConfigSources())
+	for _, element := range elements {
+		// elements are shared between testdefinitions and might already be resolved
+		if !element.Info.ValueFrom.IsExternal() {
+			continue
+		}
+		value, err := resolver.Resolve(ctx, element.Info.ValueFrom)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to resolve value of config %s", element.Info.Name)
+		}
+		secret.Data[element.Name()] = value
+
+		info := element.Info.DeepCopy()
+		info.ValueFrom = &strconf.ConfigSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: secret.Name},
+				Key:                  element.Name(),
+			},
+		}
+		element.Info = info
+	}
+
+	return []client.Object{secret}, nil
+}
@@ -0,0 +1,114 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package testrun
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/gardener/test-infra/pkg/apis/config"
+	tmv1beta1 "github.com/gardener/test-infra/pkg/apis/testmachinery/v1beta1"
+	"github.com/gardener/test-infra/pkg/testmachinery"
+	tmconfig "github.com/gardener/test-infra/pkg/testmachinery/config"
+	"github.com/gardener/test-infra/pkg/testmachinery/testdefinition"
+	"github.com/gardener/test-infra/pkg/testmachinery/testflow"
+	"github.com/gardener/test-infra/pkg/util/strconf"
+	testutils "github.com/gardener/test-infra/test/utils"
+)
+
+var _ = Describe("config sources", func() {
+	It("should store resolved values in a helper secret and only reference the secret in the status", func() {
+		Expect(testmachinery.Setup(&config.Configuration{
+			TestMachinery: config.TestMachinery{
+				ConfigSources: &config.ConfigSources{AllowedSecretNamespaces: []string{"shared"}},
+			},
+		})).To(Succeed())
+		defer func() {
+			Expect(testmachinery.Setup(&config.Configuration{})).To(Succeed())
+		}()
+
+		reader := fake.NewClientBuilder().WithObjects(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "creds", Namespace: "shared"},
+			Data:       map[string][]byte{"user": []byte("admin"), "password": []byte("s3cr3t")},
+		}).Build()
+		tr := &tmv1beta1.Testrun{ObjectMeta: metav1.ObjectMeta{Name: testrunName, Namespace: namespace}}
+
+		globalConfig := tmconfig.New([]tmv1beta1.ConfigElement{
+			{
+				Type: tmv1beta1.ConfigTypeEnv,
+				Name: "USER",
+				ValueFrom: &strconf.ConfigSource{
+					NamespacedSecretKeyRef: &strconf.NamespacedSecretKeySelector{Namespace: "shared", Name: "creds", Key: "user"},
+				},
+			},
+			{Type: tmv1beta1.ConfigTypeEnv, Name: "PLAIN", Value: "value"},
+		}, tmconfig.LevelGlobal)
+		stepConfig := []tmv1beta1.ConfigElement{
+			{
+				Type: tmv1beta1.ConfigTypeFile,
+				Name: "CREDENTIALS",
+				Path: "/tmp/credentials",
+				ValueFrom: &strconf.ConfigSource{
+					Template: &strconf.TemplateSource{
+						Template: "{{ .user }}:{{ .password }}",
+						Sources: map[string]strconf.ConfigSource{
+							"user":     {NamespacedSecretKeyRef: &strconf.NamespacedSecretKeySelector{Namespace: "shared", Name: "creds", Key: "user"}},
+							"password": {NamespacedSecretKeyRef: &strconf.NamespacedSecretKeySelector{Namespace: "shared", Name: "creds", Key: "password"}},
+						},
+					},
+				},
+			},
+		}
+		locs := &testutils.LocationsMock{TestDefinitions: []*testdefinition.TestDefinition{testutils.TestDef("td")}}
+		tf, err := testflow.New(testflow.FlowIDTest, tmv1beta1.TestFlow{
+			{Name: "step", Definition: tmv1beta1.StepDefinition{Name: "td", Config: stepConfig}},
+		}, locs, globalConfig, nil)
+		Expect(err).ToNot(HaveOccurred())
+
+		objects, err := ResolveConfigSources(context.Background(), reader, tr, tf)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(objects).To(HaveLen(1))
+		secret := objects[0].(*corev1.Secret)
+		Expect(secret.Name).To(Equal(testrunName + "-config-sources"))
+		Expect(secret.Namespace).To(Equal(namespace))
+
+		values := make([]string, 0)
+		for _, value := range secret.Data {
+			values = append(values, string(value))
+		}
+		Expect(values).To(ConsistOf("admin", "admin:s3cr3t"))
+
+		statuses := tf.Flow.GetStatuses()
+		Expect(statuses).To(HaveLen(1))
+		Expect(statuses[0].TestDefinition.Config).To(HaveLen(3))
+		for _, cfg := range statuses[0].TestDefinition.Config {
+			if cfg.Name == "PLAIN" {
+				Expect(cfg.Value).To(Equal("value"))
+				continue
+			}
+			Expect(cfg.Value).To(BeEmpty())
+			Expect(cfg.ValueFrom.IsExternal()).To(BeFalse())
+			Expect(cfg.ValueFrom.SecretKeyRef).ToNot(BeNil())
+			Expect(cfg.ValueFrom.SecretKeyRef.Name).To(Equal(secret.Name))
+			Expect(secret.Data).To(HaveKey(cfg.ValueFrom.SecretKeyRef.Key))
+		}
+	})
+
+	It("should not create a helper secret if no config has to be resolved", func() {
+		tr := &tmv1beta1.Testrun{ObjectMeta: metav1.ObjectMeta{Name: testrunName, Namespace: namespace}}
+		globalConfig := tmconfig.New([]tmv1beta1.ConfigElement{{Type: tmv1beta1.ConfigTypeEnv, Name: "PLAIN", Value: "value"}}, tmconfig.LevelGlobal)
+		tf, err := testflow.New(testflow.FlowIDTest, tmv1beta1.TestFlow{}, testutils.EmptyMockLocation, globalConfig, nil)
+		Expect(err).ToNot(HaveOccurred())
+
+		objects, err := ResolveConfigSources(context.Background(), fake.NewClientBuilder().Build(), tr, tf)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(objects).To(BeEmpty())
+	})
+})
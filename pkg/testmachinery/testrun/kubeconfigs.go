@@ -9,9 +9,11 @@ import (
 	"encoding/base64"
 	"fmt"
 	"path"
+	"sort"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	clientcmdv1 "k8s.io/client-go/tools/clientcmd/api/v1"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -31,28 +33,79 @@ const (
 	shootKubeconfig    = "shoot"
 )
 
-// ParseKubeconfigs parses the kubeconfigs defined in the testrun and returns respective configs and k8s secrets.
-func ParseKubeconfigs(ctx context.Context, reader client.Reader, tr *tmv1beta1.Testrun) ([]*config.Element, []client.Object, map[string]*node.ProjectedTokenMount, error) {
-	parsedKubeconfigs := make(map[string]*clientcmdv1.Config)
-	configs := make([]*config.Element, 0)
-	secrets := make([]client.Object, 0)
-	if tr.Spec.Kubeconfigs.Host != nil {
-		if err := addKubeconfig(ctx, reader, &configs, &secrets, parsedKubeconfigs, tr, hostKubeconfig, tr.Spec.Kubeconfigs.Host); err != nil {
-			return nil, nil, nil, err
+// namedKubeconfig is a kubeconfig of a testrun.
+type namedKubeconfig struct {
+	// name of the kubeconfig file in the kubeconfig folder of the steps.
+	name string
+	// configName is the name of the config element and the suffix of the name of the secret that are created for the kubeconfig.
+	configName string
+	fldPath    *field.Path
+	kubeconfig *strconf.StringOrConfig
+}
+
+// getKubeconfigs returns all kubeconfigs of a testrun.
+// The predefined kubeconfigs are followed by the additional kubeconfigs ordered by their name.
+func getKubeconfigs(tr *tmv1beta1.Testrun) []namedKubeconfig {
+	fldPath := field.NewPath("spec", "kubeconfigs")
+	kubeconfigs := make([]namedKubeconfig, 0)
+	for _, kc := range []struct {
+		name       string
+		kubeconfig *strconf.StringOrConfig
+	}{
+		{hostKubeconfig, tr.Spec.Kubeconfigs.Host},
+		{gardenerKubeconfig, tr.Spec.Kubeconfigs.Gardener},
+		{seedKubeconfig, tr.Spec.Kubeconfigs.Seed},
+		{shootKubeconfig, tr.Spec.Kubeconfigs.Shoot},
+	} {
+		if kc.kubeconfig == nil {
+			continue
 		}
+		kubeconfigs = append(kubeconfigs, namedKubeconfig{
+			name:       kc.name,
+			configName: kc.name,
+			fldPath:    fldPath.Child(kc.name),
+			kubeconfig: kc.kubeconfig,
+		})
 	}
-	if tr.Spec.Kubeconfigs.Gardener != nil {
-		if err := addKubeconfig(ctx, reader, &configs, &secrets, parsedKubeconfigs, tr, gardenerKubeconfig, tr.Spec.Kubeconfigs.Gardener); err != nil {
-			return nil, nil, nil, err
+
+	names := make([]string, 0, len(tr.Spec.Kubeconfigs.Additional))
+	for name, kubeconfig := range tr.Spec.Kubeconfigs.Additional {
+		if kubeconfig != nil {
+			names = append(names, name)
 		}
 	}
-	if tr.Spec.Kubeconfigs.Seed != nil {
-		if err := addKubeconfig(ctx, reader, &configs, &secrets, parsedKubeconfigs, tr, seedKubeconfig, tr.Spec.Kubeconfigs.Seed); err != nil {
-			return nil, nil, nil, err
+	sort.Strings(names)
+	for _, name := range names {
+		kubeconfigs = append(kubeconfigs, namedKubeconfig{
+			name:       name,
+			configName: fmt.Sprintf("kubeconfig-%s", name),
+			fldPath:    fldPath.Child("additional").Key(name),
+			kubeconfig: tr.Spec.Kubeconfigs.Additional[name],
+		})
+	}
+	return kubeconfigs
+}
+
+// KubeconfigNames returns the names of the kubeconfigs of a testrun that are available to a step.
+// Untrusted steps only get the shoot kubeconfig.
+func KubeconfigNames(tr *tmv1beta1.Testrun, untrusted bool) []string {
+	names := make([]string, 0)
+	for _, kc := range getKubeconfigs(tr) {
+		if untrusted && kc.name != shootKubeconfig {
+			continue
 		}
+		names = append(names, kc.name)
 	}
-	if tr.Spec.Kubeconfigs.Shoot != nil {
-		if err := addKubeconfig(ctx, reader, &configs, &secrets, parsedKubeconfigs, tr, shootKubeconfig, tr.Spec.Kubeconfigs.Shoot); err != nil {
+	return names
+}
+
+// ParseKubeconfigs parses the kubeconfigs defined in the testrun and returns respective configs and k8s secrets.
+func ParseKubeconfigs(ctx context.Context, reader client.Reader, tr *tmv1beta1.Testrun) ([]*config.Element, []client.Object, map[string]*node.ProjectedTokenMount, error) {
+	parsedKubeconfigs := make(map[string]*clientcmdv1.Config)
+	configs := make([]*config.Element, 0)
+	secrets := make([]client.Object, 0)
+	for _, kc := range getKubeconfigs(tr) {
+		if err := addKubeconfig(ctx, reader, &configs, &secrets, parsedKubeconfigs, tr, kc); err != nil {
 			return nil, nil, nil, err
 		}
 	}
@@ -65,8 +118,9 @@ func ParseKubeconfigs(ctx context.Context, reader client.Reader, tr *tmv1beta1.T
 	return configs, secrets, projectedTokenMounts, nil
 }
 
-func addKubeconfig(ctx context.Context, reader client.Reader, configs *[]*config.Element, secrets *[]client.Object, parsedKubeconfigs map[string]*clientcmdv1.Config, tr *tmv1beta1.Testrun, name string, kubeconfig *strconf.StringOrConfig) error {
+func addKubeconfig(ctx context.Context, reader client.Reader, configs *[]*config.Element, secrets *[]client.Object, parsedKubeconfigs map[string]*clientcmdv1.Config, tr *tmv1beta1.Testrun, kc namedKubeconfig) error {
 	var parsedKubeconfig clientcmdv1.Config
+	name, kubeconfig := kc.name, kc.kubeconfig
 	kubeconfigPath := fmt.Sprintf("%s/%s.config", testmachinery.TM_KUBECONFIG_PATH, name)
 
 	if kubeconfig.Type == strconf.String {
@@ -77,7 +131,7 @@ func addKubeconfig(ctx context.Context, reader client.Reader, configs *[]*config
 
 		secret := &corev1.Secret{
 			ObjectMeta: metav1.ObjectMeta{
-				Name:      fmt.Sprintf("%s-%s", tr.Name, name),
+				Name:      fmt.Sprintf("%s-%s", tr.Name, kc.configName),
 				Namespace: tr.Namespace,
 				OwnerReferences: []metav1.OwnerReference{
 					{
@@ -120,7 +174,7 @@ func addKubeconfig(ctx context.Context, reader client.Reader, configs *[]*config
 	if kubeconfig.Type == strconf.Config {
 		*configs = append(*configs, config.NewElement(&tmv1beta1.ConfigElement{
 			Type:      tmv1beta1.ConfigTypeFile,
-			Name:      name,
+			Name:      kc.configName,
 			Path:      kubeconfigPath,
 			ValueFrom: kubeconfig.Config(),
 		}, config.LevelTestDefinition))
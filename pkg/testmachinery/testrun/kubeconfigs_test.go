@@ -5,14 +5,24 @@
 package testrun
 
 import (
+	"context"
+	"encoding/base64"
 	"fmt"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	clientcmdv1 "k8s.io/client-go/tools/clientcmd/api/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
 
 	"github.com/gardener/test-infra/pkg/apis/config"
+	tmv1beta1 "github.com/gardener/test-infra/pkg/apis/testmachinery/v1beta1"
 	"github.com/gardener/test-infra/pkg/testmachinery"
+	"github.com/gardener/test-infra/pkg/testmachinery/testdefinition"
+	"github.com/gardener/test-infra/pkg/testmachinery/testflow"
+	"github.com/gardener/test-infra/pkg/util/strconf"
+	testutils "github.com/gardener/test-infra/test/utils"
 )
 
 const (
@@ -294,4 +304,69 @@ var _ = Describe("kubeconfig tests", func() {
 			Expect(len(projectedTokenMounts)).To(Equal(1))
 		})
 	})
+
+	Context("additional kubeconfigs", func() {
+		var tr *tmv1beta1.Testrun
+
+		BeforeEach(func() {
+			Expect(testmachinery.Setup(&config.Configuration{})).To(Succeed())
+			raw, err := yaml.Marshal(&clientcmdv1.Config{
+				Clusters:       []clientcmdv1.NamedCluster{{Name: clusterName, Cluster: clientcmdv1.Cluster{Server: serverUrl}}},
+				AuthInfos:      []clientcmdv1.NamedAuthInfo{{Name: authName, AuthInfo: clientcmdv1.AuthInfo{Token: "abc"}}},
+				Contexts:       []clientcmdv1.NamedContext{{Name: contextName, Context: clientcmdv1.Context{Cluster: clusterName, AuthInfo: authName}}},
+				CurrentContext: contextName,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			kubeconfig := strconf.FromString(base64.StdEncoding.EncodeToString(raw))
+
+			tr = &tmv1beta1.Testrun{
+				ObjectMeta: metav1.ObjectMeta{Name: testrunName, Namespace: namespace},
+				Spec: tmv1beta1.TestrunSpec{
+					Kubeconfigs: tmv1beta1.TestrunKubeconfigs{
+						Shoot: kubeconfig,
+						Additional: map[string]*strconf.StringOrConfig{
+							"virtual-garden": kubeconfig,
+							"runtime":        kubeconfig,
+						},
+					},
+				},
+			}
+		})
+
+		It("should mount additional kubeconfigs by their name", func() {
+			configs, secrets, _, err := ParseKubeconfigs(context.Background(), fake.NewClientBuilder().Build(), tr)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(secrets).To(HaveLen(3))
+			Expect(secrets[1].GetName()).To(Equal("test-run-kubeconfig-runtime"))
+			Expect(secrets[2].GetName()).To(Equal("test-run-kubeconfig-virtual-garden"))
+
+			paths := make([]string, 0, len(configs))
+			for _, c := range configs {
+				paths = append(paths, c.Info.Path)
+			}
+			Expect(paths).To(Equal([]string{
+				fmt.Sprintf("%s/shoot.config", testmachinery.TM_KUBECONFIG_PATH),
+				fmt.Sprintf("%s/runtime.config", testmachinery.TM_KUBECONFIG_PATH),
+				fmt.Sprintf("%s/virtual-garden.config", testmachinery.TM_KUBECONFIG_PATH),
+			}))
+		})
+
+		It("should show the kubeconfigs of the steps in their status and only provide the shoot kubeconfig to untrusted steps", func() {
+			tr.Spec.TestFlow = tmv1beta1.TestFlow{
+				{Name: "trusted", Definition: tmv1beta1.StepDefinition{Name: "td"}},
+				{Name: "untrusted", Definition: tmv1beta1.StepDefinition{Name: "td", Untrusted: true}},
+			}
+			locs := &testutils.LocationsMock{TestDefinitions: []*testdefinition.TestDefinition{testutils.TestDef("td")}}
+			tf, err := testflow.New(testflow.FlowIDTest, tr.Spec.TestFlow, locs, nil, nil)
+			Expect(err).ToNot(HaveOccurred())
+
+			statuses := (&Testrun{Info: tr, Testflow: tf}).GetStepStatuses()
+			Expect(statuses).To(HaveLen(2))
+			Expect(statuses[0].Position.Step).To(Equal("trusted"))
+			Expect(statuses[0].Kubeconfigs).To(Equal([]string{"shoot", "runtime", "virtual-garden"}))
+			Expect(statuses[1].Position.Step).To(Equal("untrusted"))
+			Expect(statuses[1].Kubeconfigs).To(Equal([]string{"shoot"}))
+		})
+	})
 })
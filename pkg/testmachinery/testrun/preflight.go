@@ -0,0 +1,281 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package testrun
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"net/url"
+	"path"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdv1 "k8s.io/client-go/tools/clientcmd/api/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	"github.com/gardener/test-infra/pkg/apis/config"
+	tmv1beta1 "github.com/gardener/test-infra/pkg/apis/testmachinery/v1beta1"
+	"github.com/gardener/test-infra/pkg/util/strconf"
+)
+
+// now is used to determine the expiration of certificates and can be overwritten in tests.
+var now = time.Now
+
+// testrunKubeconfig is a kubeconfig of a testrun that is checked by the preflight.
+type testrunKubeconfig struct {
+	name       string
+	fldPath    *field.Path
+	raw        []byte
+	kubeconfig *clientcmdv1.Config
+}
+
+// PreflightKubeconfigs checks the kubeconfigs of a testrun so that broken kubeconfigs are detected before any step runs.
+// Every kubeconfig is parsed and checked for a valid current context, expired client certificates and token files
+// that cannot be mounted. The cluster of every kubeconfig is probed with a request to its /version endpoint if enabled.
+// Certificates that expire within the configured warning period are returned as warnings.
+// Returns if the checks can be retried.
+func PreflightKubeconfigs(ctx context.Context, reader client.Reader, tr *tmv1beta1.Testrun, cfg *config.KubeconfigPreflight) (field.ErrorList, []string, bool) {
+	var (
+		allErrs     field.ErrorList
+		warnings    []string
+		kubeconfigs []*testrunKubeconfig
+		// only retry if all errors are retryable
+		retry = true
+	)
+
+	fldPath := field.NewPath("spec", "kubeconfigs")
+	for _, kc := range getKubeconfigs(tr) {
+		kubeconfig, err := readKubeconfig(ctx, reader, tr.Namespace, kc)
+		if err != nil {
+			allErrs = append(allErrs, err)
+			retry = retry && err.Type == field.ErrorTypeInternal
+			continue
+		}
+		if kubeconfig == nil {
+			continue
+		}
+
+		errs, warns := checkKubeconfig(kubeconfig, tr.Namespace, cfg)
+		allErrs = append(allErrs, errs...)
+		warnings = append(warnings, warns...)
+		if len(errs) != 0 {
+			retry = false
+			continue
+		}
+		kubeconfigs = append(kubeconfigs, kubeconfig)
+	}
+
+	if len(allErrs) == 0 {
+		parsed := make(map[string]*clientcmdv1.Config, len(kubeconfigs))
+		for _, kubeconfig := range kubeconfigs {
+			parsed[kubeconfig.name] = kubeconfig.kubeconfig
+		}
+		if _, err := processTokenFileConfigs(parsed, tr.Namespace); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath, "", err.Error()))
+			retry = false
+		}
+	}
+
+	if len(allErrs) == 0 && cfg.ProbeServer {
+		for _, kubeconfig := range kubeconfigs {
+			if err := probeServer(ctx, kubeconfig, cfg.ProbeTimeout.Duration); err != nil {
+				allErrs = append(allErrs, err)
+			}
+		}
+	}
+
+	return allErrs, warnings, retry && len(allErrs) != 0
+}
+
+// readKubeconfig reads and parses a kubeconfig of a testrun.
+// Nil is returned if the kubeconfig is read from a source that cannot be checked.
+func readKubeconfig(ctx context.Context, reader client.Reader, namespace string, kc namedKubeconfig) (*testrunKubeconfig, *field.Error) {
+	var (
+		raw        []byte
+		fldPath    = kc.fldPath
+		kubeconfig = kc.kubeconfig
+	)
+	switch kubeconfig.Type {
+	case strconf.String:
+		decoded, err := base64.StdEncoding.DecodeString(kubeconfig.String())
+		if err != nil {
+			return nil, field.Invalid(fldPath, "", fmt.Sprintf("unable to decode kubeconfig: %s", err.Error()))
+		}
+		raw = decoded
+	case strconf.Config:
+		source := kubeconfig.Config()
+		switch {
+		case source.SecretKeyRef != nil:
+			secret := &corev1.Secret{}
+			if err := reader.Get(ctx, client.ObjectKey{Namespace: namespace, Name: source.SecretKeyRef.Name}, secret); err != nil {
+				return nil, readError(fldPath.Child("secretKeyRef"), "secret", source.SecretKeyRef.Name, err)
+			}
+			data, ok := secret.Data[source.SecretKeyRef.Key]
+			if !ok {
+				return nil, field.NotFound(fldPath.Child("secretKeyRef", "key"), source.SecretKeyRef.Key)
+			}
+			raw = data
+		case source.ConfigMapKeyRef != nil:
+			configMap := &corev1.ConfigMap{}
+			if err := reader.Get(ctx, client.ObjectKey{Namespace: namespace, Name: source.ConfigMapKeyRef.Name}, configMap); err != nil {
+				return nil, readError(fldPath.Child("configMapKeyRef"), "configmap", source.ConfigMapKeyRef.Name, err)
+			}
+			data, ok := configMap.Data[source.ConfigMapKeyRef.Key]
+			if !ok {
+				return nil, field.NotFound(fldPath.Child("configMapKeyRef", "key"), source.ConfigMapKeyRef.Key)
+			}
+			raw = []byte(data)
+		default:
+			return nil, nil
+		}
+	default:
+		return nil, nil
+	}
+
+	parsed := &clientcmdv1.Config{}
+	if err := yaml.Unmarshal(raw, parsed); err != nil {
+		return nil, field.Invalid(fldPath, "", fmt.Sprintf("unable to parse kubeconfig: %s", err.Error()))
+	}
+	return &testrunKubeconfig{
+		name:       kc.name,
+		fldPath:    fldPath,
+		raw:        raw,
+		kubeconfig: parsed,
+	}, nil
+}
+
+func readError(fldPath *field.Path, kind, name string, err error) *field.Error {
+	if apierrors.IsNotFound(err) {
+		return field.NotFound(fldPath.Child("name"), name)
+	}
+	return field.InternalError(fldPath, fmt.Errorf("unable to read %s %s: %w", kind, name, err))
+}
+
+// checkKubeconfig checks the current context, the client certificate and the token file of a kubeconfig.
+func checkKubeconfig(kc *testrunKubeconfig, namespace string, cfg *config.KubeconfigPreflight) (field.ErrorList, []string) {
+	var (
+		allErrs  field.ErrorList
+		warnings []string
+	)
+
+	cluster, authInfo, err := currentClusterAndAuthInfo(kc.kubeconfig)
+	if err != nil {
+		return field.ErrorList{field.Invalid(kc.fldPath, "", err.Error())}, nil
+	}
+
+	if u, err := url.Parse(cluster.Server); err != nil || !u.IsAbs() {
+		allErrs = append(allErrs, field.Invalid(kc.fldPath, cluster.Server, "server must be an absolute url"))
+	}
+
+	if len(authInfo.ClientCertificateData) != 0 {
+		cert, err := parseCertificate(authInfo.ClientCertificateData)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(kc.fldPath, "", fmt.Sprintf("unable to parse client certificate: %s", err.Error())))
+		} else if expiration := cert.NotAfter; now().After(expiration) {
+			allErrs = append(allErrs, field.Invalid(kc.fldPath, "", fmt.Sprintf("client certificate expired at %s", expiration.UTC().Format(time.RFC3339))))
+		} else if now().Add(cfg.CertificateExpirationWarning.Duration).After(expiration) {
+			warnings = append(warnings, fmt.Sprintf("%s: client certificate expires at %s", kc.fldPath.String(), expiration.UTC().Format(time.RFC3339)))
+		}
+	}
+
+	if tokenFile := authInfo.TokenFile; len(tokenFile) != 0 {
+		if dir, file := path.Split(tokenFile); !path.IsAbs(tokenFile) || len(dir) == 0 || len(file) == 0 {
+			allErrs = append(allErrs, field.Invalid(kc.fldPath, tokenFile, "token file must be an absolute path to a file"))
+		} else if _, err := processTokenFileConfigs(map[string]*clientcmdv1.Config{kc.name: kc.kubeconfig}, namespace); err != nil {
+			allErrs = append(allErrs, field.Invalid(kc.fldPath, tokenFile, err.Error()))
+		}
+	}
+
+	return allErrs, warnings
+}
+
+// currentClusterAndAuthInfo returns the cluster and auth info of the current context of a kubeconfig.
+func currentClusterAndAuthInfo(kubeconfig *clientcmdv1.Config) (*clientcmdv1.Cluster, *clientcmdv1.AuthInfo, error) {
+	if len(kubeconfig.CurrentContext) == 0 {
+		return nil, nil, fmt.Errorf("current-context is not set")
+	}
+	var currentContext *clientcmdv1.Context
+	for i, c := range kubeconfig.Contexts {
+		if c.Name == kubeconfig.CurrentContext {
+			currentContext = &kubeconfig.Contexts[i].Context
+		}
+	}
+	if currentContext == nil {
+		return nil, nil, fmt.Errorf("current-context %q is not defined", kubeconfig.CurrentContext)
+	}
+
+	var (
+		cluster  *clientcmdv1.Cluster
+		authInfo *clientcmdv1.AuthInfo
+	)
+	for i, c := range kubeconfig.Clusters {
+		if c.Name == currentContext.Cluster {
+			cluster = &kubeconfig.Clusters[i].Cluster
+		}
+	}
+	if cluster == nil {
+		return nil, nil, fmt.Errorf("cluster %q of context %q is not defined", currentContext.Cluster, kubeconfig.CurrentContext)
+	}
+	for i, a := range kubeconfig.AuthInfos {
+		if a.Name == currentContext.AuthInfo {
+			authInfo = &kubeconfig.AuthInfos[i].AuthInfo
+		}
+	}
+	if authInfo == nil {
+		return nil, nil, fmt.Errorf("user %q of context %q is not defined", currentContext.AuthInfo, kubeconfig.CurrentContext)
+	}
+	return cluster, authInfo, nil
+}
+
+// parseCertificate parses the first certificate of pem encoded data.
+func parseCertificate(data []byte) (*x509.Certificate, error) {
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return nil, fmt.Errorf("no pem encoded certificate found")
+		}
+		if block.Type == "CERTIFICATE" {
+			return x509.ParseCertificate(block.Bytes)
+		}
+	}
+}
+
+// probeServer requests the version of the cluster of a kubeconfig.
+// Kubeconfigs whose credentials are not available to the testmachinery are not probed.
+func probeServer(ctx context.Context, kc *testrunKubeconfig, timeout time.Duration) *field.Error {
+	_, authInfo, err := currentClusterAndAuthInfo(kc.kubeconfig)
+	if err != nil {
+		return field.Invalid(kc.fldPath, "", err.Error())
+	}
+	if len(authInfo.TokenFile) != 0 || authInfo.Exec != nil || authInfo.AuthProvider != nil {
+		return nil
+	}
+
+	restConfig, err := clientcmd.RESTConfigFromKubeConfig(kc.raw)
+	if err != nil {
+		return field.Invalid(kc.fldPath, "", err.Error())
+	}
+	restConfig.Timeout = timeout
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(restConfig)
+	if err != nil {
+		return field.Invalid(kc.fldPath, "", err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if err := discoveryClient.RESTClient().Get().AbsPath("/version").Do(ctx).Error(); err != nil {
+		return field.InternalError(kc.fldPath, fmt.Errorf("unable to reach cluster %s: %w", restConfig.Host, err))
+	}
+	return nil
+}
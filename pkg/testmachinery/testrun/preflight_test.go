@@ -0,0 +1,233 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package testrun
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	clientcmdv1 "k8s.io/client-go/tools/clientcmd/api/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+
+	"github.com/gardener/test-infra/pkg/apis/config"
+	tmv1beta1 "github.com/gardener/test-infra/pkg/apis/testmachinery/v1beta1"
+	"github.com/gardener/test-infra/pkg/testmachinery"
+	"github.com/gardener/test-infra/pkg/util/strconf"
+)
+
+var _ = Describe("kubeconfig preflight", func() {
+	var (
+		ctx        context.Context
+		tr         *tmv1beta1.Testrun
+		kubeconfig *clientcmdv1.Config
+		preflight  *config.KubeconfigPreflight
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		Expect(testmachinery.Setup(&config.Configuration{})).To(Succeed())
+		now = func() time.Time { return time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC) }
+		DeferCleanup(func() { now = time.Now })
+
+		kubeconfig = &clientcmdv1.Config{
+			Clusters:       []clientcmdv1.NamedCluster{{Name: clusterName, Cluster: clientcmdv1.Cluster{Server: serverUrl}}},
+			AuthInfos:      []clientcmdv1.NamedAuthInfo{{Name: authName, AuthInfo: clientcmdv1.AuthInfo{Token: "abc"}}},
+			Contexts:       []clientcmdv1.NamedContext{{Name: contextName, Context: clientcmdv1.Context{Cluster: clusterName, AuthInfo: authName}}},
+			CurrentContext: contextName,
+		}
+		tr = &tmv1beta1.Testrun{
+			ObjectMeta: metav1.ObjectMeta{Name: testrunName, Namespace: namespace},
+		}
+		preflight = &config.KubeconfigPreflight{
+			CertificateExpirationWarning: metav1.Duration{Duration: 24 * time.Hour},
+			ProbeTimeout:                 metav1.Duration{Duration: 5 * time.Second},
+		}
+	})
+
+	run := func(objects ...corev1.Secret) (field.ErrorList, []string, bool) {
+		builder := fake.NewClientBuilder()
+		for i := range objects {
+			builder.WithObjects(&objects[i])
+		}
+		return PreflightKubeconfigs(ctx, builder.Build(), tr, preflight)
+	}
+
+	It("should accept a valid kubeconfig", func() {
+		tr.Spec.Kubeconfigs.Shoot = stringKubeconfig(kubeconfig)
+
+		allErrs, warnings, _ := run()
+		Expect(allErrs).To(BeEmpty())
+		Expect(warnings).To(BeEmpty())
+	})
+
+	It("should reject a kubeconfig that cannot be decoded", func() {
+		tr.Spec.Kubeconfigs.Shoot = strconf.FromString("not base64")
+
+		allErrs, _, retry := run()
+		Expect(allErrs).To(ConsistOf(HaveField("Field", "spec.kubeconfigs.shoot")))
+		Expect(allErrs.ToAggregate().Error()).To(ContainSubstring("unable to decode kubeconfig"))
+		Expect(retry).To(BeFalse())
+	})
+
+	It("should reject a kubeconfig with an undefined current context", func() {
+		kubeconfig.CurrentContext = "other"
+		tr.Spec.Kubeconfigs.Seed = stringKubeconfig(kubeconfig)
+
+		allErrs, _, _ := run()
+		Expect(allErrs).To(ConsistOf(HaveField("Field", "spec.kubeconfigs.seed")))
+		Expect(allErrs.ToAggregate().Error()).To(ContainSubstring(`current-context "other" is not defined`))
+	})
+
+	It("should reject a kubeconfig with an expired client certificate", func() {
+		kubeconfig.AuthInfos[0].AuthInfo = clientcmdv1.AuthInfo{ClientCertificateData: certificate(now().Add(-time.Hour))}
+		tr.Spec.Kubeconfigs.Gardener = stringKubeconfig(kubeconfig)
+
+		allErrs, _, retry := run()
+		Expect(allErrs).To(ConsistOf(HaveField("Field", "spec.kubeconfigs.gardener")))
+		Expect(allErrs.ToAggregate().Error()).To(ContainSubstring("client certificate expired at 2024-05-31T23:00:00Z"))
+		Expect(retry).To(BeFalse())
+	})
+
+	It("should warn about a client certificate that expires soon", func() {
+		kubeconfig.AuthInfos[0].AuthInfo = clientcmdv1.AuthInfo{ClientCertificateData: certificate(now().Add(time.Hour))}
+		tr.Spec.Kubeconfigs.Gardener = stringKubeconfig(kubeconfig)
+
+		allErrs, warnings, _ := run()
+		Expect(allErrs).To(BeEmpty())
+		Expect(warnings).To(ConsistOf("spec.kubeconfigs.gardener: client certificate expires at 2024-06-01T01:00:00Z"))
+	})
+
+	It("should reject a relative token file", func() {
+		kubeconfig.AuthInfos[0].AuthInfo = clientcmdv1.AuthInfo{TokenFile: "token"}
+		tr.Spec.Kubeconfigs.Shoot = stringKubeconfig(kubeconfig)
+
+		allErrs, _, _ := run()
+		Expect(allErrs.ToAggregate().Error()).To(ContainSubstring("token file must be an absolute path to a file"))
+	})
+
+	It("should reject a token file without a matching landscape mapping", func() {
+		kubeconfig.AuthInfos[0].AuthInfo = clientcmdv1.AuthInfo{TokenFile: "/var/run/secrets/token"}
+		tr.Spec.Kubeconfigs.Gardener = stringKubeconfig(kubeconfig)
+
+		allErrs, _, _ := run()
+		Expect(allErrs).To(ConsistOf(HaveField("Field", "spec.kubeconfigs.gardener")))
+		Expect(allErrs.ToAggregate().Error()).To(ContainSubstring("no matching landsacpeMapping was found"))
+	})
+
+	It("should read kubeconfigs from secrets", func() {
+		kubeconfig.CurrentContext = ""
+		raw, err := yaml.Marshal(kubeconfig)
+		Expect(err).ToNot(HaveOccurred())
+		tr.Spec.Kubeconfigs.Host = strconf.FromConfig(strconf.ConfigSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "kubeconfig"},
+				Key:                  "kubeconfig",
+			},
+		})
+
+		allErrs, _, _ := run(corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "kubeconfig", Namespace: namespace},
+			Data:       map[string][]byte{"kubeconfig": raw},
+		})
+		Expect(allErrs).To(ConsistOf(HaveField("Field", "spec.kubeconfigs.host")))
+		Expect(allErrs.ToAggregate().Error()).To(ContainSubstring("current-context is not set"))
+	})
+
+	It("should reject a kubeconfig of a secret that does not exist", func() {
+		tr.Spec.Kubeconfigs.Host = strconf.FromConfig(strconf.ConfigSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "kubeconfig"},
+				Key:                  "kubeconfig",
+			},
+		})
+
+		allErrs, _, retry := run()
+		Expect(allErrs).To(ConsistOf(And(
+			HaveField("Type", field.ErrorTypeNotFound),
+			HaveField("Field", "spec.kubeconfigs.host.secretKeyRef.name"),
+		)))
+		Expect(retry).To(BeFalse())
+	})
+
+	Context("probe", func() {
+		BeforeEach(func() {
+			preflight.ProbeServer = true
+		})
+
+		It("should request the version of the cluster", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				defer GinkgoRecover()
+				Expect(r.URL.Path).To(Equal("/version"))
+				_, _ = w.Write([]byte(`{"major": "1", "minor": "30"}`))
+			}))
+			defer server.Close()
+			kubeconfig.Clusters[0].Cluster.Server = server.URL
+			tr.Spec.Kubeconfigs.Shoot = stringKubeconfig(kubeconfig)
+
+			allErrs, _, _ := run()
+			Expect(allErrs).To(BeEmpty())
+		})
+
+		It("should return a retryable error if the cluster is not reachable", func() {
+			server := httptest.NewServer(http.NotFoundHandler())
+			server.Close()
+			kubeconfig.Clusters[0].Cluster.Server = server.URL
+			tr.Spec.Kubeconfigs.Shoot = stringKubeconfig(kubeconfig)
+
+			allErrs, _, retry := run()
+			Expect(allErrs).To(ConsistOf(And(
+				HaveField("Type", field.ErrorTypeInternal),
+				HaveField("Field", "spec.kubeconfigs.shoot"),
+			)))
+			Expect(retry).To(BeTrue())
+		})
+
+		It("should not probe clusters of kubeconfigs with exec plugins", func() {
+			kubeconfig.AuthInfos[0].AuthInfo = clientcmdv1.AuthInfo{Exec: &clientcmdv1.ExecConfig{Command: "login"}}
+			kubeconfig.Clusters[0].Cluster.Server = "https://127.0.0.1:1"
+			tr.Spec.Kubeconfigs.Shoot = stringKubeconfig(kubeconfig)
+
+			allErrs, _, _ := run()
+			Expect(allErrs).To(BeEmpty())
+		})
+	})
+})
+
+func stringKubeconfig(kubeconfig *clientcmdv1.Config) *strconf.StringOrConfig {
+	raw, err := yaml.Marshal(kubeconfig)
+	Expect(err).ToNot(HaveOccurred())
+	return strconf.FromString(base64.StdEncoding.EncodeToString(raw))
+}
+
+// certificate returns a pem encoded self-signed certificate that expires at the given time.
+func certificate(notAfter time.Time) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).ToNot(HaveOccurred())
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "test"},
+		NotBefore:    notAfter.Add(-30 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	Expect(err).ToNot(HaveOccurred())
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
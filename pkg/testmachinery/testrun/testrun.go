@@ -9,6 +9,7 @@ import (
 
 	argov1 "github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
 	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	tmv1beta1 "github.com/gardener/test-infra/pkg/apis/testmachinery/v1beta1"
@@ -29,7 +30,7 @@ func New(ctx context.Context, log logr.Logger, tr *tmv1beta1.Testrun, reader cli
 		return nil, err
 	}
 
-	locs, err := locations.NewLocations(log, tr.Spec)
+	locs, err := locations.NewLocations(log, tr.Spec, tr.Status.ResolvedLocations)
 	if err != nil {
 		return nil, err
 	}
@@ -58,6 +59,12 @@ func New(ctx context.Context, log logr.Logger, tr *tmv1beta1.Testrun, reader cli
 		return nil, err
 	}
 
+	configSecrets, err := ResolveConfigSources(ctx, reader, tr, tf, onExitFlow)
+	if err != nil {
+		return nil, err
+	}
+	secrets = append(secrets, configSecrets...)
+
 	return &Testrun{
 		Info:            tr,
 		Testflow:        tf,
@@ -67,6 +74,29 @@ func New(ctx context.Context, log logr.Logger, tr *tmv1beta1.Testrun, reader cli
 	}, nil
 }
 
+// GetStepStatuses returns the status of all steps of the testflow
+// including the names of the kubeconfigs that are available to the steps.
+func (tr *Testrun) GetStepStatuses() []*tmv1beta1.StepStatus {
+	untrustedSteps := sets.New[string]()
+	for _, step := range tr.Info.Spec.TestFlow {
+		if step.Definition.Untrusted {
+			untrustedSteps.Insert(step.Name)
+		}
+	}
+	trustedKubeconfigs := KubeconfigNames(tr.Info, false)
+	untrustedKubeconfigs := KubeconfigNames(tr.Info, true)
+
+	statuses := tr.Testflow.Flow.GetStatuses()
+	for _, status := range statuses {
+		if untrustedSteps.Has(status.Position.Step) {
+			status.Kubeconfigs = append([]string(nil), untrustedKubeconfigs...)
+			continue
+		}
+		status.Kubeconfigs = append([]string(nil), trustedKubeconfigs...)
+	}
+	return statuses
+}
+
 // GetWorkflow returns the argo workflow object of this testrun.
 func (tr *Testrun) GetWorkflow(name, namespace string, pullImageSecretNames []string) (*argov1.Workflow, error) {
 	testrunName := "testrun"
@@ -10,18 +10,25 @@ import (
 
 	tmv1beta1 "github.com/gardener/test-infra/pkg/apis/testmachinery/v1beta1"
 	"github.com/gardener/test-infra/pkg/apis/testmachinery/v1beta1/validation"
+	"github.com/gardener/test-infra/pkg/testmachinery"
+	"github.com/gardener/test-infra/pkg/testmachinery/configsource"
 	"github.com/gardener/test-infra/pkg/testmachinery/locations"
+	"github.com/gardener/test-infra/pkg/testmachinery/policy"
 	"github.com/gardener/test-infra/pkg/testmachinery/testflow"
 )
 
 // Validate validates a testrun.
+// The revisions of all git locations are resolved to commits and stored in the status of the testrun.
 // Returns if the validation can be retried
 func Validate(log logr.Logger, tr *tmv1beta1.Testrun) (error, bool) {
 	if allErrs := validation.ValidateTestrunSpec(tr.Spec); len(allErrs) != 0 {
 		return allErrs.ToAggregate(), false
 	}
+	if allErrs := validateConfigSources(tr); len(allErrs) != 0 {
+		return allErrs.ToAggregate(), false
+	}
 
-	locs, err := locations.NewLocations(log, tr.Spec)
+	locs, err := locations.NewLocations(log, tr.Spec, tr.Status.ResolvedLocations)
 	if err != nil {
 		return err, true
 	}
@@ -32,6 +39,73 @@ func Validate(log logr.Logger, tr *tmv1beta1.Testrun) (error, bool) {
 		// only retry of both errors are retryable
 		retry = retry && re
 	}
+	if len(allErrs) == 0 {
+		allErrs, retry = evaluatePolicies(log, tr, locs)
+	}
+	if len(allErrs) == 0 {
+		tr.Status.ResolvedLocations = locs.ResolvedLocations()
+	}
 
 	return allErrs.ToAggregate(), retry
 }
+
+// evaluatePolicies evaluates the admission policies for the TestDefinitions of all steps.
+// The TestDefinitions are only known after the locations are resolved so that the policies cannot be evaluated by the admission webhook.
+// Violations of policies that only warn are logged.
+func evaluatePolicies(log logr.Logger, tr *tmv1beta1.Testrun, locs locations.Locations) (field.ErrorList, bool) {
+	policies := testmachinery.AdmissionPolicies()
+	if policies == nil || (policies.Images == nil && policies.ActiveDeadline == nil) {
+		return nil, false
+	}
+
+	var (
+		allErrs field.ErrorList
+		retry   bool
+	)
+	evaluate := func(fldPath *field.Path, flow tmv1beta1.TestFlow) {
+		for i, step := range flow {
+			stepPath := fldPath.Index(i).Child("definition")
+			testDefinitions, err := locs.GetTestDefinitions(step.Definition)
+			if err != nil {
+				allErrs = append(allErrs, field.InternalError(stepPath, err))
+				retry = true
+				continue
+			}
+			for _, td := range testDefinitions {
+				result := policy.EvaluateTestDefinition(stepPath.Key(td.Info.GetName()), policies, td.Info)
+				allErrs = append(allErrs, result.Errors...)
+				for _, warning := range result.Warnings {
+					log.Info("admission policy violated", "warning", warning)
+				}
+			}
+		}
+	}
+	evaluate(field.NewPath("spec", "testflow"), tr.Spec.TestFlow)
+	evaluate(field.NewPath("spec", "onExit"), tr.Spec.OnExit)
+
+	return allErrs, retry
+}
+
+// validateConfigSources validates that the sources of all config values of the testrun are allowed by the testmachinery configuration.
+func validateConfigSources(tr *tmv1beta1.Testrun) field.ErrorList {
+	var allErrs field.ErrorList
+	allErrs = append(allErrs, validateConfigSourceList(field.NewPath("spec", "config"), tr.Spec.Config, tr.Namespace)...)
+	for i, step := range tr.Spec.TestFlow {
+		allErrs = append(allErrs, validateConfigSourceList(field.NewPath("spec", "testflow").Index(i).Child("definition", "config"), step.Definition.Config, tr.Namespace)...)
+	}
+	for i, step := range tr.Spec.OnExit {
+		allErrs = append(allErrs, validateConfigSourceList(field.NewPath("spec", "onExit").Index(i).Child("definition", "config"), step.Definition.Config, tr.Namespace)...)
+	}
+	return allErrs
+}
+
+func validateConfigSourceList(fldPath *field.Path, configs []tmv1beta1.ConfigElement, namespace string) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, cfg := range configs {
+		if cfg.ValueFrom == nil {
+			continue
+		}
+		allErrs = append(allErrs, configsource.Validate(fldPath.Index(i).Child("valueFrom"), cfg.ValueFrom, namespace, testmachinery.ConfigSources())...)
+	}
+	return allErrs
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/gardener/test-infra/pkg/testrunner/componentdescriptor"
 	"github.com/gardener/test-infra/pkg/util"
 	"github.com/gardener/test-infra/pkg/util/output"
+	"github.com/gardener/test-infra/pkg/util/redact"
 )
 
 // Collect collects results of all testruns and writes them to a file.
@@ -46,7 +47,7 @@ func (c *Collector) Collect(ctx context.Context, log logr.Logger, tmClient clien
 			testrunsFailed = append(testrunsFailed, run.Testrun.Name)
 			runLogger.Error(fmt.Errorf("testrun failed with phase %s", run.Testrun.Status.Phase), "")
 		}
-		fmt.Print(util.PrettyPrintStruct(run.Testrun.Status))
+		fmt.Print(util.PrettyPrintStruct(redact.Testrun(run.Testrun).Status))
 		output.RenderStatusTable(os.Stdout, run.Testrun.Status.Steps)
 		fmt.Println(":---------------------------------------------------------------------------------------------:")
 	}
@@ -55,8 +56,9 @@ func (c *Collector) Collect(ctx context.Context, log logr.Logger, tmClient clien
 	if err := c.postTestrunsSummaryInSlack(c.config, log, runs); err != nil {
 		log.Error(err, "error while posting notification on slack")
 	}
-	fmt.Println(runs.RenderTable())
-	if err := logger.PostToSummaryFile(runs.RenderTableWithSymbols(tw.StyleMarkdown), true); err != nil {
+	sensitiveValues := redact.SensitiveValues(runs.GetTestruns()...)
+	fmt.Println(redact.String(runs.RenderTable(), sensitiveValues))
+	if err := logger.PostToSummaryFile(redact.String(runs.RenderTableWithSymbols(tw.StyleMarkdown), sensitiveValues), true); err != nil {
 		log.Error(err, "unable to post summary to github step summary")
 	}
 
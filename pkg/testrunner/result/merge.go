@@ -0,0 +1,73 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package result
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+)
+
+// MergeAssetOverviewFiles reads all asset overview files that match the given glob patterns and merges them into one overview.
+// The merged overview is written to the target path if a target path is given.
+func MergeAssetOverviewFiles(log logr.Logger, patterns []string, target string) (AssetOverview, error) {
+	overviews := make([]AssetOverview, 0)
+	for _, pattern := range patterns {
+		files, err := filepath.Glob(pattern)
+		if err != nil {
+			return AssetOverview{}, errors.Wrapf(err, "invalid results file pattern %s", pattern)
+		}
+		if len(files) == 0 {
+			return AssetOverview{}, fmt.Errorf("no results file found for %s", pattern)
+		}
+		for _, file := range files {
+			overview, err := unmarshalOverview(file)
+			if err != nil {
+				return AssetOverview{}, err
+			}
+			log.V(3).Info("read results file", "file", file, "items", len(overview.AssetOverviewItems))
+			overviews = append(overviews, overview)
+		}
+	}
+
+	merged := MergeAssetOverviews(overviews...)
+	log.Info(fmt.Sprintf("merged %d results files with %d items", len(overviews), len(merged.AssetOverviewItems)))
+
+	if target != "" {
+		if err := writeOverviewToFile(merged, target); err != nil {
+			return AssetOverview{}, err
+		}
+	}
+	return merged, nil
+}
+
+// MergeAssetOverviews merges multiple asset overviews into one overview.
+// Items with the same name are only added once whereas a successful item takes precedence over a failed one.
+// The items of the resulting overview are sorted by their name.
+func MergeAssetOverviews(overviews ...AssetOverview) AssetOverview {
+	items := make(map[string]AssetOverviewItem)
+	for _, overview := range overviews {
+		for _, item := range overview.AssetOverviewItems {
+			if existing, ok := items[item.Name]; ok && existing.Successful {
+				continue
+			}
+			items[item.Name] = item
+		}
+	}
+
+	merged := AssetOverview{
+		AssetOverviewItems: make([]AssetOverviewItem, 0, len(items)),
+	}
+	for _, item := range items {
+		merged.AssetOverviewItems = append(merged.AssetOverviewItems, item)
+	}
+	sort.Slice(merged.AssetOverviewItems, func(i, j int) bool {
+		return merged.AssetOverviewItems[i].Name < merged.AssetOverviewItems[j].Name
+	})
+	return merged
+}
@@ -0,0 +1,64 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package result
+
+import (
+	"path/filepath"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/gardener/test-infra/pkg/testmachinery/metadata"
+)
+
+var _ = Describe("merge results", func() {
+
+	It("should merge overviews and prefer successful items", func() {
+		a := AssetOverview{AssetOverviewItems: []AssetOverviewItem{
+			{Name: "b", Successful: false},
+			{Name: "a", Successful: true},
+		}}
+		b := AssetOverview{AssetOverviewItems: []AssetOverviewItem{
+			{Name: "b", Successful: true},
+			{Name: "c", Successful: false},
+			{Name: "a", Successful: false},
+		}}
+
+		merged := MergeAssetOverviews(a, b)
+		Expect(merged.AssetOverviewItems).To(Equal([]AssetOverviewItem{
+			{Name: "a", Successful: true},
+			{Name: "b", Successful: true},
+			{Name: "c", Successful: false},
+		}))
+	})
+
+	It("should merge overview files into one file", func() {
+		dir := GinkgoT().TempDir()
+		shard0 := AssetOverview{AssetOverviewItems: []AssetOverviewItem{
+			{Name: "a", Successful: true, Dimension: metadata.Dimension{Cloudprovider: "gcp"}},
+		}}
+		shard1 := AssetOverview{AssetOverviewItems: []AssetOverviewItem{
+			{Name: "b", Successful: false, Dimension: metadata.Dimension{Cloudprovider: "aws"}},
+		}}
+		Expect(writeOverviewToFile(shard0, filepath.Join(dir, "shard-0_overview.json"))).To(Succeed())
+		Expect(writeOverviewToFile(shard1, filepath.Join(dir, "shard-1_overview.json"))).To(Succeed())
+
+		target := filepath.Join(dir, "merged.json")
+		merged, err := MergeAssetOverviewFiles(logr.Discard(), []string{filepath.Join(dir, "shard-*_overview.json")}, target)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(merged.AssetOverviewItems).To(HaveLen(2))
+
+		fromFile, err := unmarshalOverview(target)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fromFile).To(Equal(merged))
+		Expect(ParseOverviewToTableItems(fromFile)).To(HaveLen(2))
+	})
+
+	It("should fail if a pattern does not match any file", func() {
+		_, err := MergeAssetOverviewFiles(logr.Discard(), []string{filepath.Join(GinkgoT().TempDir(), "*.json")}, "")
+		Expect(err).To(HaveOccurred())
+	})
+})
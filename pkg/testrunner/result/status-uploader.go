@@ -27,6 +27,7 @@ import (
 	"github.com/gardener/test-infra/pkg/testrunner/componentdescriptor"
 	"github.com/gardener/test-infra/pkg/util"
 	"github.com/gardener/test-infra/pkg/util/output"
+	"github.com/gardener/test-infra/pkg/util/redact"
 )
 
 // StoreResultsAsFiles will assemble an overview status file and a zip archive with the detailed status of each testrun, and stores them locally as files.
@@ -263,7 +264,7 @@ func writeOverviewToFile(assetOverview AssetOverview, overviewFilepath string) e
 func storeRunsStatusAsFiles(log logr.Logger, runs testrunner.RunList, prefix, dest string) error {
 	log.Info(fmt.Sprintf("storing testruns status as files in %s", dest))
 	for _, run := range runs {
-		tr := run.Testrun
+		tr := redact.Testrun(run.Testrun)
 		tableString := strings.Builder{}
 		output.RenderStatusTable(&tableString, tr.Status.Steps)
 		statusOutput := fmt.Sprintf("Testrun: %s\n\n%s\n%s", tr.Name, tableString.String(), util.PrettyPrintStruct(tr.Status))
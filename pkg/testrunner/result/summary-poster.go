@@ -17,6 +17,7 @@ import (
 	trerrors "github.com/gardener/test-infra/pkg/common/error"
 	"github.com/gardener/test-infra/pkg/testrunner"
 	"github.com/gardener/test-infra/pkg/util"
+	"github.com/gardener/test-infra/pkg/util/redact"
 	"github.com/gardener/test-infra/pkg/util/slack"
 )
 
@@ -25,11 +26,36 @@ func (c *Collector) postTestrunsSummaryInSlack(config Config, log logr.Logger, r
 		return nil
 	}
 
-	tableItems := parseTestrunsToTableItems(runs)
+	tmDashboardURL := ""
+	if len(runs.GetTestruns()) > 0 {
+		tmDashboardURL = runs.GetTestruns()[0].Annotations[common.AnnotationTMDashboardURL]
+	}
+
+	executionGroup := ""
+	if len(runs.GetTestruns()) > 0 {
+		executionGroup = runs.GetTestruns()[0].Labels[common.LabelTestrunExecutionGroup]
+	}
+	urlFooter := buildURLFooter(config.CICDJobURL, tmDashboardURL, config.GrafanaURL, executionGroup)
+
+	return postTableItemsInSlack(config, log, parseTestrunsToTableItems(runs), urlFooter, redact.SensitiveValues(runs.GetTestruns()...))
+}
+
+// PostOverviewSummaryInSlack posts the summary of a (merged) asset overview in slack.
+func PostOverviewSummaryInSlack(config Config, log logr.Logger, overview AssetOverview) error {
+	urlFooter := buildURLFooter(config.CICDJobURL, "", config.GrafanaURL, "")
+	return postTableItemsInSlack(config, log, ParseOverviewToTableItems(overview), urlFooter, nil)
+}
+
+// postTableItemsInSlack renders the given table items and posts them with the given footer in the configured slack channel.
+// The message is split into multiple messages if it exceeds the slack message limit.
+// All occurrences of the given sensitive values are redacted.
+func postTableItemsInSlack(config Config, log logr.Logger, tableItems util.TableItems, urlFooter string, sensitiveValues []string) error {
 	table, err := util.RenderTableForSlack(log, tableItems)
 	if err != nil {
 		return errors.Wrap(err, "failed creating a table to post")
 	}
+	table = redact.String(table, sensitiveValues)
+	urlFooter = redact.String(urlFooter, sensitiveValues)
 	if table == "" {
 		log.Info("no table to render")
 		return nil
@@ -40,17 +66,6 @@ func (c *Collector) postTestrunsSummaryInSlack(config Config, log logr.Logger, r
 		return errors.Wrap(err, "was not able to create slack client")
 	}
 
-	tmDashboardURL := ""
-	if len(runs.GetTestruns()) > 0 {
-		tmDashboardURL = runs.GetTestruns()[0].Annotations[common.AnnotationTMDashboardURL]
-	}
-
-	executionGroup := ""
-	if len(runs.GetTestruns()) > 0 {
-		executionGroup = runs.GetTestruns()[0].Labels[common.LabelTestrunExecutionGroup]
-	}
-	urlFooter := buildURLFooter(config.CICDJobURL, tmDashboardURL, config.GrafanaURL, executionGroup)
-
 	chunks := util.SplitString(fmt.Sprintf("%s\n%s", table, legend()), slack.MaxMessageLimit-100) // -100 to have space for header and footer messages
 	if len(chunks) == 1 {
 		return slackClient.PostMessage(config.SlackChannel, fmt.Sprintf("%s\n```%s\n%s```\n%s", header(), table, legend(), urlFooter))
@@ -143,3 +158,23 @@ func parseTestrunsToTableItems(runs testrunner.RunList) (tableItems util.TableIt
 	}
 	return tableItems
 }
+
+// ParseOverviewToTableItems converts the items of an asset overview into table items.
+func ParseOverviewToTableItems(overview AssetOverview) (tableItems util.TableItems) {
+	for _, overviewItem := range overview.AssetOverviewItems {
+		meta := overviewItem.Dimension
+		if meta.Cloudprovider == "" {
+			// skip gardener tests
+			continue
+		}
+		status := util.StatusSymbolFailure
+		if overviewItem.Successful {
+			status = util.StatusSymbolSuccess
+		}
+		tableItems = append(tableItems, &util.TableItem{
+			Meta:         util.ItemMeta{CloudProvider: meta.Cloudprovider, TestrunID: overviewItem.Name, OperatingSystem: meta.OperatingSystem, KubernetesVersion: meta.KubernetesVersion, FlavorDescription: meta.Description},
+			StatusSymbol: status,
+		})
+	}
+	return tableItems
+}
@@ -6,6 +6,7 @@ package template
 
 import (
 	"fmt"
+	"strconv"
 
 	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
 	v1beta1helper "github.com/gardener/gardener/pkg/apis/core/v1beta1/helper"
@@ -176,6 +177,13 @@ func (r *shootValueRenderer) GetValues(shoot *common.ExtendedShoot, defaultValue
 	if shoot.AdditionalAnnotations != nil {
 		values["shoot"].(map[string]interface{})["shootAnnotations"] = util.MarshalMap(shoot.AdditionalAnnotations)
 	}
+	if shoot.Upgrade != nil {
+		upgrade, err := getUpgradeValues(shoot)
+		if err != nil {
+			return nil, err
+		}
+		values["shoot"].(map[string]any)["upgrade"] = upgrade
+	}
 	if hasInPlaceWorker {
 		values["shoot"].(map[string]any)["machineImagePrevVersion"] = prevVersionThatCanBeInPlaceUpdatedToCurrent
 		values["shoot"].(map[string]any)["machine"] = map[string]any{
@@ -195,7 +203,7 @@ func (r *shootValueRenderer) GetMetadata(shoot *common.ExtendedShoot) (*metadata
 	if shoot.Workers[0].CRI != nil {
 		containerRuntime = string(shoot.Workers[0].CRI.Name)
 	}
-	return &metadata.Metadata{
+	meta := &metadata.Metadata{
 		FlavorDescription:      shoot.Description,
 		Landscape:              r.parameters.Landscape,
 		ComponentDescriptor:    r.parameters.ComponentDescriptor.JSON(),
@@ -207,5 +215,56 @@ func (r *shootValueRenderer) GetMetadata(shoot *common.ExtendedShoot) (*metadata
 		OperatingSystemVersion: operatingsystemversion,
 		ContainerRuntime:       containerRuntime,
 		Annotations:            shoot.AdditionalAnnotations,
+	}
+	if shoot.Selection != nil {
+		meta.FlavorSelectionStrategy = string(shoot.Selection.Strategy)
+		meta.FlavorSelectionSeed = strconv.FormatInt(shoot.Selection.Seed, 10)
+		if shoot.Selection.Strategy == common.FlavorSelectionStrategyRotating {
+			meta.FlavorSelectionDay = strconv.FormatInt(shoot.Selection.Day, 10)
+		}
+	}
+	if shoot.Upgrade != nil {
+		if shoot.Upgrade.KubernetesVersion != nil {
+			meta.KubernetesUpgradeFromVersion = shoot.Upgrade.KubernetesVersion.Version
+		}
+		meta.OperatingSystemUpgradeFromVersion = shoot.Upgrade.MachineImageVersions[shoot.Workers[0].Name]
+	}
+	return meta, nil
+}
+
+// getUpgradeValues returns the chart values that describe the upgrade path of a shoot.
+// The source worker pools are rendered with the machine image versions the shoot is created with.
+func getUpgradeValues(shoot *common.ExtendedShoot) (map[string]any, error) {
+	sourceWorkers, err := encodeRawObject(shootflavors.UpgradeSourceWorkers(shoot))
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to parse upgrade worker config")
+	}
+
+	k8sFromVersion := shoot.KubernetesVersion.Version
+	if shoot.Upgrade.KubernetesVersion != nil {
+		k8sFromVersion = shoot.Upgrade.KubernetesVersion.Version
+	}
+
+	machineImages := make(map[string]any, len(shoot.Workers))
+	for _, worker := range shoot.Workers {
+		if worker.Machine.Image == nil || worker.Machine.Image.Version == nil {
+			continue
+		}
+		fromVersion, ok := shoot.Upgrade.MachineImageVersions[worker.Name]
+		if !ok {
+			fromVersion = *worker.Machine.Image.Version
+		}
+		machineImages[worker.Name] = map[string]any{
+			"image":       worker.Machine.Image.Name,
+			"fromVersion": fromVersion,
+			"toVersion":   *worker.Machine.Image.Version,
+		}
+	}
+
+	return map[string]any{
+		"k8sFromVersion": k8sFromVersion,
+		"k8sToVersion":   shoot.KubernetesVersion.Version,
+		"workers":        sourceWorkers,
+		"machineImages":  machineImages,
 	}, nil
 }
@@ -14,8 +14,11 @@ import (
 	"strings"
 
 	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	"github.com/go-logr/logr"
+	"github.com/google/uuid"
 	"github.com/pkg/errors"
 	netv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 	"k8s.io/apimachinery/pkg/types"
@@ -25,6 +28,7 @@ import (
 	tmv1beta1 "github.com/gardener/test-infra/pkg/apis/testmachinery/v1beta1"
 	"github.com/gardener/test-infra/pkg/common"
 	"github.com/gardener/test-infra/pkg/testmachinery"
+	"github.com/gardener/test-infra/pkg/testmachinery/controller/watch"
 )
 
 func GetArgoURL(ctx context.Context, k8sClient client.Client, tr *tmv1beta1.Testrun) (string, error) {
@@ -178,3 +182,29 @@ func GetCloudProfilesFromDisk(cloudProfileSearchPath string) (map[string]gardenc
 
 	return cloudProfiles, err
 }
+
+// ScopeWatchToSession restricts a watch that uses the watch informer to the testruns of the current testrunner session.
+// The testruns of a session are identified by the namespace and the execution group of the testrunner config.
+// An execution group id is generated if no id is configured.
+func ScopeWatchToSession(config *Config, options *watch.Options) {
+	if options.InformerType != watch.WatchInformerType {
+		return
+	}
+	options.Namespace = config.Namespace
+	if config.NoExecutionGroup {
+		return
+	}
+	if config.ExecutionGroupID == "" {
+		config.ExecutionGroupID = uuid.New().String()
+	}
+	options.LabelSelector = labels.SelectorFromSet(labels.Set{common.LabelTestrunExecutionGroup: config.ExecutionGroupID})
+}
+
+// LogWatchStatistics logs the connection statistics of a watch if its informer reports statistics.
+func LogWatchStatistics(log logr.Logger, w watch.Watch) {
+	stats, ok := watch.InformerStatistics(w)
+	if !ok {
+		return
+	}
+	log.Info("watch statistics", "events", stats.Events, "reconnects", stats.Reconnects, "relists", stats.Relists)
+}
@@ -7,6 +7,7 @@ package github
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 
 	"github.com/bradleyfalzon/ghinstallation/v2"
@@ -80,7 +81,8 @@ func (m *manager) getGitHubClient(installationID int64) (*internalClientItem, er
 		return ghClient, nil
 	}
 
-	trp, err := ghcache.WithRateLimitControlCache(m.log.WithName("ghCache"), http.DefaultTransport)
+	installation := fmt.Sprintf("app-%d-installation-%d", m.appId, installationID)
+	trp, err := ghcache.WithRateLimitControlCache(m.log.WithName("ghCache"), installation, http.DefaultTransport)
 	if err != nil {
 		return nil, err
 	}
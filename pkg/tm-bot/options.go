@@ -11,9 +11,12 @@ import (
 	"github.com/go-logr/logr"
 	"github.com/gorilla/mux"
 	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/client-go/rest"
 
+	"github.com/gardener/test-infra/pkg/alert"
 	"github.com/gardener/test-infra/pkg/apis/config"
+	"github.com/gardener/test-infra/pkg/apis/config/validation"
 	"github.com/gardener/test-infra/pkg/testmachinery/controller/watch"
 	"github.com/gardener/test-infra/pkg/testmachinery/ghcache"
 	"github.com/gardener/test-infra/pkg/tm-bot/github"
@@ -21,6 +24,7 @@ import (
 	"github.com/gardener/test-infra/pkg/tm-bot/tests"
 	"github.com/gardener/test-infra/pkg/tm-bot/ui"
 	"github.com/gardener/test-infra/pkg/tm-bot/ui/auth"
+	"github.com/gardener/test-infra/pkg/util/elasticsearch"
 )
 
 type options struct {
@@ -67,6 +71,14 @@ func (o *options) setupGitHubBot(router *mux.Router, runs *tests.Runs) error {
 	if !cfg.Enabled {
 		return nil
 	}
+	cachePath := field.NewPath("githubBot", "cache")
+	allErrs := validation.ValidateGitHubCache(&cfg.GitHubCache, cachePath)
+	if cfg.GitHubCache.Server != nil {
+		allErrs = append(allErrs, field.Forbidden(cachePath.Child("server"), "the github cache is only served by the controller"))
+	}
+	if len(allErrs) != 0 {
+		return errors.Wrap(allErrs.ToAggregate(), "invalid github cache configuration")
+	}
 	ghcache.InitGitHubCache(&cfg.GitHubCache)
 	ghClient, err := github.NewManager(o.log.WithName("github"), cfg)
 	if err != nil {
@@ -81,6 +93,23 @@ func (o *options) setupGitHubBot(router *mux.Router, runs *tests.Runs) error {
 	return nil
 }
 
+func (o *options) setupAlerting(router *mux.Router) error {
+	cfg := o.cfg.Alerting
+	if !cfg.Enabled {
+		return nil
+	}
+	if cfg.SlackSigningSecret == "" {
+		return errors.New("a slack signing secret is required for alerting")
+	}
+	esClient, err := elasticsearch.NewClient(cfg.ElasticSearch)
+	if err != nil {
+		return errors.Wrap(err, "unable to initialize elasticsearch client")
+	}
+	alerter := alert.New(o.log.WithName("alert"), alert.Config{ESClient: esClient})
+	router.Handle("/alerts/slack", alert.NewSlackCommandHandler(o.log.WithName("alert"), alerter, cfg.SlackSigningSecret)).Methods(http.MethodPost)
+	return nil
+}
+
 func loggingMiddleware(log logr.Logger) mux.MiddlewareFunc {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -13,7 +13,9 @@ import (
 
 	"github.com/go-logr/logr"
 	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 
 	"github.com/gardener/test-infra/pkg/apis/config"
 	"github.com/gardener/test-infra/pkg/testmachinery/controller/watch"
@@ -51,11 +53,16 @@ func Serve(ctx context.Context, log logr.Logger, restConfig *rest.Config, cfg *c
 	r := mux.NewRouter()
 	r.Use(loggingMiddleware(o.log.WithName("trace")))
 	r.HandleFunc("/healthz", healthz(o.log.WithName("health"))).Methods(http.MethodGet)
+	r.Handle("/metrics", promhttp.HandlerFor(metrics.Registry, promhttp.HandlerOpts{})).Methods(http.MethodGet)
 
 	if err := o.setupGitHubBot(r, runs); err != nil {
 		return err
 	}
 
+	if err := o.setupAlerting(r); err != nil {
+		return err
+	}
+
 	if err := o.setupDashboard(r, runs); err != nil {
 		return err
 	}
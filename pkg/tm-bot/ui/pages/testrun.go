@@ -22,6 +22,7 @@ import (
 	"github.com/gardener/test-infra/pkg/tm-bot/ui/pages/pagination"
 	"github.com/gardener/test-infra/pkg/util"
 	"github.com/gardener/test-infra/pkg/util/output"
+	"github.com/gardener/test-infra/pkg/util/redact"
 )
 
 type detailedTestrunItem struct {
@@ -60,6 +61,7 @@ func NewTestrunPage(p *Page) http.HandlerFunc {
 			http.Redirect(w, r, "/404", http.StatusTemporaryRedirect)
 			return
 		}
+		tr = redact.Testrun(tr)
 
 		argoHostURL, _ := testrunner.GetArgoHost(ctx, p.runs.GetClient())
 		grafanaHostURL, _ := testrunner.GetGrafanaHost(ctx, p.runs.GetClient())
@@ -136,7 +138,7 @@ func NewTestrunPage(p *Page) http.HandlerFunc {
 				Phase:     StepPhaseIcon(step.Phase),
 				StartTime: startTime,
 				Duration:  d.String(),
-				Location:  fmt.Sprintf("%s:%s", step.TestDefinition.Location.Repo, step.TestDefinition.Location.Revision),
+				Location:  stepLocation(tr, step.TestDefinition.Location),
 				IsSystem:  util.IsSystemStep(step),
 			}
 			if grafanaHostURL != "" {
@@ -153,6 +155,23 @@ func NewTestrunPage(p *Page) http.HandlerFunc {
 	}
 }
 
+// stepLocation returns the requested revision of the location of a step
+// together with the commit the revision was resolved to.
+func stepLocation(tr *v1beta1.Testrun, loc v1beta1.TestLocation) string {
+	location := fmt.Sprintf("%s:%s", loc.Repo, loc.Revision)
+	for _, resolved := range tr.Status.ResolvedLocations {
+		if resolved.Repo != loc.Repo || resolved.Revision != loc.Revision || resolved.Commit == loc.Revision {
+			continue
+		}
+		commit := resolved.Commit
+		if len(commit) > 7 {
+			commit = commit[:7]
+		}
+		return fmt.Sprintf("%s@%s", location, commit)
+	}
+	return location
+}
+
 type testrunItemList []testrunItem
 
 func (l testrunItemList) GetPaginatedList(from, to int) pagination.Interface {
@@ -212,9 +231,11 @@ func (l testrunStepStatusItemList) Less(a, b int) bool {
 	return l[a].step.StartTime.Before(l[b].step.StartTime)
 }
 
+// getSpecConfigValue returns the value of a config.
+// Sensitive values are never returned.
 func getSpecConfigValue(config []v1beta1.ConfigElement, name string) string {
 	for _, c := range config {
-		if c.Name == name {
+		if c.Name == name && !redact.IsSensitiveConfig(&c) {
 			return c.Value
 		}
 	}
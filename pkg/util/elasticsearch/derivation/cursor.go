@@ -0,0 +1,217 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package derivation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gardener/test-infra/pkg/util/elasticsearch"
+)
+
+// page is one page of search results
+type page struct {
+	Docs  []Document
+	Total int
+}
+
+// cursor iterates over all documents matching a query in a consistent order
+type cursor interface {
+	// next returns the next page of documents. An empty page marks the end of the results.
+	next(ctx context.Context) (page, error)
+	close(ctx context.Context) error
+	kind() string
+}
+
+// searchResponse is the part of a search response that is used to iterate over the results
+type searchResponse struct {
+	ScrollID string `json:"_scroll_id"`
+	PitID    string `json:"pit_id"`
+	Hits     struct {
+		Total struct {
+			Value int `json:"value"`
+		} `json:"total"`
+		Hits []Document `json:"hits"`
+	} `json:"hits"`
+}
+
+func (r searchResponse) page() page {
+	return page{Docs: r.Hits.Hits, Total: r.Hits.Total.Value}
+}
+
+// pitFlavor describes the point in time api of elasticsearch and opensearch
+type pitFlavor struct {
+	name       string
+	openPath   string
+	closePath  string
+	idField    string
+	tiebreaker string
+	closeBody  func(id string) interface{}
+}
+
+var (
+	elasticsearchPIT = pitFlavor{
+		name:       "elasticsearch point in time",
+		openPath:   "/%s/_pit?keep_alive=%s",
+		closePath:  "/_pit",
+		idField:    "id",
+		tiebreaker: "_shard_doc",
+		closeBody:  func(id string) interface{} { return map[string]interface{}{"id": id} },
+	}
+	openSearchPIT = pitFlavor{
+		name:       "opensearch point in time",
+		openPath:   "/%s/_search/point_in_time?keep_alive=%s",
+		closePath:  "/_search/point_in_time",
+		idField:    "pit_id",
+		tiebreaker: "_doc",
+		closeBody:  func(id string) interface{} { return map[string]interface{}{"pit_id": []string{id}} },
+	}
+)
+
+// pitCursor pages through the results of a point in time with search_after
+type pitCursor struct {
+	client    elasticsearch.Client
+	flavor    pitFlavor
+	search    map[string]interface{}
+	keepAlive string
+	id        string
+	after     []interface{}
+}
+
+// openPIT opens a point in time of the indices.
+// An error that indicates that point in time searches are not supported is returned as errUnsupported.
+func openPIT(ctx context.Context, client elasticsearch.Client, flavor pitFlavor, indices []string, keepAlive string, search map[string]interface{}) (*pitCursor, error) {
+	body, err := client.RequestWithCtx(ctx, http.MethodPost, fmt.Sprintf(flavor.openPath, strings.Join(indices, ","), keepAlive), nil)
+	if err != nil {
+		if isUnsupported(err) {
+			return nil, errUnsupported
+		}
+		return nil, err
+	}
+	res := map[string]interface{}{}
+	if err := json.Unmarshal(body, &res); err != nil {
+		return nil, fmt.Errorf("unable to parse %s response: %w", flavor.name, err)
+	}
+	id, ok := res[flavor.idField].(string)
+	if !ok || id == "" {
+		return nil, errUnsupported
+	}
+	return &pitCursor{client: client, flavor: flavor, search: search, keepAlive: keepAlive, id: id}, nil
+}
+
+func (c *pitCursor) next(ctx context.Context) (page, error) {
+	payload := copyMap(c.search)
+	payload["pit"] = map[string]interface{}{"id": c.id, "keep_alive": c.keepAlive}
+	sort, _ := payload["sort"].([]interface{})
+	payload["sort"] = append(append([]interface{}{}, sort...), map[string]interface{}{c.flavor.tiebreaker: "asc"})
+	if c.after != nil {
+		payload["search_after"] = c.after
+	}
+
+	var res searchResponse
+	if err := request(ctx, c.client, http.MethodPost, "/_search", payload, &res); err != nil {
+		return page{}, err
+	}
+	if res.PitID != "" {
+		c.id = res.PitID
+	}
+	if n := len(res.Hits.Hits); n != 0 {
+		c.after = res.Hits.Hits[n-1].Sort
+	}
+	return res.page(), nil
+}
+
+func (c *pitCursor) close(ctx context.Context) error {
+	return request(ctx, c.client, http.MethodDelete, c.flavor.closePath, c.flavor.closeBody(c.id), nil)
+}
+
+func (c *pitCursor) kind() string {
+	return c.flavor.name
+}
+
+// scrollCursor pages through the results with the scroll api which is supported by all versions
+type scrollCursor struct {
+	client    elasticsearch.Client
+	indices   []string
+	search    map[string]interface{}
+	keepAlive string
+	id        string
+}
+
+func (c *scrollCursor) next(ctx context.Context) (page, error) {
+	var res searchResponse
+	if c.id == "" {
+		path := fmt.Sprintf("/%s/_search?scroll=%s", strings.Join(c.indices, ","), c.keepAlive)
+		if err := request(ctx, c.client, http.MethodPost, path, c.search, &res); err != nil {
+			return page{}, err
+		}
+	} else {
+		payload := map[string]interface{}{"scroll": c.keepAlive, "scroll_id": c.id}
+		if err := request(ctx, c.client, http.MethodPost, "/_search/scroll", payload, &res); err != nil {
+			return page{}, err
+		}
+	}
+	if res.ScrollID != "" {
+		c.id = res.ScrollID
+	}
+	return res.page(), nil
+}
+
+func (c *scrollCursor) close(ctx context.Context) error {
+	if c.id == "" {
+		return nil
+	}
+	return request(ctx, c.client, http.MethodDelete, "/_search/scroll", map[string]interface{}{"scroll_id": c.id}, nil)
+}
+
+func (c *scrollCursor) kind() string {
+	return "scroll"
+}
+
+var errUnsupported = errors.New("point in time search is not supported")
+
+// isUnsupported returns true if elasticsearch rejected a request because the api is unknown
+func isUnsupported(err error) bool {
+	var statusErr *elasticsearch.StatusError
+	if !errors.As(err, &statusErr) {
+		return false
+	}
+	switch statusErr.StatusCode {
+	case http.StatusBadRequest, http.StatusNotFound, http.StatusMethodNotAllowed:
+		return true
+	}
+	return false
+}
+
+func request(ctx context.Context, client elasticsearch.Client, method, path string, payload, result interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("unable to marshal payload of %s: %w", path, err)
+	}
+	body, err := client.RequestWithCtx(ctx, method, path, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	if result == nil {
+		return nil
+	}
+	if err := json.Unmarshal(body, result); err != nil {
+		return fmt.Errorf("unable to unmarshal response of %s: %w", path, err)
+	}
+	return nil
+}
+
+func copyMap(m map[string]interface{}) map[string]interface{} {
+	c := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
@@ -0,0 +1,85 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package derivation
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Document is an elasticsearch document
+type Document struct {
+	Index  string          `json:"_index"`
+	ID     string          `json:"_id"`
+	Source json.RawMessage `json:"_source"`
+	Sort   []interface{}   `json:"sort,omitempty"`
+}
+
+// Decode decodes the source of the document into v
+func (d Document) Decode(v interface{}) error {
+	return json.Unmarshal(d.Source, v)
+}
+
+// DeriveFunc computes the derived fields of a document.
+// It returns the top-level fields that have to be updated or nil if the document is up to date.
+type DeriveFunc func(doc Document) (map[string]interface{}, error)
+
+// Derivation is a named computation of derived fields of elasticsearch documents
+type Derivation struct {
+	Name        string
+	Description string
+	// Filter is an optional elasticsearch query clause that selects the documents the derivation is applied to
+	Filter map[string]interface{}
+	Derive DeriveFunc
+}
+
+var (
+	registryMux sync.RWMutex
+	registry    = map[string]Derivation{}
+)
+
+// Register registers a derivation so that it can be selected by its name.
+// Registering a derivation with an already registered name panics.
+func Register(d Derivation) {
+	registryMux.Lock()
+	defer registryMux.Unlock()
+	if _, ok := registry[d.Name]; ok {
+		panic(fmt.Sprintf("derivation %s is already registered", d.Name))
+	}
+	registry[d.Name] = d
+}
+
+// Registered returns all registered derivations ordered by name
+func Registered() []Derivation {
+	registryMux.RLock()
+	defer registryMux.RUnlock()
+	derivations := make([]Derivation, 0, len(registry))
+	for _, d := range registry {
+		derivations = append(derivations, d)
+	}
+	sort.Slice(derivations, func(i, j int) bool { return derivations[i].Name < derivations[j].Name })
+	return derivations
+}
+
+// Get returns the registered derivations with the given names.
+// All registered derivations are returned if no name is given.
+func Get(names ...string) ([]Derivation, error) {
+	if len(names) == 0 {
+		return Registered(), nil
+	}
+	registryMux.RLock()
+	defer registryMux.RUnlock()
+	derivations := make([]Derivation, 0, len(names))
+	for _, name := range names {
+		d, ok := registry[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown derivation %q", name)
+		}
+		derivations = append(derivations, d)
+	}
+	return derivations, nil
+}
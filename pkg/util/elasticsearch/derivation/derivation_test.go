@@ -0,0 +1,357 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package derivation
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/gardener/test-infra/pkg/apis/config"
+	"github.com/gardener/test-infra/pkg/util/elasticsearch"
+)
+
+var _ = Describe("derivation", func() {
+
+	var (
+		ctx    = context.Background()
+		search *fakeSearch
+		server *httptest.Server
+		client elasticsearch.Client
+		double Derivation
+	)
+
+	BeforeEach(func() {
+		search = newFakeSearch(10)
+		server = httptest.NewServer(search)
+		var err error
+		client, err = elasticsearch.NewClient(config.ElasticSearch{Endpoint: server.URL, Username: "user", Password: "pass"})
+		Expect(err).ToNot(HaveOccurred())
+
+		double = Derivation{
+			Name: "double",
+			Derive: func(doc Document) (map[string]interface{}, error) {
+				var src struct {
+					Value  int  `json:"value"`
+					Double *int `json:"double"`
+				}
+				if err := doc.Decode(&src); err != nil {
+					return nil, err
+				}
+				if src.Double != nil && *src.Double == 2*src.Value {
+					return nil, nil
+				}
+				return map[string]interface{}{"double": 2 * src.Value}, nil
+			},
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Context("registry", func() {
+		It("should return registered derivations by name", func() {
+			Register(Derivation{Name: "test-registry-b"})
+			Register(Derivation{Name: "test-registry-a"})
+			Expect(func() { Register(Derivation{Name: "test-registry-a"}) }).To(Panic())
+
+			derivations, err := Get("test-registry-b")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(derivations).To(HaveLen(1))
+			Expect(derivations[0].Name).To(Equal("test-registry-b"))
+
+			_, err = Get("unknown")
+			Expect(err).To(HaveOccurred())
+
+			all, err := Get()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(len(all)).To(BeNumerically(">=", 2))
+			Expect(all[0].Name <= all[1].Name).To(BeTrue())
+		})
+	})
+
+	Context("run", func() {
+		It("should update all changed documents using a point in time", func() {
+			search.docs[3].Source["double"] = 6
+			stats, err := NewRunner(logr.Discard(), client, []Derivation{double}, Options{PageSize: 3, Concurrency: 2}).Run(ctx)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(stats.Search).To(Equal(elasticsearchPIT.name))
+			Expect(stats.Scanned).To(Equal(10))
+			Expect(stats.Changed).To(HaveKeyWithValue("double", 9))
+			Expect(stats.Updated).To(Equal(9))
+			for i, doc := range search.docs {
+				Expect(doc.Source).To(HaveKeyWithValue("double", BeEquivalentTo(2*i)))
+			}
+			Expect(search.requests).To(ContainElement("DELETE /_pit"))
+		})
+
+		It("should fall back to scrolling if point in time searches are not supported", func() {
+			search.pit = false
+			stats, err := NewRunner(logr.Discard(), client, []Derivation{double}, Options{PageSize: 4}).Run(ctx)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(stats.Search).To(Equal("scroll"))
+			Expect(stats.Scanned).To(Equal(10))
+			Expect(stats.Updated).To(Equal(10))
+			Expect(search.requests).To(ContainElement("DELETE /_search/scroll"))
+		})
+
+		It("should only count changed documents in dry-run mode", func() {
+			stats, err := NewRunner(logr.Discard(), client, []Derivation{double}, Options{PageSize: 4, DryRun: true}).Run(ctx)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(stats.Changed).To(HaveKeyWithValue("double", 10))
+			Expect(stats.Updated).To(Equal(0))
+			Expect(search.requests).ToNot(ContainElement("POST /_bulk"))
+		})
+
+		It("should count documents that could not be updated", func() {
+			search.failIDs = map[string]bool{"doc-2": true}
+			stats, err := NewRunner(logr.Discard(), client, []Derivation{double}, Options{PageSize: 4}).Run(ctx)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(stats.Updated).To(Equal(9))
+			Expect(stats.Failed).To(Equal(1))
+		})
+
+		It("should restrict the documents to the time range and the derivation filters", func() {
+			double.Filter = map[string]interface{}{"term": map[string]interface{}{"type.keyword": "teststep"}}
+			_, err := NewRunner(logr.Discard(), client, []Derivation{double}, Options{From: "now-30d", To: "now"}).Run(ctx)
+			Expect(err).ToNot(HaveOccurred())
+
+			query := search.lastQuery["bool"].(map[string]interface{})
+			Expect(query["filter"]).To(ContainElement(HaveKeyWithValue("range", HaveKeyWithValue("startTime", map[string]interface{}{"gte": "now-30d", "lt": "now"}))))
+			Expect(query["should"]).To(ConsistOf(double.Filter))
+		})
+
+		It("should resume from the checkpoint of an interrupted run", func() {
+			checkpointFile := filepath.Join(GinkgoT().TempDir(), "checkpoint")
+			search.failSearchAfter = 2
+
+			_, err := NewRunner(logr.Discard(), client, []Derivation{double}, Options{PageSize: 3, CheckpointFile: checkpointFile}).Run(ctx)
+			Expect(err).To(HaveOccurred())
+			Expect(checkpointFile).To(BeAnExistingFile())
+			updated := search.updates
+
+			search.failSearchAfter = 0
+			stats, err := NewRunner(logr.Discard(), client, []Derivation{double}, Options{PageSize: 3, CheckpointFile: checkpointFile}).Run(ctx)
+			Expect(err).ToNot(HaveOccurred())
+
+			// the last document of the checkpoint is read again but already up to date
+			Expect(stats.Scanned).To(Equal(11))
+			Expect(stats.Updated + updated).To(Equal(10))
+			Expect(checkpointFile).ToNot(BeAnExistingFile())
+		})
+	})
+})
+
+type fakeDoc struct {
+	Index  string
+	ID     string
+	Time   int64
+	Source map[string]interface{}
+}
+
+// fakeSearch is a minimal elasticsearch that supports point in time searches, scrolling and bulk updates
+type fakeSearch struct {
+	mux      sync.Mutex
+	docs     []*fakeDoc
+	requests []string
+	pit      bool
+	// failIDs are the ids of documents whose update fails
+	failIDs map[string]bool
+	// failSearchAfter fails all searches after the given number of successful searches
+	failSearchAfter int
+	searches        int
+	updates         int
+	lastQuery       map[string]interface{}
+	scroll          []*fakeDoc
+}
+
+func newFakeSearch(n int) *fakeSearch {
+	s := &fakeSearch{pit: true}
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < n; i++ {
+		t := start.Add(time.Duration(i) * time.Hour)
+		s.docs = append(s.docs, &fakeDoc{
+			Index:  "testmachinery-000001",
+			ID:     fmt.Sprintf("doc-%d", i),
+			Time:   t.UnixMilli(),
+			Source: map[string]interface{}{"startTime": t.Format(time.RFC3339), "value": i},
+		})
+	}
+	return s
+}
+
+func (s *fakeSearch) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+
+	switch {
+	case r.Method == http.MethodDelete:
+		writeJSON(w, map[string]interface{}{"succeeded": true})
+	case strings.HasSuffix(r.URL.Path, "/_pit"):
+		if !s.pit {
+			http.Error(w, `{"error":"no handler"}`, http.StatusBadRequest)
+			return
+		}
+		writeJSON(w, map[string]interface{}{"id": "pit-1"})
+	case strings.HasSuffix(r.URL.Path, "/point_in_time"):
+		http.Error(w, `{"error":"no handler"}`, http.StatusNotFound)
+	case r.URL.Path == "/_bulk":
+		s.bulk(w, r)
+	case strings.HasSuffix(r.URL.Path, "/_search") || r.URL.Path == "/_search/scroll":
+		if s.failSearchAfter != 0 && s.searches >= s.failSearchAfter {
+			http.Error(w, `{"error":"unavailable"}`, http.StatusServiceUnavailable)
+			return
+		}
+		s.searches++
+		s.search(w, r)
+	default:
+		http.Error(w, "not found", http.StatusNotFound)
+	}
+}
+
+func (s *fakeSearch) search(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Size        int                    `json:"size"`
+		Query       map[string]interface{} `json:"query"`
+		SearchAfter []float64              `json:"search_after"`
+	}
+	Expect(json.NewDecoder(r.Body).Decode(&req)).To(Succeed())
+
+	var page []*fakeDoc
+	total := len(s.docs)
+	if r.URL.Path == "/_search/scroll" {
+		page, s.scroll = take(s.scroll, 4)
+	} else {
+		s.lastQuery = req.Query
+		matching := s.matching(req.Query)
+		total = len(matching)
+		if req.SearchAfter != nil {
+			// the position of the document is used as shard doc tiebreaker
+			for i, doc := range matching {
+				if s.position(doc) == int(req.SearchAfter[1]) {
+					matching = matching[i+1:]
+					break
+				}
+			}
+		}
+		if r.URL.Query().Get("scroll") != "" {
+			page, s.scroll = take(matching, req.Size)
+		} else {
+			page, _ = take(matching, req.Size)
+		}
+	}
+
+	hits := make([]map[string]interface{}, 0, len(page))
+	for _, doc := range page {
+		hits = append(hits, map[string]interface{}{
+			"_index":  doc.Index,
+			"_id":     doc.ID,
+			"_source": doc.Source,
+			"sort":    []interface{}{doc.Time, s.position(doc)},
+		})
+	}
+	writeJSON(w, map[string]interface{}{
+		"_scroll_id": "scroll-1",
+		"hits": map[string]interface{}{
+			"total": map[string]interface{}{"value": total},
+			"hits":  hits,
+		},
+	})
+}
+
+// matching returns the documents that match the checkpoint range of the query ordered by time
+func (s *fakeSearch) matching(query map[string]interface{}) []*fakeDoc {
+	var after float64 = -1
+	if filters, ok := query["bool"].(map[string]interface{})["filter"].([]interface{}); ok {
+		for _, f := range filters {
+			timeRange := f.(map[string]interface{})["range"].(map[string]interface{})["startTime"].(map[string]interface{})
+			if timeRange["format"] == "epoch_millis" {
+				after = timeRange["gte"].(float64)
+			}
+		}
+	}
+	docs := make([]*fakeDoc, 0, len(s.docs))
+	for _, doc := range s.docs {
+		if float64(doc.Time) >= after {
+			docs = append(docs, doc)
+		}
+	}
+	sort.Slice(docs, func(i, j int) bool { return docs[i].Time < docs[j].Time })
+	return docs
+}
+
+func (s *fakeSearch) position(doc *fakeDoc) int {
+	for i, d := range s.docs {
+		if d == doc {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *fakeSearch) bulk(w http.ResponseWriter, r *http.Request) {
+	scanner := bufio.NewScanner(r.Body)
+	items := []interface{}{}
+	errorsOccurred := false
+	for scanner.Scan() {
+		var meta struct {
+			Update struct {
+				ID string `json:"_id"`
+			} `json:"update"`
+		}
+		Expect(json.Unmarshal(scanner.Bytes(), &meta)).To(Succeed())
+		Expect(scanner.Scan()).To(BeTrue())
+		var update struct {
+			Doc map[string]interface{} `json:"doc"`
+		}
+		Expect(json.Unmarshal(scanner.Bytes(), &update)).To(Succeed())
+
+		status := http.StatusOK
+		if s.failIDs[meta.Update.ID] {
+			status, errorsOccurred = http.StatusConflict, true
+		} else {
+			for _, doc := range s.docs {
+				if doc.ID == meta.Update.ID {
+					for k, v := range update.Doc {
+						doc.Source[k] = v
+					}
+				}
+			}
+			s.updates++
+		}
+		items = append(items, map[string]interface{}{"update": map[string]interface{}{"_id": meta.Update.ID, "status": status}})
+	}
+	writeJSON(w, map[string]interface{}{"errors": errorsOccurred, "items": items})
+}
+
+func take(docs []*fakeDoc, n int) ([]*fakeDoc, []*fakeDoc) {
+	if n > len(docs) {
+		n = len(docs)
+	}
+	return docs[:n], docs[n:]
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	Expect(json.NewEncoder(w).Encode(v)).To(Succeed())
+}
@@ -0,0 +1,429 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package derivation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"reflect"
+	"sync"
+
+	"github.com/go-logr/logr"
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/gardener/test-infra/pkg/util"
+	"github.com/gardener/test-infra/pkg/util/elasticsearch"
+)
+
+// Options configures a derivation run
+type Options struct {
+	// Indices are the indices or index patterns whose documents are derived
+	Indices []string
+	// TimeField is the date field that is used to filter and order the documents
+	TimeField string
+	// From and To restrict the documents to a time range. Both accept dates and elasticsearch date math like "now-30d".
+	From string
+	To   string
+	// PageSize is the number of documents that are read and updated at once
+	PageSize int
+	// Concurrency is the number of pages that are derived and updated in parallel
+	Concurrency int
+	// CheckpointFile stores the progress of the run so that an interrupted run can be resumed
+	CheckpointFile string
+	// DryRun only counts the documents that would change
+	DryRun bool
+	// KeepAlive is the time a point in time or scroll context is kept between two pages
+	KeepAlive string
+}
+
+// Stats summarizes a derivation run
+type Stats struct {
+	// Search is the search api that was used to iterate over the documents
+	Search string
+	// Scanned is the number of documents that were read
+	Scanned int
+	// Changed is the number of changed documents per derivation
+	Changed map[string]int
+	// Updated is the number of documents that were written
+	Updated int
+	// Failed is the number of documents whose derivation or update failed
+	Failed int
+}
+
+// Runner applies derivations to all matching documents of elasticsearch indices
+type Runner struct {
+	log         logr.Logger
+	client      elasticsearch.Client
+	derivations []Derivation
+	opts        Options
+
+	statsMux sync.Mutex
+	stats    Stats
+}
+
+// checkpoint is the persisted progress of a run
+type checkpoint struct {
+	Derivations []string `json:"derivations"`
+	Indices     []string `json:"indices"`
+	// After is the value of the time field in epoch milliseconds of the last completely processed page
+	After   *int64 `json:"after,omitempty"`
+	Scanned int    `json:"scanned"`
+}
+
+// NewRunner creates a new runner for the given derivations
+func NewRunner(log logr.Logger, client elasticsearch.Client, derivations []Derivation, opts Options) *Runner {
+	if len(opts.Indices) == 0 {
+		opts.Indices = []string{"testmachinery-*"}
+	}
+	if opts.TimeField == "" {
+		opts.TimeField = "startTime"
+	}
+	if opts.PageSize <= 0 {
+		opts.PageSize = 1000
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 1
+	}
+	if opts.KeepAlive == "" {
+		opts.KeepAlive = "5m"
+	}
+	return &Runner{
+		log:         log,
+		client:      client,
+		derivations: derivations,
+		opts:        opts,
+		stats:       Stats{Changed: map[string]int{}},
+	}
+}
+
+// Run applies the derivations to all documents.
+// Documents are read in the order of the time field so that the run can be resumed from the last checkpoint.
+func (r *Runner) Run(ctx context.Context) (Stats, error) {
+	cp, err := r.loadCheckpoint()
+	if err != nil {
+		return r.stats, err
+	}
+	if cp.After != nil {
+		r.log.Info("resume from checkpoint", "file", r.opts.CheckpointFile, "scanned", cp.Scanned)
+	}
+	r.stats.Scanned = cp.Scanned
+
+	cur, err := r.openCursor(ctx, r.searchPayload(cp.After))
+	if err != nil {
+		return r.stats, err
+	}
+	r.stats.Search = cur.kind()
+	r.log.V(3).Info("iterate documents", "search", cur.kind())
+	defer func() {
+		if err := cur.close(context.Background()); err != nil {
+			r.log.V(3).Info("unable to close search context", "error", err.Error())
+		}
+	}()
+
+	total := -1
+	for {
+		// pages are read sequentially and derived concurrently.
+		// The checkpoint is only moved once all pages of a batch have been written.
+		pages := make([]page, 0, r.opts.Concurrency)
+		for len(pages) < r.opts.Concurrency {
+			p, err := cur.next(ctx)
+			if err != nil {
+				return r.stats, fmt.Errorf("unable to read documents: %w", err)
+			}
+			if total == -1 {
+				total = p.Total + cp.Scanned
+			}
+			if len(p.Docs) == 0 {
+				break
+			}
+			pages = append(pages, p)
+		}
+		if len(pages) == 0 {
+			break
+		}
+
+		var (
+			wg     sync.WaitGroup
+			errMux sync.Mutex
+			errs   *multierror.Error
+		)
+		for _, p := range pages {
+			wg.Add(1)
+			go func(p page) {
+				defer wg.Done()
+				if err := r.processPage(ctx, p); err != nil {
+					errMux.Lock()
+					errs = multierror.Append(errs, err)
+					errMux.Unlock()
+				}
+			}(p)
+		}
+		wg.Wait()
+		if err := util.ReturnMultiError(errs); err != nil {
+			return r.stats, err
+		}
+
+		lastPage := pages[len(pages)-1]
+		if after, ok := sortTime(lastPage.Docs[len(lastPage.Docs)-1]); ok {
+			cp.After = &after
+		}
+		cp.Scanned = r.stats.Scanned
+		if err := r.saveCheckpoint(cp); err != nil {
+			return r.stats, err
+		}
+		if total > 0 {
+			r.log.Info(fmt.Sprintf("%d%% processed (%d/%d)", r.stats.Scanned*100/total, r.stats.Scanned, total))
+		}
+	}
+
+	if r.opts.CheckpointFile != "" && !r.opts.DryRun {
+		if err := os.Remove(r.opts.CheckpointFile); err != nil && !os.IsNotExist(err) {
+			return r.stats, fmt.Errorf("unable to remove checkpoint %s: %w", r.opts.CheckpointFile, err)
+		}
+	}
+	return r.stats, nil
+}
+
+// openCursor uses a point in time search if it is supported and falls back to scrolling otherwise
+func (r *Runner) openCursor(ctx context.Context, search map[string]interface{}) (cursor, error) {
+	for _, flavor := range []pitFlavor{elasticsearchPIT, openSearchPIT} {
+		cur, err := openPIT(ctx, r.client, flavor, r.opts.Indices, r.opts.KeepAlive, search)
+		if err == nil {
+			return cur, nil
+		}
+		if !errors.Is(err, errUnsupported) {
+			return nil, fmt.Errorf("unable to open %s: %w", flavor.name, err)
+		}
+	}
+	return &scrollCursor{client: r.client, indices: r.opts.Indices, search: search, keepAlive: r.opts.KeepAlive}, nil
+}
+
+// searchPayload builds the search for all documents that are selected by the options and the derivations
+func (r *Runner) searchPayload(after *int64) map[string]interface{} {
+	filters := []interface{}{}
+	timeRange := map[string]interface{}{}
+	if r.opts.From != "" {
+		timeRange["gte"] = r.opts.From
+	}
+	if r.opts.To != "" {
+		timeRange["lt"] = r.opts.To
+	}
+	if len(timeRange) != 0 {
+		filters = append(filters, map[string]interface{}{"range": map[string]interface{}{r.opts.TimeField: timeRange}})
+	}
+	if after != nil {
+		// documents with the checkpoint time are derived again as derivations are idempotent
+		filters = append(filters, map[string]interface{}{"range": map[string]interface{}{
+			r.opts.TimeField: map[string]interface{}{"gte": *after, "format": "epoch_millis"},
+		}})
+	}
+
+	// the derivation filters can only narrow the search if every derivation has one
+	should := make([]interface{}, 0, len(r.derivations))
+	for _, d := range r.derivations {
+		if d.Filter == nil {
+			should = nil
+			break
+		}
+		should = append(should, d.Filter)
+	}
+
+	boolQuery := map[string]interface{}{"filter": filters}
+	if len(should) != 0 {
+		boolQuery["should"] = should
+		boolQuery["minimum_should_match"] = 1
+	}
+	return map[string]interface{}{
+		"size":             r.opts.PageSize,
+		"track_total_hits": true,
+		"query":            map[string]interface{}{"bool": boolQuery},
+		"sort": []interface{}{
+			map[string]interface{}{r.opts.TimeField: map[string]interface{}{"order": "asc"}},
+		},
+	}
+}
+
+// processPage derives the fields of all documents of a page and writes the changed documents
+func (r *Runner) processPage(ctx context.Context, p page) error {
+	var (
+		buf     bytes.Buffer
+		updates int
+		failed  int
+		changed = map[string]int{}
+	)
+	for _, doc := range p.Docs {
+		fields := map[string]interface{}{}
+		docFailed := false
+		for _, d := range r.derivations {
+			update, err := d.Derive(doc)
+			if err != nil {
+				r.log.V(3).Info("unable to derive document", "derivation", d.Name, "index", doc.Index, "id", doc.ID, "error", err.Error())
+				docFailed = true
+				continue
+			}
+			if len(update) == 0 {
+				continue
+			}
+			changed[d.Name]++
+			for k, v := range update {
+				fields[k] = v
+			}
+		}
+		if docFailed {
+			failed++
+		}
+		if len(fields) == 0 {
+			continue
+		}
+		r.log.V(5).Info("document changed", "index", doc.Index, "id", doc.ID, "fields", fields)
+		if err := writeUpdate(&buf, doc, fields); err != nil {
+			return err
+		}
+		updates++
+	}
+
+	if updates != 0 && !r.opts.DryRun {
+		itemFailures, err := r.bulkUpdate(ctx, buf.Bytes())
+		if err != nil {
+			return err
+		}
+		failed += itemFailures
+		updates -= itemFailures
+	}
+
+	r.statsMux.Lock()
+	defer r.statsMux.Unlock()
+	r.stats.Scanned += len(p.Docs)
+	r.stats.Failed += failed
+	for name, n := range changed {
+		r.stats.Changed[name] += n
+	}
+	if !r.opts.DryRun {
+		r.stats.Updated += updates
+	}
+	return nil
+}
+
+// bulkUpdate writes the updates and returns the number of documents that could not be updated.
+// Failed items do not fail the run as they are retried by the next run.
+func (r *Runner) bulkUpdate(ctx context.Context, data []byte) (int, error) {
+	body, err := r.client.RequestWithCtx(ctx, http.MethodPost, "/_bulk", bytes.NewReader(data))
+	if err != nil {
+		return 0, fmt.Errorf("unable to update documents: %w", err)
+	}
+	var res elasticsearch.BulkResponse
+	if err := json.Unmarshal(body, &res); err != nil {
+		return 0, fmt.Errorf("unable to unmarshal bulk response: %w", err)
+	}
+	if !res.Errors {
+		return 0, nil
+	}
+	items := make([]map[string]elasticsearch.BulkResponseItem, 0)
+	if err := json.Unmarshal(res.Items, &items); err != nil {
+		return 0, fmt.Errorf("unable to parse bulk items: %w", err)
+	}
+	failed := 0
+	for _, action := range items {
+		for _, item := range action {
+			if item.Status < 200 || item.Status > 299 {
+				r.log.Info("unable to update document", "index", item.Index, "id", item.ID, "status", item.Status, "error", item.Error)
+				failed++
+			}
+		}
+	}
+	return failed, nil
+}
+
+func writeUpdate(buf *bytes.Buffer, doc Document, fields map[string]interface{}) error {
+	meta, err := json.Marshal(map[string]interface{}{"update": map[string]string{"_index": doc.Index, "_id": doc.ID}})
+	if err != nil {
+		return err
+	}
+	update, err := json.Marshal(map[string]interface{}{"doc": fields})
+	if err != nil {
+		return fmt.Errorf("unable to marshal update of document %s: %w", doc.ID, err)
+	}
+	buf.Write(meta)
+	buf.WriteByte('\n')
+	buf.Write(update)
+	buf.WriteByte('\n')
+	return nil
+}
+
+// sortTime returns the time field value of a document from its sort values.
+// Documents without the time field are sorted last and have no usable value.
+func sortTime(doc Document) (int64, bool) {
+	if len(doc.Sort) == 0 {
+		return 0, false
+	}
+	switch v := doc.Sort[0].(type) {
+	case float64:
+		if v >= math.MaxInt64 || v <= math.MinInt64 {
+			return 0, false
+		}
+		return int64(v), true
+	case string:
+		var millis int64
+		if _, err := fmt.Sscan(v, &millis); err != nil || millis == math.MaxInt64 {
+			return 0, false
+		}
+		return millis, true
+	}
+	return 0, false
+}
+
+// loadCheckpoint reads the checkpoint of a previous run with the same derivations and indices
+func (r *Runner) loadCheckpoint() (checkpoint, error) {
+	cp := checkpoint{Derivations: r.derivationNames(), Indices: r.opts.Indices}
+	if r.opts.CheckpointFile == "" {
+		return cp, nil
+	}
+	data, err := os.ReadFile(r.opts.CheckpointFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cp, nil
+		}
+		return cp, fmt.Errorf("unable to read checkpoint %s: %w", r.opts.CheckpointFile, err)
+	}
+	var previous checkpoint
+	if err := json.Unmarshal(data, &previous); err != nil {
+		return cp, fmt.Errorf("unable to parse checkpoint %s: %w", r.opts.CheckpointFile, err)
+	}
+	if !reflect.DeepEqual(previous.Derivations, cp.Derivations) || !reflect.DeepEqual(previous.Indices, cp.Indices) {
+		r.log.Info("ignore checkpoint of a run with other derivations or indices", "file", r.opts.CheckpointFile)
+		return cp, nil
+	}
+	return previous, nil
+}
+
+func (r *Runner) saveCheckpoint(cp checkpoint) error {
+	if r.opts.CheckpointFile == "" || r.opts.DryRun {
+		return nil
+	}
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+	// write to a temporary file first so that an interrupted run never leaves a corrupt checkpoint
+	tmp := r.opts.CheckpointFile + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("unable to write checkpoint %s: %w", tmp, err)
+	}
+	return os.Rename(tmp, r.opts.CheckpointFile)
+}
+
+func (r *Runner) derivationNames() []string {
+	names := make([]string, len(r.derivations))
+	for i, d := range r.derivations {
+		names[i] = d.Name
+	}
+	return names
+}
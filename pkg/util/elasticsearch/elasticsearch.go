@@ -15,8 +15,8 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"strings"
 
-	"github.com/hashicorp/go-multierror"
 	"github.com/pkg/errors"
 
 	"github.com/gardener/test-infra/pkg/apis/config"
@@ -91,7 +91,7 @@ func (c *client) RequestWithCtx(ctx context.Context, httpMethod, rawPath string,
 	}(res.Body)
 	if res.StatusCode < 200 || res.StatusCode > 299 {
 		errorResponse, _ := io.ReadAll(res.Body)
-		return nil, errors.Errorf("request %s returned status code %d with body %s", esURL, res.StatusCode, errorResponse)
+		return nil, &StatusError{URL: esURL, StatusCode: res.StatusCode, Body: errorResponse}
 	}
 
 	body, err := io.ReadAll(res.Body)
@@ -120,15 +120,17 @@ func (c *client) Bulk(data []byte) error {
 		if len(items) == 0 {
 			return errors.New("elastic search returned an error")
 		}
-		var allErrors *multierror.Error
-		for _, action := range items {
+		bulkErr := &BulkError{}
+		for i, action := range items {
 			for _, item := range action {
 				if item.Status < 200 || item.Status > 299 {
-					allErrors = multierror.Append(allErrors, fmt.Errorf("%#v", item.Error))
+					bulkErr.Items = append(bulkErr.Items, BulkItemError{Position: i, BulkResponseItem: item})
 				}
 			}
 		}
-		return allErrors
+		if len(bulkErr.Items) != 0 {
+			return bulkErr
+		}
 	}
 
 	return nil
@@ -163,6 +165,23 @@ func (c *client) parseUrlNoEscape(rawPath string) (string, error) {
 	return result, nil
 }
 
+// StatusError is returned if elasticsearch responds with a non successful status code
+type StatusError struct {
+	URL        string
+	StatusCode int
+	Body       []byte
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("request %s returned status code %d with body %s", e.URL, e.StatusCode, e.Body)
+}
+
+// IsNotFound returns true if the error is caused by a request for a resource that does not exist
+func IsNotFound(err error) bool {
+	var statusErr *StatusError
+	return errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound
+}
+
 // BulkResponse is the response that is returned by elastic search when doing a bulk request
 type BulkResponse struct {
 	Took   int             `json:"took"`
@@ -178,3 +197,28 @@ type BulkResponseItem struct {
 	Status int         `json:"status"`
 	Error  interface{} `json:"error"`
 }
+
+// BulkItemError is a document of a bulk request that could not be processed
+type BulkItemError struct {
+	BulkResponseItem
+	// Position is the position of the action in the bulk request
+	Position int
+}
+
+// Retryable returns true if the document was rejected temporarily, e.g. because of too many requests
+func (e BulkItemError) Retryable() bool {
+	return e.Status == http.StatusTooManyRequests || e.Status >= http.StatusInternalServerError
+}
+
+// BulkError is returned if one or more documents of a bulk request could not be processed
+type BulkError struct {
+	Items []BulkItemError
+}
+
+func (e *BulkError) Error() string {
+	reasons := make([]string, 0, len(e.Items))
+	for _, item := range e.Items {
+		reasons = append(reasons, fmt.Sprintf("%s/%s: status %d: %v", item.Index, item.ID, item.Status, item.Error))
+	}
+	return fmt.Sprintf("%d bulk items failed: %s", len(e.Items), strings.Join(reasons, "; "))
+}
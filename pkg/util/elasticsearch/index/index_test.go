@@ -0,0 +1,414 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package index
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/gardener/test-infra/pkg/apis/config"
+	"github.com/gardener/test-infra/pkg/util/elasticsearch"
+)
+
+var _ = Describe("index management", func() {
+
+	var (
+		ctx    = context.Background()
+		search *fakeSearch
+		server *httptest.Server
+		client elasticsearch.Client
+	)
+
+	BeforeEach(func() {
+		search = newFakeSearch()
+		server = httptest.NewServer(search)
+		var err error
+		client, err = elasticsearch.NewClient(config.ElasticSearch{Endpoint: server.URL, Username: "user", Password: "pass"})
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Context("mapping", func() {
+		It("should derive explicit mappings from the summaries", func() {
+			mapping := Templates()[0].Mapping
+			Expect(mapping).To(HaveKeyWithValue("startTime", map[string]interface{}{"type": "date"}))
+			Expect(mapping).To(HaveKeyWithValue("duration", map[string]interface{}{"type": "long"}))
+			Expect(mapping).To(HaveKeyWithValue("testsRun", map[string]interface{}{"type": "long"}))
+			Expect(mapping).To(HaveKeyWithValue("phase", textKeyword()))
+			Expect(mapping).To(HaveKeyWithValue("labels", textKeyword()))
+			Expect(mapping).To(HaveKey("telemetry"))
+
+			pre := mapping["pre"].(map[string]interface{})["properties"].(map[string]interface{})
+			Expect(pre).To(HaveKeyWithValue("phaseNum", map[string]interface{}{"type": "long"}))
+
+			tm := mapping["tm"].(map[string]interface{})["properties"].(map[string]interface{})
+			Expect(tm).To(HaveKeyWithValue("landscape", textKeyword()))
+			Expect(tm).To(HaveKeyWithValue("stepName", textKeyword()))
+			Expect(tm).ToNot(HaveKey("annotations"))
+			tr := tm["tr"].(map[string]interface{})["properties"].(map[string]interface{})
+			Expect(tr).To(HaveKeyWithValue("startTime", map[string]interface{}{"type": "date"}))
+		})
+	})
+
+	Context("setup", func() {
+		It("should install ilm policies, templates and bootstrap the write alias", func() {
+			manager := NewManager(logr.Discard(), client, Options{Lifecycle: LifecycleConfig{RolloverMaxAge: "30d", RetentionAge: "365d"}})
+			Expect(manager.Setup(ctx)).To(Succeed())
+
+			Expect(search.requests).To(ContainElements(
+				"PUT /_ilm/policy/testmachinery",
+				"PUT /_index_template/testmachinery",
+				"PUT /_index_template/tm-exports",
+				"PUT /testmachinery-000001",
+			))
+			policy := search.bodies["/_ilm/policy/testmachinery"]
+			Expect(policy).To(ContainSubstring(`"max_age":"30d"`))
+			Expect(policy).To(ContainSubstring(`"min_age":"365d"`))
+			Expect(search.bodies["/_index_template/testmachinery"]).To(ContainSubstring(`"index.lifecycle.rollover_alias":"testmachinery"`))
+			Expect(search.indices).To(HaveKey("testmachinery-000001"))
+			Expect(search.indices["testmachinery-000001"].aliases).To(HaveKeyWithValue("testmachinery", true))
+		})
+
+		It("should update existing ism policies and roll over outdated write indices", func() {
+			search.policySeqNo = 3
+			search.indices["testmachinery-000017"] = &fakeIndex{docs: 10, aliases: map[string]bool{"testmachinery": true}}
+
+			manager := NewManager(logr.Discard(), client, Options{Flavor: FlavorOpenSearch})
+			Expect(manager.Setup(ctx)).To(Succeed())
+
+			Expect(search.requests).To(ContainElements(
+				"PUT /_plugins/_ism/policies/testmachinery?if_seq_no=3&if_primary_term=1",
+				"POST /testmachinery/_rollover",
+			))
+			Expect(search.requests).ToNot(ContainElement("PUT /_ilm/policy/testmachinery"))
+			Expect(search.bodies["/_index_template/testmachinery"]).To(ContainSubstring(`"plugins.index_state_management.rollover_alias":"testmachinery"`))
+			Expect(search.indices["testmachinery-000018"].version).To(Equal(TemplateVersion))
+			Expect(search.indices["testmachinery-000018"].aliases).To(HaveKeyWithValue("testmachinery", true))
+			Expect(search.indices["testmachinery-000017"].aliases).To(HaveKeyWithValue("testmachinery", false))
+		})
+
+		It("should migrate a legacy index with the name of the alias", func() {
+			search.indices["testmachinery"] = &fakeIndex{docs: 42, aliases: map[string]bool{}}
+
+			manager := NewManager(logr.Discard(), client, Options{PollInterval: time.Millisecond})
+			Expect(manager.Setup(ctx)).To(Succeed())
+
+			Expect(search.requests).To(ContainElements(
+				"PUT /testmachinery/_settings",
+				"PUT /testmachinery-000001",
+				"POST /_reindex?wait_for_completion=false",
+				"POST /_aliases",
+			))
+			Expect(search.bodies["/testmachinery/_settings"]).To(ContainSubstring(`"index.blocks.write":true`))
+			Expect(search.reindex).To(Equal([2]string{"testmachinery", "testmachinery-000001"}))
+			Expect(search.indices).ToNot(HaveKey("testmachinery"))
+			Expect(search.indices["testmachinery-000001"].docs).To(Equal(42))
+			Expect(search.indices["testmachinery-000001"].aliases).To(HaveKeyWithValue("testmachinery", true))
+		})
+
+		It("should keep a legacy index if documents are missing", func() {
+			search.indices["testmachinery"] = &fakeIndex{docs: 42, aliases: map[string]bool{}}
+			search.lostDocs = 1
+
+			manager := NewManager(logr.Discard(), client, Options{PollInterval: time.Millisecond})
+			Expect(manager.Setup(ctx)).To(MatchError(ContainSubstring("the source index is kept")))
+			Expect(search.indices).To(HaveKey("testmachinery"))
+			Expect(search.indices["testmachinery-000001"].aliases).ToNot(HaveKey("testmachinery"))
+
+			// a retry reuses the already created write index
+			search.lostDocs = 0
+			Expect(manager.Setup(ctx)).To(Succeed())
+			Expect(search.indices).ToNot(HaveKey("testmachinery"))
+			Expect(search.indices["testmachinery-000001"].aliases).To(HaveKeyWithValue("testmachinery", true))
+		})
+
+		It("should not migrate a legacy index in dry-run mode", func() {
+			search.indices["testmachinery"] = &fakeIndex{docs: 42, aliases: map[string]bool{}}
+
+			manager := NewManager(logr.Discard(), client, Options{DryRun: true})
+			Expect(manager.Setup(ctx)).To(Succeed())
+			Expect(search.indices).To(HaveLen(1))
+			Expect(search.indices["testmachinery"].writeBlocked).To(BeFalse())
+		})
+
+		It("should not modify anything in dry-run mode", func() {
+			manager := NewManager(logr.Discard(), client, Options{DryRun: true})
+			Expect(manager.Setup(ctx)).To(Succeed())
+			for _, request := range search.requests {
+				Expect(request).To(HavePrefix("GET "))
+			}
+		})
+	})
+
+	Context("migrate", func() {
+		BeforeEach(func() {
+			search.indices["testmachinery-000017"] = &fakeIndex{docs: 10, aliases: map[string]bool{"testmachinery": false}}
+			search.indices["testmachinery-000018"] = &fakeIndex{docs: 2, version: TemplateVersion, aliases: map[string]bool{"testmachinery": true}}
+			search.indices["tm-test"] = &fakeIndex{docs: 5, aliases: map[string]bool{}}
+			search.indices["tm-writing"] = &fakeIndex{docs: 5, aliases: map[string]bool{"tm-w": true}}
+		})
+
+		It("should reindex outdated indices and replace them with an alias", func() {
+			manager := NewManager(logr.Discard(), client, Options{PollInterval: time.Millisecond})
+			results, err := manager.Migrate(ctx, []string{"testmachinery-*", "tm-*"})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(results).To(ConsistOf(
+				MigrationResult{Index: "testmachinery-000017", Destination: "testmachinery-000017-v1", Docs: 10},
+				HaveField("Skipped", ContainSubstring("up to date")),
+				MigrationResult{Index: "tm-test", Destination: "tm-test-v1", Docs: 5},
+				HaveField("Skipped", ContainSubstring("rolled over")),
+			))
+			Expect(search.indices).ToNot(HaveKey("testmachinery-000017"))
+			Expect(search.indices["testmachinery-000017-v1"].docs).To(Equal(10))
+			Expect(search.indices["testmachinery-000017-v1"].aliases).To(HaveKey("testmachinery-000017"))
+			Expect(search.indices["testmachinery-000017-v1"].aliases).To(HaveKeyWithValue("testmachinery", false))
+			Expect(search.bodies["/testmachinery-000017-v1"]).To(ContainSubstring(`"index.lifecycle.indexing_complete":true`))
+			Expect(search.indices["tm-test-v1"].aliases).To(HaveKey("tm-test"))
+			Expect(search.indices).To(HaveKey("tm-writing"))
+		})
+
+		It("should block writes and count the documents of the source index before reindexing", func() {
+			search.indices["tm-test"].uncounted = 2
+			manager := NewManager(logr.Discard(), client, Options{PollInterval: time.Millisecond})
+			results, err := manager.Migrate(ctx, []string{"tm-test"})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(results).To(ConsistOf(MigrationResult{Index: "tm-test", Destination: "tm-test-v1", Docs: 5}))
+			Expect(search.requests).To(HaveExactElements(
+				HavePrefix("GET /_cat/indices/tm-test"),
+				"GET /tm-test/_mapping",
+				"GET /tm-test/_alias",
+				"PUT /tm-test/_settings",
+				"PUT /tm-test-v1",
+				"GET /tm-test/_count",
+				"POST /_reindex?wait_for_completion=false",
+				"GET /_tasks/node:1",
+				"GET /_tasks/node:1",
+				"POST /tm-test-v1/_refresh",
+				"GET /tm-test-v1/_count",
+				"POST /_aliases",
+			))
+			Expect(search.bodies["/tm-test/_settings"]).To(ContainSubstring(`"index.blocks.write":true`))
+			Expect(search.indices["tm-test-v1"].docs).To(Equal(5))
+		})
+
+		It("should keep the source index writable if documents are missing", func() {
+			search.lostDocs = 1
+			manager := NewManager(logr.Discard(), client, Options{PollInterval: time.Millisecond})
+			_, err := manager.Migrate(ctx, []string{"testmachinery-*"})
+			Expect(err).To(MatchError(ContainSubstring("the source index is kept")))
+			Expect(search.indices).To(HaveKey("testmachinery-000017"))
+			Expect(search.indices["testmachinery-000017"].writeBlocked).To(BeFalse())
+			Expect(search.bodies["/testmachinery-000017/_settings"]).To(Equal(`{"index.blocks.write":null}`))
+		})
+
+		It("should only report the indices in dry-run mode", func() {
+			manager := NewManager(logr.Discard(), client, Options{DryRun: true})
+			results, err := manager.Migrate(ctx, []string{"testmachinery-*", "tm-*"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(results).To(HaveLen(4))
+			Expect(search.indices).To(HaveLen(4))
+		})
+	})
+})
+
+type fakeIndex struct {
+	docs int
+	// uncounted are the latest documents that are not yet part of the docs count of the cat api
+	uncounted    int
+	version      int
+	aliases      map[string]bool
+	writeBlocked bool
+}
+
+// fakeSearch is a minimal in-memory stand-in for the index management api of elasticsearch and opensearch
+type fakeSearch struct {
+	mux         sync.Mutex
+	indices     map[string]*fakeIndex
+	requests    []string
+	bodies      map[string]string
+	policySeqNo int
+	lostDocs    int
+	taskPolls   int
+	reindex     [2]string
+}
+
+func newFakeSearch() *fakeSearch {
+	return &fakeSearch{indices: map[string]*fakeIndex{}, bodies: map[string]string{}}
+}
+
+func (f *fakeSearch) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	body, _ := io.ReadAll(r.Body)
+	request := r.Method + " " + r.URL.Path
+	if r.URL.RawQuery != "" {
+		request += "?" + r.URL.RawQuery
+	}
+	f.requests = append(f.requests, request)
+	if len(body) != 0 {
+		f.bodies[r.URL.Path] = string(body)
+	}
+
+	segments := strings.Split(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	respond := func(v interface{}) {
+		_ = json.NewEncoder(w).Encode(v)
+	}
+	switch {
+	case segments[0] == "_plugins" && r.Method == http.MethodGet:
+		if f.policySeqNo == 0 {
+			http.NotFound(w, r)
+			return
+		}
+		respond(map[string]interface{}{"_seq_no": f.policySeqNo, "_primary_term": 1})
+	case segments[0] == "_ilm" || segments[0] == "_plugins" || segments[0] == "_index_template":
+		respond(map[string]interface{}{"acknowledged": true})
+	case segments[0] == "_alias":
+		result := map[string]interface{}{}
+		for name, index := range f.indices {
+			if isWrite, ok := index.aliases[segments[1]]; ok {
+				result[name] = map[string]interface{}{"aliases": map[string]interface{}{segments[1]: map[string]interface{}{"is_write_index": isWrite}}}
+			}
+		}
+		if len(result) == 0 {
+			http.NotFound(w, r)
+			return
+		}
+		respond(result)
+	case segments[0] == "_cat":
+		result := make([]map[string]string, 0)
+		for name, index := range f.indices {
+			for _, pattern := range strings.Split(segments[2], ",") {
+				if ok, _ := path.Match(pattern, name); ok {
+					result = append(result, map[string]string{"index": name, "docs.count": fmt.Sprint(index.docs - index.uncounted)})
+					break
+				}
+			}
+		}
+		respond(result)
+	case segments[0] == "_reindex":
+		reindex := struct {
+			Source struct{ Index string } `json:"source"`
+			Dest   struct{ Index string } `json:"dest"`
+		}{}
+		_ = json.Unmarshal(body, &reindex)
+		f.reindex = [2]string{reindex.Source.Index, reindex.Dest.Index}
+		f.taskPolls = 0
+		respond(map[string]interface{}{"task": "node:1"})
+	case segments[0] == "_tasks":
+		f.taskPolls++
+		source := f.indices[f.reindex[0]]
+		if f.taskPolls < 2 {
+			respond(map[string]interface{}{"completed": false, "task": map[string]interface{}{"status": map[string]interface{}{"total": source.docs, "created": source.docs / 2}}})
+			return
+		}
+		f.indices[f.reindex[1]].docs = source.docs - f.lostDocs
+		respond(map[string]interface{}{"completed": true, "task": map[string]interface{}{"status": map[string]interface{}{"total": source.docs, "created": source.docs}}})
+	case segments[0] == "_aliases":
+		actions := struct {
+			Actions []struct {
+				RemoveIndex *struct {
+					Index string `json:"index"`
+				} `json:"remove_index"`
+				Add *struct {
+					Index        string `json:"index"`
+					Alias        string `json:"alias"`
+					IsWriteIndex bool   `json:"is_write_index"`
+				} `json:"add"`
+			} `json:"actions"`
+		}{}
+		_ = json.Unmarshal(body, &actions)
+		for _, action := range actions.Actions {
+			if action.RemoveIndex != nil {
+				delete(f.indices, action.RemoveIndex.Index)
+			}
+			if add := action.Add; add != nil {
+				if _, ok := f.indices[add.Alias]; ok {
+					http.Error(w, "an index exists with the same name as the alias", http.StatusBadRequest)
+					return
+				}
+				f.indices[add.Index].aliases[add.Alias] = add.IsWriteIndex
+			}
+		}
+		respond(map[string]interface{}{"acknowledged": true})
+	case len(segments) == 1 && r.Method == http.MethodPut:
+		if _, ok := f.indices[segments[0]]; ok {
+			http.Error(w, "resource_already_exists_exception", http.StatusBadRequest)
+			return
+		}
+		index := &fakeIndex{version: TemplateVersion, aliases: map[string]bool{}}
+		create := struct {
+			Aliases map[string]struct {
+				IsWriteIndex bool `json:"is_write_index"`
+			} `json:"aliases"`
+		}{}
+		_ = json.Unmarshal(body, &create)
+		for alias, a := range create.Aliases {
+			if _, ok := f.indices[alias]; ok {
+				http.Error(w, "invalid_alias_name_exception", http.StatusBadRequest)
+				return
+			}
+			index.aliases[alias] = a.IsWriteIndex
+		}
+		f.indices[segments[0]] = index
+		respond(map[string]interface{}{"acknowledged": true})
+	case len(segments) == 2 && segments[1] == "_rollover":
+		for name, index := range f.indices {
+			if index.aliases[segments[0]] {
+				index.aliases[segments[0]] = false
+				var number int
+				_, _ = fmt.Sscanf(name[strings.LastIndex(name, "-")+1:], "%d", &number)
+				f.indices[fmt.Sprintf("%s-%06d", segments[0], number+1)] = &fakeIndex{version: TemplateVersion, aliases: map[string]bool{segments[0]: true}}
+				break
+			}
+		}
+		respond(map[string]interface{}{"acknowledged": true})
+	case len(segments) == 2:
+		index, ok := f.indices[segments[0]]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		switch segments[1] {
+		case "_mapping":
+			respond(map[string]interface{}{segments[0]: map[string]interface{}{"mappings": map[string]interface{}{"_meta": map[string]interface{}{"version": index.version}}}})
+		case "_alias":
+			aliases := map[string]interface{}{}
+			for alias, isWrite := range index.aliases {
+				aliases[alias] = map[string]interface{}{"is_write_index": isWrite}
+			}
+			respond(map[string]interface{}{segments[0]: map[string]interface{}{"aliases": aliases}})
+		case "_count":
+			respond(map[string]interface{}{"count": index.docs})
+		case "_settings":
+			settings := struct {
+				WriteBlocked *bool `json:"index.blocks.write"`
+			}{}
+			_ = json.Unmarshal(body, &settings)
+			index.writeBlocked = settings.WriteBlocked != nil && *settings.WriteBlocked
+			respond(map[string]interface{}{"acknowledged": true})
+		default:
+			respond(map[string]interface{}{})
+		}
+	default:
+		http.Error(w, "unexpected request "+request, http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,118 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package index
+
+import (
+	"reflect"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Mapping is the mapping of the properties of an elasticsearch document
+type Mapping map[string]interface{}
+
+var timeType = reflect.TypeOf(metav1.Time{})
+
+// textKeyword is the mapping of strings. It equals the dynamic mapping of elasticsearch
+// so that existing queries and dashboards on the keyword fields keep working.
+func textKeyword() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "text",
+		"fields": map[string]interface{}{
+			"keyword": map[string]interface{}{"type": "keyword", "ignore_above": 256},
+		},
+	}
+}
+
+// MappingOf derives the explicit mapping of the properties of all given documents from their json fields.
+// The properties of several documents are merged so that documents of different types can be stored in the same index.
+func MappingOf(docs ...interface{}) Mapping {
+	mapping := Mapping{}
+	for _, doc := range docs {
+		mergeProperties(mapping, propertiesOf(reflect.TypeOf(doc)))
+	}
+	return mapping
+}
+
+func propertiesOf(t reflect.Type) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	properties := make(map[string]interface{})
+	if t.Kind() != reflect.Struct {
+		return properties
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, skip := jsonName(field)
+		if skip {
+			continue
+		}
+		if field.Anonymous && name == "" {
+			mergeProperties(properties, propertiesOf(field.Type))
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if property := propertyOf(field.Type); property != nil {
+			properties[name] = property
+		}
+	}
+	return properties
+}
+
+// propertyOf returns the mapping of a field type.
+// Maps and interfaces return nil and are mapped dynamically.
+func propertyOf(t reflect.Type) map[string]interface{} {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	if t == timeType {
+		return map[string]interface{}{"type": "date"}
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return textKeyword()
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "long"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "double"}
+	case reflect.Struct:
+		return map[string]interface{}{"properties": propertiesOf(t)}
+	default:
+		return nil
+	}
+}
+
+func jsonName(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", true
+	}
+	return strings.Split(tag, ",")[0], false
+}
+
+func mergeProperties(dst, src map[string]interface{}) {
+	for name, property := range src {
+		existing, ok := dst[name].(map[string]interface{})
+		if !ok {
+			dst[name] = property
+			continue
+		}
+		srcProperties, srcOK := property.(map[string]interface{})["properties"].(map[string]interface{})
+		dstProperties, dstOK := existing["properties"].(map[string]interface{})
+		if srcOK && dstOK {
+			mergeProperties(dstProperties, srcProperties)
+		}
+	}
+}
@@ -0,0 +1,236 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package index
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// MigrationResult describes the migration of one index
+type MigrationResult struct {
+	Index       string
+	Destination string
+	Docs        int
+	// Skipped is the reason why the index has not been migrated
+	Skipped string
+}
+
+// Migrate reindexes all indices matching the patterns whose mapping is older than the current template version.
+// Every index is reindexed into a new index with the version suffix that gets the current mapping from the index template.
+// Writes to the old index are blocked during the migration so that no document is lost.
+// Once all documents have been copied, the old index is deleted and its name becomes an alias of the new index.
+// All aliases of the old index are moved to the new index.
+// Write indices of an alias are skipped and have to be rolled over with Setup first.
+func (m *Manager) Migrate(ctx context.Context, patterns []string) ([]MigrationResult, error) {
+	var indices []struct {
+		Index     string `json:"index"`
+		DocsCount string `json:"docs.count"`
+	}
+	if err := m.read(ctx, fmt.Sprintf("/_cat/indices/%s?format=json&h=index,docs.count", strings.Join(patterns, ",")), &indices); err != nil {
+		return nil, errors.Wrap(err, "unable to list indices")
+	}
+	sort.Slice(indices, func(i, j int) bool { return indices[i].Index < indices[j].Index })
+
+	results := make([]MigrationResult, 0, len(indices))
+	for _, index := range indices {
+		docs, _ := strconv.Atoi(index.DocsCount)
+		result := MigrationResult{Index: index.Index, Docs: docs, Destination: migratedName(index.Index)}
+		if strings.HasPrefix(index.Index, ".") {
+			continue
+		}
+
+		skipped, aliases, err := m.skipMigration(ctx, index.Index)
+		if err != nil {
+			return results, err
+		}
+		if skipped != "" {
+			result.Skipped, result.Destination = skipped, ""
+			m.log.V(3).Info("skip index", "index", index.Index, "reason", skipped)
+			results = append(results, result)
+			continue
+		}
+
+		m.log.Info("migrate index", "index", index.Index, "destination", result.Destination, "docs", docs)
+		if err := m.migrate(ctx, &result, aliases); err != nil {
+			return results, errors.Wrapf(err, "unable to migrate index %s", index.Index)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// skipMigration returns the reason why an index must not be migrated and the aliases of the index
+func (m *Manager) skipMigration(ctx context.Context, index string) (string, []string, error) {
+	version, err := m.mappingVersion(ctx, index)
+	if err != nil {
+		return "", nil, err
+	}
+	if version >= TemplateVersion {
+		return fmt.Sprintf("mapping version %d is up to date", version), nil, nil
+	}
+
+	aliases := map[string]struct {
+		Aliases map[string]struct {
+			IsWriteIndex *bool `json:"is_write_index"`
+		} `json:"aliases"`
+	}{}
+	if err := m.read(ctx, "/"+index+"/_alias", &aliases); err != nil {
+		return "", nil, errors.Wrapf(err, "unable to get aliases of index %s", index)
+	}
+	names := make([]string, 0, len(aliases[index].Aliases))
+	for alias, a := range aliases[index].Aliases {
+		if a.IsWriteIndex != nil && *a.IsWriteIndex {
+			return fmt.Sprintf("write index of alias %s has to be rolled over first", alias), nil, nil
+		}
+		names = append(names, alias)
+	}
+	sort.Strings(names)
+	return "", names, nil
+}
+
+func (m *Manager) migrate(ctx context.Context, result *MigrationResult, aliases []string) error {
+	if err := m.write(ctx, http.MethodPut, "/"+result.Index+"/_settings", map[string]interface{}{"index.blocks.write": true}); err != nil {
+		return errors.Wrapf(err, "unable to block writes to index %s", result.Index)
+	}
+	if err := m.copyIndex(ctx, result); err != nil {
+		// the source index is kept and has to be writable again
+		if unblockErr := m.write(ctx, http.MethodPut, "/"+result.Index+"/_settings", map[string]interface{}{"index.blocks.write": nil}); unblockErr != nil {
+			m.log.Error(unblockErr, "unable to remove the write block", "index", result.Index)
+		}
+		return err
+	}
+
+	// replace the old index with an alias so that it can still be written and read by its name
+	// and keep all aliases of the old index, e.g. the read alias of rolled over indices
+	actions := []interface{}{
+		map[string]interface{}{"remove_index": map[string]interface{}{"index": result.Index}},
+		map[string]interface{}{"add": map[string]interface{}{"index": result.Destination, "alias": result.Index}},
+	}
+	for _, alias := range aliases {
+		actions = append(actions, map[string]interface{}{"add": map[string]interface{}{"index": result.Destination, "alias": alias}})
+	}
+	return m.write(ctx, http.MethodPost, "/_aliases", map[string]interface{}{"actions": actions})
+}
+
+// copyIndex creates the destination index and reindexes all documents of the write blocked source index into it.
+// The documents are counted after the write block so that documents written during the listing are not missed.
+func (m *Manager) copyIndex(ctx context.Context, result *MigrationResult) error {
+	settings := map[string]interface{}{}
+	// migrated indices are no write indices and must not be rolled over by the lifecycle policy
+	switch m.opts.Flavor {
+	case FlavorOpenSearch:
+		settings["plugins.index_state_management.rollover_skip"] = true
+	default:
+		settings["index.lifecycle.indexing_complete"] = true
+	}
+	if err := m.write(ctx, http.MethodPut, "/"+result.Destination, map[string]interface{}{"settings": settings}); err != nil {
+		return errors.Wrapf(err, "unable to create index %s", result.Destination)
+	}
+
+	var count struct {
+		Count int `json:"count"`
+	}
+	if err := m.read(ctx, "/"+result.Index+"/_count", &count); err != nil {
+		return errors.Wrapf(err, "unable to count documents of %s", result.Index)
+	}
+	result.Docs = count.Count
+	return m.reindex(ctx, result.Index, result.Destination, result.Docs)
+}
+
+// reindex copies all documents of the source index into the destination index
+// and verifies that the destination index contains the expected number of documents.
+func (m *Manager) reindex(ctx context.Context, source, destination string, docs int) error {
+	body, err := m.writeWithResponse(ctx, http.MethodPost, "/_reindex?wait_for_completion=false", map[string]interface{}{
+		"source": map[string]interface{}{"index": source},
+		"dest":   map[string]interface{}{"index": destination},
+	})
+	if err != nil {
+		return errors.Wrap(err, "unable to start reindex")
+	}
+	if m.opts.DryRun {
+		return nil
+	}
+	var task struct {
+		Task string `json:"task"`
+	}
+	if err := json.Unmarshal(body, &task); err != nil {
+		return errors.Wrap(err, "unable to parse reindex task")
+	}
+	if err := m.waitForTask(ctx, task.Task, source); err != nil {
+		return err
+	}
+
+	if err := m.write(ctx, http.MethodPost, "/"+destination+"/_refresh", nil); err != nil {
+		return errors.Wrapf(err, "unable to refresh index %s", destination)
+	}
+	var count struct {
+		Count int `json:"count"`
+	}
+	if err := m.read(ctx, "/"+destination+"/_count", &count); err != nil {
+		return errors.Wrapf(err, "unable to count documents of %s", destination)
+	}
+	if count.Count != docs {
+		return fmt.Errorf("index %s contains %d documents but %d were expected; the source index is kept", destination, count.Count, docs)
+	}
+	return nil
+}
+
+// waitForTask waits until the reindex task has finished and reports its progress
+func (m *Manager) waitForTask(ctx context.Context, taskID string, source string) error {
+	for {
+		var task struct {
+			Completed bool `json:"completed"`
+			Task      struct {
+				Status struct {
+					Total   int `json:"total"`
+					Created int `json:"created"`
+					Updated int `json:"updated"`
+				} `json:"status"`
+			} `json:"task"`
+			Error    json.RawMessage `json:"error"`
+			Response struct {
+				Failures []json.RawMessage `json:"failures"`
+			} `json:"response"`
+		}
+		if err := m.read(ctx, "/_tasks/"+taskID, &task); err != nil {
+			return errors.Wrapf(err, "unable to get reindex task %s", taskID)
+		}
+
+		status := task.Task.Status
+		processed := status.Created + status.Updated
+		if status.Total != 0 {
+			m.log.Info(fmt.Sprintf("%d%% migrated (%d/%d)", processed*100/status.Total, processed, status.Total), "index", source)
+		}
+		if task.Completed {
+			if len(task.Error) != 0 {
+				return fmt.Errorf("reindex task %s failed: %s", taskID, task.Error)
+			}
+			if len(task.Response.Failures) != 0 {
+				return fmt.Errorf("reindex task %s failed for %d documents, e.g. %s", taskID, len(task.Response.Failures), task.Response.Failures[0])
+			}
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(m.opts.PollInterval):
+		}
+	}
+}
+
+// migratedName returns the name of the index with the current mapping version
+func migratedName(index string) string {
+	return fmt.Sprintf("%s-v%d", index, TemplateVersion)
+}
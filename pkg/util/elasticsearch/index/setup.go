@@ -0,0 +1,243 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package index
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+
+	"github.com/gardener/test-infra/pkg/util/elasticsearch"
+)
+
+// Options configures the index management
+type Options struct {
+	Flavor    Flavor
+	Lifecycle LifecycleConfig
+	// DryRun only logs the requests that would modify elasticsearch
+	DryRun bool
+	// PollInterval is the interval in which the progress of a reindex task is checked
+	PollInterval time.Duration
+}
+
+// Manager installs the index templates, lifecycle policies and aliases of the testmachinery and migrates existing indices
+type Manager struct {
+	log    logr.Logger
+	client elasticsearch.Client
+	opts   Options
+}
+
+// NewManager creates a new index manager
+func NewManager(log logr.Logger, client elasticsearch.Client, opts Options) *Manager {
+	if opts.Flavor == "" {
+		opts.Flavor = FlavorElasticsearch
+	}
+	if opts.PollInterval == 0 {
+		opts.PollInterval = 5 * time.Second
+	}
+	return &Manager{log: log, client: client, opts: opts}
+}
+
+// Setup installs the lifecycle policies and index templates and bootstraps the write aliases.
+// A write index with an outdated mapping is rolled over so that new documents are written with the current mapping.
+func (m *Manager) Setup(ctx context.Context) error {
+	for _, template := range Templates() {
+		if template.Policy != "" {
+			if err := m.putPolicy(ctx, template); err != nil {
+				return errors.Wrapf(err, "unable to install lifecycle policy %s", template.Policy)
+			}
+		}
+		if err := m.write(ctx, http.MethodPut, "/_index_template/"+template.Name, template.indexTemplate(m.opts.Flavor)); err != nil {
+			return errors.Wrapf(err, "unable to install index template %s", template.Name)
+		}
+		m.log.Info("installed index template", "template", template.Name, "version", TemplateVersion)
+
+		if template.RolloverAlias != "" {
+			if err := m.setupAlias(ctx, template.RolloverAlias); err != nil {
+				return errors.Wrapf(err, "unable to setup alias %s", template.RolloverAlias)
+			}
+		}
+	}
+	return nil
+}
+
+func (m *Manager) putPolicy(ctx context.Context, template Template) error {
+	if m.opts.Flavor != FlavorOpenSearch {
+		return m.write(ctx, http.MethodPut, "/_ilm/policy/"+template.Policy, template.ilmPolicy(m.opts.Lifecycle))
+	}
+
+	// existing ism policies can only be updated with their current sequence number
+	path := "/_plugins/_ism/policies/" + template.Policy
+	var existing struct {
+		SeqNo       *int `json:"_seq_no"`
+		PrimaryTerm *int `json:"_primary_term"`
+	}
+	if err := m.read(ctx, path, &existing); err != nil && !elasticsearch.IsNotFound(err) {
+		return err
+	}
+	if existing.SeqNo != nil && existing.PrimaryTerm != nil {
+		path = fmt.Sprintf("%s?if_seq_no=%d&if_primary_term=%d", path, *existing.SeqNo, *existing.PrimaryTerm)
+	}
+	return m.write(ctx, http.MethodPut, path, template.ismPolicy(m.opts.Lifecycle))
+}
+
+// setupAlias creates the first index of a write alias or rolls over its write index if the mapping is outdated
+func (m *Manager) setupAlias(ctx context.Context, alias string) error {
+	aliases := map[string]struct {
+		Aliases map[string]struct {
+			IsWriteIndex *bool `json:"is_write_index"`
+		} `json:"aliases"`
+	}{}
+	if err := m.read(ctx, "/_alias/"+alias, &aliases); err != nil {
+		if !elasticsearch.IsNotFound(err) {
+			return err
+		}
+		return m.bootstrapAlias(ctx, alias)
+	}
+
+	writeIndex := ""
+	for index, a := range aliases {
+		if isWriteIndex := a.Aliases[alias].IsWriteIndex; (isWriteIndex != nil && *isWriteIndex) || len(aliases) == 1 {
+			writeIndex = index
+		}
+	}
+	if writeIndex == "" {
+		return fmt.Errorf("alias %s has no write index", alias)
+	}
+	version, err := m.mappingVersion(ctx, writeIndex)
+	if err != nil {
+		return err
+	}
+	if version >= TemplateVersion {
+		m.log.Info("write index is up to date", "alias", alias, "index", writeIndex, "version", version)
+		return nil
+	}
+	m.log.Info("rollover outdated write index", "alias", alias, "index", writeIndex, "version", version)
+	return m.write(ctx, http.MethodPost, "/"+alias+"/_rollover", nil)
+}
+
+// bootstrapAlias creates the first write index of an alias.
+// Installations that still write to a concrete index with the name of the alias are migrated,
+// as the alias cannot be created as long as the index exists.
+func (m *Manager) bootstrapAlias(ctx context.Context, alias string) error {
+	index := alias + "-000001"
+	exists, err := m.indexExists(ctx, alias)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		m.log.Info("bootstrap write alias", "alias", alias, "index", index)
+		return m.write(ctx, http.MethodPut, "/"+index, map[string]interface{}{
+			"aliases": map[string]interface{}{
+				alias: map[string]interface{}{"is_write_index": true},
+			},
+		})
+	}
+	return m.migrateLegacyIndex(ctx, alias, index)
+}
+
+// migrateLegacyIndex reindexes a concrete index with the name of an alias into the first write index of the alias
+// and replaces the concrete index with the alias.
+// Writes to the legacy index are blocked during the migration so that no document is lost.
+func (m *Manager) migrateLegacyIndex(ctx context.Context, alias, index string) error {
+	m.log.Info("migrate legacy index to write alias", "alias", alias, "index", index)
+	if err := m.write(ctx, http.MethodPut, "/"+alias+"/_settings", map[string]interface{}{"index.blocks.write": true}); err != nil {
+		return errors.Wrapf(err, "unable to block writes to index %s", alias)
+	}
+
+	// the write index may already exist if a previous migration failed
+	exists, err := m.indexExists(ctx, index)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		if err := m.write(ctx, http.MethodPut, "/"+index, nil); err != nil {
+			return errors.Wrapf(err, "unable to create index %s", index)
+		}
+	}
+
+	var count struct {
+		Count int `json:"count"`
+	}
+	if err := m.read(ctx, "/"+alias+"/_count", &count); err != nil {
+		return errors.Wrapf(err, "unable to count documents of %s", alias)
+	}
+	if err := m.reindex(ctx, alias, index, count.Count); err != nil {
+		return errors.Wrapf(err, "unable to reindex %s", alias)
+	}
+
+	return m.write(ctx, http.MethodPost, "/_aliases", map[string]interface{}{
+		"actions": []interface{}{
+			map[string]interface{}{"remove_index": map[string]interface{}{"index": alias}},
+			map[string]interface{}{"add": map[string]interface{}{"index": index, "alias": alias, "is_write_index": true}},
+		},
+	})
+}
+
+// indexExists checks whether a concrete index exists
+func (m *Manager) indexExists(ctx context.Context, index string) (bool, error) {
+	if _, err := m.client.RequestWithCtx(ctx, http.MethodGet, "/"+index+"/_mapping", nil); err != nil {
+		if elasticsearch.IsNotFound(err) {
+			return false, nil
+		}
+		return false, errors.Wrapf(err, "unable to get index %s", index)
+	}
+	return true, nil
+}
+
+// mappingVersion returns the template version of the mapping of an index. Indices without version are version 0.
+func (m *Manager) mappingVersion(ctx context.Context, index string) (int, error) {
+	mappings := map[string]struct {
+		Mappings struct {
+			Meta struct {
+				Version int `json:"version"`
+			} `json:"_meta"`
+		} `json:"mappings"`
+	}{}
+	if err := m.read(ctx, "/"+index+"/_mapping", &mappings); err != nil {
+		return 0, errors.Wrapf(err, "unable to get mapping of index %s", index)
+	}
+	return mappings[index].Mappings.Meta.Version, nil
+}
+
+func (m *Manager) read(ctx context.Context, path string, result interface{}) error {
+	body, err := m.client.RequestWithCtx(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, result); err != nil {
+		return errors.Wrapf(err, "unable to unmarshal response of %s", path)
+	}
+	return nil
+}
+
+// write sends a modifying request to elasticsearch or only logs it in dry-run mode
+func (m *Manager) write(ctx context.Context, method, path string, payload interface{}) error {
+	_, err := m.writeWithResponse(ctx, method, path, payload)
+	return err
+}
+
+func (m *Manager) writeWithResponse(ctx context.Context, method, path string, payload interface{}) ([]byte, error) {
+	data := []byte{}
+	if payload != nil {
+		var err error
+		data, err = json.Marshal(payload)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to marshal payload of %s", path)
+		}
+	}
+	if m.opts.DryRun {
+		m.log.Info("dry run: skip request", "method", method, "path", path)
+		m.log.V(5).Info("dry run: request payload", "payload", string(data))
+		return nil, nil
+	}
+	return m.client.RequestWithCtx(ctx, method, path, bytes.NewReader(data))
+}
@@ -0,0 +1,168 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package index
+
+import (
+	"github.com/gardener/test-infra/pkg/testmachinery/metadata"
+)
+
+// TemplateVersion is the version of the index templates.
+// It has to be increased with every change of a mapping so that the existing indices are rolled over and can be migrated.
+const TemplateVersion = 1
+
+// SummaryAlias is the write alias of the testrun and step summaries that are written by the collector
+const SummaryAlias = "testmachinery"
+
+// Flavor is the search engine that manages the index lifecycle
+type Flavor string
+
+const (
+	// FlavorElasticsearch manages the index lifecycle with index lifecycle management (ILM)
+	FlavorElasticsearch Flavor = "elasticsearch"
+	// FlavorOpenSearch manages the index lifecycle with index state management (ISM)
+	FlavorOpenSearch Flavor = "opensearch"
+)
+
+// LifecycleConfig configures the rollover and retention of the summary indices
+type LifecycleConfig struct {
+	// RolloverMaxAge rolls over the write index after the given age, e.g. 30d
+	RolloverMaxAge string
+	// RolloverMaxSize rolls over the write index once its primary shards reach the given size, e.g. 50gb
+	RolloverMaxSize string
+	// RetentionAge deletes the indices after the given age. The indices are kept forever if empty.
+	RetentionAge string
+}
+
+// Template is a versioned index template with explicit mappings
+type Template struct {
+	Name          string
+	IndexPatterns []string
+	// Policy is the lifecycle policy of the indices. Indices without policy are kept forever.
+	Policy string
+	// RolloverAlias is the write alias that is rolled over by the lifecycle policy
+	RolloverAlias string
+	Mapping       Mapping
+}
+
+// Templates returns the index templates of all documents that are written by the testmachinery
+func Templates() []Template {
+	return []Template{
+		{
+			Name:          "testmachinery",
+			IndexPatterns: []string{"testmachinery-*"},
+			Policy:        "testmachinery",
+			RolloverAlias: SummaryAlias,
+			Mapping:       MappingOf(metadata.TestrunSummary{}, metadata.StepSummary{}),
+		},
+		{
+			// exported documents are written to tm-<testdefinition> indices and are only mapped by their metadata
+			Name:          "tm-exports",
+			IndexPatterns: []string{"tm-*"},
+			Mapping: MappingOf(struct {
+				Metadata metadata.StepExportMetadata `json:"tm"`
+			}{}),
+		},
+	}
+}
+
+// indexTemplate returns the composable index template body of a template
+func (t Template) indexTemplate(flavor Flavor) map[string]interface{} {
+	settings := map[string]interface{}{}
+	if t.RolloverAlias != "" {
+		switch flavor {
+		case FlavorOpenSearch:
+			settings["plugins.index_state_management.rollover_alias"] = t.RolloverAlias
+		default:
+			settings["index.lifecycle.name"] = t.Policy
+			settings["index.lifecycle.rollover_alias"] = t.RolloverAlias
+		}
+	}
+	return map[string]interface{}{
+		"index_patterns": t.IndexPatterns,
+		"version":        TemplateVersion,
+		"priority":       100,
+		"_meta":          map[string]interface{}{"managedBy": "testmachinery", "version": TemplateVersion},
+		"template": map[string]interface{}{
+			"settings": settings,
+			"mappings": map[string]interface{}{
+				"_meta": map[string]interface{}{"version": TemplateVersion},
+				"dynamic_templates": []interface{}{
+					map[string]interface{}{
+						"strings": map[string]interface{}{
+							"match_mapping_type": "string",
+							"mapping":            textKeyword(),
+						},
+					},
+				},
+				"properties": t.Mapping,
+			},
+		},
+	}
+}
+
+// ilmPolicy returns the elasticsearch index lifecycle policy of a template
+func (t Template) ilmPolicy(cfg LifecycleConfig) map[string]interface{} {
+	rollover := map[string]interface{}{}
+	if cfg.RolloverMaxAge != "" {
+		rollover["max_age"] = cfg.RolloverMaxAge
+	}
+	if cfg.RolloverMaxSize != "" {
+		rollover["max_primary_shard_size"] = cfg.RolloverMaxSize
+	}
+	phases := map[string]interface{}{
+		"hot": map[string]interface{}{
+			"actions": map[string]interface{}{"rollover": rollover},
+		},
+	}
+	if cfg.RetentionAge != "" {
+		phases["delete"] = map[string]interface{}{
+			"min_age": cfg.RetentionAge,
+			"actions": map[string]interface{}{"delete": map[string]interface{}{}},
+		}
+	}
+	return map[string]interface{}{
+		"policy": map[string]interface{}{
+			"_meta":  map[string]interface{}{"managedBy": "testmachinery", "version": TemplateVersion},
+			"phases": phases,
+		},
+	}
+}
+
+// ismPolicy returns the opensearch index state management policy of a template
+func (t Template) ismPolicy(cfg LifecycleConfig) map[string]interface{} {
+	rollover := map[string]interface{}{}
+	if cfg.RolloverMaxAge != "" {
+		rollover["min_index_age"] = cfg.RolloverMaxAge
+	}
+	if cfg.RolloverMaxSize != "" {
+		rollover["min_primary_shard_size"] = cfg.RolloverMaxSize
+	}
+	hot := map[string]interface{}{
+		"name":        "hot",
+		"actions":     []interface{}{map[string]interface{}{"rollover": rollover}},
+		"transitions": []interface{}{},
+	}
+	states := []interface{}{hot}
+	if cfg.RetentionAge != "" {
+		hot["transitions"] = []interface{}{
+			map[string]interface{}{"state_name": "delete", "conditions": map[string]interface{}{"min_index_age": cfg.RetentionAge}},
+		}
+		states = append(states, map[string]interface{}{
+			"name":        "delete",
+			"actions":     []interface{}{map[string]interface{}{"delete": map[string]interface{}{}}},
+			"transitions": []interface{}{},
+		})
+	}
+	return map[string]interface{}{
+		"policy": map[string]interface{}{
+			"description":   "testmachinery rollover and retention policy",
+			"default_state": "hot",
+			"states":        states,
+			"ism_template": []interface{}{
+				map[string]interface{}{"index_patterns": t.IndexPatterns, "priority": 100},
+			},
+		},
+	}
+}
@@ -162,3 +162,48 @@ func GetLatestK8sVersion(cloudprofile gardencorev1beta1.CloudProfile) (gardencor
 
 	return GetLatestVersion(FilterExpiredVersions(cloudprofile.Spec.Kubernetes.Versions))
 }
+
+// GetKubernetesUpgradeSourceVersion returns the kubernetes version a shoot is created with before it is upgraded to the target version.
+// The latest non-expired version of the cloudprofile that matches the pattern and is lower than the target version is returned.
+// Next to semver constraints and the kubernetes version patterns, the pattern "latestPatchOfPreviousMinor" is supported
+// which selects the latest patch version of the minor version before the target version.
+func GetKubernetesUpgradeSourceVersion(cloudprofile gardencorev1beta1.CloudProfile, pattern string, target gardencorev1beta1.ExpirableVersion) (gardencorev1beta1.ExpirableVersion, error) {
+	targetSemver, err := semver.NewVersion(target.Version)
+	if err != nil {
+		return gardencorev1beta1.ExpirableVersion{}, errors.Wrapf(err, "invalid target version %s", target.Version)
+	}
+
+	if pattern == common.PatternLatestPatchOfPreviousMinor {
+		if targetSemver.Minor() == 0 {
+			return gardencorev1beta1.ExpirableVersion{}, fmt.Errorf("there is no previous minor version of %s", target.Version)
+		}
+		pattern = fmt.Sprintf("~%d.%d.0", targetSemver.Major(), targetSemver.Minor()-1)
+	}
+
+	candidates, err := GetK8sVersions(cloudprofile, common.ShootKubernetesVersionFlavor{Pattern: &pattern}, false)
+	if err != nil {
+		return gardencorev1beta1.ExpirableVersion{}, err
+	}
+
+	var (
+		source       *gardencorev1beta1.ExpirableVersion
+		sourceSemver *semver.Version
+	)
+	for i, candidate := range candidates {
+		version, err := semver.NewVersion(candidate.Version)
+		if err != nil {
+			return gardencorev1beta1.ExpirableVersion{}, err
+		}
+		if !version.LessThan(targetSemver) {
+			continue
+		}
+		if source == nil || version.GreaterThan(sourceSemver) {
+			source = &candidates[i]
+			sourceSemver = version
+		}
+	}
+	if source == nil {
+		return gardencorev1beta1.ExpirableVersion{}, fmt.Errorf("no kubernetes version matching %s that is lower than %s found in cloudprofile %s", pattern, target.Version, cloudprofile.Name)
+	}
+	return *source, nil
+}
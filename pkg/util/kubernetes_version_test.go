@@ -393,4 +393,47 @@ var _ = Describe("kubernetes version util", func() {
 		})
 
 	})
+
+	Context("get upgrade source version", func() {
+		var (
+			cloudprofile gardencorev1beta1.CloudProfile
+		)
+		BeforeEach(func() {
+			cloudprofile = gardencorev1beta1.CloudProfile{Spec: gardencorev1beta1.CloudProfileSpec{
+				Kubernetes: gardencorev1beta1.KubernetesSettings{
+					Versions: []gardencorev1beta1.ExpirableVersion{
+						newExpirableVersion("1.15.2"),
+						newExpirableVersion("1.15.1"),
+						newExpirableVersion("1.14.6"),
+						newExpirableVersion("1.14.5"),
+						newExpirableVersion("1.13.5"),
+					},
+				},
+			}}
+		})
+
+		It("should return the latest patch version of the previous minor", func() {
+			version, err := util.GetKubernetesUpgradeSourceVersion(cloudprofile, common.PatternLatestPatchOfPreviousMinor, newExpirableVersion("1.15.2"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(version).To(Equal(newExpirableVersion("1.14.6")))
+		})
+
+		It("should return the latest version of a pattern that is lower than the target", func() {
+			version, err := util.GetKubernetesUpgradeSourceVersion(cloudprofile, "1.15.*", newExpirableVersion("1.15.2"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(version).To(Equal(newExpirableVersion("1.15.1")))
+
+			version, err = util.GetKubernetesUpgradeSourceVersion(cloudprofile, common.PatternTwoMinorBeforeLatest, newExpirableVersion("1.15.2"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(version).To(Equal(newExpirableVersion("1.13.5")))
+		})
+
+		It("should fail if no lower version matches the pattern", func() {
+			_, err := util.GetKubernetesUpgradeSourceVersion(cloudprofile, common.PatternLatest, newExpirableVersion("1.15.2"))
+			Expect(err).To(HaveOccurred())
+
+			_, err = util.GetKubernetesUpgradeSourceVersion(cloudprofile, common.PatternLatestPatchOfPreviousMinor, newExpirableVersion("1.13.5"))
+			Expect(err).To(HaveOccurred())
+		})
+	})
 })
@@ -8,6 +8,7 @@ import (
 
 	"github.com/Masterminds/semver/v3"
 	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	v1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
 	versionutils "github.com/gardener/gardener/pkg/utils/version"
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -359,3 +360,33 @@ func GetLatestPreviousVersionForInPlaceUpdate(cloudprofile gardencorev1beta1.Clo
 
 	return parsedVersions[0].Original(), nil
 }
+
+// GetMachineImageUpgradeSourceVersion returns the machine image version a worker pool is created with before it is upgraded to its current version.
+// Next to the machine image version patterns, the pattern "latestInPlaceUpdatable" is supported
+// which selects the latest previous version that can be updated in-place to the current version.
+func GetMachineImageUpgradeSourceVersion(cloudprofile gardencorev1beta1.CloudProfile, worker gardencorev1beta1.Worker, pattern string) (string, error) {
+	if worker.Machine.Image == nil || worker.Machine.Image.Version == nil {
+		return "", fmt.Errorf("no machine image version defined for worker pool %s", worker.Name)
+	}
+	arch := ptr.Deref(worker.Machine.Architecture, v1beta1constants.ArchitectureAMD64)
+
+	if pattern == common.PatternLatestInPlaceUpdatable {
+		return GetLatestPreviousVersionForInPlaceUpdate(cloudprofile, *worker.Machine.Image, arch)
+	}
+
+	source := worker.DeepCopy()
+	source.Machine.Image.Version = &pattern
+	source.Machine.Architecture = &arch
+	version, err := GetMachineImageVersion(cloudprofile, source)
+	if err != nil {
+		return "", err
+	}
+	lower, err := versionutils.CompareVersions(version.Version, "<", *worker.Machine.Image.Version)
+	if err != nil {
+		return "", err
+	}
+	if !lower {
+		return "", fmt.Errorf("machine image version %s of pattern %s is not lower than the version %s of worker pool %s", version.Version, pattern, *worker.Machine.Image.Version, worker.Name)
+	}
+	return version.Version, nil
+}
@@ -358,6 +358,68 @@ var _ = Describe("machine image version", func() {
 		})
 	})
 
+	Describe("#GetMachineImageUpgradeSourceVersion", func() {
+		BeforeEach(func() {
+			futureTime = metav1.NewTime(time.Now().Add(time.Hour * 24))
+			cloudprofile = gardencorev1beta1.CloudProfile{
+				Spec: gardencorev1beta1.CloudProfileSpec{
+					ProviderConfig: BuildCapabilityProviderConfig(ArchsByImage{imageName: {
+						"3.4.5": {arch_amd64},
+						"3.4.0": {arch_amd64},
+						"2.3.3": {arch_amd64},
+					}}),
+					MachineImages: []gardencorev1beta1.MachineImage{
+						{
+							Name: imageName,
+							Versions: []gardencorev1beta1.MachineImageVersion{
+								{ExpirableVersion: gardencorev1beta1.ExpirableVersion{
+									Version:        "3.4.5",
+									ExpirationDate: &futureTime,
+								}, InPlaceUpdates: &gardencorev1beta1.InPlaceUpdates{Supported: true, MinVersionForUpdate: ptr.To("2.3.0")}},
+								{ExpirableVersion: gardencorev1beta1.ExpirableVersion{
+									Version:        "3.4.0",
+									ExpirationDate: &futureTime,
+								}, InPlaceUpdates: &gardencorev1beta1.InPlaceUpdates{Supported: true}},
+								{ExpirableVersion: gardencorev1beta1.ExpirableVersion{
+									Version:        "2.3.3",
+									ExpirationDate: &futureTime,
+								}},
+							},
+						},
+					},
+				},
+			}
+
+			worker = &gardencorev1beta1.Worker{
+				Name: "pool",
+				Machine: gardencorev1beta1.Machine{
+					Image: &gardencorev1beta1.ShootMachineImage{
+						Name:    imageName,
+						Version: ptr.To("3.4.5"),
+					},
+					Architecture: ptr.To(arch_amd64),
+				},
+			}
+		})
+
+		It("should return the latest version that can be updated in-place", func() {
+			version, err := GetMachineImageUpgradeSourceVersion(cloudprofile, *worker, common.PatternLatestInPlaceUpdatable)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(version).To(Equal("3.4.0"))
+		})
+
+		It("should return the version of a pattern", func() {
+			version, err := GetMachineImageUpgradeSourceVersion(cloudprofile, *worker, common.PatternOneMajorBeforeLatest)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(version).To(Equal("2.3.3"))
+		})
+
+		It("should fail if the version of the pattern is not lower than the current version", func() {
+			_, err := GetMachineImageUpgradeSourceVersion(cloudprofile, *worker, common.PatternLatest)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
 	Describe("#FilterMachineImageVersionsByArch", func() {
 		versions := []gardencorev1beta1.MachineImageVersion{
 			{ExpirableVersion: gardencorev1beta1.ExpirableVersion{Version: "1.0.0"}},
@@ -0,0 +1,172 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package redact
+
+import (
+	"encoding/base64"
+	"sort"
+	"strings"
+
+	tmv1beta1 "github.com/gardener/test-infra/pkg/apis/testmachinery/v1beta1"
+)
+
+// Placeholder replaces redacted values.
+const Placeholder = "--- REDACTED ---"
+
+// minValueLength is the minimal length of a sensitive value that is replaced in arbitrary text.
+// Shorter values would redact unrelated parts of the text.
+const minValueLength = 4
+
+// sensitiveNameParts are parts of config names that indicate a sensitive value.
+var sensitiveNameParts = []string{
+	"PASSWORD",
+	"PASSWD",
+	"SECRET",
+	"TOKEN",
+	"CREDENTIAL",
+	"APIKEY",
+	"API_KEY",
+	"ACCESS_KEY",
+	"PRIVATE_KEY",
+	"KUBECONFIG",
+}
+
+// sensitiveNameSegments are segments of config names separated by "_", "-" or "." that indicate a sensitive value.
+var sensitiveNameSegments = []string{"KEY", "PASS", "PWD", "AUTH"}
+
+// IsSensitiveConfig returns whether the value of a config element has to be redacted.
+// The explicit sensitive marker of the config has precedence over the detection by its name.
+func IsSensitiveConfig(config *tmv1beta1.ConfigElement) bool {
+	if config.Sensitive != nil {
+		return *config.Sensitive
+	}
+	return IsSensitiveName(config.Name)
+}
+
+// IsSensitiveName returns whether a config name indicates a sensitive value like a token or password.
+func IsSensitiveName(name string) bool {
+	upper := strings.ToUpper(name)
+	for _, part := range sensitiveNameParts {
+		if strings.Contains(upper, part) {
+			return true
+		}
+	}
+	segments := strings.FieldsFunc(upper, func(r rune) bool {
+		return r == '_' || r == '-' || r == '.'
+	})
+	for _, segment := range segments {
+		for _, sensitive := range sensitiveNameSegments {
+			if segment == sensitive {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ConfigElement returns a copy of the config element whose value is redacted if it is sensitive.
+func ConfigElement(config *tmv1beta1.ConfigElement) *tmv1beta1.ConfigElement {
+	redacted := config.DeepCopy()
+	if len(redacted.Value) != 0 && IsSensitiveConfig(redacted) {
+		redacted.Value = Placeholder
+	}
+	return redacted
+}
+
+// ConfigElements returns copies of the config elements whose sensitive values are redacted.
+func ConfigElements(configs []*tmv1beta1.ConfigElement) []*tmv1beta1.ConfigElement {
+	if configs == nil {
+		return nil
+	}
+	redacted := make([]*tmv1beta1.ConfigElement, len(configs))
+	for i, config := range configs {
+		redacted[i] = ConfigElement(config)
+	}
+	return redacted
+}
+
+func redactConfigList(configs []tmv1beta1.ConfigElement) {
+	for i := range configs {
+		if len(configs[i].Value) != 0 && IsSensitiveConfig(&configs[i]) {
+			configs[i].Value = Placeholder
+		}
+	}
+}
+
+// Testrun returns a copy of the testrun whose sensitive config values are redacted in its spec and status.
+func Testrun(tr *tmv1beta1.Testrun) *tmv1beta1.Testrun {
+	redacted := tr.DeepCopy()
+	redactConfigList(redacted.Spec.Config)
+	for _, step := range redacted.Spec.TestFlow {
+		redactConfigList(step.Definition.Config)
+	}
+	for _, step := range redacted.Spec.OnExit {
+		redactConfigList(step.Definition.Config)
+	}
+	for _, step := range redacted.Status.Steps {
+		step.TestDefinition.Config = ConfigElements(step.TestDefinition.Config)
+	}
+	return redacted
+}
+
+// SensitiveValues returns the sensitive config values of the testruns.
+// Values of file configs are returned base64 encoded and decoded.
+func SensitiveValues(testruns ...*tmv1beta1.Testrun) []string {
+	values := make(map[string]struct{})
+	add := func(config *tmv1beta1.ConfigElement) {
+		if len(config.Value) == 0 || config.Value == Placeholder || !IsSensitiveConfig(config) {
+			return
+		}
+		values[config.Value] = struct{}{}
+		if config.Type == tmv1beta1.ConfigTypeFile {
+			if decoded, err := base64.StdEncoding.DecodeString(config.Value); err == nil {
+				values[string(decoded)] = struct{}{}
+			}
+		}
+	}
+	for _, tr := range testruns {
+		if tr == nil {
+			continue
+		}
+		for i := range tr.Spec.Config {
+			add(&tr.Spec.Config[i])
+		}
+		for _, flow := range []tmv1beta1.TestFlow{tr.Spec.TestFlow, tr.Spec.OnExit} {
+			for _, step := range flow {
+				for i := range step.Definition.Config {
+					add(&step.Definition.Config[i])
+				}
+			}
+		}
+		for _, step := range tr.Status.Steps {
+			for _, config := range step.TestDefinition.Config {
+				add(config)
+			}
+		}
+	}
+
+	list := make([]string, 0, len(values))
+	for value := range values {
+		if len(value) >= minValueLength {
+			list = append(list, value)
+		}
+	}
+	// replace longer values first so that values containing other values are completely redacted
+	sort.Slice(list, func(i, j int) bool {
+		if len(list[i]) != len(list[j]) {
+			return len(list[i]) > len(list[j])
+		}
+		return list[i] < list[j]
+	})
+	return list
+}
+
+// String replaces all occurrences of the sensitive values in s.
+func String(s string, sensitiveValues []string) string {
+	for _, value := range sensitiveValues {
+		s = strings.ReplaceAll(s, value, Placeholder)
+	}
+	return s
+}
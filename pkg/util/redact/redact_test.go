@@ -0,0 +1,77 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package redact_test
+
+import (
+	"encoding/base64"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/ptr"
+
+	tmv1beta1 "github.com/gardener/test-infra/pkg/apis/testmachinery/v1beta1"
+	"github.com/gardener/test-infra/pkg/util/redact"
+)
+
+var _ = Describe("Redact", func() {
+	DescribeTable("IsSensitiveName",
+		func(name string, sensitive bool) {
+			Expect(redact.IsSensitiveName(name)).To(Equal(sensitive))
+		},
+		Entry("token", "GITHUB_TOKEN", true),
+		Entry("password", "db_password", true),
+		Entry("secret", "CLIENT_SECRET", true),
+		Entry("key segment", "SSH_KEY", true),
+		Entry("kubeconfig", "SEED_KUBECONFIG", true),
+		Entry("shoot name", "SHOOT_NAME", false),
+		Entry("key as part of a word", "MONKEY_NAME", false),
+		Entry("testrun id", "TM_TESTRUN_ID", false),
+	)
+
+	It("should prefer the explicit sensitive marker over the name", func() {
+		Expect(redact.IsSensitiveConfig(&tmv1beta1.ConfigElement{Name: "VERSION", Sensitive: ptr.To(true)})).To(BeTrue())
+		Expect(redact.IsSensitiveConfig(&tmv1beta1.ConfigElement{Name: "TOKEN_COUNT", Sensitive: ptr.To(false)})).To(BeFalse())
+	})
+
+	It("should redact a copy of the testrun", func() {
+		tr := &tmv1beta1.Testrun{
+			Spec: tmv1beta1.TestrunSpec{
+				Config: []tmv1beta1.ConfigElement{{Type: tmv1beta1.ConfigTypeEnv, Name: "TOKEN", Value: "abcdef"}},
+				TestFlow: tmv1beta1.TestFlow{
+					{Name: "step", Definition: tmv1beta1.StepDefinition{Config: []tmv1beta1.ConfigElement{{Type: tmv1beta1.ConfigTypeEnv, Name: "SHOOT_NAME", Value: "shoot"}}}},
+				},
+			},
+			Status: tmv1beta1.TestrunStatus{
+				Steps: []*tmv1beta1.StepStatus{
+					{TestDefinition: tmv1beta1.StepStatusTestDefinition{Config: []*tmv1beta1.ConfigElement{{Type: tmv1beta1.ConfigTypeEnv, Name: "TOKEN", Value: "abcdef"}}}},
+				},
+			},
+		}
+
+		redacted := redact.Testrun(tr)
+		Expect(redacted.Spec.Config[0].Value).To(Equal(redact.Placeholder))
+		Expect(redacted.Spec.TestFlow[0].Definition.Config[0].Value).To(Equal("shoot"))
+		Expect(redacted.Status.Steps[0].TestDefinition.Config[0].Value).To(Equal(redact.Placeholder))
+		Expect(tr.Spec.Config[0].Value).To(Equal("abcdef"))
+		Expect(tr.Status.Steps[0].TestDefinition.Config[0].Value).To(Equal("abcdef"))
+	})
+
+	It("should redact sensitive values of testruns in text", func() {
+		tr := &tmv1beta1.Testrun{
+			Spec: tmv1beta1.TestrunSpec{
+				Config: []tmv1beta1.ConfigElement{
+					{Type: tmv1beta1.ConfigTypeEnv, Name: "TOKEN", Value: "abcdef"},
+					{Type: tmv1beta1.ConfigTypeFile, Name: "CREDENTIALS", Value: base64.StdEncoding.EncodeToString([]byte("user:password"))},
+					{Type: tmv1beta1.ConfigTypeEnv, Name: "PASSWORD", Value: "abc"},
+					{Type: tmv1beta1.ConfigTypeEnv, Name: "SHOOT_NAME", Value: "shoot"},
+				},
+			},
+		}
+
+		values := redact.SensitiveValues(tr, nil)
+		Expect(redact.String("token abcdef, credentials user:password, pw abc, shoot", values)).
+			To(Equal("token --- REDACTED ---, credentials --- REDACTED ---, pw abc, shoot"))
+	})
+})
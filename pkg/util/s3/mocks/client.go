@@ -10,6 +10,7 @@
 package mock_s3
 
 import (
+	io "io"
 	reflect "reflect"
 
 	s3 "github.com/gardener/test-infra/pkg/util/s3"
@@ -56,6 +57,49 @@ func (mr *MockClientMockRecorder) GetObject(bucketName, objectName any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetObject", reflect.TypeOf((*MockClient)(nil).GetObject), bucketName, objectName)
 }
 
+// ListObjects mocks base method.
+func (m *MockClient) ListObjects(bucketName, prefix string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListObjects", bucketName, prefix)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListObjects indicates an expected call of ListObjects.
+func (mr *MockClientMockRecorder) ListObjects(bucketName, prefix any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListObjects", reflect.TypeOf((*MockClient)(nil).ListObjects), bucketName, prefix)
+}
+
+// PutObject mocks base method.
+func (m *MockClient) PutObject(bucketName, key string, data []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutObject", bucketName, key, data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PutObject indicates an expected call of PutObject.
+func (mr *MockClientMockRecorder) PutObject(bucketName, key, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutObject", reflect.TypeOf((*MockClient)(nil).PutObject), bucketName, key, data)
+}
+
+// PutObjectStream mocks base method.
+func (m *MockClient) PutObjectStream(bucketName, key string, reader io.Reader, size int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutObjectStream", bucketName, key, reader, size)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PutObjectStream indicates an expected call of PutObjectStream.
+func (mr *MockClientMockRecorder) PutObjectStream(bucketName, key, reader, size any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutObjectStream", reflect.TypeOf((*MockClient)(nil).PutObjectStream), bucketName, key, reader, size)
+}
+
 // RemoveObject mocks base method.
 func (m *MockClient) RemoveObject(bucketName, key string) error {
 	m.ctrl.T.Helper()
@@ -5,6 +5,7 @@
 package s3
 
 import (
+	"bytes"
 	"io"
 
 	"github.com/minio/minio-go"
@@ -16,6 +17,9 @@ import (
 // Client is a interface to interact with a S3 object store
 type Client interface {
 	GetObject(bucketName, objectName string) (Object, error)
+	ListObjects(bucketName, prefix string) ([]string, error)
+	PutObject(bucketName, key string, data []byte) error
+	PutObjectStream(bucketName, key string, reader io.Reader, size int64) error
 	RemoveObject(bucketName, key string) error
 }
 
@@ -67,6 +71,42 @@ func (c *client) GetObject(bucketName, objectName string) (Object, error) {
 	return c.minioClient.GetObject(bucketName, objectName, minio.GetObjectOptions{})
 }
 
+// ListObjects returns the keys of all objects with the given prefix
+func (c *client) ListObjects(bucketName, prefix string) ([]string, error) {
+	if bucketName == "" {
+		bucketName = c.defaultBucketName
+	}
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+
+	keys := make([]string, 0)
+	for object := range c.minioClient.ListObjectsV2(bucketName, prefix, true, doneCh) {
+		if object.Err != nil {
+			return nil, errors.Wrapf(object.Err, "unable to list objects with prefix %s", prefix)
+		}
+		keys = append(keys, object.Key)
+	}
+	return keys, nil
+}
+
+func (c *client) PutObject(bucketName, key string, data []byte) error {
+	if bucketName == "" {
+		bucketName = c.defaultBucketName
+	}
+	_, err := c.minioClient.PutObject(bucketName, key, bytes.NewReader(data), int64(len(data)), minio.PutObjectOptions{})
+	return err
+}
+
+// PutObjectStream uploads the content of the reader without loading it into memory.
+// Large objects are uploaded in multiple parts.
+func (c *client) PutObjectStream(bucketName, key string, reader io.Reader, size int64) error {
+	if bucketName == "" {
+		bucketName = c.defaultBucketName
+	}
+	_, err := c.minioClient.PutObject(bucketName, key, reader, size, minio.PutObjectOptions{})
+	return err
+}
+
 func (c *client) RemoveObject(bucketName, key string) error {
 	if bucketName == "" {
 		bucketName = c.defaultBucketName
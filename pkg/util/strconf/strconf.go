@@ -17,6 +17,58 @@ type ConfigSource struct {
 	// Selects a key of a secret in the pod's namespace
 	// +optional
 	SecretKeyRef *corev1.SecretKeySelector `json:"secretKeyRef,omitempty"`
+	// Selects a key of a secret in another namespace.
+	// The namespace has to be allowed by the testmachinery configuration.
+	// +optional
+	NamespacedSecretKeyRef *NamespacedSecretKeySelector `json:"namespacedSecretKeyRef,omitempty"`
+	// Selects a key of a secret in the Vault compatible KV secrets engine configured for the testmachinery.
+	// +optional
+	Vault *VaultSecretSelector `json:"vault,omitempty"`
+	// Renders the value from a template that combines the values of other sources.
+	// +optional
+	Template *TemplateSource `json:"template,omitempty"`
+}
+
+// NamespacedSecretKeySelector selects a key of a secret in a specific namespace.
+// +k8s:deepcopy-gen=true
+// +k8s:openapi-gen=true
+type NamespacedSecretKeySelector struct {
+	// Namespace of the secret.
+	Namespace string `json:"namespace"`
+	// Name of the secret.
+	Name string `json:"name"`
+	// Key of the secret to select from.
+	Key string `json:"key"`
+}
+
+// VaultSecretSelector selects a key of a secret in a Vault compatible KV secrets engine.
+// +k8s:deepcopy-gen=true
+// +k8s:openapi-gen=true
+type VaultSecretSelector struct {
+	// Mount is the path the KV secrets engine is mounted at, e.g. "secret".
+	Mount string `json:"mount"`
+	// Path of the secret in the secrets engine.
+	Path string `json:"path"`
+	// Key of the secret to select from.
+	Key string `json:"key"`
+}
+
+// TemplateSource renders a value from a go template.
+// +k8s:deepcopy-gen=true
+// +k8s:openapi-gen=true
+type TemplateSource struct {
+	// Template is the go template that is rendered with the values of the sources,
+	// e.g. "{{ .username }}:{{ .password }}".
+	Template string `json:"template"`
+	// Sources are the named sources whose values can be referenced in the template.
+	// Sources must not be templates themselves.
+	Sources map[string]ConfigSource `json:"sources"`
+}
+
+// IsExternal returns true if the value of the source cannot be referenced by a pod of the testrun
+// but has to be resolved by the testmachinery.
+func (s *ConfigSource) IsExternal() bool {
+	return s.NamespacedSecretKeyRef != nil || s.Vault != nil || s.Template != nil
 }
 
 // StringOrConfig represents a type that could be from a string or a configuration
@@ -1,16 +1,29 @@
 package strconf
 
 import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
 // Validate validates a testrun config element.
 func Validate(fldPath *field.Path, source *ConfigSource) field.ErrorList {
+	return validate(fldPath, source, true)
+}
+
+func validate(fldPath *field.Path, source *ConfigSource, allowTemplate bool) field.ErrorList {
 	var allErrs field.ErrorList
-	if source.ConfigMapKeyRef == nil && source.SecretKeyRef == nil {
-		allErrs = append(allErrs, field.Required(fldPath.Child("configMapKeyRef/secretMapKeyRef"), "Either a configmap ref or a secretmap ref have to be defined"))
+	if source.ConfigMapKeyRef == nil && source.SecretKeyRef == nil && !source.IsExternal() {
+		allErrs = append(allErrs, field.Required(fldPath.Child("configMapKeyRef/secretMapKeyRef"), "Either a configmap ref, a secret ref, a namespaced secret ref, a vault secret or a template have to be defined"))
 		return allErrs
 	}
+	if source.IsExternal() && numberOfSources(source) != 1 {
+		allErrs = append(allErrs, field.Invalid(fldPath, "", "namespaced secret refs, vault secrets and templates cannot be combined with other sources"))
+	}
 	if source.ConfigMapKeyRef != nil {
 		cmPath := fldPath.Child("configMapKeyRef")
 		if source.ConfigMapKeyRef.Key == "" {
@@ -29,5 +42,83 @@ func Validate(fldPath *field.Path, source *ConfigSource) field.ErrorList {
 			allErrs = append(allErrs, field.Required(skPath.Child("name"), "has to be defined"))
 		}
 	}
+	if source.NamespacedSecretKeyRef != nil {
+		nskPath := fldPath.Child("namespacedSecretKeyRef")
+		if source.NamespacedSecretKeyRef.Namespace == "" {
+			allErrs = append(allErrs, field.Required(nskPath.Child("namespace"), "has to be defined"))
+		}
+		if source.NamespacedSecretKeyRef.Name == "" {
+			allErrs = append(allErrs, field.Required(nskPath.Child("name"), "has to be defined"))
+		}
+		if source.NamespacedSecretKeyRef.Key == "" {
+			allErrs = append(allErrs, field.Required(nskPath.Child("key"), "has to be defined"))
+		}
+	}
+	if source.Vault != nil {
+		vaultPath := fldPath.Child("vault")
+		if source.Vault.Mount == "" {
+			allErrs = append(allErrs, field.Required(vaultPath.Child("mount"), "has to be defined"))
+		}
+		if source.Vault.Path == "" {
+			allErrs = append(allErrs, field.Required(vaultPath.Child("path"), "has to be defined"))
+		}
+		if source.Vault.Key == "" {
+			allErrs = append(allErrs, field.Required(vaultPath.Child("key"), "has to be defined"))
+		}
+	}
+	if source.Template != nil {
+		allErrs = append(allErrs, validateTemplate(fldPath.Child("template"), source.Template, allowTemplate)...)
+	}
+	return allErrs
+}
+
+func validateTemplate(fldPath *field.Path, source *TemplateSource, allowTemplate bool) field.ErrorList {
+	var allErrs field.ErrorList
+	if !allowTemplate {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "templates cannot be nested"))
+		return allErrs
+	}
+	if source.Template == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("template"), "has to be defined"))
+	} else if _, err := template.New("").Parse(source.Template); err != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("template"), source.Template, fmt.Sprintf("cannot parse template: %s", err.Error())))
+	}
+	if len(source.Sources) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("sources"), "at least one source has to be defined"))
+	}
+
+	names := make([]string, 0, len(source.Sources))
+	for name := range source.Sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		srcPath := fldPath.Child("sources").Key(name)
+		if errs := validation.IsCIdentifier(name); len(errs) != 0 {
+			allErrs = append(allErrs, field.Invalid(srcPath, name, strings.Join(errs, ":")))
+		}
+		src := source.Sources[name]
+		allErrs = append(allErrs, validate(srcPath, &src, false)...)
+	}
 	return allErrs
 }
+
+func numberOfSources(source *ConfigSource) int {
+	n := 0
+	if source.ConfigMapKeyRef != nil {
+		n++
+	}
+	if source.SecretKeyRef != nil {
+		n++
+	}
+	if source.NamespacedSecretKeyRef != nil {
+		n++
+	}
+	if source.Vault != nil {
+		n++
+	}
+	if source.Template != nil {
+		n++
+	}
+	return n
+}
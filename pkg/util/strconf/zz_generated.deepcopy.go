@@ -26,6 +26,21 @@ func (in *ConfigSource) DeepCopyInto(out *ConfigSource) {
 		*out = new(v1.SecretKeySelector)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.NamespacedSecretKeyRef != nil {
+		in, out := &in.NamespacedSecretKeyRef, &out.NamespacedSecretKeyRef
+		*out = new(NamespacedSecretKeySelector)
+		**out = **in
+	}
+	if in.Vault != nil {
+		in, out := &in.Vault, &out.Vault
+		*out = new(VaultSecretSelector)
+		**out = **in
+	}
+	if in.Template != nil {
+		in, out := &in.Template, &out.Template
+		*out = new(TemplateSource)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -39,6 +54,22 @@ func (in *ConfigSource) DeepCopy() *ConfigSource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespacedSecretKeySelector) DeepCopyInto(out *NamespacedSecretKeySelector) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespacedSecretKeySelector.
+func (in *NamespacedSecretKeySelector) DeepCopy() *NamespacedSecretKeySelector {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespacedSecretKeySelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StringOrConfig) DeepCopyInto(out *StringOrConfig) {
 	*out = *in
@@ -55,3 +86,42 @@ func (in *StringOrConfig) DeepCopy() *StringOrConfig {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TemplateSource) DeepCopyInto(out *TemplateSource) {
+	*out = *in
+	if in.Sources != nil {
+		in, out := &in.Sources, &out.Sources
+		*out = make(map[string]ConfigSource, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemplateSource.
+func (in *TemplateSource) DeepCopy() *TemplateSource {
+	if in == nil {
+		return nil
+	}
+	out := new(TemplateSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VaultSecretSelector) DeepCopyInto(out *VaultSecretSelector) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultSecretSelector.
+func (in *VaultSecretSelector) DeepCopy() *VaultSecretSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(VaultSecretSelector)
+	in.DeepCopyInto(out)
+	return out
+}
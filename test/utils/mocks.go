@@ -38,6 +38,10 @@ func (t *LocationsMock) GetTestDefinitions(step tmv1beta1.StepDefinition) ([]*te
 	return nil, nil
 }
 
+func (t *LocationsMock) ResolvedLocations() []tmv1beta1.ResolvedLocation {
+	return nil
+}
+
 var EmptyMockLocation = &LocationsMock{
 	GetTestDefinitionsFunc: func(step tmv1beta1.StepDefinition) ([]*testdefinition.TestDefinition, error) {
 		return []*testdefinition.TestDefinition{}, nil